@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+var _ xclient.TokenMetadataClient = &Client{}
+
+// Read the token_info of a CW20 token
+func (client *Client) FetchTokenMetadata(ctx context.Context, contract xc.ContractAddress) (*xclient.TokenMetadata, error) {
+	input := json.RawMessage(`{"token_info": {}}`)
+	type TokenInfo struct {
+		Name     string `json:"name"`
+		Symbol   string `json:"symbol"`
+		Decimals int32  `json:"decimals"`
+	}
+	var info TokenInfo
+
+	resp, err := wasmtypes.NewQueryClient(client.Ctx).SmartContractState(ctx, &wasmtypes.QuerySmartContractStateRequest{
+		QueryData: wasmtypes.RawContractMessage(input),
+		Address:   string(contract),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token info: '%v': %v", contract, err)
+	}
+	err = json.Unmarshal(resp.Data.Bytes(), &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token info: '%v': %v", contract, err)
+	}
+	return xclient.NewTokenMetadata(contract, info.Name, info.Symbol, info.Decimals), nil
+}
@@ -0,0 +1,177 @@
+package cctp
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc20"
+)
+
+//go:embed token_messenger.json
+var tokenMessengerAbiJson string
+var tokenMessengerAbi abi.ABI
+
+//go:embed message_transmitter.json
+var messageTransmitterAbiJson string
+var messageTransmitterAbi abi.ABI
+
+var erc20Abi abi.ABI
+
+func NewTokenMessengerAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(tokenMessengerAbiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func NewMessageTransmitterAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(messageTransmitterAbiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func init() {
+	var err error
+	tokenMessengerAbi = NewTokenMessengerAbi()
+	messageTransmitterAbi = NewMessageTransmitterAbi()
+	erc20Abi, err = abi.JSON(strings.NewReader(erc20.Erc20ABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+type Contracts struct {
+	TokenMessenger     common.Address
+	MessageTransmitter common.Address
+}
+
+// CCTP deployments by chain id
+var Deployments = map[int64]Contracts{
+	// ethereum
+	1: {
+		TokenMessenger:     common.HexToAddress("0xBd3fa81B58Ba92a82136038B25aDec7066af3155"),
+		MessageTransmitter: common.HexToAddress("0x0a992d191DEeC32aFe36203Ad87D7d289a738F81"),
+	},
+	// avalanche
+	43114: {
+		TokenMessenger:     common.HexToAddress("0x6B25532e1060CE10cc3B0A99e5683b91BFDe6982"),
+		MessageTransmitter: common.HexToAddress("0x8186359aF5F57FbB40c6b14A588d2A59C0C29880"),
+	},
+	// optimism
+	10: {
+		TokenMessenger:     common.HexToAddress("0x2B4069517957735bE00ceE0fadAE88a26365528f"),
+		MessageTransmitter: common.HexToAddress("0x4D41f22c5a0e5c74090899E5a8Fb597a8842b3e8"),
+	},
+	// arbitrum
+	42161: {
+		TokenMessenger:     common.HexToAddress("0x19330d10D9Cc8751218eaf51E8885D058642E08A"),
+		MessageTransmitter: common.HexToAddress("0xC30362313FBBA5cf9163F0bb16a0e01f01A896ca"),
+	},
+	// base
+	8453: {
+		TokenMessenger:     common.HexToAddress("0x1682Ae6375C4E4A97e4B583BC394c861A46D8962"),
+		MessageTransmitter: common.HexToAddress("0xAD09780d193884d503182aD4588450C416D6F9D4"),
+	},
+	// polygon
+	137: {
+		TokenMessenger:     common.HexToAddress("0x9daF8c91AEFAE50b9c0E69629D3F6Ca40cA3B3FE"),
+		MessageTransmitter: common.HexToAddress("0xF3be9355363857F3e001be68856A2f96b4C39Ba9"),
+	},
+	// testnets share the same addresses
+	11155111: testnetContracts,
+	43113:    testnetContracts,
+	11155420: testnetContracts,
+	421614:   testnetContracts,
+	84532:    testnetContracts,
+	80002:    testnetContracts,
+}
+
+var testnetContracts = Contracts{
+	TokenMessenger:     common.HexToAddress("0x9f3B8679c73C2Fef8b59B4f3444d4e156fb70AA5"),
+	MessageTransmitter: common.HexToAddress("0x7865fAfC2db2093669d92c0F33AeEF291086BEFD"),
+}
+
+func SerializeDepositForBurn(amount *big.Int, destinationDomain uint32, mintRecipient [32]byte, burnToken common.Address) ([]byte, error) {
+	return tokenMessengerAbi.Pack("depositForBurn", amount, destinationDomain, mintRecipient, burnToken)
+}
+
+func SerializeDepositForBurnWithCaller(amount *big.Int, destinationDomain uint32, mintRecipient [32]byte, burnToken common.Address, destinationCaller [32]byte) ([]byte, error) {
+	return tokenMessengerAbi.Pack("depositForBurnWithCaller", amount, destinationDomain, mintRecipient, burnToken, destinationCaller)
+}
+
+func SerializeReceiveMessage(message []byte, attestation []byte) ([]byte, error) {
+	return messageTransmitterAbi.Pack("receiveMessage", message, attestation)
+}
+
+func SerializeUsedNonces(sourceAndNonce [32]byte) ([]byte, error) {
+	return messageTransmitterAbi.Pack("usedNonces", sourceAndNonce)
+}
+
+func ParseUsedNonces(output []byte) (*big.Int, error) {
+	values, err := messageTransmitterAbi.Unpack("usedNonces", output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected usedNonces output length %d", len(values))
+	}
+	used, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected usedNonces output type %T", values[0])
+	}
+	return used, nil
+}
+
+func SerializeApprove(spender common.Address, amount *big.Int) ([]byte, error) {
+	return erc20Abi.Pack("approve", spender, amount)
+}
+
+func SerializeAllowance(owner common.Address, spender common.Address) ([]byte, error) {
+	return erc20Abi.Pack("allowance", owner, spender)
+}
+
+func ParseAllowance(output []byte) (*big.Int, error) {
+	values, err := erc20Abi.Unpack("allowance", output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected allowance output length %d", len(values))
+	}
+	allowance, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected allowance output type %T", values[0])
+	}
+	return allowance, nil
+}
+
+func MessageSentTopic() common.Hash {
+	return messageTransmitterAbi.Events["MessageSent"].ID
+}
+
+// ParseMessageSent returns the message of a MessageSent log from a message transmitter
+func ParseMessageSent(log types.Log) ([]byte, error) {
+	if len(log.Topics) == 0 || log.Topics[0] != MessageSentTopic() {
+		return nil, fmt.Errorf("not a MessageSent log")
+	}
+	values, err := messageTransmitterAbi.Unpack("MessageSent", log.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected MessageSent length %d", len(values))
+	}
+	message, ok := values[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected MessageSent type %T", values[0])
+	}
+	return message, nil
+}
@@ -0,0 +1,110 @@
+package erc1155
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//go:embed abi.json
+var abiJson string
+var erc1155Abi abi.ABI
+
+func NewAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+func init() {
+	erc1155Abi = NewAbi()
+}
+
+// Serialize a call to safeTransferFrom(address,address,uint256,uint256,bytes)
+func SerializeSafeTransferFrom(from common.Address, to common.Address, id *big.Int, amount *big.Int, data []byte) ([]byte, error) {
+	if data == nil {
+		data = []byte{}
+	}
+	return erc1155Abi.Pack("safeTransferFrom", from, to, id, amount, data)
+}
+
+func SerializeBalanceOf(account common.Address, id *big.Int) ([]byte, error) {
+	return erc1155Abi.Pack("balanceOf", account, id)
+}
+
+func ParseBalanceOf(output []byte) (*big.Int, error) {
+	values, err := erc1155Abi.Unpack("balanceOf", output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected balanceOf output length %d", len(values))
+	}
+	balance, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected balanceOf output type %T", values[0])
+	}
+	return balance, nil
+}
+
+// A single movement of an ERC1155 token.  TransferBatch events are flattened into
+// one of these for each id.
+type Transfer struct {
+	Operator common.Address
+	From     common.Address
+	To       common.Address
+	Id       *big.Int
+	Value    *big.Int
+}
+
+type transferSingleRaw struct {
+	Id    *big.Int
+	Value *big.Int
+}
+type transferBatchRaw struct {
+	Ids    []*big.Int
+	Values []*big.Int
+}
+
+func ParseTransfers(log types.Log) ([]*Transfer, error) {
+	if len(log.Topics) != 4 {
+		return nil, fmt.Errorf("expected 4 topics for erc1155 transfer, received %d", len(log.Topics))
+	}
+	operator := common.BytesToAddress(log.Topics[1].Bytes())
+	from := common.BytesToAddress(log.Topics[2].Bytes())
+	to := common.BytesToAddress(log.Topics[3].Bytes())
+
+	transfers := []*Transfer{}
+	switch log.Topics[0] {
+	case erc1155Abi.Events["TransferSingle"].ID:
+		event := new(transferSingleRaw)
+		if err := erc1155Abi.UnpackIntoInterface(event, "TransferSingle", log.Data); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, &Transfer{operator, from, to, event.Id, event.Value})
+	case erc1155Abi.Events["TransferBatch"].ID:
+		event := new(transferBatchRaw)
+		if err := erc1155Abi.UnpackIntoInterface(event, "TransferBatch", log.Data); err != nil {
+			return nil, err
+		}
+		if len(event.Ids) != len(event.Values) {
+			return nil, fmt.Errorf("mismatched ids and values in erc1155 batch transfer")
+		}
+		for i := range event.Ids {
+			transfers = append(transfers, &Transfer{operator, from, to, event.Ids[i], event.Values[i]})
+		}
+	default:
+		return nil, fmt.Errorf("log is not an erc1155 transfer")
+	}
+	return transfers, nil
+}
+
+func EventByID(topic common.Hash) (*abi.Event, error) {
+	return erc1155Abi.EventByID(topic)
+}
@@ -0,0 +1,78 @@
+package erc721
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//go:embed abi.json
+var abiJson string
+var erc721Abi abi.ABI
+
+func NewAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+func init() {
+	erc721Abi = NewAbi()
+}
+
+// Serialize a call to safeTransferFrom(address,address,uint256)
+func SerializeSafeTransferFrom(from common.Address, to common.Address, tokenId *big.Int) ([]byte, error) {
+	return erc721Abi.Pack("safeTransferFrom", from, to, tokenId)
+}
+
+func SerializeOwnerOf(tokenId *big.Int) ([]byte, error) {
+	return erc721Abi.Pack("ownerOf", tokenId)
+}
+
+func ParseOwnerOf(output []byte) (common.Address, error) {
+	values, err := erc721Abi.Unpack("ownerOf", output)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(values) != 1 {
+		return common.Address{}, fmt.Errorf("unexpected ownerOf output length %d", len(values))
+	}
+	owner, ok := values[0].(common.Address)
+	if !ok {
+		return common.Address{}, fmt.Errorf("unexpected ownerOf output type %T", values[0])
+	}
+	return owner, nil
+}
+
+type Transfer struct {
+	From    common.Address
+	To      common.Address
+	TokenId *big.Int
+}
+
+// ERC721 shares the same Transfer event signature as ERC20, but
+// the token ID is indexed, so it's only distinguishable by the number of topics.
+func IsTransfer(log types.Log) bool {
+	return len(log.Topics) == 4 && log.Topics[0] == erc721Abi.Events["Transfer"].ID
+}
+
+func ParseTransfer(log types.Log) (*Transfer, error) {
+	if !IsTransfer(log) {
+		return nil, fmt.Errorf("log is not an erc721 transfer")
+	}
+	return &Transfer{
+		From:    common.BytesToAddress(log.Topics[1].Bytes()),
+		To:      common.BytesToAddress(log.Topics[2].Bytes()),
+		TokenId: new(big.Int).SetBytes(log.Topics[3].Bytes()),
+	}, nil
+}
+
+func EventByID(topic common.Hash) (*abi.Event, error) {
+	return erc721Abi.EventByID(topic)
+}
@@ -0,0 +1,49 @@
+package gas_price_oracle
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed abi.json
+var abiJson string
+var oracleAbi abi.ABI
+
+// GasPriceOracle predeploy address on OP Stack chains
+var Address = common.HexToAddress("0x420000000000000000000000000000000000000F")
+
+func NewAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+func init() {
+	oracleAbi = NewAbi()
+}
+
+// Serialize a call to getL1Fee(bytes), where the data is the RLP encoded transaction.
+func SerializeGetL1Fee(txData []byte) ([]byte, error) {
+	return oracleAbi.Pack("getL1Fee", txData)
+}
+
+func ParseGetL1Fee(output []byte) (*big.Int, error) {
+	values, err := oracleAbi.Unpack("getL1Fee", output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected getL1Fee output length %d", len(values))
+	}
+	fee, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected getL1Fee output type %T", values[0])
+	}
+	return fee, nil
+}
@@ -0,0 +1,193 @@
+package lido
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed steth.json
+var stEthAbiJson string
+var stEthAbi abi.ABI
+
+//go:embed withdrawal_queue.json
+var withdrawalQueueAbiJson string
+var withdrawalQueueAbi abi.ABI
+
+func NewStEthAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(stEthAbiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func NewWithdrawalQueueAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(withdrawalQueueAbiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func init() {
+	stEthAbi = NewStEthAbi()
+	withdrawalQueueAbi = NewWithdrawalQueueAbi()
+}
+
+type Contracts struct {
+	StEth           common.Address
+	WithdrawalQueue common.Address
+}
+
+// Lido deployments by chain id
+var Deployments = map[int64]Contracts{
+	1: {
+		StEth:           common.HexToAddress("0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84"),
+		WithdrawalQueue: common.HexToAddress("0x889edC2eDab5f40e902b864aD4d7AdE8E412F9B1"),
+	},
+	17000: {
+		StEth:           common.HexToAddress("0x3F1c547b21f65e10480dE3ad8E19fAAC46C95034"),
+		WithdrawalQueue: common.HexToAddress("0xc7cc160b58F8Bb0baC94b80847E2CF2800565C50"),
+	},
+}
+
+// Limits on the stETH of a single withdrawal request
+var MinWithdrawalAmount = big.NewInt(100)
+var MaxWithdrawalAmount, _ = new(big.Int).SetString("1000000000000000000000", 10)
+
+func SerializeSubmit(referral common.Address) ([]byte, error) {
+	return stEthAbi.Pack("submit", referral)
+}
+
+func SerializeBalanceOf(account common.Address) ([]byte, error) {
+	return stEthAbi.Pack("balanceOf", account)
+}
+
+func ParseBalanceOf(output []byte) (*big.Int, error) {
+	return parseUint256(stEthAbi, "balanceOf", output)
+}
+
+func SerializeAllowance(owner common.Address, spender common.Address) ([]byte, error) {
+	return stEthAbi.Pack("allowance", owner, spender)
+}
+
+func ParseAllowance(output []byte) (*big.Int, error) {
+	return parseUint256(stEthAbi, "allowance", output)
+}
+
+func SerializeApprove(spender common.Address, amount *big.Int) ([]byte, error) {
+	return stEthAbi.Pack("approve", spender, amount)
+}
+
+// SplitWithdrawalAmounts splits an amount of stETH into withdrawal requests within the limits
+func SplitWithdrawalAmounts(amount *big.Int) ([]*big.Int, error) {
+	if amount.Cmp(MinWithdrawalAmount) < 0 {
+		return nil, fmt.Errorf("must withdraw at least %s wei of stETH", MinWithdrawalAmount)
+	}
+	amounts := []*big.Int{}
+	remaining := new(big.Int).Set(amount)
+	for remaining.Cmp(MaxWithdrawalAmount) > 0 {
+		amounts = append(amounts, new(big.Int).Set(MaxWithdrawalAmount))
+		remaining.Sub(remaining, MaxWithdrawalAmount)
+	}
+	if remaining.Cmp(MinWithdrawalAmount) < 0 {
+		// borrow from the previous request so the last one isn't too small
+		last := amounts[len(amounts)-1]
+		borrow := new(big.Int).Sub(MinWithdrawalAmount, remaining)
+		last.Sub(last, borrow)
+		remaining.Add(remaining, borrow)
+	}
+	return append(amounts, remaining), nil
+}
+
+func SerializeRequestWithdrawals(amounts []*big.Int, owner common.Address) ([]byte, error) {
+	return withdrawalQueueAbi.Pack("requestWithdrawals", amounts, owner)
+}
+
+func SerializeClaimWithdrawals(requestIds []*big.Int, hints []*big.Int) ([]byte, error) {
+	return withdrawalQueueAbi.Pack("claimWithdrawals", requestIds, hints)
+}
+
+func SerializeGetWithdrawalRequests(owner common.Address) ([]byte, error) {
+	return withdrawalQueueAbi.Pack("getWithdrawalRequests", owner)
+}
+
+func ParseGetWithdrawalRequests(output []byte) ([]*big.Int, error) {
+	return parseUint256Array(withdrawalQueueAbi, "getWithdrawalRequests", output)
+}
+
+type WithdrawalRequestStatus struct {
+	AmountOfStETH  *big.Int
+	AmountOfShares *big.Int
+	Owner          common.Address
+	Timestamp      *big.Int
+	IsFinalized    bool
+	IsClaimed      bool
+}
+
+func SerializeGetWithdrawalStatus(requestIds []*big.Int) ([]byte, error) {
+	return withdrawalQueueAbi.Pack("getWithdrawalStatus", requestIds)
+}
+
+func ParseGetWithdrawalStatus(output []byte) ([]WithdrawalRequestStatus, error) {
+	values, err := withdrawalQueueAbi.Unpack("getWithdrawalStatus", output)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []WithdrawalRequestStatus{}
+	if err := withdrawalQueueAbi.Methods["getWithdrawalStatus"].Outputs.Copy(&statuses, values); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func SerializeFindCheckpointHints(requestIds []*big.Int, firstIndex *big.Int, lastIndex *big.Int) ([]byte, error) {
+	return withdrawalQueueAbi.Pack("findCheckpointHints", requestIds, firstIndex, lastIndex)
+}
+
+func ParseFindCheckpointHints(output []byte) ([]*big.Int, error) {
+	return parseUint256Array(withdrawalQueueAbi, "findCheckpointHints", output)
+}
+
+func SerializeGetLastCheckpointIndex() ([]byte, error) {
+	return withdrawalQueueAbi.Pack("getLastCheckpointIndex")
+}
+
+func ParseGetLastCheckpointIndex(output []byte) (*big.Int, error) {
+	return parseUint256(withdrawalQueueAbi, "getLastCheckpointIndex", output)
+}
+
+func parseUint256(contractAbi abi.ABI, method string, output []byte) (*big.Int, error) {
+	values, err := contractAbi.Unpack(method, output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected %s output length %d", method, len(values))
+	}
+	value, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s output type %T", method, values[0])
+	}
+	return value, nil
+}
+
+func parseUint256Array(contractAbi abi.ABI, method string, output []byte) ([]*big.Int, error) {
+	values, err := contractAbi.Unpack(method, output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected %s output length %d", method, len(values))
+	}
+	value, ok := values[0].([]*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s output type %T", method, values[0])
+	}
+	return value, nil
+}
@@ -0,0 +1,52 @@
+package node_interface
+
+import (
+	_ "embed"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed abi.json
+var abiJson string
+var nodeInterfaceAbi abi.ABI
+
+// NodeInterface virtual contract address on Arbitrum chains.  It's only available via eth_call.
+var Address = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+
+func NewAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+func init() {
+	nodeInterfaceAbi = NewAbi()
+}
+
+func SerializeGasEstimateComponents(to common.Address, contractCreation bool, data []byte) ([]byte, error) {
+	if data == nil {
+		data = []byte{}
+	}
+	return nodeInterfaceAbi.Pack("gasEstimateComponents", to, contractCreation, data)
+}
+
+type GasEstimateComponents struct {
+	// total gas, including the L1 component
+	GasEstimate uint64
+	// the gas charged to pay for posting the tx to L1
+	GasEstimateForL1  uint64
+	BaseFee           *big.Int
+	L1BaseFeeEstimate *big.Int
+}
+
+func ParseGasEstimateComponents(output []byte) (*GasEstimateComponents, error) {
+	components := new(GasEstimateComponents)
+	if err := nodeInterfaceAbi.UnpackIntoInterface(components, "gasEstimateComponents", output); err != nil {
+		return nil, err
+	}
+	return components, nil
+}
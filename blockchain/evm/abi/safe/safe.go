@@ -0,0 +1,109 @@
+package safe
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed abi.json
+var abiJson string
+var safeAbi abi.ABI
+
+func NewAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+func init() {
+	safeAbi = NewAbi()
+}
+
+type ExecTransaction struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Signatures     []byte
+}
+
+// Serialize a call to execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)
+func SerializeExecTransaction(args *ExecTransaction) ([]byte, error) {
+	return safeAbi.Pack("execTransaction",
+		args.To,
+		args.Value,
+		args.Data,
+		args.Operation,
+		args.SafeTxGas,
+		args.BaseGas,
+		args.GasPrice,
+		args.GasToken,
+		args.RefundReceiver,
+		args.Signatures,
+	)
+}
+
+func SerializeNonce() ([]byte, error) {
+	return safeAbi.Pack("nonce")
+}
+
+func ParseNonce(output []byte) (*big.Int, error) {
+	return parseUint256("nonce", output)
+}
+
+func SerializeGetThreshold() ([]byte, error) {
+	return safeAbi.Pack("getThreshold")
+}
+
+func ParseGetThreshold(output []byte) (*big.Int, error) {
+	return parseUint256("getThreshold", output)
+}
+
+func SerializeGetOwners() ([]byte, error) {
+	return safeAbi.Pack("getOwners")
+}
+
+func ParseGetOwners(output []byte) ([]common.Address, error) {
+	values, err := safeAbi.Unpack("getOwners", output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected getOwners output length %d", len(values))
+	}
+	owners, ok := values[0].([]common.Address)
+	if !ok {
+		return nil, fmt.Errorf("unexpected getOwners output type %T", values[0])
+	}
+	return owners, nil
+}
+
+func parseUint256(method string, output []byte) (*big.Int, error) {
+	values, err := safeAbi.Unpack(method, output)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected %s output length %d", method, len(values))
+	}
+	value, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s output type %T", method, values[0])
+	}
+	return value, nil
+}
+
+func EventByID(topic common.Hash) (*abi.Event, error) {
+	return safeAbi.EventByID(topic)
+}
@@ -0,0 +1,140 @@
+package wormhole
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//go:embed core_bridge.json
+var coreBridgeAbiJson string
+var coreBridgeAbi abi.ABI
+
+//go:embed token_bridge.json
+var tokenBridgeAbiJson string
+var tokenBridgeAbi abi.ABI
+
+func NewCoreBridgeAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(coreBridgeAbiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func NewTokenBridgeAbi() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(tokenBridgeAbiJson))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func init() {
+	coreBridgeAbi = NewCoreBridgeAbi()
+	tokenBridgeAbi = NewTokenBridgeAbi()
+}
+
+type Contracts struct {
+	CoreBridge  common.Address
+	TokenBridge common.Address
+}
+
+// Wormhole deployments by chain id
+var Deployments = map[int64]Contracts{
+	// ethereum
+	1: {
+		CoreBridge:  common.HexToAddress("0x98f3c9e6E3fAce36bAAd05FE09d375Ef1464288B"),
+		TokenBridge: common.HexToAddress("0x3ee18B2214AFF97000D974cf647E7C347E8fa585"),
+	},
+	// bsc
+	56: {
+		CoreBridge:  common.HexToAddress("0x98f3c9e6E3fAce36bAAd05FE09d375Ef1464288B"),
+		TokenBridge: common.HexToAddress("0xB6F6D86a8f9879A9c87f643768d9efc38c1Da6E7"),
+	},
+	// polygon
+	137: {
+		CoreBridge:  common.HexToAddress("0x7A4B5a56256163F07b2C80A7cA55aBE66c4ec4d7"),
+		TokenBridge: common.HexToAddress("0x5a58505a96D1dbf8dF91cB21B54419FC36e93fdE"),
+	},
+	// avalanche
+	43114: {
+		CoreBridge:  common.HexToAddress("0x54a8e5f9c4CbA08F9943965859F6c34eAF03E26c"),
+		TokenBridge: common.HexToAddress("0x0e082F06FF657D94310cB8cE8B0D9a04541d8052"),
+	},
+	// arbitrum
+	42161: {
+		CoreBridge:  common.HexToAddress("0xa5f208e072434bC67592E4C49C1B991BA79BCA46"),
+		TokenBridge: common.HexToAddress("0x0b2402144Bb366A632D14B83F244D2e0e21bD39c"),
+	},
+	// optimism
+	10: {
+		CoreBridge:  common.HexToAddress("0xEe91C335eab126dF5fDB3797EA9d6aD93aeC9722"),
+		TokenBridge: common.HexToAddress("0x1D68124e65faFC907325e3EDbF8c4d84499DAa8b"),
+	},
+	// base
+	8453: {
+		CoreBridge:  common.HexToAddress("0xbebdb6C8ddC678FfA9f8748f85C815C556Dd8ac6"),
+		TokenBridge: common.HexToAddress("0x8d2de8d2f73F1F4cAB472AC9A881C9b123C79627"),
+	},
+	// sepolia
+	11155111: {
+		CoreBridge:  common.HexToAddress("0x4a8bc80Ed5a4067f1CCf107057b8270E0cC11A78"),
+		TokenBridge: common.HexToAddress("0xDB5492265f6038831E89f495670FF909aDe94bd9"),
+	},
+}
+
+func SerializeCompleteTransfer(vaa []byte) ([]byte, error) {
+	return tokenBridgeAbi.Pack("completeTransfer", vaa)
+}
+
+func SerializeIsTransferCompleted(digest [32]byte) ([]byte, error) {
+	return tokenBridgeAbi.Pack("isTransferCompleted", digest)
+}
+
+func ParseIsTransferCompleted(output []byte) (bool, error) {
+	values, err := tokenBridgeAbi.Unpack("isTransferCompleted", output)
+	if err != nil {
+		return false, err
+	}
+	if len(values) != 1 {
+		return false, fmt.Errorf("unexpected isTransferCompleted output length %d", len(values))
+	}
+	completed, ok := values[0].(bool)
+	if !ok {
+		return false, fmt.Errorf("unexpected isTransferCompleted output type %T", values[0])
+	}
+	return completed, nil
+}
+
+func LogMessagePublishedTopic() common.Hash {
+	return coreBridgeAbi.Events["LogMessagePublished"].ID
+}
+
+// A message published to the core bridge
+type LogMessagePublished struct {
+	Sender           common.Address
+	Sequence         uint64
+	Nonce            uint32
+	Payload          []byte
+	ConsistencyLevel uint8
+}
+
+// ParseLogMessagePublished parses a LogMessagePublished log from a core bridge
+func ParseLogMessagePublished(log types.Log) (*LogMessagePublished, error) {
+	if len(log.Topics) != 2 || log.Topics[0] != LogMessagePublishedTopic() {
+		return nil, fmt.Errorf("not a LogMessagePublished log")
+	}
+	published := &LogMessagePublished{
+		Sender: common.BytesToAddress(log.Topics[1].Bytes()),
+	}
+	err := coreBridgeAbi.UnpackIntoInterface(published, "LogMessagePublished", log.Data)
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
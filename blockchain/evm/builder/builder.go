@@ -14,6 +14,7 @@ import (
 	"github.com/openweb3-io/crosschain/blockchain/evm/address"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/evm/validator"
 	"github.com/openweb3-io/crosschain/builder/validation"
 	xc "github.com/openweb3-io/crosschain/types"
 	"go.uber.org/zap"
@@ -84,6 +85,10 @@ func (txBuilder TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc.Tx
 		return txBuilder.NewNativeTransfer(args, input)
 
 	case *xc.TokenAssetConfig:
+		switch asset.Standard {
+		case xc.TokenStandardErc721, xc.TokenStandardErc1155:
+			return txBuilder.NewNftTransfer(args, input)
+		}
 		return txBuilder.NewTokenTransfer(args, input)
 
 	default:
@@ -212,14 +217,10 @@ func (txBuilder TxBuilder) Stake(stakeArgs xcbuilder.StakeArgs, input xc.StakeTx
 		if err != nil {
 			return nil, err
 		}
-		ownerBz := ownerAddr.Bytes()
-		withdrawCred := [32]byte{}
-		copy(withdrawCred[32-len(ownerBz):], ownerBz)
-		// set the credential type
-		withdrawCred[0] = 1
+		withdrawCred := validator.Eth1WithdrawalCredentials(ownerAddr)
 		credentials := make([][]byte, len(input.PublicKeys))
 		for i := range credentials {
-			credentials[i] = withdrawCred[:]
+			credentials[i] = withdrawCred
 		}
 		data, err := stake_batch_deposit.Serialize(txBuilder.Chain, input.PublicKeys, credentials, input.Signatures)
 		if err != nil {
@@ -231,6 +232,8 @@ func (txBuilder TxBuilder) Stake(stakeArgs xcbuilder.StakeArgs, input xc.StakeTx
 			return nil, fmt.Errorf("could not build tx for %T: %v", input, err)
 		}
 		return tx, nil
+	case *tx_input.LidoStakeInput:
+		return txBuilder.stakeLido(stakeArgs, input)
 	default:
 		return nil, fmt.Errorf("unsupported staking type %T", input)
 	}
@@ -259,11 +262,18 @@ func (txBuilder TxBuilder) Unstake(stakeArgs xcbuilder.StakeArgs, input xc.Unsta
 			return nil, fmt.Errorf("could not build tx for %T: %v", input, err)
 		}
 		return tx, nil
+	case *tx_input.LidoUnstakeInput:
+		return txBuilder.unstakeLido(stakeArgs, input)
 	default:
 		return nil, fmt.Errorf("unsupported unstaking type %T", input)
 	}
 }
 
 func (txBuilder TxBuilder) Withdraw(stakeArgs xcbuilder.StakeArgs, input xc.WithdrawTxInput) (xc.Tx, error) {
-	return nil, fmt.Errorf("ethereum stakes are claimed automatically")
+	switch input := input.(type) {
+	case *tx_input.LidoWithdrawInput:
+		return txBuilder.withdrawLido(stakeArgs, input)
+	default:
+		return nil, fmt.Errorf("ethereum stakes are claimed automatically")
+	}
 }
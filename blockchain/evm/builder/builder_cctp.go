@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/cctp"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// NewCctpBurn burns USDC with the token messenger, to be minted to the mint recipient on the destination domain.
+// The token messenger must have been approved to spend the USDC, see NewCctpApproval.
+func (txBuilder TxBuilder) NewCctpBurn(args *xcbuilder.TransferArgs, input *tx_input.CctpBurnInput) (xc.Tx, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil || asset.GetContract() == "" {
+		return nil, fmt.Errorf("the usdc token contract is required to burn")
+	}
+	burnToken, err := address.FromHex(xc.Address(asset.GetContract()))
+	if err != nil {
+		return nil, err
+	}
+	if input.MintRecipient == (common.Hash{}) {
+		return nil, fmt.Errorf("mint recipient is required")
+	}
+
+	var data []byte
+	if input.DestinationCaller == (common.Hash{}) {
+		data, err = cctp.SerializeDepositForBurn(args.GetAmount().Int(), input.DestinationDomain, input.MintRecipient, burnToken)
+	} else {
+		data, err = cctp.SerializeDepositForBurnWithCaller(args.GetAmount().Int(), input.DestinationDomain, input.MintRecipient, burnToken, input.DestinationCaller)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid input for %T: %v", input, err)
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, input.TokenMessenger, zero, data, &input.TxInput)
+}
+
+// NewCctpApproval creates the USDC approval the token messenger needs before it can burn
+func (txBuilder TxBuilder) NewCctpApproval(args *xcbuilder.TransferArgs, tokenMessenger xc.Address, input xc.TxInput) (xc.Tx, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil || asset.GetContract() == "" {
+		return nil, fmt.Errorf("the usdc token contract is required to approve")
+	}
+	spender, err := address.FromHex(tokenMessenger)
+	if err != nil {
+		return nil, err
+	}
+	data, err := cctp.SerializeApprove(spender, args.GetAmount().Int())
+	if err != nil {
+		return nil, err
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, xc.Address(asset.GetContract()), zero, data, input)
+}
+
+// NewCctpReceive receives an attested message with the message transmitter, minting the USDC
+func (txBuilder TxBuilder) NewCctpReceive(message []byte, attestation []byte, input *tx_input.CctpReceiveInput) (xc.Tx, error) {
+	if len(attestation) == 0 {
+		return nil, fmt.Errorf("the message must be attested before it can be received")
+	}
+	data, err := cctp.SerializeReceiveMessage(message, attestation)
+	if err != nil {
+		return nil, err
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, input.MessageTransmitter, zero, data, &input.TxInput)
+}
@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/lido"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Submit ether to stETH, which mints stETH to the sender
+func (txBuilder TxBuilder) stakeLido(stakeArgs xcbuilder.StakeArgs, input *tx_input.LidoStakeInput) (xc.Tx, error) {
+	if owner, ok := stakeArgs.GetStakeOwner(); ok && !strings.EqualFold(string(owner), string(stakeArgs.GetFrom())) {
+		return nil, fmt.Errorf("lido mints stETH to the sender, a different stake owner is not supported")
+	}
+	data, err := lido.SerializeSubmit(common.Address{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid input for %T: %v", input, err)
+	}
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, input.StEth, stakeArgs.GetAmount(), data, &input.TxInput)
+}
+
+// Request withdrawal of stETH, split into as many requests as the withdrawal queue limits need.  The withdrawal
+// queue must have been approved to spend the stETH, see ApproveLidoWithdrawals.
+func (txBuilder TxBuilder) unstakeLido(stakeArgs xcbuilder.StakeArgs, input *tx_input.LidoUnstakeInput) (xc.Tx, error) {
+	owner, ok := stakeArgs.GetStakeOwner()
+	if !ok {
+		owner = stakeArgs.GetFrom()
+	}
+	ownerAddr, err := address.FromHex(owner)
+	if err != nil {
+		return nil, err
+	}
+	amounts, err := lido.SplitWithdrawalAmounts(stakeArgs.GetAmount().Int())
+	if err != nil {
+		return nil, err
+	}
+	data, err := lido.SerializeRequestWithdrawals(amounts, ownerAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input for %T: %v", input, err)
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, input.WithdrawalQueue, zero, data, &input.TxInput)
+}
+
+// Claim ether for finalized withdrawal requests
+func (txBuilder TxBuilder) withdrawLido(stakeArgs xcbuilder.StakeArgs, input *tx_input.LidoWithdrawInput) (xc.Tx, error) {
+	if len(input.RequestIds) == 0 {
+		return nil, fmt.Errorf("no finalized lido withdrawal requests to claim")
+	}
+	if len(input.RequestIds) != len(input.Hints) {
+		return nil, fmt.Errorf("received %d withdrawal requests but %d hints", len(input.RequestIds), len(input.Hints))
+	}
+	requestIds := make([]*big.Int, len(input.RequestIds))
+	hints := make([]*big.Int, len(input.Hints))
+	for i := range requestIds {
+		requestIds[i] = input.RequestIds[i].Int()
+		hints[i] = input.Hints[i].Int()
+	}
+	data, err := lido.SerializeClaimWithdrawals(requestIds, hints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input for %T: %v", input, err)
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, input.WithdrawalQueue, zero, data, &input.TxInput)
+}
+
+// ApproveLidoWithdrawals creates the stETH approval the withdrawal queue needs before stETH can be unstaked
+func (txBuilder TxBuilder) ApproveLidoWithdrawals(stakeArgs xcbuilder.StakeArgs, stEth xc.Address, withdrawalQueue xc.Address, input xc.TxInput) (xc.Tx, error) {
+	queueAddr, err := address.FromHex(withdrawalQueue)
+	if err != nil {
+		return nil, err
+	}
+	data, err := lido.SerializeApprove(queueAddr, stakeArgs.GetAmount().Int())
+	if err != nil {
+		return nil, err
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, stEth, zero, data, input)
+}
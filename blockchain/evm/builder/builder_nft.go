@@ -0,0 +1,64 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc1155"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc721"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// NewNftTransfer creates a new safeTransferFrom call for an ERC721 or ERC1155 token
+func (txBuilder TxBuilder) NewNftTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil {
+		return nil, errors.New("asset needed")
+	}
+	standard := xc.TokenStandardErc721
+	if token, ok := asset.(*xc.TokenAssetConfig); ok && token.Standard != "" {
+		standard = token.Standard
+	}
+	tokenId, ok := args.GetTokenId()
+	if !ok {
+		return nil, errors.New("token id needed for non-fungible transfer")
+	}
+
+	payload, err := BuildNftPayload(standard, args.GetFrom(), args.GetTo(), tokenId, args.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	contract := asset.GetContract()
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, xc.Address(contract), zero, payload, input)
+}
+
+func BuildNftPayload(standard xc.TokenStandard, from xc.Address, to xc.Address, tokenId xc.BigInt, amount xc.BigInt) ([]byte, error) {
+	fromAddress, err := address.FromHex(from)
+	if err != nil {
+		return nil, err
+	}
+	toAddress, err := address.FromHex(to)
+	if err != nil {
+		return nil, err
+	}
+
+	switch standard {
+	case xc.TokenStandardErc721:
+		// erc721 tokens are unique, so only an amount of 1 makes sense
+		one := xc.NewBigIntFromUint64(1)
+		if !amount.IsZero() && amount.Cmp(&one) != 0 {
+			return nil, fmt.Errorf("erc721 transfer amount must be 1, received %s", amount.String())
+		}
+		return erc721.SerializeSafeTransferFrom(fromAddress, toAddress, tokenId.Int())
+	case xc.TokenStandardErc1155:
+		if amount.IsZero() {
+			return nil, errors.New("erc1155 transfer amount must be greater than 0")
+		}
+		return erc1155.SerializeSafeTransferFrom(fromAddress, toAddress, tokenId.Int(), amount.Int(), nil)
+	default:
+		return nil, fmt.Errorf("unsupported non-fungible token standard '%s'", standard)
+	}
+}
@@ -0,0 +1,91 @@
+package builder
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// NewSafeTransfer creates a Safe tx proposal that transfers the native asset, a token or an NFT out of the Safe
+func (txBuilder TxBuilder) NewSafeTransfer(args *xcbuilder.TransferArgs, input *tx_input.SafeTxInput) (*tx.SafeTx, error) {
+	if !strings.EqualFold(string(args.GetFrom()), string(input.Safe)) {
+		return nil, fmt.Errorf("transfer must be from the safe %s, not %s", input.Safe, args.GetFrom())
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	asset, _ := args.GetAsset()
+	if asset == nil || asset.GetContract() == "" {
+		return txBuilder.NewSafeTx(args.GetTo(), args.GetAmount(), []byte{}, input)
+	}
+
+	var payload []byte
+	var err error
+	token, ok := asset.(*xc.TokenAssetConfig)
+	if ok && (token.Standard == xc.TokenStandardErc721 || token.Standard == xc.TokenStandardErc1155) {
+		tokenId, ok := args.GetTokenId()
+		if !ok {
+			return nil, fmt.Errorf("token id needed for non-fungible transfer")
+		}
+		payload, err = BuildNftPayload(token.Standard, input.Safe, args.GetTo(), tokenId, args.GetAmount())
+	} else {
+		payload, err = BuildERC20Payload(args.GetTo(), args.GetAmount())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return txBuilder.NewSafeTx(xc.Address(asset.GetContract()), zero, payload, input)
+}
+
+// NewSafeTx creates a Safe tx proposal for an arbitrary call made by the Safe
+func (txBuilder TxBuilder) NewSafeTx(to xc.Address, value xc.BigInt, data []byte, input *tx_input.SafeTxInput) (*tx.SafeTx, error) {
+	safeAddress, err := address.FromHex(input.Safe)
+	if err != nil {
+		return nil, fmt.Errorf("bad safe address '%v': %v", input.Safe, err)
+	}
+	toAddress, err := address.FromHex(to)
+	if err != nil {
+		return nil, err
+	}
+	if input.Threshold == 0 {
+		return nil, fmt.Errorf("safe threshold is not set")
+	}
+	owners := make([]common.Address, len(input.Owners))
+	for i, owner := range input.Owners {
+		owners[i], err = address.FromHex(owner)
+		if err != nil {
+			return nil, fmt.Errorf("bad safe owner '%v': %v", owner, err)
+		}
+	}
+	chainId := input.ChainId.Int()
+	if input.ChainId.Uint64() == 0 {
+		chainId = new(big.Int).SetInt64(txBuilder.Chain.ChainID)
+	}
+
+	return &tx.SafeTx{
+		Safe:      safeAddress,
+		ChainId:   chainId,
+		To:        toAddress,
+		Value:     value.Int(),
+		Data:      data,
+		Operation: tx.SafeOperationCall,
+		Nonce:     new(big.Int).SetUint64(input.Nonce),
+		Owners:    owners,
+		Threshold: input.Threshold,
+	}, nil
+}
+
+// NewSafeExecTransaction creates the execTransaction call for a signed Safe tx, sent and paid for by a relayer
+func (txBuilder TxBuilder) NewSafeExecTransaction(safeTx *tx.SafeTx, input xc.TxInput) (xc.Tx, error) {
+	data, err := safeTx.ExecTransactionData()
+	if err != nil {
+		return nil, err
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, xc.Address(safeTx.Safe.String()), zero, data, input)
+}
@@ -2,11 +2,17 @@ package builder_test
 
 import (
 	"encoding/hex"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/cctp"
 	"github.com/openweb3-io/crosschain/blockchain/evm/abi/exit_request"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/lido"
 	"github.com/openweb3-io/crosschain/blockchain/evm/abi/stake_batch_deposit"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/wormhole"
 	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
@@ -104,3 +110,255 @@ func TestUnstakingTx(t *testing.T) {
 
 	require.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(data))
 }
+
+func TestNftTransfer(t *testing.T) {
+	b, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to := xc_types.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	contract := xc_types.ContractAddress("0x495f947276749Ce646f68AC8c248420045cb7b5e")
+	tokenId := xc_types.NewBigIntFromUint64(1234)
+
+	vectors := []struct {
+		standard xc_types.TokenStandard
+		amount   uint64
+		selector string
+		err      string
+	}{
+		// safeTransferFrom(address,address,uint256)
+		{xc_types.TokenStandardErc721, 1, "42842e0e", ""},
+		{xc_types.TokenStandardErc721, 2, "", "erc721 transfer amount must be 1"},
+		// safeTransferFrom(address,address,uint256,uint256,bytes)
+		{xc_types.TokenStandardErc1155, 5, "f242432a", ""},
+		{xc_types.TokenStandardErc1155, 0, "", "erc1155 transfer amount must be greater than 0"},
+	}
+	for _, v := range vectors {
+		asset := &xc_types.TokenAssetConfig{Contract: contract, Standard: v.standard}
+		args, err := xcbuilder.NewTransferArgs(from, to, xc_types.NewBigIntFromUint64(v.amount), xcbuilder.WithAsset(asset), xcbuilder.WithTokenId(tokenId))
+		require.NoError(t, err)
+
+		trans, err := b.NewTransfer(args, tx_input.NewTxInput())
+		if v.err != "" {
+			require.ErrorContains(t, err, v.err)
+			continue
+		}
+		require.NoError(t, err)
+		ethTx := trans.(*tx.Tx).EthTx
+		require.EqualValues(t, 0, ethTx.Value().Uint64())
+		require.Equal(t, string(contract), ethTx.To().String())
+		require.Equal(t, v.selector, hex.EncodeToString(ethTx.Data()[:4]))
+		// token id is the third argument
+		require.EqualValues(t, 1234, new(big.Int).SetBytes(ethTx.Data()[4+64:4+96]).Uint64())
+	}
+
+	// token id is required
+	asset := &xc_types.TokenAssetConfig{Contract: contract, Standard: xc_types.TokenStandardErc721}
+	args, _ := xcbuilder.NewTransferArgs(from, to, xc_types.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset))
+	_, err := b.NewTransfer(args, tx_input.NewTxInput())
+	require.ErrorContains(t, err, "token id needed")
+}
+
+func TestSafeTransfer(t *testing.T) {
+	b, _ := builder.NewTxBuilder(&xc_types.ChainConfig{ChainID: 1})
+	safe := xc_types.Address("0x5aFE3855358E112B5647B952709E6165e1c1eEEe")
+	to := xc_types.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	contract := xc_types.ContractAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	key, _ := crypto.GenerateKey()
+	owner := xc_types.Address(crypto.PubkeyToAddress(key.PublicKey).String())
+	input := tx_input.NewSafeTxInput()
+	input.Safe = safe
+	input.Nonce = 3
+	input.Owners = []xc_types.Address{owner}
+	input.Threshold = 1
+
+	// native transfer
+	args, _ := xcbuilder.NewTransferArgs(safe, to, xc_types.NewBigIntFromUint64(100))
+	safeTx, err := b.NewSafeTransfer(args, input)
+	require.NoError(t, err)
+	require.Equal(t, string(to), safeTx.To.String())
+	require.EqualValues(t, 100, safeTx.Value.Uint64())
+	require.Empty(t, safeTx.Data)
+	require.EqualValues(t, 3, safeTx.Nonce.Uint64())
+	require.EqualValues(t, 1, safeTx.ChainId.Uint64())
+
+	// token transfer
+	asset := &xc_types.TokenAssetConfig{Contract: contract, Decimals: 6}
+	args, _ = xcbuilder.NewTransferArgs(safe, to, xc_types.NewBigIntFromUint64(100), xcbuilder.WithAsset(asset))
+	safeTx, err = b.NewSafeTransfer(args, input)
+	require.NoError(t, err)
+	require.Equal(t, string(contract), safeTx.To.String())
+	require.EqualValues(t, 0, safeTx.Value.Uint64())
+	expected, _ := builder.BuildERC20Payload(to, xc_types.NewBigIntFromUint64(100))
+	require.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(safeTx.Data))
+
+	// must be sent from the safe
+	args, _ = xcbuilder.NewTransferArgs(to, to, xc_types.NewBigIntFromUint64(100))
+	_, err = b.NewSafeTransfer(args, input)
+	require.ErrorContains(t, err, "must be from the safe")
+
+	// can't execute until signed
+	_, err = b.NewSafeExecTransaction(safeTx, tx_input.NewTxInput())
+	require.ErrorContains(t, err, "0 of 1 required signatures")
+
+	sighashes, _ := safeTx.Sighashes()
+	sig, _ := crypto.Sign(sighashes[0], key)
+	require.NoError(t, safeTx.AddSignatures(sig))
+
+	relayerInput := tx_input.NewTxInput()
+	relayerInput.Nonce = 10
+	relayerInput.GasLimit = 150_000
+	trans, err := b.NewSafeExecTransaction(safeTx, relayerInput)
+	require.NoError(t, err)
+	ethTx := trans.(*tx.Tx).EthTx
+	require.Equal(t, string(safe), ethTx.To().String())
+	require.EqualValues(t, 0, ethTx.Value().Uint64())
+	require.EqualValues(t, 10, ethTx.Nonce())
+	execData, _ := safeTx.ExecTransactionData()
+	require.Equal(t, execData, ethTx.Data())
+}
+
+func TestLidoStaking(t *testing.T) {
+	require := require.New(t)
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("0x273b437645Ba723299d07B1BdFFcf508bE64771f")
+	contracts := lido.Deployments[1]
+	stEth := xc_types.Address(contracts.StEth.String())
+	queue := xc_types.Address(contracts.WithdrawalQueue.String())
+	queueAbi := lido.NewWithdrawalQueueAbi()
+
+	// liquid staking isn't in 32 ether increments
+	human, _ := xc_types.NewAmountHumanReadableFromStr("1.5")
+	_, err := xcbuilder.NewStakeArgs(xc_types.ETH, from, human.ToBlockchain(18))
+	require.ErrorContains(err, "32 ether")
+	args, err := xcbuilder.NewStakeArgs(xc_types.ETH, from, human.ToBlockchain(18), xcbuilder.WithStakingProvider(xc_types.Lido))
+	require.NoError(err)
+
+	stakeInput := tx_input.NewLidoStakeInput()
+	stakeInput.StEth = stEth
+	trans, err := txBuilder.Stake(args, stakeInput)
+	require.NoError(err)
+	ethTx := trans.(*tx.Tx).EthTx
+	require.Equal(contracts.StEth, *ethTx.To())
+	require.Equal(human.ToBlockchain(18).String(), ethTx.Value().String())
+	expected, _ := lido.SerializeSubmit(common.Address{})
+	require.Equal(expected, ethTx.Data())
+
+	// over the per-request maximum is split into multiple requests
+	human, _ = xc_types.NewAmountHumanReadableFromStr("2500")
+	args, err = xcbuilder.NewStakeArgs(xc_types.ETH, from, human.ToBlockchain(18), xcbuilder.WithStakingProvider(xc_types.Lido))
+	require.NoError(err)
+	unstakeInput := tx_input.NewLidoUnstakeInput()
+	unstakeInput.WithdrawalQueue = queue
+	trans, err = txBuilder.Unstake(args, unstakeInput)
+	require.NoError(err)
+	ethTx = trans.(*tx.Tx).EthTx
+	require.Equal(contracts.WithdrawalQueue, *ethTx.To())
+	require.EqualValues(0, ethTx.Value().Uint64())
+	values, err := queueAbi.Methods["requestWithdrawals"].Inputs.Unpack(ethTx.Data()[4:])
+	require.NoError(err)
+	amounts := values[0].([]*big.Int)
+	require.Len(amounts, 3)
+	require.Equal("1000000000000000000000", amounts[0].String())
+	require.Equal("1000000000000000000000", amounts[1].String())
+	require.Equal("500000000000000000000", amounts[2].String())
+	require.Equal(common.HexToAddress(string(from)), values[1].(common.Address))
+
+	withdrawInput := tx_input.NewLidoWithdrawInput()
+	withdrawInput.WithdrawalQueue = queue
+	_, err = txBuilder.Withdraw(args, withdrawInput)
+	require.ErrorContains(err, "no finalized lido withdrawal requests")
+	withdrawInput.RequestIds = []xc_types.BigInt{xc_types.NewBigIntFromUint64(10), xc_types.NewBigIntFromUint64(11)}
+	withdrawInput.Hints = []xc_types.BigInt{xc_types.NewBigIntFromUint64(3), xc_types.NewBigIntFromUint64(4)}
+	trans, err = txBuilder.Withdraw(args, withdrawInput)
+	require.NoError(err)
+	ethTx = trans.(*tx.Tx).EthTx
+	require.Equal(contracts.WithdrawalQueue, *ethTx.To())
+	expected, _ = lido.SerializeClaimWithdrawals([]*big.Int{big.NewInt(10), big.NewInt(11)}, []*big.Int{big.NewInt(3), big.NewInt(4)})
+	require.Equal(expected, ethTx.Data())
+}
+
+func TestSplitLidoWithdrawalAmounts(t *testing.T) {
+	require := require.New(t)
+	max := lido.MaxWithdrawalAmount
+	amounts, err := lido.SplitWithdrawalAmounts(new(big.Int).Add(max, big.NewInt(50)))
+	require.NoError(err)
+	require.Len(amounts, 2)
+	// the last request is topped up to the minimum
+	require.Equal(new(big.Int).Sub(max, big.NewInt(50)).String(), amounts[0].String())
+	require.Equal("100", amounts[1].String())
+
+	_, err = lido.SplitWithdrawalAmounts(big.NewInt(99))
+	require.Error(err)
+	// the limit itself isn't modified
+	require.Equal("1000000000000000000000", lido.MaxWithdrawalAmount.String())
+}
+
+func TestCctp(t *testing.T) {
+	require := require.New(t)
+	b, _ := builder.NewTxBuilder(&xc_types.ChainConfig{ChainID: 1})
+	from := xc_types.Address("0x273b437645Ba723299d07B1BdFFcf508bE64771f")
+	usdc := &xc_types.TokenAssetConfig{Contract: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Decimals: 6}
+	contracts := cctp.Deployments[1]
+	args, _ := xcbuilder.NewTransferArgs(from, "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F", xc_types.NewBigIntFromUint64(2_500_000), xcbuilder.WithAsset(usdc))
+
+	burnInput := tx_input.NewCctpBurnInput()
+	burnInput.TokenMessenger = xc_types.Address(contracts.TokenMessenger.String())
+	burnInput.DestinationDomain = 3
+	_, err := b.NewCctpBurn(args, burnInput)
+	require.ErrorContains(err, "mint recipient is required")
+	burnInput.MintRecipient = common.BytesToHash(common.HexToAddress("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F").Bytes())
+	trans, err := b.NewCctpBurn(args, burnInput)
+	require.NoError(err)
+	ethTx := trans.(*tx.Tx).EthTx
+	require.Equal(contracts.TokenMessenger, *ethTx.To())
+	require.EqualValues(0, ethTx.Value().Uint64())
+	// depositForBurn(uint256,uint32,bytes32,address)
+	require.Equal("6fd3504e", hex.EncodeToString(ethTx.Data()[:4]))
+	expected, _ := cctp.SerializeDepositForBurn(big.NewInt(2_500_000), 3, burnInput.MintRecipient, common.HexToAddress(string(usdc.Contract)))
+	require.Equal(expected, ethTx.Data())
+
+	// restricting the receiver uses depositForBurnWithCaller
+	burnInput.DestinationCaller = burnInput.MintRecipient
+	trans, err = b.NewCctpBurn(args, burnInput)
+	require.NoError(err)
+	expected, _ = cctp.SerializeDepositForBurnWithCaller(big.NewInt(2_500_000), 3, burnInput.MintRecipient, common.HexToAddress(string(usdc.Contract)), burnInput.DestinationCaller)
+	require.Equal(expected, trans.(*tx.Tx).EthTx.Data())
+
+	trans, err = b.NewCctpApproval(args, burnInput.TokenMessenger, tx_input.NewTxInput())
+	require.NoError(err)
+	ethTx = trans.(*tx.Tx).EthTx
+	require.Equal(common.HexToAddress(string(usdc.Contract)), *ethTx.To())
+	expected, _ = cctp.SerializeApprove(contracts.TokenMessenger, big.NewInt(2_500_000))
+	require.Equal(expected, ethTx.Data())
+
+	receiveInput := tx_input.NewCctpReceiveInput()
+	receiveInput.MessageTransmitter = xc_types.Address(contracts.MessageTransmitter.String())
+	_, err = b.NewCctpReceive([]byte{1, 2, 3}, nil, receiveInput)
+	require.ErrorContains(err, "must be attested")
+	trans, err = b.NewCctpReceive([]byte{1, 2, 3}, []byte{4, 5}, receiveInput)
+	require.NoError(err)
+	ethTx = trans.(*tx.Tx).EthTx
+	require.Equal(contracts.MessageTransmitter, *ethTx.To())
+	// receiveMessage(bytes,bytes)
+	require.Equal("57ecfd28", hex.EncodeToString(ethTx.Data()[:4]))
+}
+
+func TestWormholeRedeem(t *testing.T) {
+	require := require.New(t)
+	b, _ := builder.NewTxBuilder(&xc_types.ChainConfig{ChainID: 1})
+	contracts := wormhole.Deployments[1]
+
+	input := tx_input.NewWormholeRedeemInput()
+	input.TokenBridge = xc_types.Address(contracts.TokenBridge.String())
+	_, err := b.NewWormholeRedeem(nil, input)
+	require.ErrorContains(err, "must be signed")
+	trans, err := b.NewWormholeRedeem([]byte{1, 2, 3}, input)
+	require.NoError(err)
+	ethTx := trans.(*tx.Tx).EthTx
+	require.Equal(contracts.TokenBridge, *ethTx.To())
+	require.EqualValues(0, ethTx.Value().Uint64())
+	// completeTransfer(bytes)
+	require.Equal("c6878519", hex.EncodeToString(ethTx.Data()[:4]))
+	expected, _ := wormhole.SerializeCompleteTransfer([]byte{1, 2, 3})
+	require.Equal(expected, ethTx.Data())
+}
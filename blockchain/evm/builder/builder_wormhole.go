@@ -0,0 +1,22 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/wormhole"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// NewWormholeRedeem completes a token bridge transfer sent with BuildWormholeTransferTx, using the signed VAA
+func (txBuilder TxBuilder) NewWormholeRedeem(vaa []byte, input *tx_input.WormholeRedeemInput) (xc.Tx, error) {
+	if len(vaa) == 0 {
+		return nil, fmt.Errorf("the transfer must be signed before it can be redeemed")
+	}
+	data, err := wormhole.SerializeCompleteTransfer(vaa)
+	if err != nil {
+		return nil, err
+	}
+	zero := xc.NewBigIntFromUint64(0)
+	return txBuilder.gethTxBuilder.BuildTxWithPayload(txBuilder.Chain, input.TokenBridge, zero, data, &input.TxInput)
+}
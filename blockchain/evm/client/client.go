@@ -16,9 +16,11 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/cctp"
 	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc20"
 	"github.com/openweb3-io/crosschain/blockchain/evm/abi/exit_request"
 	"github.com/openweb3-io/crosschain/blockchain/evm/abi/stake_deposit"
+	wormhole_abi "github.com/openweb3-io/crosschain/blockchain/evm/abi/wormhole"
 	"github.com/openweb3-io/crosschain/blockchain/evm/address"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
 	xclient "github.com/openweb3-io/crosschain/client"
@@ -150,6 +152,10 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHashStr xc.TxHash
 	}
 
 	chainID := new(big.Int).SetInt64(nativeAsset.ChainID)
+	if nativeAsset.ChainID == 0 && trans.Protected() {
+		// use the chain id the tx was signed with
+		chainID = trans.ChainId()
+	}
 
 	// If the transaction is still pending, return an empty txInfo.
 	if pending {
@@ -247,11 +253,42 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHashStr xc.TxHash
 	result.ContractAddress = confirmedTx.ContractAddress()
 	result.Amount = confirmedTx.Amount()
 	result.Fee = confirmedTx.Fee(baseFee, gasUsed)
+	l1Fee, err := client.FetchL1Fee(ctx, txHash)
+	if err != nil {
+		zap.S().Warn("could not fetch L1 fee",
+			zap.String("tx_hash", string(txHashStr)),
+			zap.String("chain", string(nativeAsset.Chain)),
+			zap.Error(err),
+		)
+	} else if !l1Fee.Fee.IsZero() && l1Fee.GasUsedForL1 <= gasUsed {
+		// report the L1 fee separately, without double counting any L1 gas already in the gas used
+		l2Fee := confirmedTx.Fee(baseFee, gasUsed-l1Fee.GasUsedForL1)
+		result.Fee = l2Fee.Add(&l1Fee.Fee)
+		result.L1Fee = l1Fee.Fee
+	}
 	result.Sources = append(ethMovements.Sources, tokenMovements.Sources...)
 	result.Destinations = append(ethMovements.Destinations, tokenMovements.Destinations...)
 
 	// Look for stake/unstake events
 	for _, log := range receipt.Logs {
+		if len(log.Topics) > 0 && log.Topics[0] == cctp.MessageSentTopic() {
+			message, err := cctp.ParseMessageSent(*log)
+			if err != nil {
+				zap.S().Error("could not parse cctp message log", err)
+				continue
+			}
+			result.AddMessage(xc.CrossChainProtocolCctp, xc.Address(log.Address.String()), message)
+			continue
+		}
+		if len(log.Topics) > 0 && log.Topics[0] == wormhole_abi.LogMessagePublishedTopic() {
+			obs, err := wormholeObservation(log, chainID.Int64(), result.BlockTime)
+			if err != nil {
+				zap.S().Error("could not parse wormhole message log", err)
+				continue
+			}
+			result.AddMessage(xc.CrossChainProtocolWormhole, xc.Address(common.BytesToAddress(obs.EmitterAddress[12:]).String()), obs.Bytes())
+			continue
+		}
 		ev, _ := stake_deposit.EventByID(log.Topics[0])
 		if ev != nil {
 			// fmt.Println("found staking event")
@@ -363,7 +400,22 @@ func (client *Client) EstimateGasFee(ctx context.Context, _tx xc.Tx) (*xc.BigInt
 		return nil, err
 	}
 
+	// L2 chains additionally charge for posting the tx data to L1
+	l1Fee, err := client.EstimateL1Fee(ctx, xc.Address(from.Hex()), tx)
+	if err != nil {
+		// as when fetching the tx input, carry on without the L1 fee
+		zap.S().Warn("could not estimate L1 fee",
+			zap.String("from", from.Hex()),
+			zap.Error(err),
+		)
+		l1Fee = &L1FeeEstimate{Fee: xc.NewBigIntFromUint64(0)}
+	}
+	if l1Fee.GasForL1 < gasLimit {
+		gasLimit -= l1Fee.GasForL1
+	}
+
 	gasCost := new(big.Int).Mul(big.NewInt(int64(gasLimit)), gasPrice)
+	gasCost.Add(gasCost, l1Fee.Fee.Int())
 
 	retCost := xc.NewBigIntFromStr(gasCost.String())
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -63,6 +64,17 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+var _ error = &Error{}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+func IsNotFound(err error) bool {
+	beaconErr := &Error{}
+	return errors.As(err, &beaconErr) && beaconErr.Code == http.StatusNotFound
+}
+
 // Fetch validator using beacon API
 func (client *Client) FetchValidator(ctx context.Context, validator string) (*GetValidatorResponse, error) {
 	if !strings.HasPrefix(validator, "0x") {
@@ -157,9 +169,10 @@ func (cli *Client) Send(method string, path string, requestBody any, response an
 			return fmt.Errorf("failed to unmarshal error response: %v", err)
 		}
 		if errorResponse.Message != "" {
-			return fmt.Errorf("%s", errorResponse.Message)
+			errorResponse.Code = resp.StatusCode
+			return &errorResponse
 		}
 		logrus.WithField("body", string(body)).WithField("chain", cli.Chain.Chain).Warn("unknown beacon api error")
-		return fmt.Errorf("unknown beacon api error (%d)", resp.StatusCode)
+		return &Error{Code: resp.StatusCode, Message: fmt.Sprintf("unknown beacon api error (%d)", resp.StatusCode)}
 	}
 }
@@ -0,0 +1,549 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Epoch of exits and withdrawals that have not been scheduled
+const FarFutureEpoch = uint64(math.MaxUint64)
+
+type BeaconSpec struct {
+	SlotsPerEpoch  uint64
+	SecondsPerSlot uint64
+	GenesisTime    time.Time
+}
+
+func (spec *BeaconSpec) EpochTime(epoch uint64) time.Time {
+	return spec.GenesisTime.Add(time.Duration(epoch*spec.SlotsPerEpoch*spec.SecondsPerSlot) * time.Second)
+}
+
+type beaconSpecResponse struct {
+	Data struct {
+		SlotsPerEpoch  string `json:"SLOTS_PER_EPOCH"`
+		SecondsPerSlot string `json:"SECONDS_PER_SLOT"`
+	} `json:"data"`
+}
+
+type beaconGenesisResponse struct {
+	Data struct {
+		GenesisTime string `json:"genesis_time"`
+	} `json:"data"`
+}
+
+// Fetch the timing parameters of the beacon chain, which differ between networks
+func (client *Client) FetchBeaconSpec(ctx context.Context) (*BeaconSpec, error) {
+	var spec beaconSpecResponse
+	if err := client.Get("eth/v1/config/spec", &spec); err != nil {
+		return nil, fmt.Errorf("could not fetch beacon spec: %v", err)
+	}
+	var genesis beaconGenesisResponse
+	if err := client.Get("eth/v1/beacon/genesis", &genesis); err != nil {
+		return nil, fmt.Errorf("could not fetch beacon genesis: %v", err)
+	}
+	slotsPerEpoch, err := strconv.ParseUint(spec.Data.SlotsPerEpoch, 10, 64)
+	if err != nil || slotsPerEpoch == 0 {
+		return nil, fmt.Errorf("invalid SLOTS_PER_EPOCH '%s'", spec.Data.SlotsPerEpoch)
+	}
+	secondsPerSlot, err := strconv.ParseUint(spec.Data.SecondsPerSlot, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SECONDS_PER_SLOT '%s'", spec.Data.SecondsPerSlot)
+	}
+	genesisTime, err := strconv.ParseInt(genesis.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis_time '%s'", genesis.Data.GenesisTime)
+	}
+	return &BeaconSpec{
+		SlotsPerEpoch:  slotsPerEpoch,
+		SecondsPerSlot: secondsPerSlot,
+		GenesisTime:    time.Unix(genesisTime, 0),
+	}, nil
+}
+
+// Lifecycle of a validator.  Exit and withdrawable epochs are only set once an exit has been initiated.
+type ValidatorLifecycle struct {
+	Status            ValidatorStatus `json:"status"`
+	Slashed           bool            `json:"slashed"`
+	ActivationEpoch   *uint64         `json:"activation_epoch,omitempty"`
+	ExitEpoch         *uint64         `json:"exit_epoch,omitempty"`
+	WithdrawableEpoch *uint64         `json:"withdrawable_epoch,omitempty"`
+	// Estimated from the epochs
+	ActivationTime   *time.Time `json:"activation_time,omitempty"`
+	ExitTime         *time.Time `json:"exit_time,omitempty"`
+	WithdrawableTime *time.Time `json:"withdrawable_time,omitempty"`
+}
+
+func parseEpoch(epoch string) *uint64 {
+	value, err := strconv.ParseUint(epoch, 10, 64)
+	if err != nil || value == FarFutureEpoch {
+		return nil
+	}
+	return &value
+}
+
+func NewValidatorLifecycle(validator *Validator, spec *BeaconSpec) *ValidatorLifecycle {
+	lifecycle := &ValidatorLifecycle{
+		Status:            validator.Status,
+		Slashed:           validator.Validator.Slashed,
+		ActivationEpoch:   parseEpoch(validator.Validator.ActivationEpoch),
+		ExitEpoch:         parseEpoch(validator.Validator.ExitEpoch),
+		WithdrawableEpoch: parseEpoch(validator.Validator.WithdrawableEpoch),
+	}
+	toTime := func(epoch *uint64) *time.Time {
+		if epoch == nil {
+			return nil
+		}
+		t := spec.EpochTime(*epoch)
+		return &t
+	}
+	lifecycle.ActivationTime = toTime(lifecycle.ActivationEpoch)
+	lifecycle.ExitTime = toTime(lifecycle.ExitEpoch)
+	lifecycle.WithdrawableTime = toTime(lifecycle.WithdrawableEpoch)
+	return lifecycle
+}
+
+type ValidatorWithdrawal struct {
+	Index       uint64     `json:"index"`
+	Slot        uint64     `json:"slot"`
+	BlockNumber uint64     `json:"block_number"`
+	Address     xc.Address `json:"address"`
+	Amount      xc.BigInt  `json:"amount"`
+	// Full withdrawals sweep the whole balance of an exited validator, otherwise it's a partial
+	// withdrawal of the balance in excess of the effective balance.
+	Full bool `json:"full"`
+}
+
+// Execution layer rewards for a block proposed by the validator
+type ExecutionReward struct {
+	Slot         uint64     `json:"slot"`
+	BlockNumber  uint64     `json:"block_number"`
+	FeeRecipient xc.Address `json:"fee_recipient"`
+	// Priority fees paid to the fee recipient
+	PriorityFees xc.BigInt `json:"priority_fees"`
+	// Payment from the block builder in the last tx of the block, when the block was built with mev-boost
+	MevPayment   xc.BigInt  `json:"mev_payment"`
+	MevRecipient xc.Address `json:"mev_recipient,omitempty"`
+}
+
+// Rewards of a validator over a range of epochs.  Consensus amounts are in wei.  Penalties for being
+// slashed are reported separately from the other penalties.
+type ValidatorRewards struct {
+	Validator     string                 `json:"validator"`
+	Index         string                 `json:"index"`
+	Attestations  xc.BigInt              `json:"attestations"`
+	SyncCommittee xc.BigInt              `json:"sync_committee"`
+	Proposals     xc.BigInt              `json:"proposals"`
+	Penalties     xc.BigInt              `json:"penalties"`
+	Slashing      xc.BigInt              `json:"slashing"`
+	Withdrawals   []*ValidatorWithdrawal `json:"withdrawals"`
+	Execution     []*ExecutionReward     `json:"execution,omitempty"`
+	Lifecycle     *ValidatorLifecycle    `json:"lifecycle"`
+
+	// accumulated in gwei
+	attestations  int64
+	syncCommittee int64
+	proposals     int64
+	penalties     int64
+	slashing      int64
+	// net rewards applied in blocks (proposals and sync committee), for working out slashing penalties
+	applied   int64
+	withdrawn int64
+}
+
+type StakingRewardsArgs struct {
+	// Validator public keys or indices
+	Validators []string
+	// Inclusive range of epochs
+	FromEpoch uint64
+	ToEpoch   uint64
+	// Attribute execution layer fees and mev payments of proposed blocks, which needs the execution RPC
+	ExecutionRewards bool
+}
+
+type attestationRewardsResponse struct {
+	Data struct {
+		TotalRewards []struct {
+			ValidatorIndex string `json:"validator_index"`
+			Head           string `json:"head"`
+			Target         string `json:"target"`
+			Source         string `json:"source"`
+			InclusionDelay string `json:"inclusion_delay"`
+			Inactivity     string `json:"inactivity"`
+		} `json:"total_rewards"`
+	} `json:"data"`
+}
+
+type validatorBalancesResponse struct {
+	Data []struct {
+		Index   string `json:"index"`
+		Balance string `json:"balance"`
+	} `json:"data"`
+}
+
+type syncCommitteeRewardsResponse struct {
+	Data []struct {
+		ValidatorIndex string `json:"validator_index"`
+		Reward         string `json:"reward"`
+	} `json:"data"`
+}
+
+type syncCommitteeResponse struct {
+	Data struct {
+		Validators []string `json:"validators"`
+	} `json:"data"`
+}
+
+type blockRewardsResponse struct {
+	Data struct {
+		ProposerIndex string `json:"proposer_index"`
+		Total         string `json:"total"`
+	} `json:"data"`
+}
+
+type beaconBlockResponse struct {
+	Data struct {
+		Message struct {
+			Slot          string `json:"slot"`
+			ProposerIndex string `json:"proposer_index"`
+			Body          struct {
+				ExecutionPayload *struct {
+					BlockNumber  string `json:"block_number"`
+					FeeRecipient string `json:"fee_recipient"`
+					Withdrawals  []struct {
+						Index          string `json:"index"`
+						ValidatorIndex string `json:"validator_index"`
+						Address        string `json:"address"`
+						Amount         string `json:"amount"`
+					} `json:"withdrawals"`
+				} `json:"execution_payload"`
+			} `json:"body"`
+		} `json:"message"`
+	} `json:"data"`
+}
+
+func parseGwei(amount string) int64 {
+	value, _ := strconv.ParseInt(amount, 10, 64)
+	return value
+}
+
+func gweiToWei(gwei int64) xc.BigInt {
+	return xc.BigInt(*new(big.Int).Mul(big.NewInt(gwei), big.NewInt(1_000_000_000)))
+}
+
+// rewards are positive, penalties are negative
+func (rewards *ValidatorRewards) add(total *int64, gwei int64) {
+	if gwei < 0 {
+		rewards.penalties -= gwei
+	} else {
+		*total += gwei
+	}
+}
+
+// FetchStakingRewards reports the consensus rewards, penalties, slashing penalties and withdrawals of
+// validators over a range of epochs, using the standard beacon API.  Every slot in the range is fetched,
+// so keep the range small and track progress by epoch.
+func (client *Client) FetchStakingRewards(ctx context.Context, args *StakingRewardsArgs) ([]*ValidatorRewards, error) {
+	if args.ToEpoch < args.FromEpoch {
+		return nil, fmt.Errorf("invalid epoch range %d to %d", args.FromEpoch, args.ToEpoch)
+	}
+	spec, err := client.FetchBeaconSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byIndex := map[string]*ValidatorRewards{}
+	result := []*ValidatorRewards{}
+	indices := []string{}
+	for _, validator := range args.Validators {
+		info, err := client.FetchValidator(ctx, validator)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch validator %s: %v", validator, err)
+		}
+		rewards := &ValidatorRewards{
+			Validator:   info.Data.Validator.Pubkey,
+			Index:       info.Data.Index,
+			Withdrawals: []*ValidatorWithdrawal{},
+			Lifecycle:   NewValidatorLifecycle(&info.Data, spec),
+		}
+		byIndex[info.Data.Index] = rewards
+		result = append(result, rewards)
+		indices = append(indices, info.Data.Index)
+	}
+
+	for epoch := args.FromEpoch; epoch <= args.ToEpoch; epoch++ {
+		if err := client.addAttestationRewards(epoch, indices, byIndex); err != nil {
+			return nil, err
+		}
+		inSyncCommittee, err := client.inSyncCommittee(epoch*spec.SlotsPerEpoch, epoch, byIndex)
+		if err != nil {
+			return nil, err
+		}
+		for slot := epoch * spec.SlotsPerEpoch; slot < (epoch+1)*spec.SlotsPerEpoch; slot++ {
+			if err := client.addSlotRewards(ctx, args, spec, slot, inSyncCommittee, indices, byIndex); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := client.addSlashingPenalties(spec, args, result); err != nil {
+		return nil, err
+	}
+
+	for _, rewards := range result {
+		rewards.Slashing = gweiToWei(rewards.slashing)
+		rewards.Attestations = gweiToWei(rewards.attestations)
+		rewards.SyncCommittee = gweiToWei(rewards.syncCommittee)
+		rewards.Proposals = gweiToWei(rewards.proposals)
+		rewards.Penalties = gweiToWei(rewards.penalties)
+	}
+	return result, nil
+}
+
+// Attestation rewards and penalties of each validator in the epoch, in gwei
+func (client *Client) fetchAttestationRewards(epoch uint64, indices []string) (map[string][]int64, error) {
+	var res attestationRewardsResponse
+	if err := client.Post(fmt.Sprintf("eth/v1/beacon/rewards/attestations/%d", epoch), indices, &res); err != nil {
+		return nil, fmt.Errorf("could not fetch attestation rewards for epoch %d: %v", epoch, err)
+	}
+	components := map[string][]int64{}
+	for _, reward := range res.Data.TotalRewards {
+		for _, component := range []string{reward.Head, reward.Target, reward.Source, reward.InclusionDelay, reward.Inactivity} {
+			components[reward.ValidatorIndex] = append(components[reward.ValidatorIndex], parseGwei(component))
+		}
+	}
+	return components, nil
+}
+
+func (client *Client) addAttestationRewards(epoch uint64, indices []string, byIndex map[string]*ValidatorRewards) error {
+	components, err := client.fetchAttestationRewards(epoch, indices)
+	if err != nil {
+		return err
+	}
+	for index, gwei := range components {
+		rewards, ok := byIndex[index]
+		if !ok {
+			continue
+		}
+		for _, component := range gwei {
+			rewards.add(&rewards.attestations, component)
+		}
+	}
+	return nil
+}
+
+// Balance of each validator at the end of the slot, in gwei
+func (client *Client) fetchValidatorBalances(slot uint64, indices []string) (map[string]int64, error) {
+	var res validatorBalancesResponse
+	if err := client.Post(fmt.Sprintf("eth/v1/beacon/states/%d/validator_balances", slot), indices, &res); err != nil {
+		return nil, fmt.Errorf("could not fetch validator balances at slot %d: %v", slot, err)
+	}
+	balances := map[string]int64{}
+	for _, balance := range res.Data {
+		balances[balance.Index] = parseGwei(balance.Balance)
+	}
+	return balances, nil
+}
+
+// Slashing penalties aren't reported by the rewards API, so for slashed validators they're the part of the
+// balance change over the range that isn't explained by everything else.  Attestation rewards are applied
+// at the end of the following epoch, so the ones in the balance change are from two epochs earlier than
+// the range.  Deposits to a slashed validator would lower the penalty.
+func (client *Client) addSlashingPenalties(spec *BeaconSpec, args *StakingRewardsArgs, result []*ValidatorRewards) error {
+	slashed := []*ValidatorRewards{}
+	indices := []string{}
+	for _, rewards := range result {
+		if rewards.Lifecycle.Slashed {
+			slashed = append(slashed, rewards)
+			indices = append(indices, rewards.Index)
+		}
+	}
+	if len(slashed) == 0 {
+		return nil
+	}
+
+	// the states before the first and after the last epoch transition of the range
+	startSlot := args.FromEpoch * spec.SlotsPerEpoch
+	if startSlot > 0 {
+		startSlot--
+	}
+	endSlot := (args.ToEpoch+1)*spec.SlotsPerEpoch - 1
+	start, err := client.fetchValidatorBalances(startSlot, indices)
+	if err != nil {
+		return err
+	}
+	end, err := client.fetchValidatorBalances(endSlot, indices)
+	if err != nil {
+		return err
+	}
+
+	attestations := map[string]int64{}
+	for epoch := args.FromEpoch; epoch <= args.ToEpoch; epoch++ {
+		if epoch < 2 {
+			continue
+		}
+		components, err := client.fetchAttestationRewards(epoch-2, indices)
+		if err != nil {
+			return err
+		}
+		for index, gwei := range components {
+			for _, component := range gwei {
+				attestations[index] += component
+			}
+		}
+	}
+
+	for _, rewards := range slashed {
+		expected := start[rewards.Index] + attestations[rewards.Index] + rewards.applied - rewards.withdrawn
+		if penalty := expected - end[rewards.Index]; penalty > 0 {
+			rewards.slashing = penalty
+		}
+	}
+	return nil
+}
+
+func (client *Client) inSyncCommittee(slot uint64, epoch uint64, byIndex map[string]*ValidatorRewards) (bool, error) {
+	var res syncCommitteeResponse
+	if err := client.Get(fmt.Sprintf("eth/v1/beacon/states/%d/sync_committees?epoch=%d", slot, epoch), &res); err != nil {
+		// states exist for missed slots too, so a missing state has been pruned by the node (or is in the future)
+		return false, fmt.Errorf("could not fetch sync committee for epoch %d: %v", epoch, err)
+	}
+	for _, index := range res.Data.Validators {
+		if _, ok := byIndex[index]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (client *Client) addSlotRewards(ctx context.Context, args *StakingRewardsArgs, spec *BeaconSpec, slot uint64, inSyncCommittee bool, indices []string, byIndex map[string]*ValidatorRewards) error {
+	var block beaconBlockResponse
+	if err := client.Get(fmt.Sprintf("eth/v2/beacon/blocks/%d", slot), &block); err != nil {
+		if IsNotFound(err) {
+			// no block was proposed in the slot, so there's nothing to add
+			return nil
+		}
+		return fmt.Errorf("could not fetch block at slot %d: %v", slot, err)
+	}
+	message := &block.Data.Message
+	payload := message.Body.ExecutionPayload
+	var blockNumber uint64
+	if payload != nil {
+		blockNumber, _ = strconv.ParseUint(payload.BlockNumber, 10, 64)
+		for _, withdrawal := range payload.Withdrawals {
+			rewards, ok := byIndex[withdrawal.ValidatorIndex]
+			if !ok {
+				continue
+			}
+			index, _ := strconv.ParseUint(withdrawal.Index, 10, 64)
+			amount := parseGwei(withdrawal.Amount)
+			rewards.withdrawn += amount
+			withdrawableEpoch := rewards.Lifecycle.WithdrawableEpoch
+			rewards.Withdrawals = append(rewards.Withdrawals, &ValidatorWithdrawal{
+				Index:       index,
+				Slot:        slot,
+				BlockNumber: blockNumber,
+				Address:     xc.Address(withdrawal.Address),
+				Amount:      gweiToWei(amount),
+				Full:        withdrawableEpoch != nil && slot/spec.SlotsPerEpoch >= *withdrawableEpoch,
+			})
+		}
+	}
+
+	if proposer, ok := byIndex[message.ProposerIndex]; ok {
+		var res blockRewardsResponse
+		if err := client.Get(fmt.Sprintf("eth/v1/beacon/rewards/blocks/%d", slot), &res); err != nil {
+			return fmt.Errorf("could not fetch block rewards for slot %d: %v", slot, err)
+		}
+		total := parseGwei(res.Data.Total)
+		proposer.add(&proposer.proposals, total)
+		proposer.applied += total
+		if args.ExecutionRewards && payload != nil {
+			reward, err := client.FetchExecutionReward(ctx, blockNumber, xc.Address(payload.FeeRecipient))
+			if err != nil {
+				return err
+			}
+			reward.Slot = slot
+			proposer.Execution = append(proposer.Execution, reward)
+		}
+	}
+
+	if inSyncCommittee {
+		var res syncCommitteeRewardsResponse
+		if err := client.Post(fmt.Sprintf("eth/v1/beacon/rewards/sync_committee/%d", slot), indices, &res); err != nil {
+			return fmt.Errorf("could not fetch sync committee rewards for slot %d: %v", slot, err)
+		}
+		for _, reward := range res.Data {
+			if rewards, ok := byIndex[reward.ValidatorIndex]; ok {
+				gwei := parseGwei(reward.Reward)
+				rewards.add(&rewards.syncCommittee, gwei)
+				rewards.applied += gwei
+			}
+		}
+	}
+	return nil
+}
+
+type rewardBlock struct {
+	BaseFeePerGas *hexutil.Big `json:"baseFeePerGas"`
+	Transactions  []*scanTx    `json:"transactions"`
+}
+
+type rewardReceipt struct {
+	GasUsed           hexutil.Uint64 `json:"gasUsed"`
+	EffectiveGasPrice hexutil.Big    `json:"effectiveGasPrice"`
+}
+
+// FetchExecutionReward attributes the execution layer rewards of a block to its fee recipient
+func (client *Client) FetchExecutionReward(ctx context.Context, blockNumber uint64, feeRecipient xc.Address) (*ExecutionReward, error) {
+	var block *rewardBlock
+	err := client.EthClient.Client().CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(blockNumber), true)
+	if err == nil && block == nil {
+		err = fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch block %d: %v", blockNumber, err)
+	}
+	var receipts []*rewardReceipt
+	err = client.EthClient.Client().CallContext(ctx, &receipts, "eth_getBlockReceipts", hexutil.EncodeUint64(blockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch receipts of block %d: %v", blockNumber, err)
+	}
+	if len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("block %d has %d txs but %d receipts", blockNumber, len(block.Transactions), len(receipts))
+	}
+	baseFee := big.NewInt(0)
+	if block.BaseFeePerGas != nil {
+		baseFee = block.BaseFeePerGas.ToInt()
+	}
+	fees := big.NewInt(0)
+	for _, receipt := range receipts {
+		tip := new(big.Int).Sub(receipt.EffectiveGasPrice.ToInt(), baseFee)
+		if tip.Sign() > 0 {
+			fees.Add(fees, tip.Mul(tip, new(big.Int).SetUint64(uint64(receipt.GasUsed))))
+		}
+	}
+	reward := &ExecutionReward{
+		BlockNumber:  blockNumber,
+		FeeRecipient: feeRecipient,
+		PriorityFees: xc.BigInt(*fees),
+		MevPayment:   xc.NewBigIntFromUint64(0),
+	}
+	// mev-boost builders set themselves as fee recipient and pay the proposer in the last tx
+	if len(block.Transactions) > 0 {
+		last := block.Transactions[len(block.Transactions)-1]
+		builder := common.HexToAddress(string(feeRecipient))
+		if last.From == builder && last.To != nil && last.Value.ToInt().Sign() > 0 {
+			reward.MevPayment = xc.BigInt(*new(big.Int).Set(last.Value.ToInt()))
+			reward.MevRecipient = xc.Address(strings.ToLower(last.To.Hex()))
+		}
+	}
+	return reward, nil
+}
@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	cctp_abi "github.com/openweb3-io/crosschain/blockchain/evm/abi/cctp"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	"github.com/openweb3-io/crosschain/bridge/cctp"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+var _ cctp.Endpoint = &Client{}
+
+func (client *Client) fetchChainId(ctx context.Context) (int64, error) {
+	if client.Chain.ChainID != 0 {
+		return client.Chain.ChainID, nil
+	}
+	id, err := client.EthClient.ChainID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not lookup chain_id: %v", err)
+	}
+	return id.Int64(), nil
+}
+
+// Lookup the CCTP contracts and domain of this chain
+func (client *Client) FetchCctpContracts(ctx context.Context) (cctp_abi.Contracts, cctp.Domain, error) {
+	chainId, err := client.fetchChainId(ctx)
+	if err != nil {
+		return cctp_abi.Contracts{}, 0, err
+	}
+	contracts, ok := cctp_abi.Deployments[chainId]
+	if !ok {
+		return cctp_abi.Contracts{}, 0, fmt.Errorf("cctp is not deployed on chain id %d", chainId)
+	}
+	domain, ok := cctp.EvmDomains[chainId]
+	if !ok {
+		return cctp_abi.Contracts{}, 0, fmt.Errorf("no cctp domain for chain id %d", chainId)
+	}
+	return contracts, domain, nil
+}
+
+func (client *Client) callContract(ctx context.Context, contract common.Address, data []byte) ([]byte, error) {
+	return client.EthClient.CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: data,
+	}, nil)
+}
+
+// Fetch how much USDC the token messenger may burn on behalf of the owner
+func (client *Client) FetchCctpAllowance(ctx context.Context, token xc.ContractAddress, owner xc.Address) (*big.Int, error) {
+	contracts, _, err := client.FetchCctpContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokenAddr, err := address.FromHex(xc.Address(token))
+	if err != nil {
+		return nil, err
+	}
+	ownerAddr, err := address.FromHex(owner)
+	if err != nil {
+		return nil, err
+	}
+	data, err := cctp_abi.SerializeAllowance(ownerAddr, contracts.TokenMessenger)
+	if err != nil {
+		return nil, err
+	}
+	output, err := client.callContract(ctx, tokenAddr, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch usdc allowance: %v", err)
+	}
+	return cctp_abi.ParseAllowance(output)
+}
+
+// Fetch the input to burn USDC for the recipient on the destination domain.  The destination token
+// is only needed when minting to solana, where the recipient's token account is minted to.
+func (client *Client) FetchCctpBurnInput(ctx context.Context, args *xcbuilder.TransferArgs, destinationDomain cctp.Domain, destinationToken xc.ContractAddress) (*tx_input.CctpBurnInput, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil || asset.GetContract() == "" {
+		return nil, fmt.Errorf("the usdc token contract is required to burn")
+	}
+	contracts, domain, err := client.FetchCctpContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if domain == destinationDomain {
+		return nil, fmt.Errorf("cannot burn to the same domain %d", domain)
+	}
+	mintRecipient, err := cctp.MintRecipient(destinationDomain, args.GetTo(), destinationToken)
+	if err != nil {
+		return nil, err
+	}
+	allowance, err := client.FetchCctpAllowance(ctx, asset.GetContract(), args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(args.GetAmount().Int()) < 0 {
+		return nil, fmt.Errorf("the cctp token messenger is approved to spend %s but %s is needed, approve it first", allowance, args.GetAmount().String())
+	}
+
+	partialTxInput, err := client.FetchUnsimulatedInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	burnInput := tx_input.NewCctpBurnInput()
+	burnInput.TxInput = *partialTxInput
+	burnInput.TokenMessenger = xc.Address(contracts.TokenMessenger.String())
+	burnInput.DestinationDomain = uint32(destinationDomain)
+	burnInput.MintRecipient = mintRecipient
+
+	builder, err := builder.NewTxBuilder(client.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.NewCctpBurn(args, burnInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	burnInput.GasLimit, err = client.SimulateGasWithLimit(ctx, args.GetFrom(), exampleTx.(*tx.Tx), asset)
+	if err != nil {
+		return nil, err
+	}
+	return burnInput, nil
+}
+
+// Fetch the input to approve the token messenger to burn the USDC being transferred
+func (client *Client) FetchCctpApprovalInput(ctx context.Context, args *xcbuilder.TransferArgs) (*tx_input.TxInput, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil {
+		return nil, fmt.Errorf("the usdc token contract is required to approve")
+	}
+	contracts, _, err := client.FetchCctpContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txInput, err := client.FetchUnsimulatedInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	builder, err := builder.NewTxBuilder(client.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.NewCctpApproval(args, xc.Address(contracts.TokenMessenger.String()), txInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	txInput.GasLimit, err = client.SimulateGasWithLimit(ctx, args.GetFrom(), exampleTx.(*tx.Tx), asset)
+	if err != nil {
+		return nil, err
+	}
+	return txInput, nil
+}
+
+// Fetch the input to receive an attested message on this chain
+func (client *Client) FetchCctpReceiveInput(ctx context.Context, from xc.Address, message []byte, attestation []byte) (*tx_input.CctpReceiveInput, error) {
+	msg, err := cctp.ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	contracts, domain, err := client.FetchCctpContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if msg.DestinationDomain != domain {
+		return nil, fmt.Errorf("cctp message is for domain %d, not %d", msg.DestinationDomain, domain)
+	}
+	fromAddr, err := address.FromHex(from)
+	if err != nil {
+		return nil, err
+	}
+	if msg.DestinationCaller != ([32]byte{}) && common.BytesToAddress(msg.DestinationCaller[12:]) != fromAddr {
+		return nil, fmt.Errorf("cctp message may only be received by %s", common.BytesToAddress(msg.DestinationCaller[12:]))
+	}
+	received, err := client.IsCctpMessageReceived(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if received {
+		return nil, fmt.Errorf("cctp message from domain %d with nonce %d has already been received", msg.SourceDomain, msg.Nonce)
+	}
+
+	partialTxInput, err := client.FetchUnsimulatedInput(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	receiveInput := tx_input.NewCctpReceiveInput()
+	receiveInput.TxInput = *partialTxInput
+	receiveInput.MessageTransmitter = xc.Address(contracts.MessageTransmitter.String())
+
+	builder, err := builder.NewTxBuilder(client.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.NewCctpReceive(message, attestation, receiveInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	receiveInput.GasLimit, err = client.SimulateGasWithLimit(ctx, from, exampleTx.(*tx.Tx), client.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return receiveInput, nil
+}
+
+// Check if the message has been received on this chain
+func (client *Client) IsCctpMessageReceived(ctx context.Context, msg *cctp.Message) (bool, error) {
+	contracts, _, err := client.FetchCctpContracts(ctx)
+	if err != nil {
+		return false, err
+	}
+	data, err := cctp_abi.SerializeUsedNonces(msg.SourceAndNonce())
+	if err != nil {
+		return false, err
+	}
+	output, err := client.callContract(ctx, contracts.MessageTransmitter, data)
+	if err != nil {
+		return false, fmt.Errorf("could not fetch used cctp nonces: %v", err)
+	}
+	used, err := cctp_abi.ParseUsedNonces(output)
+	if err != nil {
+		return false, err
+	}
+	return used.Sign() != 0, nil
+}
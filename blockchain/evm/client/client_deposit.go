@@ -0,0 +1,86 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/evm/validator"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+)
+
+// Look up the beacon chain network the deposits are made on
+func (client *Client) FetchBeaconNetwork(ctx context.Context, input *tx_input.BatchDepositInput) (validator.Network, error) {
+	chainId := input.ChainId.Int().Int64()
+	if chainId == 0 {
+		chainId = client.Chain.ChainID
+	}
+	if chainId == 0 {
+		id, err := client.EthClient.ChainID(ctx)
+		if err != nil {
+			return validator.Network{}, fmt.Errorf("could not lookup chain_id: %v", err)
+		}
+		chainId = id.Int64()
+	}
+	return validator.NetworkForChainId(chainId)
+}
+
+// ValidateBatchDeposit checks validator deposit data before it's used to stake, as a mistake here means the
+// ether is lost.  Each signature must be valid for the 0x01 withdrawal credentials of the stake owner, which
+// is what Stake will deposit with, and no validator may have been deposited to already.  Providers that report
+// the withdrawal credentials can pass them, and they must also match; otherwise pass nil.
+func (client *Client) ValidateBatchDeposit(ctx context.Context, args xcbuilder.StakeArgs, input *tx_input.BatchDepositInput, providerCredentials [][]byte) error {
+	if len(input.PublicKeys) == 0 {
+		return fmt.Errorf("no validators to deposit to")
+	}
+	if len(input.PublicKeys) != len(input.Signatures) {
+		return fmt.Errorf("received %d validator public keys but %d signatures", len(input.PublicKeys), len(input.Signatures))
+	}
+	if providerCredentials != nil && len(providerCredentials) != len(input.PublicKeys) {
+		return fmt.Errorf("received %d validator public keys but %d withdrawal credentials", len(input.PublicKeys), len(providerCredentials))
+	}
+	owner, ok := args.GetStakeOwner()
+	if !ok {
+		owner = args.GetFrom()
+	}
+	ownerAddr, err := address.FromHex(owner)
+	if err != nil {
+		return err
+	}
+	creds := validator.Eth1WithdrawalCredentials(ownerAddr)
+	network, err := client.FetchBeaconNetwork(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for i, publicKey := range input.PublicKeys {
+		pubkeyHex := hex.EncodeToString(publicKey)
+		if seen[pubkeyHex] {
+			return fmt.Errorf("validator %s is deposited to more than once", pubkeyHex)
+		}
+		seen[pubkeyHex] = true
+
+		if providerCredentials != nil && !bytes.Equal(providerCredentials[i], creds) {
+			return fmt.Errorf("withdrawal credentials for validator %s are %x, expected %x for %s", pubkeyHex, providerCredentials[i], creds, owner)
+		}
+		if err := validator.VerifyDeposit(publicKey, creds, input.Signatures[i], validator.DepositAmountGwei, network); err != nil {
+			return err
+		}
+	}
+
+	// deposits to an existing validator would top up its balance rather than create a new validator
+	for _, publicKey := range input.PublicKeys {
+		existing, err := client.FetchValidator(ctx, hex.EncodeToString(publicKey))
+		if err == nil {
+			return fmt.Errorf("validator %x has already been deposited to (status %s)", publicKey, existing.Data.Status)
+		}
+		if !IsNotFound(err) {
+			return fmt.Errorf("could not confirm validator %x is new: %v", publicKey, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/gas_price_oracle"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/node_interface"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+type L1FeeEstimate struct {
+	// The fee paid for posting the tx data to L1
+	Fee xc.BigInt
+	// Arbitrum charges the L1 fee as extra gas, which is already included in the gas limit.
+	// This is the amount of that gas, so it's not double counted.
+	GasForL1 uint64
+}
+
+// Estimate the L1 data fee for a transaction on an L2 chain.  Returns zero for chains without one.
+func (client *Client) EstimateL1Fee(ctx context.Context, from xc.Address, trans *tx.Tx) (*L1FeeEstimate, error) {
+	estimate := &L1FeeEstimate{Fee: xc.NewBigIntFromUint64(0)}
+	switch client.Chain.GetL2FeeModel() {
+	case xc.L2FeeModelOpStack:
+		txData, err := trans.EthTx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data, err := gas_price_oracle.SerializeGetL1Fee(txData)
+		if err != nil {
+			return nil, err
+		}
+		output, err := client.EthClient.CallContract(ctx, ethereum.CallMsg{
+			To:   &gas_price_oracle.Address,
+			Data: data,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not call GasPriceOracle.getL1Fee: %v", err)
+		}
+		fee, err := gas_price_oracle.ParseGetL1Fee(output)
+		if err != nil {
+			return nil, err
+		}
+		estimate.Fee = xc.BigInt(*fee)
+	case xc.L2FeeModelArbitrum:
+		fromAddr, _ := address.FromHex(from)
+		to := common.Address{}
+		contractCreation := trans.EthTx.To() == nil
+		if !contractCreation {
+			to = *trans.EthTx.To()
+		}
+		data, err := node_interface.SerializeGasEstimateComponents(to, contractCreation, trans.EthTx.Data())
+		if err != nil {
+			return nil, err
+		}
+		output, err := client.EthClient.CallContract(ctx, ethereum.CallMsg{
+			From:  fromAddr,
+			To:    &node_interface.Address,
+			Value: trans.EthTx.Value(),
+			Data:  data,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not call NodeInterface.gasEstimateComponents: %v", err)
+		}
+		components, err := node_interface.ParseGasEstimateComponents(output)
+		if err != nil {
+			return nil, err
+		}
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(components.GasEstimateForL1), components.BaseFee)
+		estimate.Fee = xc.BigInt(*fee)
+		estimate.GasForL1 = components.GasEstimateForL1
+	}
+	return estimate, nil
+}
+
+// L2 specific fields that are not present on the go-ethereum receipt type
+type l2ReceiptFields struct {
+	// op-stack
+	L1Fee *hexutil.Big `json:"l1Fee"`
+	// arbitrum
+	GasUsedForL1      *hexutil.Big `json:"gasUsedForL1"`
+	EffectiveGasPrice *hexutil.Big `json:"effectiveGasPrice"`
+}
+
+type L1FeeReceipt struct {
+	// The fee paid for posting the tx data to L1
+	Fee xc.BigInt
+	// Arbitrum charges the L1 fee as extra gas, which is already included in the gas used.
+	GasUsedForL1 uint64
+}
+
+// Fetch the L1 data fee that was paid by a confirmed transaction on an L2 chain.  Returns zero for chains without one.
+func (client *Client) FetchL1Fee(ctx context.Context, txHash common.Hash) (*L1FeeReceipt, error) {
+	result := &L1FeeReceipt{Fee: xc.NewBigIntFromUint64(0)}
+	model := client.Chain.GetL2FeeModel()
+	if model != xc.L2FeeModelOpStack && model != xc.L2FeeModelArbitrum {
+		return result, nil
+	}
+
+	var fields l2ReceiptFields
+	err := client.EthClient.Client().CallContext(ctx, &fields, "eth_getTransactionReceipt", txHash)
+	if err != nil {
+		return result, fmt.Errorf("fetching receipt for tx %v : %v", txHash, err)
+	}
+	switch model {
+	case xc.L2FeeModelOpStack:
+		if fields.L1Fee != nil {
+			result.Fee = xc.BigInt(*fields.L1Fee.ToInt())
+		}
+	case xc.L2FeeModelArbitrum:
+		if fields.GasUsedForL1 != nil && fields.EffectiveGasPrice != nil {
+			result.GasUsedForL1 = fields.GasUsedForL1.ToInt().Uint64()
+			fee := new(big.Int).Mul(fields.GasUsedForL1.ToInt(), fields.EffectiveGasPrice.ToInt())
+			result.Fee = xc.BigInt(*fee)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc1155"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc721"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Fetch the current owner of an ERC721 token
+func (client *Client) FetchNftOwner(ctx context.Context, contract xc.ContractAddress, tokenId xc.BigInt) (xc.Address, error) {
+	contractAddr, err := address.FromHex(xc.Address(contract))
+	if err != nil {
+		return "", fmt.Errorf("bad contract address '%v': %v", contract, err)
+	}
+	data, err := erc721.SerializeOwnerOf(tokenId.Int())
+	if err != nil {
+		return "", err
+	}
+	output, err := client.EthClient.CallContract(ctx, ethereum.CallMsg{
+		To:   &contractAddr,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get owner of token %s: %v", tokenId.String(), err)
+	}
+	owner, err := erc721.ParseOwnerOf(output)
+	if err != nil {
+		return "", err
+	}
+	return xc.Address(owner.String()), nil
+}
+
+// Fetch the balance of a single non-fungible token ID held by an address.  For ERC721,
+// this is 1 if the address owns the token, and 0 otherwise.
+func (client *Client) FetchNftBalance(ctx context.Context, addr xc.Address, contract xc.ContractAddress, standard xc.TokenStandard, tokenId xc.BigInt) (*xc.BigInt, error) {
+	zero := xc.NewBigIntFromUint64(0)
+	switch standard {
+	case xc.TokenStandardErc721:
+		owner, err := client.FetchNftOwner(ctx, contract, tokenId)
+		if err != nil {
+			return &zero, err
+		}
+		if strings.EqualFold(string(owner), string(addr)) {
+			one := xc.NewBigIntFromUint64(1)
+			return &one, nil
+		}
+		return &zero, nil
+	case xc.TokenStandardErc1155:
+		contractAddr, err := address.FromHex(xc.Address(contract))
+		if err != nil {
+			return &zero, fmt.Errorf("bad contract address '%v': %v", contract, err)
+		}
+		ownerAddr, err := address.FromHex(addr)
+		if err != nil {
+			return &zero, fmt.Errorf("bad address '%v': %v", addr, err)
+		}
+		data, err := erc1155.SerializeBalanceOf(ownerAddr, tokenId.Int())
+		if err != nil {
+			return &zero, err
+		}
+		output, err := client.EthClient.CallContract(ctx, ethereum.CallMsg{
+			To:   &contractAddr,
+			Data: data,
+		}, nil)
+		if err != nil {
+			return &zero, fmt.Errorf("failed to get balance of token %s for '%v': %v", tokenId.String(), addr, err)
+		}
+		balance, err := erc1155.ParseBalanceOf(output)
+		if err != nil {
+			return &zero, err
+		}
+		return (*xc.BigInt)(balance), nil
+	default:
+		return &zero, fmt.Errorf("unsupported non-fungible token standard '%s'", standard)
+	}
+}
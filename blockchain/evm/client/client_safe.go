@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/safe"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+func (client *Client) callSafe(ctx context.Context, safeAddress xc.Address, data []byte) ([]byte, error) {
+	safeAddr, err := address.FromHex(safeAddress)
+	if err != nil {
+		return nil, fmt.Errorf("bad safe address '%v': %v", safeAddress, err)
+	}
+	return client.EthClient.CallContract(ctx, ethereum.CallMsg{
+		To:   &safeAddr,
+		Data: data,
+	}, nil)
+}
+
+// Fetch the nonce of the next tx to be executed by the safe
+func (client *Client) FetchSafeNonce(ctx context.Context, safeAddress xc.Address) (uint64, error) {
+	data, err := safe.SerializeNonce()
+	if err != nil {
+		return 0, err
+	}
+	output, err := client.callSafe(ctx, safeAddress, data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce of safe '%v': %v", safeAddress, err)
+	}
+	nonce, err := safe.ParseNonce(output)
+	if err != nil {
+		return 0, err
+	}
+	return nonce.Uint64(), nil
+}
+
+func (client *Client) FetchSafeOwners(ctx context.Context, safeAddress xc.Address) ([]xc.Address, error) {
+	data, err := safe.SerializeGetOwners()
+	if err != nil {
+		return nil, err
+	}
+	output, err := client.callSafe(ctx, safeAddress, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owners of safe '%v': %v", safeAddress, err)
+	}
+	owners, err := safe.ParseGetOwners(output)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]xc.Address, len(owners))
+	for i, owner := range owners {
+		result[i] = xc.Address(owner.String())
+	}
+	return result, nil
+}
+
+// Fetch the number of owner signatures required to execute a safe tx
+func (client *Client) FetchSafeThreshold(ctx context.Context, safeAddress xc.Address) (uint64, error) {
+	data, err := safe.SerializeGetThreshold()
+	if err != nil {
+		return 0, err
+	}
+	output, err := client.callSafe(ctx, safeAddress, data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get threshold of safe '%v': %v", safeAddress, err)
+	}
+	threshold, err := safe.ParseGetThreshold(output)
+	if err != nil {
+		return 0, err
+	}
+	return threshold.Uint64(), nil
+}
+
+// Fetch the input needed to propose a new tx to a safe
+func (client *Client) FetchSafeTxInput(ctx context.Context, safeAddress xc.Address) (*tx_input.SafeTxInput, error) {
+	result := tx_input.NewSafeTxInput()
+	result.Safe = safeAddress
+
+	chainId, err := client.EthClient.ChainID(ctx)
+	if err != nil {
+		return result, fmt.Errorf("could not lookup chain_id: %v", err)
+	}
+	result.ChainId = xc.BigInt(*chainId)
+
+	result.Nonce, err = client.FetchSafeNonce(ctx, safeAddress)
+	if err != nil {
+		return result, err
+	}
+	result.Owners, err = client.FetchSafeOwners(ctx, safeAddress)
+	if err != nil {
+		return result, err
+	}
+	result.Threshold, err = client.FetchSafeThreshold(ctx, safeAddress)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// Fetch the input for a relayer to execute a signed safe tx
+func (client *Client) FetchSafeExecInput(ctx context.Context, relayer xc.Address, safeTx *tx.SafeTx) (*tx_input.TxInput, error) {
+	txInput, err := client.FetchUnsimulatedInput(ctx, relayer)
+	if err != nil {
+		return txInput, err
+	}
+	txBuilder, err := builder.NewTxBuilder(client.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := txBuilder.NewSafeExecTransaction(safeTx, txInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	gasLimit, err := client.SimulateGasWithLimit(ctx, relayer, exampleTx.(*tx.Tx), client.Chain)
+	if err != nil {
+		return nil, err
+	}
+	txInput.GasLimit = gasLimit
+	return txInput, nil
+}
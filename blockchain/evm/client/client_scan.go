@@ -0,0 +1,389 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	xc "github.com/openweb3-io/crosschain/types"
+	"go.uber.org/zap"
+)
+
+// Number of recent block hashes kept in a scan checkpoint to detect reorgs.  If all of them were
+// reorganized, the scan restarts this many blocks before the oldest.
+var ScanReorgDepth = 64
+
+// How many times a scan is retried when the chain reorganizes while scanning
+var ScanReorgRetries = 3
+
+var ErrReorgDuringScan = errors.New("chain reorganized during scan")
+
+// keccak256("Transfer(address,address,uint256)")
+var TransferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+type ScanArgs struct {
+	// Addresses to report transfers to or from
+	Addresses []xc.Address
+	// Token contracts to report transfers of.  If empty, transfers of any ERC20 token are reported.
+	Tokens []xc.ContractAddress
+}
+
+// ScannedTransfer is a transfer of the native asset or an ERC20 token to or from a watched address
+type ScannedTransfer struct {
+	BlockNumber uint64     `json:"block_number"`
+	BlockHash   string     `json:"block_hash"`
+	TxHash      string     `json:"tx_hash"`
+	From        xc.Address `json:"from"`
+	To          xc.Address `json:"to"`
+	// Empty for the native asset
+	Contract xc.ContractAddress `json:"contract,omitempty"`
+	Amount   xc.BigInt          `json:"amount"`
+	// Log index for token transfers, or the index of the call in the tx trace for native transfers
+	Index uint `json:"index"`
+}
+
+type BlockRef struct {
+	Number uint64 `json:"number"`
+	Hash   string `json:"hash"`
+}
+
+// ScanCheckpoint records the last scanned blocks, so a later scan can resume and detect reorgs
+type ScanCheckpoint struct {
+	// First block to scan if no blocks have been scanned yet
+	Start uint64 `json:"start"`
+	// Most recently scanned blocks, oldest first
+	Blocks []BlockRef `json:"blocks"`
+}
+
+func NewScanCheckpoint(start uint64) *ScanCheckpoint {
+	return &ScanCheckpoint{Start: start}
+}
+
+func (checkpoint *ScanCheckpoint) Next() uint64 {
+	if len(checkpoint.Blocks) == 0 {
+		return checkpoint.Start
+	}
+	return checkpoint.Blocks[len(checkpoint.Blocks)-1].Number + 1
+}
+
+type ScanResult struct {
+	Transfers  []*ScannedTransfer
+	Checkpoint *ScanCheckpoint
+	// If set, blocks from this number on were reorganized since the last scan.  Any transfers
+	// previously reported in these blocks should be discarded, as they have been scanned again.
+	ReorgedFrom *uint64
+	// Blocks that could not be traced, so only their direct native transfers were found.  Internal
+	// native transfers in these blocks are missing.
+	Incomplete []uint64
+}
+
+type scanBlockRef struct {
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+}
+
+type scanTx struct {
+	Hash  common.Hash     `json:"hash"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value hexutil.Big     `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+type scanBlock struct {
+	scanBlockRef
+	Transactions []*scanTx `json:"transactions"`
+}
+
+func (client *Client) fetchScanBlockRef(ctx context.Context, number uint64) (*scanBlockRef, error) {
+	var block *scanBlockRef
+	err := client.EthClient.Client().CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	if err == nil && block == nil {
+		err = ethereum.NotFound
+	}
+	return block, err
+}
+
+func (client *Client) fetchScanBlock(ctx context.Context, number uint64) (*scanBlock, error) {
+	var block *scanBlock
+	err := client.EthClient.Client().CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), true)
+	if err == nil && block == nil {
+		err = ethereum.NotFound
+	}
+	return block, err
+}
+
+// Scan from a checkpoint up to and including `toBlock`.  Blocks in the checkpoint that are no longer
+// on the canonical chain are scanned again.
+func (client *Client) ScanFromCheckpoint(ctx context.Context, checkpoint *ScanCheckpoint, toBlock uint64, args *ScanArgs) (*ScanResult, error) {
+	result := &ScanResult{
+		Checkpoint: &ScanCheckpoint{
+			Start:  checkpoint.Start,
+			Blocks: append([]BlockRef{}, checkpoint.Blocks...),
+		},
+	}
+	for attempt := 0; ; attempt++ {
+		err := client.rewindCheckpoint(ctx, result)
+		if err != nil {
+			return nil, err
+		}
+		fromBlock := result.Checkpoint.Next()
+		if toBlock < fromBlock {
+			result.Transfers = []*ScannedTransfer{}
+			return result, nil
+		}
+		var parent *BlockRef
+		if len(result.Checkpoint.Blocks) > 0 {
+			parent = &result.Checkpoint.Blocks[len(result.Checkpoint.Blocks)-1]
+		}
+		scanned, err := client.scanBlockRange(ctx, fromBlock, toBlock, parent, args)
+		if errors.Is(err, ErrReorgDuringScan) && attempt < ScanReorgRetries {
+			// rewinding finds where the chain changed, if it was within the checkpoint
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Transfers = scanned.Transfers
+		result.Incomplete = scanned.Incomplete
+		result.Checkpoint.Blocks = append(result.Checkpoint.Blocks, scanned.Checkpoint.Blocks...)
+		if len(result.Checkpoint.Blocks) > ScanReorgDepth {
+			result.Checkpoint.Blocks = result.Checkpoint.Blocks[len(result.Checkpoint.Blocks)-ScanReorgDepth:]
+		}
+		return result, nil
+	}
+}
+
+// Walk back the checkpoint until its last block is still on the canonical chain.  If none are,
+// the scan restarts ScanReorgDepth blocks before the oldest.
+func (client *Client) rewindCheckpoint(ctx context.Context, result *ScanResult) error {
+	for len(result.Checkpoint.Blocks) > 0 {
+		last := result.Checkpoint.Blocks[len(result.Checkpoint.Blocks)-1]
+		block, err := client.fetchScanBlockRef(ctx, last.Number)
+		if err != nil && err != ethereum.NotFound {
+			return fmt.Errorf("could not fetch block %d: %v", last.Number, err)
+		}
+		if err == nil && strings.EqualFold(block.Hash.Hex(), last.Hash) {
+			return nil
+		}
+		reorgedFrom := last.Number
+		result.ReorgedFrom = &reorgedFrom
+		result.Checkpoint.Blocks = result.Checkpoint.Blocks[:len(result.Checkpoint.Blocks)-1]
+		if len(result.Checkpoint.Blocks) == 0 {
+			start := uint64(0)
+			if last.Number > uint64(ScanReorgDepth) {
+				start = last.Number - uint64(ScanReorgDepth)
+			}
+			zap.S().Warn("reorg is deeper than the scan checkpoint, scanning again from an earlier block",
+				zap.Uint64("reorged_block", last.Number),
+				zap.Uint64("start", start),
+			)
+			result.Checkpoint.Start = start
+			result.ReorgedFrom = &start
+		}
+	}
+	return nil
+}
+
+// Scan a block range (inclusive) for transfers to or from the watched addresses.  Token transfers
+// come from Transfer logs, and native transfers, including internal calls, from block traces.
+// The checkpoint of the result covers the scanned blocks.
+func (client *Client) ScanBlockRange(ctx context.Context, fromBlock uint64, toBlock uint64, args *ScanArgs) (*ScanResult, error) {
+	return client.scanBlockRange(ctx, fromBlock, toBlock, nil, args)
+}
+
+// Scans the range, checking that each block follows the one before, starting from the parent if set
+func (client *Client) scanBlockRange(ctx context.Context, fromBlock uint64, toBlock uint64, parent *BlockRef, args *ScanArgs) (*ScanResult, error) {
+	if toBlock < fromBlock {
+		return nil, fmt.Errorf("invalid block range %d to %d", fromBlock, toBlock)
+	}
+	watched := map[common.Address]bool{}
+	for _, addr := range args.Addresses {
+		watchedAddr, err := address.FromHex(addr)
+		if err != nil {
+			return nil, fmt.Errorf("bad address '%v': %v", addr, err)
+		}
+		watched[watchedAddr] = true
+	}
+
+	result := &ScanResult{
+		Transfers:  []*ScannedTransfer{},
+		Checkpoint: NewScanCheckpoint(fromBlock),
+	}
+	blockHashes := map[uint64]common.Hash{}
+	for number := fromBlock; number <= toBlock; number++ {
+		block, err := client.fetchScanBlock(ctx, number)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch block %d: %v", number, err)
+		}
+		if parent != nil && parent.Number+1 == number && !strings.EqualFold(block.ParentHash.Hex(), parent.Hash) {
+			return nil, ErrReorgDuringScan
+		}
+		blockHashes[number] = block.Hash
+		result.Checkpoint.Blocks = append(result.Checkpoint.Blocks, BlockRef{Number: number, Hash: block.Hash.Hex()})
+		parent = &result.Checkpoint.Blocks[len(result.Checkpoint.Blocks)-1]
+
+		native, complete, err := client.scanNativeTransfers(ctx, block, watched)
+		if err != nil {
+			return nil, err
+		}
+		result.Transfers = append(result.Transfers, native...)
+		if !complete {
+			result.Incomplete = append(result.Incomplete, number)
+		}
+	}
+
+	tokens, err := client.scanTokenTransfers(ctx, fromBlock, toBlock, args.Tokens, watched)
+	if err != nil {
+		return nil, err
+	}
+	for _, transfer := range tokens {
+		// the logs must come from the same blocks we scanned
+		if hash, ok := blockHashes[transfer.BlockNumber]; !ok || !strings.EqualFold(hash.Hex(), transfer.BlockHash) {
+			return nil, ErrReorgDuringScan
+		}
+	}
+	result.Transfers = append(result.Transfers, tokens...)
+	if len(result.Checkpoint.Blocks) > ScanReorgDepth {
+		result.Checkpoint.Blocks = result.Checkpoint.Blocks[len(result.Checkpoint.Blocks)-ScanReorgDepth:]
+	}
+	return result, nil
+}
+
+// Finds the native transfers of the block from a trace of the whole block.  If the block can't be traced,
+// only direct transfers are returned, and the block is reported as incomplete.
+func (client *Client) scanNativeTransfers(ctx context.Context, block *scanBlock, watched map[common.Address]bool) (transfers []*ScannedTransfer, complete bool, err error) {
+	transfers = []*ScannedTransfer{}
+	if len(block.Transactions) == 0 {
+		return transfers, true, nil
+	}
+	traces, err := client.TraceBlockByNumber(ctx, uint64(block.Number))
+	if err == nil && len(traces) != len(block.Transactions) {
+		err = fmt.Errorf("trace has %d txs, but the block has %d", len(traces), len(block.Transactions))
+	}
+	if err != nil {
+		// Not all RPC nodes support tracing
+		zap.S().Warn("could not trace block for internal transfers",
+			zap.Uint64("block", uint64(block.Number)),
+			zap.Error(err),
+		)
+		traces = nil
+	}
+
+	complete = true
+	for i, trans := range block.Transactions {
+		newTransfer := func(index uint, from xc.Address, to xc.Address, amount xc.BigInt) *ScannedTransfer {
+			return &ScannedTransfer{
+				BlockNumber: uint64(block.Number),
+				BlockHash:   block.Hash.Hex(),
+				TxHash:      trans.Hash.Hex(),
+				From:        from,
+				To:          to,
+				Amount:      amount,
+				Index:       index,
+			}
+		}
+		var trace *TraceBlockResult
+		if traces != nil {
+			trace = traces[i]
+			if trace.TxHash != (common.Hash{}) && trace.TxHash != trans.Hash {
+				return nil, false, fmt.Errorf("trace of block %d has tx %s where %s was expected", block.Number, trace.TxHash.Hex(), trans.Hash.Hex())
+			}
+		}
+		if trace == nil || trace.Result == nil {
+			// only contract calls can make internal transfers
+			if len(trans.Input) > 0 {
+				complete = false
+			}
+			if trans.To == nil || !watched[*trans.To] || trans.Value.ToInt().Sign() == 0 {
+				continue
+			}
+			receipt, err := client.EthClient.TransactionReceipt(ctx, trans.Hash)
+			if err != nil {
+				return nil, false, fmt.Errorf("could not fetch receipt for %s: %v", trans.Hash.Hex(), err)
+			}
+			if receipt.Status == 0 {
+				continue
+			}
+			transfers = append(transfers, newTransfer(0, xc.Address(trans.From.String()), xc.Address(trans.To.String()), xc.BigInt(*trans.Value.ToInt())))
+			continue
+		}
+
+		movements := client.traceMovements(trace.Result)
+		for j := range movements.Sources {
+			from := movements.Sources[j].Address
+			to := movements.Destinations[j].Address
+			fromAddr, _ := address.FromHex(from)
+			toAddr, _ := address.FromHex(to)
+			if watched[fromAddr] || watched[toAddr] {
+				transfers = append(transfers, newTransfer(uint(j), from, to, movements.Destinations[j].Amount))
+			}
+		}
+	}
+	return transfers, complete, nil
+}
+
+func (client *Client) scanTokenTransfers(ctx context.Context, fromBlock uint64, toBlock uint64, tokens []xc.ContractAddress, watched map[common.Address]bool) ([]*ScannedTransfer, error) {
+	contracts := []common.Address{}
+	for _, token := range tokens {
+		contract, err := address.FromHex(xc.Address(token))
+		if err != nil {
+			return nil, fmt.Errorf("bad token contract '%v': %v", token, err)
+		}
+		contracts = append(contracts, contract)
+	}
+	watchedTopics := []common.Hash{}
+	for addr := range watched {
+		watchedTopics = append(watchedTopics, common.BytesToHash(addr.Bytes()))
+	}
+	if len(watchedTopics) == 0 {
+		return nil, nil
+	}
+
+	transfers := []*ScannedTransfer{}
+	seen := map[string]bool{}
+	// query transfers from and then to the watched addresses
+	for _, topics := range [][][]common.Hash{
+		{{TransferTopic}, watchedTopics},
+		{{TransferTopic}, nil, watchedTopics},
+	} {
+		logs, err := client.EthClient.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: contracts,
+			Topics:    topics,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch transfer logs: %v", err)
+		}
+		for _, log := range logs {
+			// erc721 transfers share the topic, but index the token id
+			if log.Removed || len(log.Topics) != 3 {
+				continue
+			}
+			key := fmt.Sprintf("%s-%d", log.TxHash.Hex(), log.Index)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			transfers = append(transfers, &ScannedTransfer{
+				BlockNumber: log.BlockNumber,
+				BlockHash:   log.BlockHash.Hex(),
+				TxHash:      log.TxHash.Hex(),
+				From:        xc.Address(common.BytesToAddress(log.Topics[1].Bytes()).String()),
+				To:          xc.Address(common.BytesToAddress(log.Topics[2].Bytes()).String()),
+				Contract:    xc.ContractAddress(log.Address.String()),
+				Amount:      xc.BigInt(*new(big.Int).SetBytes(log.Data)),
+				Index:       log.Index,
+			})
+		}
+	}
+	return transfers, nil
+}
@@ -3,16 +3,27 @@ package client_test
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/openweb3-io/crosschain/blockchain/evm"
 	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
 	"github.com/openweb3-io/crosschain/blockchain/evm/client"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/evm/validator"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
 	"github.com/openweb3-io/crosschain/signer"
+	testtypes "github.com/openweb3-io/crosschain/testutil/types"
 	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -165,3 +176,655 @@ func (suite *ClientTestSuite) TestFetchBalance() {
 	fmt.Printf("[EVM] contract %v, address: %v, balance: %v\n", contractAddress, addr, balance)
 
 }
+
+func TestEstimateL1Fee(t *testing.T) {
+	from := xc_types.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to := xc_types.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	args, _ := xcbuilder.NewTransferArgs(from, to, xc_types.NewBigIntFromUint64(1))
+
+	vectors := []struct {
+		chain    xc_types.NativeAsset
+		resp     interface{}
+		fee      uint64
+		gasForL1 uint64
+	}{
+		{
+			chain: xc_types.OptETH,
+			// getL1Fee
+			resp: `"0x00000000000000000000000000000000000000000000000000000000000f4240"`,
+			fee:  1_000_000,
+		},
+		{
+			chain: xc_types.ArbETH,
+			// gasEstimateComponents: (gasEstimate=100000, gasEstimateForL1=2000, baseFee=10, l1BaseFeeEstimate=1)
+			resp: `"0x` +
+				`00000000000000000000000000000000000000000000000000000000000186a0` +
+				`00000000000000000000000000000000000000000000000000000000000007d0` +
+				`000000000000000000000000000000000000000000000000000000000000000a` +
+				`0000000000000000000000000000000000000000000000000000000000000001"`,
+			fee:      20_000,
+			gasForL1: 2000,
+		},
+		{
+			chain: xc_types.ETH,
+			resp:  `"0x"`,
+			fee:   0,
+		},
+	}
+	for _, v := range vectors {
+		server, close := testtypes.MockJSONRPC(t, v.resp)
+		defer close()
+		cli, err := client.NewClient(&xc_types.ChainConfig{Chain: v.chain, URL: server.URL, ChainID: 1})
+		require.NoError(t, err)
+
+		b, _ := builder.NewTxBuilder(cli.Chain)
+		trans, err := b.NewTransfer(args, tx_input.NewTxInput())
+		require.NoError(t, err)
+
+		estimate, err := cli.EstimateL1Fee(context.Background(), from, trans.(*tx.Tx))
+		require.NoError(t, err)
+		require.EqualValues(t, v.fee, estimate.Fee.Uint64())
+		require.EqualValues(t, v.gasForL1, estimate.GasForL1)
+	}
+}
+
+func TestBaseFeeWithHeadroom(t *testing.T) {
+	require.EqualValues(t, 100, client.BaseFeeWithHeadroom(xc_types.NewBigIntFromUint64(100), 0).Uint64())
+	require.EqualValues(t, 9, client.BaseFeeWithHeadroom(xc_types.NewBigIntFromUint64(8), 1).Uint64())
+	require.EqualValues(t, 81, client.BaseFeeWithHeadroom(xc_types.NewBigIntFromUint64(64), 2).Uint64())
+	// rounds up
+	require.EqualValues(t, 2, client.BaseFeeWithHeadroom(xc_types.NewBigIntFromUint64(1), 1).Uint64())
+}
+
+func TestLimitGasFees(t *testing.T) {
+	gwei := func(v uint64) xc_types.BigInt {
+		return builder.GweiToWei(v)
+	}
+	// fractional gwei settings are kept
+	chain := &xc_types.ChainConfig{Chain: xc_types.OptETH, ChainMinGasPrice: 0.01, ChainMaxGasPrice: 0.5}
+	require.EqualValues(t, 10_000_000, client.LimitGasTipCap(chain, xc_types.NewBigIntFromUint64(1)).Uint64())
+	require.EqualValues(t, 500_000_000, client.LimitGasTipCap(chain, gwei(1)).Uint64())
+
+	// a base fee spike doesn't take the fee cap past the max gas price
+	chain = &xc_types.ChainConfig{Chain: xc_types.ETH, ChainMaxGasPrice: 30}
+	tip, feeCap := client.LimitGasFees(chain, gwei(2), gwei(50))
+	require.Equal(t, gwei(2).String(), tip.String())
+	require.Equal(t, gwei(30).String(), feeCap.String())
+
+	// the tip floor can't go past the fee cap
+	chain = &xc_types.ChainConfig{Chain: xc_types.ETH, ChainMinGasPrice: 5, ChainMaxGasPrice: 3}
+	tip, feeCap = client.LimitGasFees(chain, gwei(1), gwei(10))
+	require.Equal(t, gwei(3).String(), feeCap.String())
+	require.Equal(t, gwei(3).String(), tip.String())
+
+	// without a max gas price, only the tip is limited
+	chain = &xc_types.ChainConfig{Chain: xc_types.ETH}
+	tip, feeCap = client.LimitGasFees(chain, gwei(2), gwei(50))
+	require.Equal(t, gwei(2).String(), tip.String())
+	require.Equal(t, gwei(52).String(), feeCap.String())
+}
+
+func TestFetchUnsimulatedInputFeeHistory(t *testing.T) {
+	from := xc_types.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	gwei := func(v uint64) xc_types.BigInt {
+		return builder.GweiToWei(v)
+	}
+	addFee := func(a, b xc_types.BigInt) string {
+		return new(big.Int).Add(a.Int(), b.Int()).String()
+	}
+	feeHistory := `{
+		"oldestBlock": "0x1",
+		"baseFeePerGas": ["0x2540be400", "0x2540be400", "0x2540be400", "0x2540be400"],
+		"gasUsedRatio": [0, 0.5, 0.5],
+		"reward": [
+			["0x0", "0x0", "0x0", "0x0"],
+			["0x3b9aca00", "0x77359400", "0xb2d05e00", "0x165a0bc00"],
+			["0x3b9aca00", "0x77359400", "0xee6b2800", "0x1dcd65000"]
+		]
+	}`
+	// every sampled tip is zero, as on quiet chains and L2s
+	quietFeeHistory := `{
+		"oldestBlock": "0x1",
+		"baseFeePerGas": ["0x2540be400", "0x2540be400", "0x2540be400"],
+		"gasUsedRatio": [0.1, 0.1],
+		"reward": [
+			["0x0", "0x0", "0x0", "0x0"],
+			["0x0", "0x0", "0x0", "0x0"]
+		]
+	}`
+
+	vectors := []struct {
+		name     string
+		resp     interface{}
+		chain    *xc_types.ChainConfig
+		tips     []xc_types.BigInt
+		feeCapOf func(tip xc_types.BigInt) xc_types.BigInt
+	}{
+		{
+			name: "fee history",
+			resp: []string{
+				// eth_getTransactionCount
+				`"0x6"`,
+				// eth_chainId
+				`"0x1"`,
+				// eth_feeHistory
+				feeHistory,
+				// txpool_contentFrom
+				`{"pending":{},"queued":{}}`,
+			},
+			chain: &xc_types.ChainConfig{Chain: xc_types.ETH},
+			// VeryAggressive is limited to the default max tip
+			tips: []xc_types.BigInt{gwei(1), gwei(2), gwei(4), gwei(builder.DefaultMaxTipCapGwei)},
+		},
+		{
+			name: "fee history with min and max gas price",
+			resp: []string{
+				`"0x6"`,
+				`"0x1"`,
+				feeHistory,
+				`{"pending":{},"queued":{}}`,
+			},
+			chain: &xc_types.ChainConfig{Chain: xc_types.ETH, ChainMinGasPrice: 2, ChainMaxGasPrice: 7},
+			tips:  []xc_types.BigInt{gwei(2), gwei(2), gwei(4), gwei(7)},
+			// the max gas price also caps the fee cap
+			feeCapOf: func(tip xc_types.BigInt) xc_types.BigInt { return gwei(7) },
+		},
+		{
+			name: "fee history without tips",
+			resp: []string{
+				`"0x6"`,
+				`"0x1"`,
+				quietFeeHistory,
+				`{"pending":{},"queued":{}}`,
+			},
+			chain: &xc_types.ChainConfig{Chain: xc_types.ETH},
+			tips:  []xc_types.BigInt{gwei(0), gwei(0), gwei(0), gwei(0)},
+		},
+		{
+			name: "fee history without tips uses the min gas price",
+			resp: []string{
+				`"0x6"`,
+				`"0x1"`,
+				quietFeeHistory,
+				`{"pending":{},"queued":{}}`,
+			},
+			chain: &xc_types.ChainConfig{Chain: xc_types.ETH, ChainMinGasPrice: 1},
+			tips:  []xc_types.BigInt{gwei(1), gwei(1), gwei(1), gwei(1)},
+		},
+		{
+			name: "fee history not supported",
+			resp: []string{
+				`"0x6"`,
+				`"0x1"`,
+				`{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"},"id":0}`,
+				// eth_getBlockByNumber
+				`{"baseFeePerGas":"0x2540be400","difficulty":"0x0","extraData":"0x","gasLimit":"0x1c9c380","gasUsed":"0x0","hash":"0x0000000000000000000000000000000000000000000000000000000000000001","logsBloom":"0x` + strings.Repeat("00", 256) + `","miner":"0x0000000000000000000000000000000000000000","mixHash":"0x0000000000000000000000000000000000000000000000000000000000000000","nonce":"0x0000000000000000","number":"0x1","parentHash":"0x0000000000000000000000000000000000000000000000000000000000000000","receiptsRoot":"0x0000000000000000000000000000000000000000000000000000000000000000","sha3Uncles":"0x0000000000000000000000000000000000000000000000000000000000000000","size":"0x0","stateRoot":"0x0000000000000000000000000000000000000000000000000000000000000000","timestamp":"0x1","transactionsRoot":"0x0000000000000000000000000000000000000000000000000000000000000000"}`,
+				// eth_maxPriorityFeePerGas
+				`"0x77359400"`,
+				`{"pending":{},"queued":{}}`,
+			},
+			chain: &xc_types.ChainConfig{Chain: xc_types.ETH},
+			tips:  []xc_types.BigInt{gwei(2)},
+		},
+	}
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			server, close := testtypes.MockJSONRPC(t, v.resp)
+			defer close()
+			v.chain.URL = server.URL
+			cli, err := client.NewClient(v.chain)
+			require.NoError(t, err)
+
+			input, err := cli.FetchUnsimulatedInput(context.Background(), from)
+			require.NoError(t, err)
+			require.EqualValues(t, 6, input.Nonce)
+
+			maxBaseFee := client.BaseFeeWithHeadroom(gwei(10), client.BaseFeeHeadroomBlocks)
+			if len(v.tips) == 1 {
+				require.Nil(t, input.FeeEstimates)
+				require.Equal(t, v.tips[0].String(), input.GasTipCap.String())
+				require.Equal(t, addFee(maxBaseFee, v.tips[0]), input.GasFeeCap.String())
+				return
+			}
+
+			// market is the default
+			require.Equal(t, v.tips[1].String(), input.GasTipCap.String())
+			priorities := []xc_types.GasFeePriority{xc_types.Low, xc_types.Market, xc_types.Aggressive, xc_types.VeryAggressive}
+			for i, priority := range priorities {
+				err = input.SetGasFeePriority(priority)
+				require.NoError(t, err)
+				require.Equal(t, v.tips[i].String(), input.GasTipCap.String(), priority)
+				feeCap := addFee(maxBaseFee, v.tips[i])
+				if v.feeCapOf != nil {
+					feeCap = v.feeCapOf(v.tips[i]).String()
+				}
+				require.Equal(t, feeCap, input.GasFeeCap.String(), priority)
+			}
+		})
+	}
+}
+
+func TestFetchSafeTxInput(t *testing.T) {
+	safe := xc_types.Address("0x5aFE3855358E112B5647B952709E6165e1c1eEEe")
+	server, close := testtypes.MockJSONRPC(t, []string{
+		// eth_chainId
+		`"0x1"`,
+		// nonce()
+		`"0x0000000000000000000000000000000000000000000000000000000000000005"`,
+		// getOwners()
+		`"0x00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000724435cc1b2821362c2cd425f2744bd7347bf2990000000000000000000000003ad57b83b2e3dc5648f32e98e386935a9b10bb9f"`,
+		// getThreshold()
+		`"0x0000000000000000000000000000000000000000000000000000000000000002"`,
+	})
+	defer close()
+	cli, err := client.NewClient(&xc_types.ChainConfig{Chain: xc_types.ETH, URL: server.URL})
+	require.NoError(t, err)
+
+	input, err := cli.FetchSafeTxInput(context.Background(), safe)
+	require.NoError(t, err)
+	require.Equal(t, safe, input.Safe)
+	require.EqualValues(t, 1, input.ChainId.Uint64())
+	require.EqualValues(t, 5, input.Nonce)
+	require.Equal(t, []xc_types.Address{
+		"0x724435CC1B2821362c2CD425F2744Bd7347bf299",
+		"0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F",
+	}, input.Owners)
+	require.EqualValues(t, 2, input.Threshold)
+}
+
+func TestScanFromCheckpoint(t *testing.T) {
+	watched := xc_types.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	other := "0x3ad57b83b2e3dc5648f32e98e386935a9b10bb9f"
+	contract := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	hash99 := "0x0990000000000000000000000000000000000000000000000000000000000000"
+	hash100 := "0x1000000000000000000000000000000000000000000000000000000000000000"
+	hash101 := "0x1010000000000000000000000000000000000000000000000000000000000000"
+	tx1 := "0xaa00000000000000000000000000000000000000000000000000000000000000"
+	tx2 := "0xbb00000000000000000000000000000000000000000000000000000000000000"
+	watchedTopic := "0x000000000000000000000000724435cc1b2821362c2cd425f2744bd7347bf299"
+	otherTopic := "0x0000000000000000000000003ad57b83b2e3dc5648f32e98e386935a9b10bb9f"
+	scanArgs := &client.ScanArgs{Addresses: []xc_types.Address{watched}}
+
+	emptyBlock := func(number uint64, hash string, parentHash string) string {
+		return fmt.Sprintf(`{"number":"0x%x","hash":"%s","parentHash":"%s","transactions":[]}`, number, hash, parentHash)
+	}
+	block101 := `{"number":"0x65","hash":"` + hash101 + `","parentHash":"` + hash100 + `","transactions":[
+		{"hash":"` + tx1 + `","from":"` + other + `","to":"` + string(watched) + `","value":"0x1","input":"0x"},
+		{"hash":"` + tx2 + `","from":"` + other + `","to":"` + contract + `","value":"0x0","input":"0x1234"}
+	]}`
+	// debug_traceBlockByNumber: a direct transfer, and an internal transfer next to a reverted one
+	trace101 := `[
+		{"txHash":"` + tx1 + `","result":{"type":"CALL","from":"` + other + `","to":"` + string(watched) + `","value":"0x1"}},
+		{"txHash":"` + tx2 + `","result":{"type":"CALL","from":"` + other + `","to":"` + contract + `","value":"0x0","calls":[
+			{"type":"CALL","from":"` + contract + `","to":"` + string(watched) + `","value":"0x5"},
+			{"type":"CALL","from":"` + contract + `","to":"` + string(watched) + `","value":"0x7","error":"execution reverted"}
+		]}}
+	]`
+	transferLog := func(blockHash string) string {
+		return `[{"address":"` + contract + `","topics":["` + client.TransferTopic.Hex() + `","` + otherTopic + `","` + watchedTopic + `"],` +
+			`"data":"0x0000000000000000000000000000000000000000000000000000000000000064","blockNumber":"0x65","transactionHash":"` + tx2 + `",` +
+			`"transactionIndex":"0x1","blockHash":"` + blockHash + `","logIndex":"0x3","removed":false}]`
+	}
+
+	server, close := testtypes.MockJSONRPC(t, []string{
+		// eth_getBlockByNumber, to check the checkpoint
+		`{"number":"0x64","hash":"` + hash100 + `"}`,
+		// eth_getBlockByNumber
+		block101,
+		trace101,
+		// eth_getLogs from the watched addresses
+		`[]`,
+		// eth_getLogs to the watched addresses
+		transferLog(hash101),
+	})
+	defer close()
+	cli, err := client.NewClient(&xc_types.ChainConfig{Chain: xc_types.ETH, URL: server.URL})
+	require.NoError(t, err)
+
+	checkpoint := &client.ScanCheckpoint{Blocks: []client.BlockRef{{Number: 100, Hash: hash100}}}
+	result, err := cli.ScanFromCheckpoint(context.Background(), checkpoint, 101, scanArgs)
+	require.NoError(t, err)
+	require.Nil(t, result.ReorgedFrom)
+	require.Empty(t, result.Incomplete)
+	require.Len(t, result.Transfers, 3)
+
+	require.Equal(t, tx1, result.Transfers[0].TxHash)
+	require.EqualValues(t, 1, result.Transfers[0].Amount.Uint64())
+	require.Equal(t, "", string(result.Transfers[0].Contract))
+
+	require.Equal(t, tx2, result.Transfers[1].TxHash)
+	require.EqualValues(t, 5, result.Transfers[1].Amount.Uint64())
+	require.Equal(t, watched, result.Transfers[1].To)
+
+	require.Equal(t, tx2, result.Transfers[2].TxHash)
+	require.EqualValues(t, 100, result.Transfers[2].Amount.Uint64())
+	require.Equal(t, contract, strings.ToLower(string(result.Transfers[2].Contract)))
+	require.EqualValues(t, 3, result.Transfers[2].Index)
+	require.EqualValues(t, 101, result.Transfers[2].BlockNumber)
+
+	require.EqualValues(t, 102, result.Checkpoint.Next())
+	require.Equal(t, []client.BlockRef{{Number: 100, Hash: hash100}, {Number: 101, Hash: hash101}}, result.Checkpoint.Blocks)
+	// the original checkpoint is not modified
+	require.Len(t, checkpoint.Blocks, 1)
+
+	// block 101 was replaced
+	hash101b := "0x1011000000000000000000000000000000000000000000000000000000000000"
+	server.Counter = 0
+	server.Response = []string{
+		`{"number":"0x65","hash":"` + hash101b + `"}`,
+		`{"number":"0x64","hash":"` + hash100 + `"}`,
+		emptyBlock(101, hash101b, hash100),
+		`[]`,
+		`[]`,
+	}
+	reorged, err := cli.ScanFromCheckpoint(context.Background(), result.Checkpoint, 101, scanArgs)
+	require.NoError(t, err)
+	require.NotNil(t, reorged.ReorgedFrom)
+	require.EqualValues(t, 101, *reorged.ReorgedFrom)
+	require.Empty(t, reorged.Transfers)
+	require.Equal(t, []client.BlockRef{{Number: 100, Hash: hash100}, {Number: 101, Hash: hash101b}}, reorged.Checkpoint.Blocks)
+
+	// block 100 is replaced while scanning, so the next block doesn't follow the checkpoint
+	hash100b := "0x1001000000000000000000000000000000000000000000000000000000000000"
+	server.Counter = 0
+	server.Response = []string{
+		`{"number":"0x64","hash":"` + hash100 + `"}`,
+		emptyBlock(101, hash101b, hash100b),
+		// the scan is retried, finding block 100 was replaced
+		`{"number":"0x64","hash":"` + hash100b + `"}`,
+		`{"number":"0x63","hash":"` + hash99 + `"}`,
+		emptyBlock(100, hash100b, hash99),
+		emptyBlock(101, hash101b, hash100b),
+		`[]`,
+		`[]`,
+	}
+	checkpoint = &client.ScanCheckpoint{Blocks: []client.BlockRef{{Number: 99, Hash: hash99}, {Number: 100, Hash: hash100}}}
+	reorged, err = cli.ScanFromCheckpoint(context.Background(), checkpoint, 101, scanArgs)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, *reorged.ReorgedFrom)
+	require.Equal(t, []client.BlockRef{{Number: 99, Hash: hash99}, {Number: 100, Hash: hash100b}, {Number: 101, Hash: hash101b}}, reorged.Checkpoint.Blocks)
+
+	// the logs come from a different block than was scanned
+	server.Counter = 0
+	server.Response = []string{
+		block101,
+		trace101,
+		`[]`,
+		transferLog(hash101b),
+	}
+	_, err = cli.ScanBlockRange(context.Background(), 101, 101, scanArgs)
+	require.ErrorIs(t, err, client.ErrReorgDuringScan)
+
+	// without tracing, only direct transfers are found
+	server.Counter = 0
+	server.Response = []string{
+		block101,
+		`{"code":-32601,"message":"the method debug_traceBlockByNumber does not exist"}`,
+		// eth_getTransactionReceipt
+		`{"transactionHash":"` + tx1 + `","status":"0x1","cumulativeGasUsed":"0x5208","gasUsed":"0x5208","logs":[],"logsBloom":"0x` + strings.Repeat("0", 512) + `"}`,
+		`[]`,
+		`[]`,
+	}
+	scanned, err := cli.ScanBlockRange(context.Background(), 101, 101, scanArgs)
+	require.NoError(t, err)
+	require.Len(t, scanned.Transfers, 1)
+	require.Equal(t, tx1, scanned.Transfers[0].TxHash)
+	require.Equal(t, []uint64{101}, scanned.Incomplete)
+}
+
+func TestScanFromCheckpointDeepReorg(t *testing.T) {
+	client.ScanReorgDepth = 2
+	defer func() { client.ScanReorgDepth = 64 }()
+	hash := func(number uint64, fork int) string {
+		return fmt.Sprintf("0x%064x", number<<8|uint64(fork))
+	}
+	emptyBlock := func(number uint64, fork int) string {
+		return fmt.Sprintf(`{"number":"0x%x","hash":"%s","parentHash":"%s","transactions":[]}`, number, hash(number, fork), hash(number-1, fork))
+	}
+
+	// the only block in the checkpoint was replaced, so the scan restarts a reorg depth before it
+	server, close := testtypes.MockJSONRPC(t, []string{
+		`{"number":"0x64","hash":"` + hash(100, 1) + `"}`,
+		emptyBlock(98, 1),
+		emptyBlock(99, 1),
+		emptyBlock(100, 1),
+		emptyBlock(101, 1),
+		`[]`,
+		`[]`,
+	})
+	defer close()
+	cli, err := client.NewClient(&xc_types.ChainConfig{Chain: xc_types.ETH, URL: server.URL})
+	require.NoError(t, err)
+
+	checkpoint := &client.ScanCheckpoint{Blocks: []client.BlockRef{{Number: 100, Hash: hash(100, 0)}}}
+	result, err := cli.ScanFromCheckpoint(context.Background(), checkpoint, 101, &client.ScanArgs{
+		Addresses: []xc_types.Address{"0x724435CC1B2821362c2CD425F2744Bd7347bf299"},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 98, *result.ReorgedFrom)
+	require.Equal(t, []client.BlockRef{{Number: 100, Hash: hash(100, 1)}, {Number: 101, Hash: hash(101, 1)}}, result.Checkpoint.Blocks)
+}
+
+func TestValidateBatchDeposit(t *testing.T) {
+	require := require.New(t)
+	owner := xc_types.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	network, _ := validator.NetworkForChainId(17000)
+	seed := make([]byte, 32)
+
+	newInput := func(owner xc_types.Address, network validator.Network, count uint32) *tx_input.BatchDepositInput {
+		input := tx_input.NewBatchDepositInput()
+		input.ChainId = xc_types.NewBigIntFromUint64(17000)
+		creds := validator.Eth1WithdrawalCredentials(common.HexToAddress(string(owner)))
+		for i := uint32(0); i < count; i++ {
+			key, err := validator.DeriveSigningKey(seed, i)
+			require.NoError(err)
+			deposit, err := validator.NewDepositData(key, creds, validator.DepositAmountGwei, network)
+			require.NoError(err)
+			sig, _ := deposit.SignatureBytes()
+			input.PublicKeys = append(input.PublicKeys, key.PublicKey())
+			input.Signatures = append(input.Signatures, sig)
+		}
+		return input
+	}
+	amount, _ := xc_types.NewAmountHumanReadableFromStr("64")
+	args, err := xcbuilder.NewStakeArgs(xc_types.ETH, owner, amount.ToBlockchain(18))
+	require.NoError(err)
+	notFound := `{"code":404,"message":"Validator not found"}`
+
+	vectors := []struct {
+		name        string
+		input       *tx_input.BatchDepositInput
+		credentials [][]byte
+		responses   []string
+		statuses    []int
+		err         string
+	}{
+		{
+			name:      "valid",
+			input:     newInput(owner, network, 2),
+			responses: []string{notFound, notFound},
+			statuses:  []int{404, 404},
+		},
+		{
+			name:        "valid with provider credentials",
+			input:       newInput(owner, network, 1),
+			credentials: [][]byte{validator.Eth1WithdrawalCredentials(common.HexToAddress(string(owner)))},
+			responses:   []string{notFound},
+			statuses:    []int{404},
+		},
+		{
+			name:  "signed for another owner",
+			input: newInput("0x1111111111111111111111111111111111111111", network, 1),
+			err:   "is not valid for the withdrawal credentials on holesky",
+		},
+		{
+			name:  "signed for another network",
+			input: newInput(owner, validator.Networks[1], 1),
+			err:   "is not valid for the withdrawal credentials on holesky",
+		},
+		{
+			name:        "provider reports other credentials",
+			input:       newInput(owner, network, 1),
+			credentials: [][]byte{validator.Eth1WithdrawalCredentials(common.HexToAddress("0x1111111111111111111111111111111111111111"))},
+			err:         "withdrawal credentials for validator",
+		},
+		{
+			name: "duplicate validator",
+			input: func() *tx_input.BatchDepositInput {
+				input := newInput(owner, network, 1)
+				input.PublicKeys = append(input.PublicKeys, input.PublicKeys[0])
+				input.Signatures = append(input.Signatures, input.Signatures[0])
+				return input
+			}(),
+			err: "deposited to more than once",
+		},
+		{
+			name:      "already deposited",
+			input:     newInput(owner, network, 2),
+			responses: []string{notFound, `{"data":{"index":"1","status":"pending_queued"}}`},
+			statuses:  []int{404, 200},
+			err:       "has already been deposited to (status pending_queued)",
+		},
+		{
+			name:      "beacon unavailable",
+			input:     newInput(owner, network, 1),
+			responses: []string{`{"code":500,"message":"internal error"}`},
+			statuses:  []int{500},
+			err:       "could not confirm validator",
+		},
+	}
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			server, close := testtypes.MockHTTP(t, v.responses, 200)
+			defer close()
+			server.StatusCodes = v.statuses
+			cli, err := client.NewClient(&xc_types.ChainConfig{Chain: xc_types.ETH, URL: server.URL})
+			require.NoError(err)
+			err = cli.ValidateBatchDeposit(context.Background(), args, v.input, v.credentials)
+			if v.err != "" {
+				require.ErrorContains(err, v.err)
+			} else {
+				require.NoError(err)
+			}
+		})
+	}
+}
+
+func TestFetchStakingRewards(t *testing.T) {
+	require := require.New(t)
+	validatorA := "0xa776cfc875b15a1444bbda22e47e759ade11b39912a3e210807204f410d43baa332acb38aab206bc8ac7ad476a42839a"
+	validatorB := "0xa776cfc875b15a1444bbda22e47e759ade11b39912a3e210807204f410d43baa332acb38aab206bc8ac7ad476a42839b"
+	feeRecipient := "0x1111111111111111111111111111111111111111"
+	beacon := map[string]string{
+		"/eth/v1/config/spec":                                 `{"data":{"SLOTS_PER_EPOCH":"2","SECONDS_PER_SLOT":"12"}}`,
+		"/eth/v1/beacon/genesis":                              `{"data":{"genesis_time":"1606824023"}}`,
+		"/eth/v1/beacon/states/head/validators/" + validatorA: `{"data":{"index":"7","status":"active_exiting","validator":{"pubkey":"` + validatorA + `","slashed":false,"activation_epoch":"1","exit_epoch":"9","withdrawable_epoch":"11"}}}`,
+		"/eth/v1/beacon/states/head/validators/" + validatorB: `{"data":{"index":"9","status":"active_slashed","validator":{"pubkey":"` + validatorB + `","slashed":true,"activation_epoch":"2","exit_epoch":"18446744073709551615","withdrawable_epoch":"18446744073709551615"}}}`,
+		"/eth/v1/beacon/rewards/attestations/10": `{"data":{"total_rewards":[
+			{"validator_index":"7","head":"10","target":"20","source":"30","inclusion_delay":"0","inactivity":"0"},
+			{"validator_index":"9","head":"10","target":"-20","source":"-30","inclusion_delay":"0","inactivity":"-5"}
+		]}}`,
+		"/eth/v1/beacon/rewards/attestations/11": `{"data":{"total_rewards":[
+			{"validator_index":"9","head":"1","target":"2","source":"3","inclusion_delay":"0","inactivity":"0"}
+		]}}`,
+		// the attestation rewards and balances for working out the slashing penalty of 9
+		"/eth/v1/beacon/rewards/attestations/8": `{"data":{"total_rewards":[
+			{"validator_index":"9","head":"10","target":"10","source":"10","inclusion_delay":"0","inactivity":"0"}
+		]}}`,
+		"/eth/v1/beacon/rewards/attestations/9": `{"data":{"total_rewards":[
+			{"validator_index":"9","head":"0","target":"0","source":"0","inclusion_delay":"0","inactivity":"-5"}
+		]}}`,
+		"/eth/v1/beacon/states/19/validator_balances": `{"data":[{"index":"9","balance":"32000000000"}]}`,
+		// 32 ETH + 25 attestations + 5100 proposal and sync committee - 1 ETH slashed
+		"/eth/v1/beacon/states/23/validator_balances": `{"data":[{"index":"9","balance":"31000005125"}]}`,
+		"/eth/v1/beacon/states/20/sync_committees":    `{"data":{"validators":["3","9"]}}`,
+		"/eth/v1/beacon/states/22/sync_committees":    `{"data":{"validators":["3"]}}`,
+		"/eth/v2/beacon/blocks/20": `{"data":{"message":{"slot":"20","proposer_index":"9","body":{"execution_payload":{"block_number":"100","fee_recipient":"` + feeRecipient + `","withdrawals":[
+			{"index":"1","validator_index":"7","address":"0x3ad57b83b2e3dc5648f32e98e386935a9b10bb9f","amount":"1000"},
+			{"index":"2","validator_index":"8","address":"0x3ad57b83b2e3dc5648f32e98e386935a9b10bb9f","amount":"1000"}
+		]}}}}}`,
+		"/eth/v2/beacon/blocks/22": `{"data":{"message":{"slot":"22","proposer_index":"3","body":{"execution_payload":{"block_number":"101","fee_recipient":"` + feeRecipient + `","withdrawals":[
+			{"index":"3","validator_index":"7","address":"0x3ad57b83b2e3dc5648f32e98e386935a9b10bb9f","amount":"32000000000"}
+		]}}}}}`,
+		"/eth/v2/beacon/blocks/23":                 `{"data":{"message":{"slot":"23","proposer_index":"3","body":{"execution_payload":{"block_number":"102","fee_recipient":"` + feeRecipient + `","withdrawals":[]}}}}}`,
+		"/eth/v1/beacon/rewards/blocks/20":         `{"data":{"proposer_index":"9","total":"5000"}}`,
+		"/eth/v1/beacon/rewards/sync_committee/20": `{"data":[{"validator_index":"9","reward":"100"}]}`,
+	}
+	rpc := map[string]string{
+		"eth_getBlockByNumber": `{"baseFeePerGas":"0x64","transactions":[
+			{"hash":"0x0000000000000000000000000000000000000000000000000000000000000001","from":"0x2222222222222222222222222222222222222222","to":"0x3333333333333333333333333333333333333333","value":"0x0","input":"0x"},
+			{"hash":"0x0000000000000000000000000000000000000000000000000000000000000002","from":"` + feeRecipient + `","to":"0x4444444444444444444444444444444444444444","value":"0x3e8","input":"0x"}
+		]}`,
+		"eth_getBlockReceipts": `[{"gasUsed":"0x5208","effectiveGasPrice":"0x6e"},{"gasUsed":"0x5208","effectiveGasPrice":"0x64"}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/" {
+			var request struct {
+				Id     json.RawMessage `json:"id"`
+				Method string          `json:"method"`
+			}
+			require.NoError(json.NewDecoder(req.Body).Decode(&request))
+			rw.Write([]byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":%s}`, request.Id, rpc[request.Method])))
+			return
+		}
+		response, ok := beacon[req.URL.Path]
+		if !ok {
+			rw.WriteHeader(404)
+			rw.Write([]byte(`{"code":404,"message":"not found"}`))
+			return
+		}
+		rw.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	cli, err := client.NewClient(&xc_types.ChainConfig{Chain: xc_types.ETH, URL: server.URL})
+	require.NoError(err)
+	rewards, err := cli.FetchStakingRewards(context.Background(), &client.StakingRewardsArgs{
+		Validators:       []string{validatorA, validatorB},
+		FromEpoch:        10,
+		ToEpoch:          11,
+		ExecutionRewards: true,
+	})
+	require.NoError(err)
+	require.Len(rewards, 2)
+	gwei := func(amount uint64) string {
+		return new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(1_000_000_000)).String()
+	}
+
+	a := rewards[0]
+	require.Equal("7", a.Index)
+	require.Equal(gwei(60), a.Attestations.String())
+	require.Equal("0", a.Penalties.String())
+	require.Equal("0", a.Slashing.String())
+	require.Equal("0", a.Proposals.String())
+	require.Len(a.Withdrawals, 2)
+	require.EqualValues(20, a.Withdrawals[0].Slot)
+	require.EqualValues(100, a.Withdrawals[0].BlockNumber)
+	require.Equal(gwei(1000), a.Withdrawals[0].Amount.String())
+	require.False(a.Withdrawals[0].Full)
+	require.Equal(gwei(32000000000), a.Withdrawals[1].Amount.String())
+	require.True(a.Withdrawals[1].Full)
+	require.EqualValues(9, *a.Lifecycle.ExitEpoch)
+	require.EqualValues(11, *a.Lifecycle.WithdrawableEpoch)
+	// genesis + 11 epochs * 2 slots * 12s
+	require.EqualValues(1606824023+11*2*12, a.Lifecycle.WithdrawableTime.Unix())
+	require.Empty(a.Execution)
+
+	b := rewards[1]
+	require.Equal("9", b.Index)
+	require.Equal(gwei(16), b.Attestations.String())
+	require.Equal(gwei(55), b.Penalties.String())
+	require.Equal(gwei(1_000_000_000), b.Slashing.String())
+	require.Equal(gwei(100), b.SyncCommittee.String())
+	require.Equal(gwei(5000), b.Proposals.String())
+	require.Empty(b.Withdrawals)
+	require.Nil(b.Lifecycle.ExitEpoch)
+	require.Nil(b.Lifecycle.ExitTime)
+	require.Len(b.Execution, 1)
+	require.EqualValues(20, b.Execution[0].Slot)
+	require.EqualValues(100, b.Execution[0].BlockNumber)
+	// 21000 gas * 10 wei tip
+	require.Equal("210000", b.Execution[0].PriorityFees.String())
+	require.Equal("1000", b.Execution[0].MevPayment.String())
+	require.Equal(xc_types.Address("0x4444444444444444444444444444444444444444"), b.Execution[0].MevRecipient)
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc20"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+var _ xclient.TokenMetadataClient = &Client{}
+
+// Read the ERC-20 metadata of a token
+func (client *Client) FetchTokenMetadata(ctx context.Context, contract xc.ContractAddress) (*xclient.TokenMetadata, error) {
+	tokenAddress, err := address.FromHex(xc.Address(contract))
+	if err != nil {
+		return nil, err
+	}
+	instance, err := erc20.NewErc20(tokenAddress, client.EthClient)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	decimals, err := instance.Decimals(opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch decimals of token %s: %v", contract, err)
+	}
+	// name and symbol are optional in ERC-20, and some older tokens return them as bytes32
+	name, _ := instance.Name(opts)
+	symbol, _ := instance.Symbol(opts)
+	return xclient.NewTokenMetadata(contract, name, symbol, int32(decimals)), nil
+}
@@ -2,11 +2,14 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
 	xc_types "github.com/openweb3-io/crosschain/types"
 )
@@ -24,9 +27,17 @@ type TraceTransactionResult struct {
 	Input   hexutil.Bytes             `json:"input"`
 	Value   hexutil.Big               `json:"value"`
 	Type    TraceTransactionType      `json:"type"`
+	Error   string                    `json:"error,omitempty"`
 	Calls   []*TraceTransactionResult `json:"calls"`
 }
 
+// The trace of a tx in a block trace.  Older nodes don't report the tx hash.
+type TraceBlockResult struct {
+	TxHash common.Hash             `json:"txHash"`
+	Result *TraceTransactionResult `json:"result"`
+	Error  string                  `json:"error,omitempty"`
+}
+
 type TraceTransactionArgs struct {
 	Tracer string `json:"tracer"`
 }
@@ -41,6 +52,19 @@ func FlattenTraceResult(result *TraceTransactionResult, traces []*TraceTransacti
 	return traces
 }
 
+// Same as FlattenTraceResult, but skips reverted calls, including any calls they made.
+func FlattenSuccessfulTraceResult(result *TraceTransactionResult, traces []*TraceTransactionResult) []*TraceTransactionResult {
+	if result.Error != "" {
+		return traces
+	}
+	traces = append(traces, result)
+
+	for _, innerResult := range result.Calls {
+		traces = FlattenSuccessfulTraceResult(innerResult, traces)
+	}
+	return traces
+}
+
 // Implements debug_traceTransaction, which is supported on GETH and most RPC providers,
 // but likely not implemented on public nodes.
 // This will reveal ETH transfers in internal transactions and removes the need for us
@@ -53,20 +77,39 @@ func (client *Client) TraceTransaction(ctx context.Context, txHash common.Hash)
 	return &result, err
 }
 
+// Implements debug_traceBlockByNumber, which traces every tx of a block in one request.  The
+// traces are in the same order as the txs of the block.
+func (client *Client) TraceBlockByNumber(ctx context.Context, number uint64) ([]*TraceBlockResult, error) {
+	var result []*TraceBlockResult
+	err := client.EthClient.Client().CallContext(ctx, &result, "debug_traceBlockByNumber", hexutil.EncodeUint64(number), &TraceTransactionArgs{
+		Tracer: "callTracer",
+	})
+	return result, err
+}
+
 func (client *Client) TraceEthMovements(ctx context.Context, txHash common.Hash) (tx.SourcesAndDests, error) {
 	result, err := client.TraceTransaction(ctx, txHash)
 	if err != nil {
 		return tx.SourcesAndDests{}, err
 	}
-	traces := FlattenTraceResult(result, []*TraceTransactionResult{})
+	return client.traceMovements(result), nil
+}
+
+// Native asset movements of the successful calls in a trace
+func (client *Client) traceMovements(result *TraceTransactionResult) tx.SourcesAndDests {
+	traces := FlattenSuccessfulTraceResult(result, []*TraceTransactionResult{})
 	sourcesAndDests := tx.SourcesAndDests{}
 	zero := big.NewInt(0)
 	native := client.Chain.Chain
 
 	for _, trace := range traces {
+		if trace.Type == DELEGATE_CALL {
+			// delegate calls only report the caller's value
+			continue
+		}
 		if trace.Value.ToInt().Cmp(zero) > 0 {
 			amount := xc_types.BigInt(*trace.Value.ToInt())
-			sourcesAndDests.Sources = append(sourcesAndDests.Destinations, &xc_types.LegacyTxInfoEndpoint{
+			sourcesAndDests.Sources = append(sourcesAndDests.Sources, &xc_types.LegacyTxInfoEndpoint{
 				Address:     xc_types.Address(trace.From.String()),
 				Amount:      amount,
 				NativeAsset: native,
@@ -79,14 +122,14 @@ func (client *Client) TraceEthMovements(ctx context.Context, txHash common.Hash)
 		}
 	}
 
-	return sourcesAndDests, nil
-
+	return sourcesAndDests
 }
 
 type TxPoolResult struct {
 	// map of nonce to txinfo
 	Pending map[string]*TxPoolTxInfo `json:"pending"`
-	// Queued  map[string]*TxPoolTxInfo `json:"pending"`
+	// map of nonce to txinfo, for txs waiting on a nonce gap
+	Queued map[string]*TxPoolTxInfo `json:"queued"`
 }
 
 func (result *TxPoolResult) PendingCount() int {
@@ -123,3 +166,26 @@ func (client *Client) TxPoolContentFrom(ctx context.Context, from common.Address
 	err := client.EthClient.Client().CallContext(ctx, &result, "txpool_contentFrom", from.Hex())
 	return &result, err
 }
+
+// Fetch the nonces of txs from an address that are in the txpool
+func (client *Client) FetchPendingNonces(ctx context.Context, from xc_types.Address) ([]uint64, error) {
+	fromAddr, err := address.FromHex(from)
+	if err != nil {
+		return nil, fmt.Errorf("bad address '%v': %v", from, err)
+	}
+	result, err := client.TxPoolContentFrom(ctx, fromAddr)
+	if err != nil {
+		return nil, err
+	}
+	nonces := []uint64{}
+	for _, txs := range []map[string]*TxPoolTxInfo{result.Pending, result.Queued} {
+		for nonceStr := range txs {
+			nonce, err := strconv.ParseUint(nonceStr, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid txpool nonce '%s': %v", nonceStr, err)
+			}
+			nonces = append(nonces, nonce)
+		}
+	}
+	return nonces, nil
+}
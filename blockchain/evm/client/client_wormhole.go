@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	wormhole_abi "github.com/openweb3-io/crosschain/blockchain/evm/abi/wormhole"
+	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	"github.com/openweb3-io/crosschain/bridge/wormhole"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+var _ wormhole.Endpoint = &Client{}
+
+// Lookup the wormhole contracts and wormhole chain id of this chain
+func (client *Client) FetchWormholeContracts(ctx context.Context) (wormhole_abi.Contracts, wormhole.ChainID, error) {
+	chainId, err := client.fetchChainId(ctx)
+	if err != nil {
+		return wormhole_abi.Contracts{}, 0, err
+	}
+	contracts, ok := wormhole_abi.Deployments[chainId]
+	if !ok {
+		return wormhole_abi.Contracts{}, 0, fmt.Errorf("wormhole is not deployed on chain id %d", chainId)
+	}
+	wormholeChain, ok := wormhole.EvmChainIds[chainId]
+	if !ok {
+		return wormhole_abi.Contracts{}, 0, fmt.Errorf("no wormhole chain for chain id %d", chainId)
+	}
+	return contracts, wormholeChain, nil
+}
+
+// Fetch the input to redeem a signed VAA on this chain
+func (client *Client) FetchWormholeRedeemInput(ctx context.Context, from xc.Address, vaaBytes []byte) (*tx_input.WormholeRedeemInput, error) {
+	vaa, err := wormhole.ParseVAA(vaaBytes)
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := wormhole.ParseTokenTransfer(vaa.Payload)
+	if err != nil {
+		return nil, err
+	}
+	contracts, wormholeChain, err := client.FetchWormholeContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToChain != wormholeChain {
+		return nil, fmt.Errorf("wormhole transfer is for chain %d, not %d", transfer.ToChain, wormholeChain)
+	}
+	completed, err := client.IsWormholeTransferCompleted(ctx, vaa)
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		return nil, fmt.Errorf("wormhole transfer %s has already been redeemed", vaa.ID())
+	}
+
+	partialTxInput, err := client.FetchUnsimulatedInput(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	redeemInput := tx_input.NewWormholeRedeemInput()
+	redeemInput.TxInput = *partialTxInput
+	redeemInput.TokenBridge = xc.Address(contracts.TokenBridge.String())
+
+	builder, err := builder.NewTxBuilder(client.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.NewWormholeRedeem(vaaBytes, redeemInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	redeemInput.GasLimit, err = client.SimulateGasWithLimit(ctx, from, exampleTx.(*tx.Tx), client.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return redeemInput, nil
+}
+
+// Check if the transfer has been redeemed on this chain
+func (client *Client) IsWormholeTransferCompleted(ctx context.Context, vaa *wormhole.VAA) (bool, error) {
+	contracts, _, err := client.FetchWormholeContracts(ctx)
+	if err != nil {
+		return false, err
+	}
+	var digest [32]byte
+	copy(digest[:], vaa.Digest())
+	data, err := wormhole_abi.SerializeIsTransferCompleted(digest)
+	if err != nil {
+		return false, err
+	}
+	output, err := client.callContract(ctx, contracts.TokenBridge, data)
+	if err != nil {
+		return false, fmt.Errorf("could not fetch wormhole transfer status: %v", err)
+	}
+	return wormhole_abi.ParseIsTransferCompleted(output)
+}
+
+// Rebuild the observation the guardians will sign for a message published to the core bridge
+func wormholeObservation(log *types.Log, chainId int64, blockTime int64) (*wormhole.Observation, error) {
+	contracts, ok := wormhole_abi.Deployments[chainId]
+	if !ok || contracts.CoreBridge != log.Address {
+		return nil, fmt.Errorf("not published to the wormhole core bridge on chain id %d", chainId)
+	}
+	wormholeChain, ok := wormhole.EvmChainIds[chainId]
+	if !ok {
+		return nil, fmt.Errorf("no wormhole chain for chain id %d", chainId)
+	}
+	published, err := wormhole_abi.ParseLogMessagePublished(*log)
+	if err != nil {
+		return nil, err
+	}
+	obs := &wormhole.Observation{
+		Timestamp:        uint32(blockTime),
+		Nonce:            published.Nonce,
+		EmitterChain:     wormholeChain,
+		Sequence:         published.Sequence,
+		ConsistencyLevel: published.ConsistencyLevel,
+		Payload:          published.Payload,
+	}
+	copy(obs.EmitterAddress[12:], published.Sender.Bytes())
+	return obs, nil
+}
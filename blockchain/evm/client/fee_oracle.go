@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/shopspring/decimal"
+)
+
+// Number of recent blocks to sample rewards from
+var FeeHistoryBlockCount uint64 = 20
+
+// Number of blocks of max base fee growth (12.5% each) that the max fee cap should tolerate.
+// 6 blocks is roughly 2x the current base fee.
+var BaseFeeHeadroomBlocks = 6
+
+// Reward percentiles requested from eth_feeHistory, in order of Low, Market, Aggressive, VeryAggressive.
+var FeeHistoryPercentiles = []float64{10, 50, 75, 90}
+
+// Fetch EIP-1559 fee estimates for each priority using eth_feeHistory
+func (client *Client) FetchFeeEstimates(ctx context.Context) (*tx_input.FeeEstimates, error) {
+	history, err := client.EthClient.FeeHistory(ctx, FeeHistoryBlockCount, nil, FeeHistoryPercentiles)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch fee history: %v", err)
+	}
+	return NewFeeEstimates(client.Chain, history, BaseFeeHeadroomBlocks)
+}
+
+// Calculate the fees for each priority from the fee history.  The tip is the median of the reward
+// percentile over the sampled blocks, and the fee cap is the next block's base fee grown by
+// `headroomBlocks` full blocks, plus the tip.
+func NewFeeEstimates(chain *xc.ChainConfig, history *ethereum.FeeHistory, headroomBlocks int) (*tx_input.FeeEstimates, error) {
+	if history == nil || len(history.BaseFee) == 0 {
+		return nil, fmt.Errorf("fee history is empty")
+	}
+	// the last base fee is for the next (pending) block
+	nextBaseFee := history.BaseFee[len(history.BaseFee)-1]
+	maxBaseFee := BaseFeeWithHeadroom(xc.BigInt(*nextBaseFee), headroomBlocks)
+
+	estimates := &tx_input.FeeEstimates{}
+	for i, fee := range estimates.All() {
+		rewards := []*big.Int{}
+		for _, blockRewards := range history.Reward {
+			// skip empty blocks, they always report a zero reward
+			if i < len(blockRewards) && blockRewards[i] != nil && blockRewards[i].Sign() > 0 {
+				rewards = append(rewards, blockRewards[i])
+			}
+		}
+		// quiet chains and L2s may have no tips at all, which leaves the chain's min tip
+		tip := xc.NewBigIntFromUint64(0)
+		if len(rewards) > 0 {
+			sort.Slice(rewards, func(a, b int) bool {
+				return rewards[a].Cmp(rewards[b]) < 0
+			})
+			tip = xc.BigInt(*rewards[len(rewards)/2]).ApplyGasPriceMultiplier(chain)
+		}
+		fee.GasTipCap, fee.GasFeeCap = LimitGasFees(chain, tip, maxBaseFee)
+	}
+
+	// rewards are sampled independently, so keep each priority at least as high as the one below it
+	all := estimates.All()
+	for i := 1; i < len(all); i++ {
+		if all[i].GasTipCap.Cmp(&all[i-1].GasTipCap) < 0 {
+			*all[i] = *all[i-1]
+		}
+	}
+	return estimates, nil
+}
+
+// Grow the base fee by the max EIP-1559 increase (12.5%) for each block
+func BaseFeeWithHeadroom(baseFee xc.BigInt, blocks int) xc.BigInt {
+	fee := new(big.Int).Set(baseFee.Int())
+	for i := 0; i < blocks; i++ {
+		// round up
+		fee.Mul(fee, big.NewInt(9))
+		fee.Add(fee, big.NewInt(7))
+		fee.Div(fee, big.NewInt(8))
+	}
+	return xc.BigInt(*fee)
+}
+
+// Convert a gas price setting in gwei, which may be fractional on L2s, to wei
+func GasPriceGweiToWei(gwei float64) xc.BigInt {
+	return xc.BigInt(*decimal.NewFromFloat(gwei).Shift(9).BigInt())
+}
+
+// Keep the tip within the chain's min/max gas price (in gwei), matching the builder's max tip default
+func LimitGasTipCap(chain *xc.ChainConfig, tip xc.BigInt) xc.BigInt {
+	minTipWei := GasPriceGweiToWei(chain.ChainMinGasPrice)
+	if tip.Cmp(&minTipWei) < 0 {
+		tip = minTipWei
+	}
+	maxTipWei := GasPriceGweiToWei(chain.ChainMaxGasPrice)
+	if chain.ChainMaxGasPrice <= 0 {
+		maxTipWei = builder.GweiToWei(builder.DefaultMaxTipCapGwei)
+	}
+	if tip.Cmp(&maxTipWei) > 0 {
+		tip = maxTipWei
+	}
+	return tip
+}
+
+// Limit the tip, and set the fee cap to the max base fee plus the tip.  The chain's max gas price caps the
+// fee cap as well, and the tip is lowered to the fee cap if needed, even below the chain's min gas price.
+func LimitGasFees(chain *xc.ChainConfig, tip xc.BigInt, maxBaseFee xc.BigInt) (gasTipCap xc.BigInt, gasFeeCap xc.BigInt) {
+	gasTipCap = LimitGasTipCap(chain, tip)
+	gasFeeCap = xc.BigInt(*new(big.Int).Add(maxBaseFee.Int(), gasTipCap.Int()))
+	if chain.ChainMaxGasPrice > 0 {
+		maxGasPrice := GasPriceGweiToWei(chain.ChainMaxGasPrice)
+		if gasFeeCap.Cmp(&maxGasPrice) > 0 {
+			gasFeeCap = maxGasPrice
+		}
+	}
+	if gasTipCap.Cmp(&gasFeeCap) > 0 {
+		gasTipCap = gasFeeCap
+	}
+	return gasTipCap, gasFeeCap
+}
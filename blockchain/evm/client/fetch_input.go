@@ -135,6 +135,18 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 		return nil, err
 	}
 	txInput.GasLimit = gasLimit
+
+	if client.Chain.GetL2FeeModel() != xc.L2FeeModelNone {
+		l1Fee, err := client.EstimateL1Fee(ctx, args.GetFrom(), exampleTf.(*tx.Tx))
+		if err != nil {
+			zap.S().Warn("could not estimate L1 fee",
+				zap.String("from", string(args.GetFrom())),
+				zap.Error(err),
+			)
+		} else {
+			txInput.L1Fee = l1Fee.Fee
+		}
+	}
 	return txInput, nil
 }
 
@@ -171,22 +183,29 @@ func (client *Client) FetchUnsimulatedInput(ctx context.Context, from xc.Address
 
 	// Gas
 	if !nativeAsset.NoGasFees {
-		latestHeader, err := client.EthClient.HeaderByNumber(ctx, nil)
-		if err != nil {
-			return result, err
-		}
-
-		gasTipCap, err := client.EthClient.SuggestGasTipCap(ctx)
-		if err != nil {
-			return result, err
-		}
-		result.GasFeeCap = xc.BigInt(*latestHeader.BaseFee)
-		// should only multiply one cap, not both.
-		result.GasTipCap = xc.BigInt(*gasTipCap).ApplyGasPriceMultiplier(client.Chain)
+		estimates, err := client.FetchFeeEstimates(ctx)
+		if err == nil {
+			result.FeeEstimates = estimates
+			result.GasTipCap = estimates.Market.GasTipCap
+			result.GasFeeCap = estimates.Market.GasFeeCap
+		} else {
+			// not all nodes support eth_feeHistory
+			zap.S().Warn("could not estimate fees from fee history, using suggested tip",
+				zap.Error(err),
+			)
+			latestHeader, err := client.EthClient.HeaderByNumber(ctx, nil)
+			if err != nil {
+				return result, err
+			}
 
-		if result.GasFeeCap.Cmp(&result.GasTipCap) < 0 {
-			// increase max fee cap to accomodate tip if needed
-			result.GasFeeCap = result.GasTipCap
+			gasTipCap, err := client.EthClient.SuggestGasTipCap(ctx)
+			if err != nil {
+				return result, err
+			}
+			// leave room for the base fee to rise before the tx is included
+			maxBaseFee := BaseFeeWithHeadroom(xc.BigInt(*latestHeader.BaseFee), BaseFeeHeadroomBlocks)
+			// should only multiply one cap, not both.
+			result.GasTipCap, result.GasFeeCap = LimitGasFees(client.Chain, xc.BigInt(*gasTipCap).ApplyGasPriceMultiplier(client.Chain), maxBaseFee)
 		}
 
 		fromAddr, _ := address.FromHex(from)
@@ -217,6 +236,17 @@ func (client *Client) FetchUnsimulatedInput(ctx context.Context, from xc.Address
 					log.Debug("replacing max-priority-fee-cap because of pending tx")
 					result.GasTipCap = minPriorityFee
 				}
+				// the replacement minimums apply to any priority that gets set later
+				if result.FeeEstimates != nil {
+					for _, fee := range result.FeeEstimates.All() {
+						if fee.GasFeeCap.Cmp(&minMaxFee) < 0 {
+							fee.GasFeeCap = minMaxFee
+						}
+						if fee.GasTipCap.Cmp(&minPriorityFee) < 0 {
+							fee.GasTipCap = minPriorityFee
+						}
+					}
+				}
 			}
 		}
 
@@ -109,13 +109,19 @@ func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeAr
 	if err != nil {
 		return nil, err
 	}
-	res, err := cli.providerClient.CreateValidator(int(count), string(args.GetFrom()))
+	// the validators must withdraw to the same address that Stake will deposit with
+	owner, ok := args.GetStakeOwner()
+	if !ok {
+		owner = args.GetFrom()
+	}
+	res, err := cli.providerClient.CreateValidator(int(count), string(owner))
 	if err != nil {
 		return nil, err
 	}
 	testutil.JsonPrint(res)
 	stakingInput := tx_input.NewBatchDepositInput()
 	stakingInput.TxInput = *partialTxInput
+	var credentials [][]byte
 
 	for _, validator := range res.Data {
 		pubkeyBz, err := address.DecodeHex(validator.Pubkey)
@@ -126,8 +132,16 @@ func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeAr
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode figment validator signature: %v", err)
 		}
+		credentialsBz, err := address.DecodeHex(validator.WithdrawalCredentials)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode figment validator withdrawal credentials: %v", err)
+		}
 		stakingInput.PublicKeys = append(stakingInput.PublicKeys, pubkeyBz)
 		stakingInput.Signatures = append(stakingInput.Signatures, signatureBz)
+		credentials = append(credentials, credentialsBz)
+	}
+	if err := cli.rpcClient.ValidateBatchDeposit(ctx, args, stakingInput, credentials); err != nil {
+		return nil, fmt.Errorf("refusing figment deposit data: %v", err)
 	}
 
 	builder, err := builder.NewTxBuilder(cli.chain)
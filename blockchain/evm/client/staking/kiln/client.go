@@ -103,7 +103,7 @@ func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeAr
 		asset = cli.chain
 	}
 
-	stakingInput, err := cli.FetchKilnInput(ctx, args)
+	stakingInput, credentials, err := cli.FetchKilnInput(ctx, args)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +114,10 @@ func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeAr
 	}
 	stakingInput.TxInput = *partialTxInput
 
+	if err := cli.rpcClient.ValidateBatchDeposit(ctx, args, stakingInput, credentials); err != nil {
+		return nil, fmt.Errorf("refusing kiln deposit data: %v", err)
+	}
+
 	builder, err := builder.NewTxBuilder(cli.chain)
 	if err != nil {
 		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
@@ -133,51 +137,72 @@ func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeAr
 	return stakingInput, nil
 }
 
-func (cli *Client) FetchKilnInput(ctx context.Context, args xcbuilder.StakeArgs) (*tx_input.BatchDepositInput, error) {
+// Create validator keys with kiln, returning the deposit input and the withdrawal credentials kiln reports, if any
+func (cli *Client) FetchKilnInput(ctx context.Context, args xcbuilder.StakeArgs) (*tx_input.BatchDepositInput, [][]byte, error) {
 	count, err := validation.Count32EthChunks(args.GetAmount())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	accountId, _ := args.GetStakeAccount()
 	acc, err := cli.kilnClient.ResolveAccount(accountId)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	// the validators must withdraw to the same address that Stake will deposit with
+	owner, ok := args.GetStakeOwner()
+	if !ok {
+		owner = args.GetFrom()
 	}
 
-	keys, err := cli.kilnClient.CreateValidatorKeys(acc.ID, string(args.GetFrom()), int(count))
+	keys, err := cli.kilnClient.CreateValidatorKeys(acc.ID, string(owner), int(count))
 	if err != nil {
-		return nil, fmt.Errorf("could not create validator keys: %v", err)
+		return nil, nil, fmt.Errorf("could not create validator keys: %v", err)
 	}
 
 	input := tx_input.NewBatchDepositInput()
 	pubkeys := []string{}
 	sigs := []string{}
+	creds := []string{}
 
 	// tolerate ambiguous kiln type
 	if keys.Response1 != nil {
 		for _, data := range keys.Response1.Data {
 			pubkeys = append(pubkeys, data.PubKey)
 			sigs = append(sigs, data.Signature)
+			creds = append(creds, data.WithdrawalCredentials)
 		}
 	} else if keys.Response2 != nil {
 		pubkeys = append(pubkeys, keys.Response2.Data.PubKeys...)
 		sigs = append(sigs, keys.Response2.Data.Signatures...)
+		creds = append(creds, keys.Response2.Data.WithdrawalCredentials...)
 	}
 	for _, pubkey := range pubkeys {
 		pubkeyBz, err := address.DecodeHex(pubkey)
 		if err != nil {
-			return nil, fmt.Errorf("kiln provided invalid validator public key %s: %v", pubkey, err)
+			return nil, nil, fmt.Errorf("kiln provided invalid validator public key %s: %v", pubkey, err)
 		}
 		input.PublicKeys = append(input.PublicKeys, pubkeyBz)
 	}
 	for _, sig := range sigs {
 		sigBiz, err := address.DecodeHex(sig)
 		if err != nil {
-			return nil, fmt.Errorf("kiln provided invalid signature %s: %v", sig, err)
+			return nil, nil, fmt.Errorf("kiln provided invalid signature %s: %v", sig, err)
 		}
 		input.Signatures = append(input.Signatures, sigBiz)
 	}
-	return input, nil
+	var credentials [][]byte
+	for _, cred := range creds {
+		if cred == "" {
+			// not reported
+			return input, nil, nil
+		}
+		credBz, err := address.DecodeHex(cred)
+		if err != nil {
+			return nil, nil, fmt.Errorf("kiln provided invalid withdrawal credentials %s: %v", cred, err)
+		}
+		credentials = append(credentials, credBz)
+	}
+	return input, credentials, nil
 }
 
 func (cli *Client) FetchUnstakingInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.UnstakeTxInput, error) {
@@ -0,0 +1,360 @@
+package lido
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/lido"
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	evmclient "github.com/openweb3-io/crosschain/blockchain/evm/client"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xcclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Client for liquid staking with Lido.  Staked ether is held as stETH, which is unstaked through
+// the withdrawal queue and claimed once the request is finalized.
+type Client struct {
+	rpcClient *evmclient.Client
+	chain     *xc_types.ChainConfig
+}
+
+var _ xcclient.StakingClient = &Client{}
+
+func NewClient(rpcClient *evmclient.Client, chain *xc_types.ChainConfig) (xcclient.StakingClient, error) {
+	return &Client{rpcClient, chain}, nil
+}
+
+func (cli *Client) contracts(ctx context.Context) (lido.Contracts, error) {
+	chainId := cli.chain.ChainID
+	if chainId == 0 {
+		id, err := cli.rpcClient.EthClient.ChainID(ctx)
+		if err != nil {
+			return lido.Contracts{}, fmt.Errorf("could not lookup chain_id: %v", err)
+		}
+		chainId = id.Int64()
+	}
+	contracts, ok := lido.Deployments[chainId]
+	if !ok {
+		return lido.Contracts{}, fmt.Errorf("lido is not deployed on chain id %d", chainId)
+	}
+	return contracts, nil
+}
+
+func (cli *Client) call(ctx context.Context, contract common.Address, data []byte) ([]byte, error) {
+	return cli.rpcClient.EthClient.CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: data,
+	}, nil)
+}
+
+func (cli *Client) FetchStEthBalance(ctx context.Context, contracts lido.Contracts, owner common.Address) (*big.Int, error) {
+	data, err := lido.SerializeBalanceOf(owner)
+	if err != nil {
+		return nil, err
+	}
+	output, err := cli.call(ctx, contracts.StEth, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch stETH balance: %v", err)
+	}
+	return lido.ParseBalanceOf(output)
+}
+
+func (cli *Client) FetchAllowance(ctx context.Context, contracts lido.Contracts, owner common.Address) (*big.Int, error) {
+	data, err := lido.SerializeAllowance(owner, contracts.WithdrawalQueue)
+	if err != nil {
+		return nil, err
+	}
+	output, err := cli.call(ctx, contracts.StEth, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch stETH allowance: %v", err)
+	}
+	return lido.ParseAllowance(output)
+}
+
+type WithdrawalRequest struct {
+	Id     *big.Int
+	Status lido.WithdrawalRequestStatus
+}
+
+// Fetch the withdrawal requests of the owner that have not been claimed
+func (cli *Client) FetchWithdrawalRequests(ctx context.Context, contracts lido.Contracts, owner common.Address) ([]*WithdrawalRequest, error) {
+	data, err := lido.SerializeGetWithdrawalRequests(owner)
+	if err != nil {
+		return nil, err
+	}
+	output, err := cli.call(ctx, contracts.WithdrawalQueue, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch withdrawal requests: %v", err)
+	}
+	ids, err := lido.ParseGetWithdrawalRequests(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []*WithdrawalRequest{}, nil
+	}
+	data, err = lido.SerializeGetWithdrawalStatus(ids)
+	if err != nil {
+		return nil, err
+	}
+	output, err = cli.call(ctx, contracts.WithdrawalQueue, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch withdrawal status: %v", err)
+	}
+	statuses, err := lido.ParseGetWithdrawalStatus(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) != len(ids) {
+		return nil, fmt.Errorf("received %d withdrawal statuses for %d requests", len(statuses), len(ids))
+	}
+	requests := []*WithdrawalRequest{}
+	for i, status := range statuses {
+		if status.IsClaimed {
+			continue
+		}
+		requests = append(requests, &WithdrawalRequest{Id: ids[i], Status: status})
+	}
+	return requests, nil
+}
+
+// stETH is active, pending withdrawal requests are deactivating, and finalized requests are inactive until claimed.
+func (cli *Client) FetchStakeBalance(ctx context.Context, args xcclient.StakedBalanceArgs) ([]*xcclient.StakedBalance, error) {
+	owner, err := address.FromHex(args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := cli.contracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := cli.FetchStEthBalance(ctx, contracts, owner)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := cli.FetchWithdrawalRequests(ctx, contracts, owner)
+	if err != nil {
+		return nil, err
+	}
+	deactivating := big.NewInt(0)
+	inactive := big.NewInt(0)
+	for _, request := range requests {
+		if request.Status.IsFinalized {
+			inactive.Add(inactive, request.Status.AmountOfStETH)
+		} else {
+			deactivating.Add(deactivating, request.Status.AmountOfStETH)
+		}
+	}
+	return []*xcclient.StakedBalance{
+		xcclient.NewStakedBalances(xcclient.StakedBalanceState{
+			Active:       xc_types.BigInt(*balance),
+			Deactivating: xc_types.BigInt(*deactivating),
+			Inactive:     xc_types.BigInt(*inactive),
+		}, "", contracts.StEth.String()),
+	}, nil
+}
+
+func (cli *Client) simulate(ctx context.Context, args xcbuilder.StakeArgs, exampleTx xc_types.Tx) (uint64, error) {
+	var asset xc_types.IAsset
+	if as, ok := args.GetAsset(); ok {
+		asset = as
+	} else {
+		asset = cli.chain
+	}
+	return cli.rpcClient.SimulateGasWithLimit(ctx, args.GetFrom(), exampleTx.(*tx.Tx), asset)
+}
+
+func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.StakeTxInput, error) {
+	contracts, err := cli.contracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	partialTxInput, err := cli.rpcClient.FetchUnsimulatedInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	stakingInput := tx_input.NewLidoStakeInput()
+	stakingInput.TxInput = *partialTxInput
+	stakingInput.StEth = xc_types.Address(contracts.StEth.String())
+
+	builder, err := builder.NewTxBuilder(cli.chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.Stake(args, stakingInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	stakingInput.GasLimit, err = cli.simulate(ctx, args, exampleTx)
+	if err != nil {
+		return nil, err
+	}
+	return stakingInput, nil
+}
+
+func (cli *Client) FetchUnstakingInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.UnstakeTxInput, error) {
+	from, err := address.FromHex(args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := cli.contracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount := args.GetAmount()
+	balance, err := cli.FetchStEthBalance(ctx, contracts, from)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(amount.Int()) < 0 {
+		return nil, fmt.Errorf("stETH balance %s is less than the %s requested to unstake", balance, amount.String())
+	}
+	allowance, err := cli.FetchAllowance(ctx, contracts, from)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(amount.Int()) < 0 {
+		return nil, fmt.Errorf("the lido withdrawal queue is approved to spend %s stETH but %s is needed, approve it first", allowance, amount.String())
+	}
+
+	partialTxInput, err := cli.rpcClient.FetchUnsimulatedInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	unstakingInput := tx_input.NewLidoUnstakeInput()
+	unstakingInput.TxInput = *partialTxInput
+	unstakingInput.WithdrawalQueue = xc_types.Address(contracts.WithdrawalQueue.String())
+
+	builder, err := builder.NewTxBuilder(cli.chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.Unstake(args, unstakingInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	unstakingInput.GasLimit, err = cli.simulate(ctx, args, exampleTx)
+	if err != nil {
+		return nil, err
+	}
+	return unstakingInput, nil
+}
+
+// Fetch the input to approve the withdrawal queue to spend the stETH being unstaked
+func (cli *Client) FetchApprovalInput(ctx context.Context, args xcbuilder.StakeArgs) (*tx_input.TxInput, error) {
+	contracts, err := cli.contracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txInput, err := cli.rpcClient.FetchUnsimulatedInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	builder, err := builder.NewTxBuilder(cli.chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.ApproveLidoWithdrawals(args, xc_types.Address(contracts.StEth.String()), xc_types.Address(contracts.WithdrawalQueue.String()), txInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	txInput.GasLimit, err = cli.simulate(ctx, args, exampleTx)
+	if err != nil {
+		return nil, err
+	}
+	return txInput, nil
+}
+
+// Claims all finalized withdrawal requests of the sender
+func (cli *Client) FetchWithdrawInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.WithdrawTxInput, error) {
+	from, err := address.FromHex(args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := cli.contracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := cli.FetchWithdrawalRequests(ctx, contracts, from)
+	if err != nil {
+		return nil, err
+	}
+	finalized := []*big.Int{}
+	for _, request := range requests {
+		if request.Status.IsFinalized {
+			finalized = append(finalized, request.Id)
+		}
+	}
+	if len(finalized) == 0 {
+		return nil, fmt.Errorf("no finalized lido withdrawal requests to claim")
+	}
+	hints, err := cli.FetchCheckpointHints(ctx, contracts, finalized)
+	if err != nil {
+		return nil, err
+	}
+
+	partialTxInput, err := cli.rpcClient.FetchUnsimulatedInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	withdrawInput := tx_input.NewLidoWithdrawInput()
+	withdrawInput.TxInput = *partialTxInput
+	withdrawInput.WithdrawalQueue = xc_types.Address(contracts.WithdrawalQueue.String())
+	for i := range finalized {
+		withdrawInput.RequestIds = append(withdrawInput.RequestIds, xc_types.BigInt(*finalized[i]))
+		withdrawInput.Hints = append(withdrawInput.Hints, xc_types.BigInt(*hints[i]))
+	}
+
+	builder, err := builder.NewTxBuilder(cli.chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.Withdraw(args, withdrawInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	withdrawInput.GasLimit, err = cli.simulate(ctx, args, exampleTx)
+	if err != nil {
+		return nil, err
+	}
+	return withdrawInput, nil
+}
+
+// Claims need the checkpoint of the finalization of each request, as a hint
+func (cli *Client) FetchCheckpointHints(ctx context.Context, contracts lido.Contracts, requestIds []*big.Int) ([]*big.Int, error) {
+	data, err := lido.SerializeGetLastCheckpointIndex()
+	if err != nil {
+		return nil, err
+	}
+	output, err := cli.call(ctx, contracts.WithdrawalQueue, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch last checkpoint index: %v", err)
+	}
+	lastIndex, err := lido.ParseGetLastCheckpointIndex(output)
+	if err != nil {
+		return nil, err
+	}
+	data, err = lido.SerializeFindCheckpointHints(requestIds, big.NewInt(1), lastIndex)
+	if err != nil {
+		return nil, err
+	}
+	output, err = cli.call(ctx, contracts.WithdrawalQueue, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not find checkpoint hints: %v", err)
+	}
+	hints, err := lido.ParseFindCheckpointHints(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(hints) != len(requestIds) {
+		return nil, fmt.Errorf("received %d checkpoint hints for %d requests", len(hints), len(requestIds))
+	}
+	return hints, nil
+}
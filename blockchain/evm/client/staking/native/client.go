@@ -0,0 +1,145 @@
+package native
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/address"
+	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	evmclient "github.com/openweb3-io/crosschain/blockchain/evm/client"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/evm/validator"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	"github.com/openweb3-io/crosschain/builder/validation"
+	xcclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Client for staking with validator keys generated and kept locally, rather than by a provider
+type Client struct {
+	rpcClient *evmclient.Client
+	chain     *xc_types.ChainConfig
+	keys      KeyStore
+}
+
+var _ xcclient.StakingClient = &Client{}
+
+func NewClient(rpcClient *evmclient.Client, chain *xc_types.ChainConfig, keys KeyStore) (xcclient.StakingClient, error) {
+	return &Client{rpcClient, chain, keys}, nil
+}
+
+func (cli *Client) FetchStakeBalance(ctx context.Context, args xcclient.StakedBalanceArgs) ([]*xcclient.StakedBalance, error) {
+	return cli.rpcClient.FetchStakeBalance(ctx, args)
+}
+
+func (cli *Client) network(ctx context.Context) (validator.Network, error) {
+	chainId := cli.chain.ChainID
+	if chainId == 0 {
+		id, err := cli.rpcClient.EthClient.ChainID(ctx)
+		if err != nil {
+			return validator.Network{}, fmt.Errorf("could not lookup chain_id: %v", err)
+		}
+		chainId = id.Int64()
+	}
+	return validator.NetworkForChainId(chainId)
+}
+
+// Create the deposit data for new validators.  Each call generates new keys, which are saved before
+// being returned, so a failed or repeated call only leaves unused keys behind.
+func (cli *Client) FetchDepositData(ctx context.Context, args xcbuilder.StakeArgs) ([]*validator.DepositData, error) {
+	count, err := validation.Count32EthChunks(args.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	owner, ok := args.GetStakeOwner()
+	if !ok {
+		owner = args.GetFrom()
+	}
+	ownerAddr, err := address.FromHex(owner)
+	if err != nil {
+		return nil, err
+	}
+	network, err := cli.network(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := cli.keys.NewKeys(ctx, int(count))
+	if err != nil {
+		return nil, fmt.Errorf("could not create validator keys: %v", err)
+	}
+	creds := validator.Eth1WithdrawalCredentials(ownerAddr)
+	deposits := make([]*validator.DepositData, len(keys))
+	for i, key := range keys {
+		deposits[i], err = validator.NewDepositData(key, creds, validator.DepositAmountGwei, network)
+		if err != nil {
+			return nil, fmt.Errorf("could not sign deposit for validator %s: %v", key.PublicKeyHex(), err)
+		}
+	}
+	if err := cli.keys.SaveDepositData(ctx, deposits); err != nil {
+		return nil, fmt.Errorf("could not save deposit data: %v", err)
+	}
+	return deposits, nil
+}
+
+func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.StakeTxInput, error) {
+	var asset xc_types.IAsset
+	if as, ok := args.GetAsset(); ok {
+		asset = as
+	} else {
+		asset = cli.chain
+	}
+
+	deposits, err := cli.FetchDepositData(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	stakingInput := tx_input.NewBatchDepositInput()
+	for _, deposit := range deposits {
+		pubkeyBz, err := deposit.PublicKeyBytes()
+		if err != nil {
+			return nil, err
+		}
+		signatureBz, err := deposit.SignatureBytes()
+		if err != nil {
+			return nil, err
+		}
+		stakingInput.PublicKeys = append(stakingInput.PublicKeys, pubkeyBz)
+		stakingInput.Signatures = append(stakingInput.Signatures, signatureBz)
+	}
+
+	partialTxInput, err := cli.rpcClient.FetchUnsimulatedInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	stakingInput.TxInput = *partialTxInput
+	// the keys are new, but confirm nothing went wrong before depositing
+	if err := cli.rpcClient.ValidateBatchDeposit(ctx, args, stakingInput, nil); err != nil {
+		return nil, err
+	}
+
+	builder, err := builder.NewTxBuilder(cli.chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	exampleTx, err := builder.Stake(args, stakingInput)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare to simulate: %v", err)
+	}
+	gasLimit, err := cli.rpcClient.SimulateGasWithLimit(ctx, args.GetFrom(), exampleTx.(*tx.Tx), asset)
+	if err != nil {
+		return nil, err
+	}
+	stakingInput.GasLimit = gasLimit
+
+	return stakingInput, nil
+}
+
+func (cli *Client) FetchUnstakingInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.UnstakeTxInput, error) {
+	return nil, fmt.Errorf("natively staked validators exit by signing a voluntary exit with the validator key")
+}
+
+func (cli *Client) FetchWithdrawInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.WithdrawTxInput, error) {
+	return nil, fmt.Errorf("ethereum stakes are withdrawn automatically by the protocol")
+}
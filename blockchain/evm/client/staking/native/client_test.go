@@ -0,0 +1,103 @@
+package native_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/client/staking/native"
+	"github.com/openweb3-io/crosschain/blockchain/evm/validator"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeyStore(t *testing.T, dir string) *native.DirKeyStore {
+	seed, _ := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	store, err := native.NewDirKeyStore(dir, seed, "password")
+	require.NoError(t, err)
+	// fast for testing
+	store.Kdf = validator.KdfPbkdf2
+	store.Clock = func() time.Time { return time.Unix(1700000000, 0) }
+	return store
+}
+
+func TestDirKeyStore(t *testing.T) {
+	require := require.New(t)
+	defaultPbkdf2C := validator.Pbkdf2C
+	defer func() { validator.Pbkdf2C = defaultPbkdf2C }()
+	validator.Pbkdf2C = 16
+	dir := t.TempDir()
+	store := newKeyStore(t, dir)
+
+	keys, err := store.NewKeys(context.Background(), 2)
+	require.NoError(err)
+	require.Len(keys, 2)
+	require.Equal("m/12381/3600/0/0/0", keys[0].Path)
+	require.Equal("m/12381/3600/1/0/0", keys[1].Path)
+
+	bz, err := os.ReadFile(filepath.Join(dir, "keystore-m_12381_3600_1_0_0-1700000000.json"))
+	require.NoError(err)
+	keystore := &validator.Keystore{}
+	require.NoError(json.Unmarshal(bz, keystore))
+	decrypted, err := keystore.Decrypt("password")
+	require.NoError(err)
+	require.Equal(keys[1].Bytes(), decrypted.Bytes())
+
+	// keys are never reused, even by a new store on the same directory
+	keys, err = newKeyStore(t, dir).NewKeys(context.Background(), 1)
+	require.NoError(err)
+	require.Equal("m/12381/3600/2/0/0", keys[0].Path)
+
+	_, err = native.NewDirKeyStore(dir, []byte{1, 2, 3}, "password")
+	require.Error(err)
+	_, err = native.NewDirKeyStore(dir, make([]byte, 32), "")
+	require.Error(err)
+}
+
+func TestFetchDepositData(t *testing.T) {
+	require := require.New(t)
+	defaultPbkdf2C := validator.Pbkdf2C
+	defer func() { validator.Pbkdf2C = defaultPbkdf2C }()
+	validator.Pbkdf2C = 16
+	dir := t.TempDir()
+
+	chain := &xc_types.ChainConfig{Chain: xc_types.ETH, ChainID: 17000, Decimals: 18}
+	cli, err := native.NewClient(nil, chain, newKeyStore(t, dir))
+	require.NoError(err)
+
+	amount, _ := xc_types.NewAmountHumanReadableFromStr("64")
+	args, err := xcbuilder.NewStakeArgs(chain.Chain, "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F", amount.ToBlockchain(18),
+		xcbuilder.WithStakeOwner("0x1111111111111111111111111111111111111111"),
+	)
+	require.NoError(err)
+	deposits, err := cli.(*native.Client).FetchDepositData(context.Background(), args)
+	require.NoError(err)
+	require.Len(deposits, 2)
+	for _, deposit := range deposits {
+		// withdraws to the stake owner
+		require.Equal("0100000000000000000000001111111111111111111111111111111111111111", deposit.WithdrawalCredentials)
+		require.Equal("holesky", deposit.NetworkName)
+		pubkey, _ := deposit.PublicKeyBytes()
+		sig, _ := deposit.SignatureBytes()
+		messageRoot, _ := hex.DecodeString(deposit.DepositMessageRoot)
+		network, _ := validator.NetworkForChainId(17000)
+		ok, err := validator.Verify(pubkey, validator.SigningRoot(messageRoot, validator.ComputeDepositDomain(network.GenesisForkVersion)), sig)
+		require.NoError(err)
+		require.True(ok)
+	}
+	require.NotEqual(deposits[0].PubKey, deposits[1].PubKey)
+
+	matches, err := filepath.Glob(filepath.Join(dir, "deposit_data-*.json"))
+	require.NoError(err)
+	require.Len(matches, 1)
+
+	// no beacon chain for the chain
+	chain.ChainID = 56
+	_, err = cli.(*native.Client).FetchDepositData(context.Background(), args)
+	require.ErrorContains(err, "no beacon chain network")
+}
@@ -0,0 +1,133 @@
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openweb3-io/crosschain/blockchain/evm/validator"
+)
+
+// KeyStore allocates validator keys that have not been used before, and keeps their keystores
+type KeyStore interface {
+	NewKeys(ctx context.Context, count int) ([]*validator.SecretKey, error)
+	SaveDepositData(ctx context.Context, deposits []*validator.DepositData) error
+}
+
+// DirKeyStore derives validator keys from a seed and writes EIP-2335 keystores and deposit data
+// to a directory, using the same file names as the ethereum deposit cli.  The next validator index is
+// taken from the keystores already in the directory, so the directory must not be shared by seeds.
+type DirKeyStore struct {
+	Dir      string
+	Seed     []byte
+	Password string
+	Kdf      validator.Kdf
+	// Used for testing
+	Clock func() time.Time
+	lock  sync.Mutex
+}
+
+var _ KeyStore = &DirKeyStore{}
+
+var keystoreFileRegex = regexp.MustCompile(`^keystore-m_12381_3600_(\d+)_0_0-\d+\.json$`)
+
+func NewDirKeyStore(dir string, seed []byte, password string) (*DirKeyStore, error) {
+	if len(seed) < 32 {
+		return nil, fmt.Errorf("seed must be at least 32 bytes, received %d", len(seed))
+	}
+	if password == "" {
+		return nil, fmt.Errorf("a password is required to encrypt validator keystores")
+	}
+	return &DirKeyStore{
+		Dir:      dir,
+		Seed:     seed,
+		Password: password,
+		Kdf:      validator.KdfScrypt,
+		Clock:    time.Now,
+	}, nil
+}
+
+func (store *DirKeyStore) nextIndex() (uint32, error) {
+	entries, err := os.ReadDir(store.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	next := uint32(0)
+	for _, entry := range entries {
+		match := keystoreFileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		index, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			continue
+		}
+		if uint32(index) >= next {
+			next = uint32(index) + 1
+		}
+	}
+	return next, nil
+}
+
+func (store *DirKeyStore) writeJson(name string, value interface{}) error {
+	bz, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	// keystores must be written before the keys are used, and never overwritten
+	file, err := os.OpenFile(filepath.Join(store.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err = file.Write(bz); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func (store *DirKeyStore) NewKeys(ctx context.Context, count int) ([]*validator.SecretKey, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if err := os.MkdirAll(store.Dir, 0700); err != nil {
+		return nil, err
+	}
+	next, err := store.nextIndex()
+	if err != nil {
+		return nil, fmt.Errorf("could not read keystore directory: %v", err)
+	}
+	unix := store.Clock().Unix()
+	keys := make([]*validator.SecretKey, count)
+	for i := range keys {
+		index := next + uint32(i)
+		keys[i], err = validator.DeriveSigningKey(store.Seed, index)
+		if err != nil {
+			return nil, err
+		}
+		keystore, err := validator.NewKeystore(keys[i], store.Password, store.Kdf)
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprintf("keystore-m_12381_3600_%d_0_0-%d.json", index, unix)
+		if err := store.writeJson(name, keystore); err != nil {
+			return nil, fmt.Errorf("could not save keystore for validator %d: %v", index, err)
+		}
+	}
+	return keys, nil
+}
+
+func (store *DirKeyStore) SaveDepositData(ctx context.Context, deposits []*validator.DepositData) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	name := fmt.Sprintf("deposit_data-%d.json", store.Clock().UnixNano())
+	return store.writeJson(name, deposits)
+}
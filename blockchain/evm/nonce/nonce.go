@@ -0,0 +1,278 @@
+package nonce
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Source reports the nonces the node knows about.  The evm client implements this.
+type Source interface {
+	// Nonce of the next tx to be confirmed
+	GetNonce(ctx context.Context, from xc.Address) (uint64, error)
+	// Nonces of txs from the address waiting in the txpool
+	FetchPendingNonces(ctx context.Context, from xc.Address) ([]uint64, error)
+}
+
+// Key identifies a sender
+type Key struct {
+	Chain   xc.NativeAsset
+	Address xc.Address
+}
+
+func NewKey(chain xc.NativeAsset, address xc.Address) Key {
+	// evm addresses are case insensitive
+	return Key{Chain: chain, Address: xc.Address(strings.ToLower(string(address)))}
+}
+
+func (key Key) String() string {
+	return fmt.Sprintf("%s/%s", key.Chain, key.Address)
+}
+
+// State of the nonces allocated for a sender
+type State struct {
+	// Next nonce to allocate when there are no released nonces to reuse
+	Next uint64 `json:"next"`
+	// Nonces handed out and not yet sent
+	Reserved map[uint64]*ReservedNonce `json:"reserved,omitempty"`
+	// Nonces that have been sent, with the unix time they were sent
+	Sent map[uint64]int64 `json:"sent,omitempty"`
+	// Nonces below Next that are free to allocate again, from abandoned sends or dropped txs
+	Released []uint64 `json:"released,omitempty"`
+}
+
+type ReservedNonce struct {
+	Id   string `json:"id"`
+	Unix int64  `json:"unix"`
+}
+
+func NewState() *State {
+	return &State{
+		Reserved: map[uint64]*ReservedNonce{},
+		Sent:     map[uint64]int64{},
+	}
+}
+
+func (state *State) Clone() *State {
+	clone := NewState()
+	clone.Next = state.Next
+	for nonce, reserved := range state.Reserved {
+		clone.Reserved[nonce] = &ReservedNonce{Id: reserved.Id, Unix: reserved.Unix}
+	}
+	for nonce, unix := range state.Sent {
+		clone.Sent[nonce] = unix
+	}
+	clone.Released = append([]uint64{}, state.Released...)
+	return clone
+}
+
+func (state *State) isReleased(nonce uint64) bool {
+	for _, released := range state.Released {
+		if released == nonce {
+			return true
+		}
+	}
+	return false
+}
+
+func (state *State) release(nonce uint64) {
+	delete(state.Reserved, nonce)
+	delete(state.Sent, nonce)
+	if nonce < state.Next && !state.isReleased(nonce) {
+		state.Released = append(state.Released, nonce)
+		sort.Slice(state.Released, func(i, j int) bool {
+			return state.Released[i] < state.Released[j]
+		})
+	}
+}
+
+// Store persists nonce state so that multiple processes can share a sender.  Update must be atomic
+// for a key (e.g. a row lock or compare-and-swap), and must not save the state if fn returns an error.
+type Store interface {
+	Update(ctx context.Context, key Key, fn func(state *State) error) error
+}
+
+// Manager allocates nonces for senders on one chain
+type Manager struct {
+	Chain  xc.NativeAsset
+	Source Source
+	Store  Store
+	// Reservations not sent within this time are released
+	ReservationTimeout time.Duration
+	// Sent txs missing from the txpool for longer than this are considered dropped, and their nonce reused
+	DroppedTimeout time.Duration
+	// Used for testing
+	Clock func() time.Time
+}
+
+var DefaultReservationTimeout = 5 * time.Minute
+var DefaultDroppedTimeout = 2 * time.Minute
+
+func NewManager(chain xc.NativeAsset, source Source, store Store) *Manager {
+	return &Manager{
+		Chain:              chain,
+		Source:             source,
+		Store:              store,
+		ReservationTimeout: DefaultReservationTimeout,
+		DroppedTimeout:     DefaultDroppedTimeout,
+		Clock:              time.Now,
+	}
+}
+
+// Reservation of a nonce, which must be either sent or released
+type Reservation struct {
+	Nonce   uint64
+	id      string
+	key     Key
+	manager *Manager
+}
+
+func (manager *Manager) update(ctx context.Context, key Key, fn func(state *State) error) error {
+	return manager.Store.Update(ctx, key, func(state *State) error {
+		// maps may be missing from persisted state
+		if state.Reserved == nil {
+			state.Reserved = map[uint64]*ReservedNonce{}
+		}
+		if state.Sent == nil {
+			state.Sent = map[uint64]int64{}
+		}
+		return fn(state)
+	})
+}
+
+// Reserve the next nonce to use for an address
+func (manager *Manager) Reserve(ctx context.Context, address xc.Address) (*Reservation, error) {
+	// query the node outside of the store update, to keep the critical section short
+	confirmed, err := manager.Source.GetNonce(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch nonce for %s: %v", address, err)
+	}
+	pending, err := manager.Source.FetchPendingNonces(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch pending nonces for %s: %v", address, err)
+	}
+
+	key := NewKey(manager.Chain, address)
+	id, err := newReservationId()
+	if err != nil {
+		return nil, err
+	}
+	var reserved uint64
+	err = manager.update(ctx, key, func(state *State) error {
+		manager.reconcile(state, confirmed, pending)
+		if len(state.Released) > 0 {
+			reserved = state.Released[0]
+			state.Released = state.Released[1:]
+		} else {
+			reserved = state.Next
+			state.Next++
+		}
+		state.Reserved[reserved] = &ReservedNonce{Id: id, Unix: manager.Clock().Unix()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{Nonce: reserved, id: id, key: key, manager: manager}, nil
+}
+
+// Reconcile the local state with the node.  Nonces below the confirmed nonce are dropped, and any nonce
+// between the confirmed nonce and the next nonce that isn't accounted for is a gap that must be filled.
+func (manager *Manager) reconcile(state *State, confirmed uint64, pending []uint64) {
+	now := manager.Clock()
+	inPool := map[uint64]bool{}
+	for _, nonce := range pending {
+		inPool[nonce] = true
+	}
+	for nonce := range state.Reserved {
+		if nonce < confirmed {
+			delete(state.Reserved, nonce)
+		}
+	}
+	for nonce := range state.Sent {
+		if nonce < confirmed {
+			delete(state.Sent, nonce)
+		}
+	}
+	released := []uint64{}
+	for _, nonce := range state.Released {
+		// the txpool may know of txs sent without the manager
+		if nonce >= confirmed && !inPool[nonce] {
+			released = append(released, nonce)
+		}
+	}
+	state.Released = released
+	if state.Next < confirmed {
+		state.Next = confirmed
+	}
+	for _, nonce := range pending {
+		if nonce >= state.Next {
+			state.Next = nonce + 1
+		}
+	}
+
+	for nonce, reserved := range state.Reserved {
+		if manager.ReservationTimeout > 0 && now.Sub(time.Unix(reserved.Unix, 0)) >= manager.ReservationTimeout {
+			// abandoned without being released
+			state.release(nonce)
+		}
+	}
+	for nonce, unix := range state.Sent {
+		if inPool[nonce] {
+			continue
+		}
+		if manager.DroppedTimeout > 0 && now.Sub(time.Unix(unix, 0)) >= manager.DroppedTimeout {
+			// dropped from the txpool, the nonce must be reused or later txs will be stuck
+			state.release(nonce)
+		}
+	}
+	for nonce := confirmed; nonce < state.Next; nonce++ {
+		_, isReserved := state.Reserved[nonce]
+		_, isSent := state.Sent[nonce]
+		if !isReserved && !isSent && !inPool[nonce] {
+			state.release(nonce)
+		}
+	}
+}
+
+// The nonce may have expired and been reserved again by someone else
+func (reservation *Reservation) held(state *State) bool {
+	reserved, ok := state.Reserved[reservation.Nonce]
+	return ok && reserved.Id == reservation.id
+}
+
+func newReservationId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// Sent marks the nonce as used by a broadcast tx
+func (reservation *Reservation) Sent(ctx context.Context) error {
+	return reservation.manager.update(ctx, reservation.key, func(state *State) error {
+		if !reservation.held(state) {
+			return fmt.Errorf("nonce %d for %s is no longer reserved", reservation.Nonce, reservation.key)
+		}
+		delete(state.Reserved, reservation.Nonce)
+		state.Sent[reservation.Nonce] = reservation.manager.Clock().Unix()
+		return nil
+	})
+}
+
+// Release the nonce when the send is abandoned, so it can be reused
+func (reservation *Reservation) Release(ctx context.Context) error {
+	return reservation.manager.update(ctx, reservation.key, func(state *State) error {
+		if reservation.held(state) {
+			state.release(reservation.Nonce)
+		}
+		return nil
+	})
+}
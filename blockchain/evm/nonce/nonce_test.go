@@ -0,0 +1,129 @@
+package nonce_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	evmclient "github.com/openweb3-io/crosschain/blockchain/evm/client"
+	"github.com/openweb3-io/crosschain/blockchain/evm/nonce"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+var _ nonce.Source = &evmclient.Client{}
+
+type fakeSource struct {
+	lock      sync.Mutex
+	confirmed uint64
+	pending   []uint64
+}
+
+func (source *fakeSource) GetNonce(ctx context.Context, from xc.Address) (uint64, error) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+	return source.confirmed, nil
+}
+
+func (source *fakeSource) FetchPendingNonces(ctx context.Context, from xc.Address) ([]uint64, error) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+	return append([]uint64{}, source.pending...), nil
+}
+
+const from = xc.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+
+func newManager(source *fakeSource) (*nonce.Manager, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	manager := nonce.NewManager(xc.ETH, source, nonce.NewMemoryStore())
+	manager.Clock = func() time.Time { return now }
+	return manager, &now
+}
+
+func reserve(t *testing.T, manager *nonce.Manager) *nonce.Reservation {
+	reservation, err := manager.Reserve(context.Background(), from)
+	require.NoError(t, err)
+	return reservation
+}
+
+func TestReserveConcurrently(t *testing.T) {
+	manager, _ := newManager(&fakeSource{confirmed: 10})
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	nonces := []uint64{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reservation, err := manager.Reserve(context.Background(), from)
+			require.NoError(t, err)
+			lock.Lock()
+			nonces = append(nonces, reservation.Nonce)
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for i, n := range nonces {
+		require.EqualValues(t, 10+i, n)
+	}
+}
+
+func TestReleaseReusesNonce(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newManager(&fakeSource{confirmed: 3})
+
+	first := reserve(t, manager)
+	second := reserve(t, manager)
+	require.EqualValues(t, 3, first.Nonce)
+	require.EqualValues(t, 4, second.Nonce)
+	require.NoError(t, second.Sent(ctx))
+
+	// abandoned, so the next reservation fills it
+	require.NoError(t, first.Release(ctx))
+	require.EqualValues(t, 3, reserve(t, manager).Nonce)
+	require.EqualValues(t, 5, reserve(t, manager).Nonce)
+
+	// can't send a nonce that was released
+	require.ErrorContains(t, first.Sent(ctx), "no longer reserved")
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{confirmed: 0}
+	manager, now := newManager(source)
+
+	r0 := reserve(t, manager)
+	r1 := reserve(t, manager)
+	r2 := reserve(t, manager)
+	require.NoError(t, r0.Sent(ctx))
+	require.NoError(t, r1.Sent(ctx))
+	require.NoError(t, r2.Sent(ctx))
+
+	// 0 confirmed, 2 in the txpool, and 1 hasn't shown up yet
+	source.confirmed = 1
+	source.pending = []uint64{2}
+	require.EqualValues(t, 3, reserve(t, manager).Nonce)
+
+	// 1 never shows up, so it was dropped and is a gap that must be filled
+	*now = now.Add(nonce.DefaultDroppedTimeout)
+	require.EqualValues(t, 1, reserve(t, manager).Nonce)
+
+	// another process sent nonces 5 and 6, leaving 4 as a gap
+	source.pending = []uint64{1, 2, 3, 5, 6}
+	require.EqualValues(t, 4, reserve(t, manager).Nonce)
+	require.EqualValues(t, 7, reserve(t, manager).Nonce)
+
+	// the node moved ahead of the local state
+	source.confirmed = 20
+	source.pending = nil
+	require.EqualValues(t, 20, reserve(t, manager).Nonce)
+
+	// reservations that are never sent or released expire
+	*now = now.Add(nonce.DefaultReservationTimeout)
+	require.EqualValues(t, 20, reserve(t, manager).Nonce)
+}
@@ -0,0 +1,47 @@
+package nonce
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps nonce state in memory, which is only safe to share within one process
+type MemoryStore struct {
+	lock   sync.Mutex
+	states map[Key]*State
+}
+
+var _ Store = &MemoryStore{}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		states: map[Key]*State{},
+	}
+}
+
+func (store *MemoryStore) Update(ctx context.Context, key Key, fn func(state *State) error) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	state, ok := store.states[key]
+	if !ok {
+		state = NewState()
+	}
+	// only save the changes if fn succeeds
+	updated := state.Clone()
+	if err := fn(updated); err != nil {
+		return err
+	}
+	store.states[key] = updated
+	return nil
+}
+
+func (store *MemoryStore) Get(key Key) (*State, bool) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	state, ok := store.states[key]
+	if !ok {
+		return nil, false
+	}
+	return state.Clone(), true
+}
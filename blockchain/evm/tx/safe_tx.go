@@ -0,0 +1,208 @@
+package tx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/safe"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+type SafeOperation uint8
+
+const (
+	SafeOperationCall         SafeOperation = 0
+	SafeOperationDelegateCall SafeOperation = 1
+)
+
+var (
+	// keccak256("EIP712Domain(uint256 chainId,address verifyingContract)"), used by Safe >= 1.3.0
+	SafeDomainSeparatorTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	SafeTxTypeHash              = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+)
+
+type SafeSignature struct {
+	Owner     common.Address
+	Signature []byte
+}
+
+// SafeTx is a transaction proposed to a Safe multisig.  Owners sign the EIP-712 safeTxHash, and
+// once the threshold is met, it can be executed by anyone (a relayer) calling execTransaction.
+type SafeTx struct {
+	Safe    common.Address
+	ChainId *big.Int
+
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      SafeOperation
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+
+	Owners     []common.Address
+	Threshold  uint64
+	Signatures []*SafeSignature
+}
+
+var _ xc_types.Tx = &SafeTx{}
+
+func orZero(value *big.Int) *big.Int {
+	if value == nil {
+		return big.NewInt(0)
+	}
+	return value
+}
+
+func (tx *SafeTx) DomainSeparator() common.Hash {
+	return crypto.Keccak256Hash(
+		SafeDomainSeparatorTypeHash.Bytes(),
+		common.LeftPadBytes(orZero(tx.ChainId).Bytes(), 32),
+		common.LeftPadBytes(tx.Safe.Bytes(), 32),
+	)
+}
+
+// SafeTxHash returns the EIP-712 hash that owners sign
+func (tx *SafeTx) SafeTxHash() common.Hash {
+	structHash := crypto.Keccak256Hash(
+		SafeTxTypeHash.Bytes(),
+		common.LeftPadBytes(tx.To.Bytes(), 32),
+		common.LeftPadBytes(orZero(tx.Value).Bytes(), 32),
+		crypto.Keccak256(tx.Data),
+		common.LeftPadBytes([]byte{byte(tx.Operation)}, 32),
+		common.LeftPadBytes(orZero(tx.SafeTxGas).Bytes(), 32),
+		common.LeftPadBytes(orZero(tx.BaseGas).Bytes(), 32),
+		common.LeftPadBytes(orZero(tx.GasPrice).Bytes(), 32),
+		common.LeftPadBytes(tx.GasToken.Bytes(), 32),
+		common.LeftPadBytes(tx.RefundReceiver.Bytes(), 32),
+		common.LeftPadBytes(orZero(tx.Nonce).Bytes(), 32),
+	)
+	return crypto.Keccak256Hash(
+		[]byte{0x19, 0x01},
+		tx.DomainSeparator().Bytes(),
+		structHash.Bytes(),
+	)
+}
+
+// Hash returns the safeTxHash, as the tx is not on chain until it's executed
+func (tx *SafeTx) Hash() xc_types.TxHash {
+	return xc_types.TxHash(tx.SafeTxHash().Hex())
+}
+
+// Sighashes returns the safeTxHash for each owner to sign
+func (tx *SafeTx) Sighashes() ([]xc_types.TxDataToSign, error) {
+	return []xc_types.TxDataToSign{tx.SafeTxHash().Bytes()}, nil
+}
+
+func (tx *SafeTx) isOwner(address common.Address) bool {
+	for _, owner := range tx.Owners {
+		if owner == address {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSignatures adds owner signatures of the safeTxHash.  Signatures from the same owner
+// are only counted once.  Nothing is added if any of the signatures are invalid.
+func (tx *SafeTx) AddSignatures(signatures ...xc_types.TxSignature) error {
+	hash := tx.SafeTxHash().Bytes()
+	added := []*SafeSignature{}
+	signed := map[common.Address]bool{}
+	for _, existing := range tx.Signatures {
+		signed[existing.Owner] = true
+	}
+	for _, signature := range signatures {
+		if len(signature) != crypto.SignatureLength {
+			return fmt.Errorf("invalid signature length %d, expected %d", len(signature), crypto.SignatureLength)
+		}
+		// recovery expects v to be 0 or 1
+		recoverable := common.CopyBytes(signature)
+		if recoverable[64] >= 27 {
+			recoverable[64] -= 27
+		}
+		pubkey, err := crypto.SigToPub(hash, recoverable)
+		if err != nil {
+			return fmt.Errorf("could not recover safe signer: %v", err)
+		}
+		owner := crypto.PubkeyToAddress(*pubkey)
+		if !tx.isOwner(owner) {
+			return fmt.Errorf("signer %s is not an owner of safe %s", owner.String(), tx.Safe.String())
+		}
+		if signed[owner] {
+			continue
+		}
+		signed[owner] = true
+		// the safe contract expects v to be 27 or 28 for ecdsa signatures
+		recoverable[64] += 27
+		added = append(added, &SafeSignature{
+			Owner:     owner,
+			Signature: recoverable,
+		})
+	}
+	tx.Signatures = append(tx.Signatures, added...)
+	return nil
+}
+
+func (tx *SafeTx) GetSignatures() []xc_types.TxSignature {
+	signatures := make([]xc_types.TxSignature, len(tx.Signatures))
+	for i, signature := range tx.Signatures {
+		signatures[i] = signature.Signature
+	}
+	return signatures
+}
+
+// ThresholdMet returns true once enough owners have signed to execute the tx
+func (tx *SafeTx) ThresholdMet() bool {
+	return tx.Threshold > 0 && uint64(len(tx.Signatures)) >= tx.Threshold
+}
+
+// EncodeSignatures concatenates the owner signatures, sorted by owner address as the safe contract requires
+func (tx *SafeTx) EncodeSignatures() []byte {
+	sorted := make([]*SafeSignature, len(tx.Signatures))
+	copy(sorted, tx.Signatures)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Owner.Bytes(), sorted[j].Owner.Bytes()) < 0
+	})
+	encoded := []byte{}
+	for _, signature := range sorted {
+		encoded = append(encoded, signature.Signature...)
+	}
+	return encoded
+}
+
+// ExecTransactionData returns the execTransaction call data, once the threshold is met
+func (tx *SafeTx) ExecTransactionData() ([]byte, error) {
+	if !tx.ThresholdMet() {
+		return nil, fmt.Errorf("safe tx has %d of %d required signatures", len(tx.Signatures), tx.Threshold)
+	}
+	return safe.SerializeExecTransaction(&safe.ExecTransaction{
+		To:             tx.To,
+		Value:          orZero(tx.Value),
+		Data:           tx.Data,
+		Operation:      uint8(tx.Operation),
+		SafeTxGas:      orZero(tx.SafeTxGas),
+		BaseGas:        orZero(tx.BaseGas),
+		GasPrice:       orZero(tx.GasPrice),
+		GasToken:       tx.GasToken,
+		RefundReceiver: tx.RefundReceiver,
+		Signatures:     tx.EncodeSignatures(),
+	})
+}
+
+// Serialize returns the execTransaction call data.  A safe tx can't be broadcast directly, it must be
+// executed through a regular tx sent to the safe.
+func (tx *SafeTx) Serialize() ([]byte, error) {
+	if tx.Safe == (common.Address{}) {
+		return []byte{}, errors.New("safe transaction not initialized")
+	}
+	return tx.ExecTransactionData()
+}
@@ -0,0 +1,140 @@
+package tx_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newSafeTx(owners ...common.Address) *tx.SafeTx {
+	return &tx.SafeTx{
+		Safe:      common.HexToAddress("0x5aFE3855358E112B5647B952709E6165e1c1eEEe"),
+		ChainId:   big.NewInt(11155111),
+		To:        common.HexToAddress("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F"),
+		Value:     big.NewInt(1000),
+		Data:      hexutil.MustDecode("0xdeadbeef"),
+		Nonce:     big.NewInt(7),
+		Owners:    owners,
+		Threshold: 2,
+	}
+}
+
+func TestSafeTxHash(t *testing.T) {
+	safeTx := newSafeTx()
+
+	// compare against go-ethereum's generic EIP-712 implementation
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain: apitypes.TypedDataDomain{
+			ChainId:           (*math.HexOrDecimal256)(safeTx.ChainId),
+			VerifyingContract: safeTx.Safe.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to":             safeTx.To.Hex(),
+			"value":          "1000",
+			"data":           "0xdeadbeef",
+			"operation":      "0",
+			"safeTxGas":      "0",
+			"baseGas":        "0",
+			"gasPrice":       "0",
+			"gasToken":       common.Address{}.Hex(),
+			"refundReceiver": common.Address{}.Hex(),
+			"nonce":          "7",
+		},
+	}
+	expected, _, err := apitypes.TypedDataAndHash(typedData)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(safeTx.SafeTxHash().Bytes()))
+
+	sighashes, err := safeTx.Sighashes()
+	require.NoError(t, err)
+	require.Len(t, sighashes, 1)
+	require.Equal(t, expected, []byte(sighashes[0]))
+	require.Equal(t, xc_types.TxHash(safeTx.SafeTxHash().Hex()), safeTx.Hash())
+}
+
+func TestSafeTxSignatures(t *testing.T) {
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	stranger, _ := crypto.GenerateKey()
+	owner1 := crypto.PubkeyToAddress(key1.PublicKey)
+	owner2 := crypto.PubkeyToAddress(key2.PublicKey)
+
+	safeTx := newSafeTx(owner1, owner2)
+	sighashes, _ := safeTx.Sighashes()
+	sign := func(key *ecdsa.PrivateKey) xc_types.TxSignature {
+		sig, err := crypto.Sign(sighashes[0], key)
+		require.NoError(t, err)
+		return sig
+	}
+
+	// not an owner
+	err := safeTx.AddSignatures(sign(stranger))
+	require.ErrorContains(t, err, "is not an owner")
+	// nothing is added when any signature is invalid
+	err = safeTx.AddSignatures(sign(key1), sign(stranger))
+	require.ErrorContains(t, err, "is not an owner")
+	require.Empty(t, safeTx.GetSignatures())
+
+	// threshold not yet met
+	require.NoError(t, safeTx.AddSignatures(sign(key1)))
+	// signing again doesn't count twice
+	require.NoError(t, safeTx.AddSignatures(sign(key1)))
+	require.False(t, safeTx.ThresholdMet())
+	_, err = safeTx.Serialize()
+	require.ErrorContains(t, err, "1 of 2 required signatures")
+
+	require.NoError(t, safeTx.AddSignatures(sign(key2)))
+	require.True(t, safeTx.ThresholdMet())
+	require.Len(t, safeTx.GetSignatures(), 2)
+
+	// signatures are sorted by owner, with v as 27 or 28
+	encoded := safeTx.EncodeSignatures()
+	require.Len(t, encoded, 130)
+	first, second := owner1, owner2
+	if bytes.Compare(owner1.Bytes(), owner2.Bytes()) > 0 {
+		first, second = owner2, owner1
+	}
+	for i, owner := range []common.Address{first, second} {
+		sig := common.CopyBytes(encoded[i*65 : (i+1)*65])
+		require.Contains(t, []byte{27, 28}, sig[64])
+		sig[64] -= 27
+		pub, err := crypto.SigToPub(sighashes[0], sig)
+		require.NoError(t, err)
+		require.Equal(t, owner, crypto.PubkeyToAddress(*pub))
+	}
+
+	data, err := safeTx.Serialize()
+	require.NoError(t, err)
+	// execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)
+	require.Equal(t, "6a761202", hex.EncodeToString(data[:4]))
+}
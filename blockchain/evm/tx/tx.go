@@ -3,15 +3,17 @@ package tx
 import (
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc1155"
 	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc20"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/erc721"
 	xc_types "github.com/openweb3-io/crosschain/types"
+	"go.uber.org/zap"
 )
 
 var ERC20 abi.ABI
@@ -83,30 +85,75 @@ func (tx *Tx) ParseTokenLogs(receipt *types.Receipt, nativeAsset xc_types.Native
 
 	loggedSources := []*xc_types.LegacyTxInfoEndpoint{}
 	loggedDestinations := []*xc_types.LegacyTxInfoEndpoint{}
+	addMovement := func(from common.Address, to common.Address, contract common.Address, amount *big.Int, tokenId *big.Int) {
+		var tokenIdMaybe *xc_types.BigInt
+		if tokenId != nil {
+			tokenIdMaybe = (*xc_types.BigInt)(tokenId)
+		}
+		loggedDestinations = append(loggedDestinations, &xc_types.LegacyTxInfoEndpoint{
+			Address:         xc_types.Address(to.String()),
+			ContractAddress: xc_types.ContractAddress(contract.String()),
+			Amount:          xc_types.BigInt(*amount),
+			NativeAsset:     nativeAsset,
+			TokenId:         tokenIdMaybe,
+		})
+		loggedSources = append(loggedSources, &xc_types.LegacyTxInfoEndpoint{
+			Address:         xc_types.Address(from.String()),
+			ContractAddress: xc_types.ContractAddress(contract.String()),
+			Amount:          xc_types.BigInt(*amount),
+			NativeAsset:     nativeAsset,
+			TokenId:         tokenIdMaybe,
+		})
+	}
+
 	for _, log := range receipt.Logs {
-		event, _ := ERC20.EventByID(log.Topics[0])
-		if event != nil {
-			fmt.Println("PARSE LOG", event.RawName)
+		if len(log.Topics) == 0 {
+			// anonymous event
+			continue
 		}
+		if erc721.IsTransfer(*log) {
+			// must check before erc20, as the Transfer event signature is the same
+			tf, err := erc721.ParseTransfer(*log)
+			if err != nil {
+				zap.S().Warn("could not parse erc721 log",
+					zap.String("tx_hash", receipt.TxHash.Hex()),
+					zap.Uint("log_index", log.Index),
+					zap.Error(err),
+				)
+				continue
+			}
+			addMovement(tf.From, tf.To, log.Address, big.NewInt(1), tf.TokenId)
+			continue
+		}
+		if event, _ := erc1155.EventByID(log.Topics[0]); event != nil {
+			tfs, err := erc1155.ParseTransfers(*log)
+			if err != nil {
+				zap.S().Warn("could not parse erc1155 log",
+					zap.String("tx_hash", receipt.TxHash.Hex()),
+					zap.Uint("log_index", log.Index),
+					zap.Error(err),
+				)
+				continue
+			}
+			for _, tf := range tfs {
+				addMovement(tf.From, tf.To, log.Address, tf.Value, tf.Id)
+			}
+			continue
+		}
+
+		event, _ := ERC20.EventByID(log.Topics[0])
 		if event != nil && event.RawName == "Transfer" {
 			erc20, _ := erc20.NewErc20(receipt.ContractAddress, nil)
 			tf, err := erc20.ParseTransfer(*log)
 			if err != nil {
-				fmt.Println("could not parse log: ", log.Index)
+				zap.S().Warn("could not parse erc20 log",
+					zap.String("tx_hash", receipt.TxHash.Hex()),
+					zap.Uint("log_index", log.Index),
+					zap.Error(err),
+				)
 				continue
 			}
-			loggedDestinations = append(loggedDestinations, &xc_types.LegacyTxInfoEndpoint{
-				Address:         xc_types.Address(tf.To.String()),
-				ContractAddress: xc_types.ContractAddress(log.Address.String()),
-				Amount:          xc_types.BigInt(*tf.Tokens),
-				NativeAsset:     nativeAsset,
-			})
-			loggedSources = append(loggedSources, &xc_types.LegacyTxInfoEndpoint{
-				Address:         xc_types.Address(tf.From.String()),
-				ContractAddress: xc_types.ContractAddress(log.Address.String()),
-				Amount:          xc_types.BigInt(*tf.Tokens),
-				NativeAsset:     nativeAsset,
-			})
+			addMovement(tf.From, tf.To, log.Address, tf.Tokens, nil)
 		}
 	}
 	return SourcesAndDests{
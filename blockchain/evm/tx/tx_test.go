@@ -1,8 +1,13 @@
 package tx_test
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/require"
@@ -25,3 +30,85 @@ func TestTxAddSignatureEmpty(t *testing.T) {
 	err := tx.AddSignatures([]xc_types.TxSignature{}...)
 	require.EqualError(t, err, "transaction not initialized")
 }
+
+func TestParseTokenLogs(t *testing.T) {
+	from := common.HexToAddress("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to := common.HexToAddress("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	operator := common.HexToAddress("0x273b437645Ba723299d07B1BdFFcf508bE64771f")
+	erc20Contract := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	erc721Contract := common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+	erc1155Contract := common.HexToAddress("0x495f947276749Ce646f68AC8c248420045cb7b5e")
+	transferTopic := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	singleTopic := crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	batchTopic := crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	uint256ArrTy, _ := abi.NewType("uint256[]", "", nil)
+	singleData, _ := abi.Arguments{{Type: uint256Ty}, {Type: uint256Ty}}.Pack(big.NewInt(7), big.NewInt(3))
+	batchData, _ := abi.Arguments{{Type: uint256ArrTy}, {Type: uint256ArrTy}}.Pack(
+		[]*big.Int{big.NewInt(8), big.NewInt(9)},
+		[]*big.Int{big.NewInt(10), big.NewInt(11)},
+	)
+
+	receipt := &types.Receipt{
+		Logs: []*types.Log{
+			{
+				Address: erc20Contract,
+				Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:    common.LeftPadBytes(big.NewInt(500).Bytes(), 32),
+			},
+			{
+				Address: erc721Contract,
+				Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(42))},
+			},
+			{
+				Address: erc1155Contract,
+				Topics:  []common.Hash{singleTopic, common.BytesToHash(operator.Bytes()), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:    singleData,
+			},
+			{
+				Address: erc1155Contract,
+				Topics:  []common.Hash{batchTopic, common.BytesToHash(operator.Bytes()), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:    batchData,
+			},
+			{
+				// anonymous
+				Address: erc20Contract,
+			},
+		},
+	}
+
+	movements := (&tx.Tx{}).ParseTokenLogs(receipt, xc_types.ETH)
+	require.Len(t, movements.Sources, 5)
+	require.Len(t, movements.Destinations, 5)
+
+	expected := []struct {
+		contract common.Address
+		amount   uint64
+		tokenId  *uint64
+	}{
+		{erc20Contract, 500, nil},
+		{erc721Contract, 1, ptr(42)},
+		{erc1155Contract, 3, ptr(7)},
+		{erc1155Contract, 10, ptr(8)},
+		{erc1155Contract, 11, ptr(9)},
+	}
+	for i, exp := range expected {
+		for _, endpoint := range []*xc_types.LegacyTxInfoEndpoint{movements.Sources[i], movements.Destinations[i]} {
+			require.EqualValues(t, exp.contract.String(), endpoint.ContractAddress)
+			require.EqualValues(t, exp.amount, endpoint.Amount.Uint64())
+			if exp.tokenId == nil {
+				require.Nil(t, endpoint.TokenId)
+			} else {
+				require.NotNil(t, endpoint.TokenId)
+				require.EqualValues(t, *exp.tokenId, endpoint.TokenId.Uint64())
+			}
+		}
+		require.EqualValues(t, from.String(), movements.Sources[i].Address)
+		require.EqualValues(t, to.String(), movements.Destinations[i].Address)
+	}
+}
+
+func ptr(v uint64) *uint64 {
+	return &v
+}
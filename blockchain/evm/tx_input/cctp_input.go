@@ -0,0 +1,35 @@
+package tx_input
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Input for burning USDC on this chain, to be minted on another CCTP domain
+type CctpBurnInput struct {
+	TxInput
+	TokenMessenger    xc.Address `json:"token_messenger"`
+	DestinationDomain uint32     `json:"destination_domain"`
+	// The recipient on the destination domain, as 32 bytes
+	MintRecipient common.Hash `json:"mint_recipient"`
+	// Optional; restricts who may receive the message on the destination domain
+	DestinationCaller common.Hash `json:"destination_caller,omitempty"`
+}
+
+var _ xc.TxInput = &CctpBurnInput{}
+
+func NewCctpBurnInput() *CctpBurnInput {
+	return &CctpBurnInput{}
+}
+
+// Input for receiving an attested CCTP message on this chain
+type CctpReceiveInput struct {
+	TxInput
+	MessageTransmitter xc.Address `json:"message_transmitter"`
+}
+
+var _ xc.TxInput = &CctpReceiveInput{}
+
+func NewCctpReceiveInput() *CctpReceiveInput {
+	return &CctpReceiveInput{}
+}
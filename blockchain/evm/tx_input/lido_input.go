@@ -0,0 +1,68 @@
+package tx_input
+
+import (
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Stake by submitting ether to the Lido stETH contract
+type LidoStakeInput struct {
+	TxInput
+	StEth xc.Address `json:"steth"`
+}
+
+var _ xc.TxVariantInput = &LidoStakeInput{}
+var _ xc.StakeTxInput = &LidoStakeInput{}
+
+func NewLidoStakeInput() *LidoStakeInput {
+	return &LidoStakeInput{}
+}
+
+func (*LidoStakeInput) GetVariant() xc.TxVariantInputType {
+	return xc.NewStakingInputType(xc.BlockchainEVM, "lido-submit")
+}
+
+// Mark as valid for staking transactions
+func (*LidoStakeInput) Staking() {}
+
+// Unstake by requesting withdrawal of stETH from the Lido withdrawal queue
+type LidoUnstakeInput struct {
+	TxInput
+	WithdrawalQueue xc.Address `json:"withdrawal_queue"`
+}
+
+var _ xc.TxVariantInput = &LidoUnstakeInput{}
+var _ xc.UnstakeTxInput = &LidoUnstakeInput{}
+
+func NewLidoUnstakeInput() *LidoUnstakeInput {
+	return &LidoUnstakeInput{}
+}
+
+func (*LidoUnstakeInput) GetVariant() xc.TxVariantInputType {
+	return xc.NewUnstakingInputType(xc.BlockchainEVM, "lido-request-withdrawals")
+}
+
+// Mark as valid for un-staking transactions
+func (*LidoUnstakeInput) Unstaking() {}
+
+// Claim finalized withdrawal requests from the Lido withdrawal queue
+type LidoWithdrawInput struct {
+	TxInput
+	WithdrawalQueue xc.Address  `json:"withdrawal_queue"`
+	RequestIds      []xc.BigInt `json:"request_ids"`
+	// Checkpoint hints for each request, from findCheckpointHints
+	Hints []xc.BigInt `json:"hints"`
+}
+
+var _ xc.TxVariantInput = &LidoWithdrawInput{}
+var _ xc.WithdrawTxInput = &LidoWithdrawInput{}
+
+func NewLidoWithdrawInput() *LidoWithdrawInput {
+	return &LidoWithdrawInput{}
+}
+
+func (*LidoWithdrawInput) GetVariant() xc.TxVariantInputType {
+	return xc.NewWithdrawingInputType(xc.BlockchainEVM, "lido-claim-withdrawals")
+}
+
+// Mark as valid for withdraw transactions
+func (*LidoWithdrawInput) Withdrawing() {}
@@ -0,0 +1,52 @@
+package tx_input
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Input for proposing a transaction to a Safe multisig.  Gas is paid by the relayer
+// executing the tx, so there are no fees to set here.
+type SafeTxInput struct {
+	Safe      xc.Address   `json:"safe"`
+	ChainId   xc.BigInt    `json:"chain_id"`
+	Nonce     uint64       `json:"nonce"`
+	Owners    []xc.Address `json:"owners"`
+	Threshold uint64       `json:"threshold"`
+}
+
+var _ xc.TxInput = &SafeTxInput{}
+
+func NewSafeTxInput() *SafeTxInput {
+	return &SafeTxInput{}
+}
+
+func (input *SafeTxInput) GetBlockchain() xc.Blockchain {
+	return xc.BlockchainEVM
+}
+
+func (input *SafeTxInput) SetGasFeePriority(other xc.GasFeePriority) error {
+	// the relayer's tx-input carries the fees
+	return nil
+}
+
+func (input *SafeTxInput) IndependentOf(other xc.TxInput) (independent bool) {
+	// different safe nonce means independence
+	if safeOther, ok := other.(*SafeTxInput); ok {
+		return common.HexToAddress(string(safeOther.Safe)) != common.HexToAddress(string(input.Safe)) || safeOther.Nonce != input.Nonce
+	}
+	return
+}
+
+func (input *SafeTxInput) SafeFromDoubleSend(others ...xc.TxInput) (safe bool) {
+	if !xc.SameTxInputTypes(input, others...) {
+		return false
+	}
+	// all same safe nonce means only one can execute
+	for _, other := range others {
+		if input.IndependentOf(other) {
+			return false
+		}
+	}
+	return true
+}
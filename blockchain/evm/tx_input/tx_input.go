@@ -25,6 +25,14 @@ type TxInput struct {
 
 	ChainId xc.BigInt `json:"chain_id,omitempty"`
 
+	// EIP-1559 fees for each priority, from the fee oracle.  If set, these are used
+	// instead of multiplying the tip when setting the priority.
+	FeeEstimates *FeeEstimates `json:"fee_estimates,omitempty"`
+
+	// L2 only: estimated fee for posting the tx data to L1, which is charged in addition to gas.
+	// This is informational and does not change the transaction.
+	L1Fee xc.BigInt `json:"l1_fee,omitempty"`
+
 	// legacy only
 	Prices []*Price `json:"prices,omitempty"`
 }
@@ -35,6 +43,9 @@ func init() {
 	registry.RegisterTxBaseInput(&TxInput{})
 	registry.RegisterTxVariantInput(&BatchDepositInput{})
 	registry.RegisterTxVariantInput(&ExitRequestInput{})
+	registry.RegisterTxVariantInput(&LidoStakeInput{})
+	registry.RegisterTxVariantInput(&LidoUnstakeInput{})
+	registry.RegisterTxVariantInput(&LidoWithdrawInput{})
 }
 
 func NewTxInput() *TxInput {
@@ -55,6 +66,14 @@ func (input *TxInput) SetGasFeePriority(other xc.GasFeePriority) error {
 	if err != nil {
 		return err
 	}
+	if estimate, ok := input.FeeEstimates.Get(other); ok {
+		input.GasTipCap = estimate.GasTipCap
+		input.GasFeeCap = estimate.GasFeeCap
+		// multiply the legacy gas price too
+		multipliedLegacyGasPrice := multiplier.Mul(decimal.NewFromBigInt(input.GasPrice.Int(), 0)).BigInt()
+		input.GasPrice = xc.BigInt(*multipliedLegacyGasPrice)
+		return nil
+	}
 	multipliedTipCap := multiplier.Mul(decimal.NewFromBigInt(input.GasTipCap.Int(), 0)).BigInt()
 	input.GasTipCap = xc.BigInt(*multipliedTipCap)
 
@@ -69,6 +88,40 @@ func (input *TxInput) SetGasFeePriority(other xc.GasFeePriority) error {
 	return nil
 }
 
+type PriorityFee struct {
+	GasTipCap xc.BigInt `json:"gas_tip_cap"`
+	GasFeeCap xc.BigInt `json:"gas_fee_cap"`
+}
+
+type FeeEstimates struct {
+	Low            PriorityFee `json:"low"`
+	Market         PriorityFee `json:"market"`
+	Aggressive     PriorityFee `json:"aggressive"`
+	VeryAggressive PriorityFee `json:"very_aggressive"`
+}
+
+// Get the fees for one of the enumerated priorities.  Custom priorities are not mapped.
+func (estimates *FeeEstimates) Get(priority xc.GasFeePriority) (PriorityFee, bool) {
+	if estimates == nil {
+		return PriorityFee{}, false
+	}
+	switch priority {
+	case xc.Low:
+		return estimates.Low, true
+	case xc.Market:
+		return estimates.Market, true
+	case xc.Aggressive:
+		return estimates.Aggressive, true
+	case xc.VeryAggressive:
+		return estimates.VeryAggressive, true
+	}
+	return PriorityFee{}, false
+}
+
+func (estimates *FeeEstimates) All() []*PriorityFee {
+	return []*PriorityFee{&estimates.Low, &estimates.Market, &estimates.Aggressive, &estimates.VeryAggressive}
+}
+
 func (input *TxInput) IndependentOf(other xc.TxInput) (independent bool) {
 	// different sequence means independence
 	if evmOther, ok := other.(*TxInput); ok {
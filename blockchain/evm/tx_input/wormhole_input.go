@@ -0,0 +1,17 @@
+package tx_input
+
+import (
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Input for redeeming a signed wormhole VAA with the token bridge on this chain
+type WormholeRedeemInput struct {
+	TxInput
+	TokenBridge xc.Address `json:"token_bridge"`
+}
+
+var _ xc.TxInput = &WormholeRedeemInput{}
+
+func NewWormholeRedeemInput() *WormholeRedeemInput {
+	return &WormholeRedeemInput{}
+}
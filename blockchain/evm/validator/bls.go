@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Domain separation tag for the proof-of-possession BLS scheme used by the beacon chain
+var SignatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+const SecretKeyLen = 32
+const PublicKeyLen = 48
+const SignatureLen = 96
+
+// BLS12-381 secret key of a validator
+type SecretKey struct {
+	scalar *big.Int
+	// EIP-2334 path the key was derived at, if known
+	Path string
+}
+
+func NewSecretKey(bz []byte) (*SecretKey, error) {
+	if len(bz) != SecretKeyLen {
+		return nil, fmt.Errorf("wrong length for secret key, expected %d, received %d", SecretKeyLen, len(bz))
+	}
+	scalar := new(big.Int).SetBytes(bz)
+	if scalar.Sign() == 0 || scalar.Cmp(fr.Modulus()) >= 0 {
+		return nil, fmt.Errorf("secret key is not in the range of the curve order")
+	}
+	return &SecretKey{scalar: scalar}, nil
+}
+
+func (sk *SecretKey) Bytes() []byte {
+	bz := make([]byte, SecretKeyLen)
+	return sk.scalar.FillBytes(bz)
+}
+
+// Compressed G1 public key
+func (sk *SecretKey) PublicKey() []byte {
+	_, _, g1, _ := bls12381.Generators()
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplication(&g1, sk.scalar)
+	bz := pk.Bytes()
+	return bz[:]
+}
+
+func (sk *SecretKey) PublicKeyHex() string {
+	return hex.EncodeToString(sk.PublicKey())
+}
+
+// Sign returns the compressed G2 signature of the message
+func (sk *SecretKey) Sign(message []byte) ([]byte, error) {
+	point, err := bls12381.HashToG2(message, SignatureDST)
+	if err != nil {
+		return nil, err
+	}
+	var sig bls12381.G2Affine
+	sig.ScalarMultiplication(&point, sk.scalar)
+	bz := sig.Bytes()
+	return bz[:], nil
+}
+
+// Verify a compressed signature against a compressed public key
+func Verify(publicKey []byte, message []byte, signature []byte) (bool, error) {
+	var pk bls12381.G1Affine
+	if _, err := pk.SetBytes(publicKey); err != nil {
+		return false, fmt.Errorf("invalid public key: %v", err)
+	}
+	if pk.IsInfinity() {
+		return false, fmt.Errorf("invalid public key: point at infinity")
+	}
+	var sig bls12381.G2Affine
+	if _, err := sig.SetBytes(signature); err != nil {
+		return false, fmt.Errorf("invalid signature: %v", err)
+	}
+	point, err := bls12381.HashToG2(message, SignatureDST)
+	if err != nil {
+		return false, err
+	}
+	_, _, g1, _ := bls12381.Generators()
+	var negG1 bls12381.G1Affine
+	negG1.Neg(&g1)
+	// e(pk, H(m)) == e(g1, sig)
+	return bls12381.PairingCheck([]bls12381.G1Affine{pk, negG1}, []bls12381.G2Affine{point, sig})
+}
@@ -0,0 +1,158 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/openweb3-io/crosschain/blockchain/evm/abi/stake_batch_deposit"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+var DomainDeposit = [4]byte{0x03, 0x00, 0x00, 0x00}
+
+// Prefix for withdrawal credentials that withdraw to an execution layer address
+const Eth1AddressWithdrawalPrefix = 0x01
+
+// 32 ETH, the deposit for one validator
+const DepositAmountGwei = 32_000_000_000
+
+type Network struct {
+	Name               string
+	GenesisForkVersion [4]byte
+}
+
+// Deposits are signed using the genesis fork version of each network, by chain id
+var Networks = map[int64]Network{
+	1:        {"mainnet", [4]byte{0x00, 0x00, 0x00, 0x00}},
+	17000:    {"holesky", [4]byte{0x01, 0x01, 0x70, 0x00}},
+	11155111: {"sepolia", [4]byte{0x90, 0x00, 0x00, 0x69}},
+	560048:   {"hoodi", [4]byte{0x10, 0x00, 0x09, 0x10}},
+}
+
+func NetworkForChainId(chainId int64) (Network, error) {
+	network, ok := Networks[chainId]
+	if !ok {
+		return Network{}, fmt.Errorf("no beacon chain network known for chain id %d", chainId)
+	}
+	return network, nil
+}
+
+// Eth1WithdrawalCredentials are the 0x01 credentials that withdraw to the address
+func Eth1WithdrawalCredentials(address common.Address) []byte {
+	creds := make([]byte, 32)
+	creds[0] = Eth1AddressWithdrawalPrefix
+	copy(creds[12:], address.Bytes())
+	return creds
+}
+
+func sum256(datas ...[]byte) []byte {
+	h := sha256.New()
+	for _, d := range datas {
+		_, _ = h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func uint64Leaf(value uint64) []byte {
+	leaf := make([]byte, 32)
+	binary.LittleEndian.PutUint64(leaf, value)
+	return leaf
+}
+
+// SSZ hash_tree_root of DepositMessage(pubkey, withdrawal_credentials, amount)
+func DepositMessageRoot(publicKey []byte, creds []byte, amountGwei uint64) []byte {
+	pubkeyRoot := sum256(publicKey, make([]byte, 16))
+	return sum256(
+		sum256(pubkeyRoot, creds),
+		sum256(uint64Leaf(amountGwei), make([]byte, 32)),
+	)
+}
+
+// compute_domain for deposits, which always use a zero genesis_validators_root
+func ComputeDepositDomain(forkVersion [4]byte) []byte {
+	forkDataRoot := sum256(forkVersion[:], make([]byte, 28), make([]byte, 32))
+	domain := make([]byte, 32)
+	copy(domain, DomainDeposit[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return domain
+}
+
+// SSZ hash_tree_root of SigningData(object_root, domain)
+func SigningRoot(objectRoot []byte, domain []byte) []byte {
+	return sum256(objectRoot, domain)
+}
+
+// Deposit data for one validator, in the format of the ethereum deposit cli
+type DepositData struct {
+	PubKey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	Signature             string `json:"signature"`
+	DepositMessageRoot    string `json:"deposit_message_root"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+	ForkVersion           string `json:"fork_version"`
+	NetworkName           string `json:"network_name"`
+}
+
+// NewDepositData signs a deposit of the amount for the validator key
+func NewDepositData(sk *SecretKey, creds []byte, amountGwei uint64, network Network) (*DepositData, error) {
+	if len(creds) != 32 {
+		return nil, fmt.Errorf("wrong length for withdraw credential, expected 32, received %d", len(creds))
+	}
+	publicKey := sk.PublicKey()
+	messageRoot := DepositMessageRoot(publicKey, creds, amountGwei)
+	signature, err := sk.Sign(SigningRoot(messageRoot, ComputeDepositDomain(network.GenesisForkVersion)))
+	if err != nil {
+		return nil, err
+	}
+	amount := xc.BigInt(*new(big.Int).Mul(new(big.Int).SetUint64(amountGwei), big.NewInt(1_000_000_000)))
+	dataRoot, err := stake_batch_deposit.CalculateDepositDataRoot(amount, publicKey, creds, signature)
+	if err != nil {
+		return nil, err
+	}
+	return &DepositData{
+		PubKey:                hex.EncodeToString(publicKey),
+		WithdrawalCredentials: hex.EncodeToString(creds),
+		Amount:                amountGwei,
+		Signature:             hex.EncodeToString(signature),
+		DepositMessageRoot:    hex.EncodeToString(messageRoot),
+		DepositDataRoot:       hex.EncodeToString(dataRoot),
+		ForkVersion:           hex.EncodeToString(network.GenesisForkVersion[:]),
+		NetworkName:           network.Name,
+	}, nil
+}
+
+func (data *DepositData) PublicKeyBytes() ([]byte, error) {
+	return hex.DecodeString(data.PubKey)
+}
+
+func (data *DepositData) SignatureBytes() ([]byte, error) {
+	return hex.DecodeString(data.Signature)
+}
+
+// VerifyDeposit checks the deposit signature commits to the withdrawal credentials and amount on the network.
+// The beacon chain ignores deposits with an invalid signature, and the deposited ether is lost.
+func VerifyDeposit(publicKey []byte, creds []byte, signature []byte, amountGwei uint64, network Network) error {
+	if len(publicKey) != PublicKeyLen {
+		return fmt.Errorf("wrong length for public key, expected %d, received %d", PublicKeyLen, len(publicKey))
+	}
+	if len(creds) != 32 {
+		return fmt.Errorf("wrong length for withdraw credential, expected 32, received %d", len(creds))
+	}
+	if len(signature) != SignatureLen {
+		return fmt.Errorf("wrong length for signature, expected %d, received %d", SignatureLen, len(signature))
+	}
+	messageRoot := DepositMessageRoot(publicKey, creds, amountGwei)
+	ok, err := Verify(publicKey, SigningRoot(messageRoot, ComputeDepositDomain(network.GenesisForkVersion)), signature)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("deposit signature for %x is not valid for the withdrawal credentials on %s", publicKey, network.Name)
+	}
+	return nil
+}
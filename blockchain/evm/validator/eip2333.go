@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// EIP-2334 purpose and coin type for validator keys
+const PathPurpose = 12381
+const PathCoinType = 3600
+
+// SigningKeyPath is the EIP-2334 path of the signing key of the validator at index
+func SigningKeyPath(index uint32) string {
+	return fmt.Sprintf("m/%d/%d/%d/0/0", PathPurpose, PathCoinType, index)
+}
+
+// SeedFromMnemonic converts a BIP-39 mnemonic to a seed, as done by the ethereum deposit cli
+func SeedFromMnemonic(mnemonic string, passphrase string) []byte {
+	mnemonic = norm.NFKD.String(strings.Join(strings.Fields(mnemonic), " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(mnemonic), []byte(salt), 2048, 64, sha512.New)
+}
+
+// DeriveMasterSK derives the EIP-2333 master key from a seed of at least 32 bytes
+func DeriveMasterSK(seed []byte) (*SecretKey, error) {
+	if len(seed) < 32 {
+		return nil, fmt.Errorf("seed must be at least 32 bytes, received %d", len(seed))
+	}
+	return &SecretKey{scalar: hkdfModR(seed, []byte{}), Path: "m"}, nil
+}
+
+// DeriveChildSK derives the EIP-2333 child key at index
+func DeriveChildSK(parent *SecretKey, index uint32) *SecretKey {
+	compressedLamportPK := parentSKToLamportPK(parent, index)
+	child := &SecretKey{scalar: hkdfModR(compressedLamportPK, []byte{})}
+	if parent.Path != "" {
+		child.Path = fmt.Sprintf("%s/%d", parent.Path, index)
+	}
+	return child
+}
+
+// DerivePath derives the key at an EIP-2334 path like "m/12381/3600/0/0/0"
+func DerivePath(seed []byte, path string) (*SecretKey, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("invalid path '%s', must start with 'm'", path)
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range parts[1:] {
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path '%s': %v", path, err)
+		}
+		sk = DeriveChildSK(sk, uint32(index))
+	}
+	return sk, nil
+}
+
+// DeriveSigningKey derives the signing key of the validator at index
+func DeriveSigningKey(seed []byte, index uint32) (*SecretKey, error) {
+	return DerivePath(seed, SigningKeyPath(index))
+}
+
+func hkdfModR(ikm []byte, keyInfo []byte) *big.Int {
+	const L = 48
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		digest := sha256.Sum256(salt)
+		salt = digest[:]
+		prk := hkdf.Extract(sha256.New, append(append([]byte{}, ikm...), 0), salt)
+		info := binary.BigEndian.AppendUint16(append([]byte{}, keyInfo...), L)
+		okm := make([]byte, L)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), okm); err != nil {
+			panic(err)
+		}
+		sk.SetBytes(okm)
+		sk.Mod(sk, fr.Modulus())
+	}
+	return sk
+}
+
+func ikmToLamportSK(ikm []byte, salt []byte) [][]byte {
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	okm := make([]byte, 32*255)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte{}), okm); err != nil {
+		panic(err)
+	}
+	chunks := make([][]byte, 255)
+	for i := range chunks {
+		chunks[i] = okm[i*32 : (i+1)*32]
+	}
+	return chunks
+}
+
+func parentSKToLamportPK(parent *SecretKey, index uint32) []byte {
+	salt := binary.BigEndian.AppendUint32(nil, index)
+	ikm := parent.Bytes()
+	notIkm := make([]byte, len(ikm))
+	for i := range ikm {
+		notIkm[i] = ^ikm[i]
+	}
+	lamportSK := append(ikmToLamportSK(ikm, salt), ikmToLamportSK(notIkm, salt)...)
+	lamportPK := sha256.New()
+	for _, chunk := range lamportSK {
+		digest := sha256.Sum256(chunk)
+		lamportPK.Write(digest[:])
+	}
+	return lamportPK.Sum(nil)
+}
@@ -0,0 +1,239 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+type Kdf string
+
+const KdfScrypt Kdf = "scrypt"
+const KdfPbkdf2 Kdf = "pbkdf2"
+
+// Default work factors, matching the ethereum deposit cli
+var ScryptN = 262144
+var Pbkdf2C = 262144
+
+// EIP-2335 keystore
+type Keystore struct {
+	Crypto      KeystoreCrypto `json:"crypto"`
+	Description string         `json:"description"`
+	PubKey      string         `json:"pubkey"`
+	Path        string         `json:"path"`
+	UUID        string         `json:"uuid"`
+	Version     int            `json:"version"`
+}
+
+type KeystoreCrypto struct {
+	Kdf      KeystoreModule `json:"kdf"`
+	Checksum KeystoreModule `json:"checksum"`
+	Cipher   KeystoreModule `json:"cipher"`
+}
+
+type KeystoreModule struct {
+	Function string                 `json:"function"`
+	Params   map[string]interface{} `json:"params"`
+	Message  string                 `json:"message"`
+}
+
+// Passwords are NFKD normalized with control codes removed
+func processPassword(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	return []byte(strings.Map(func(r rune) rune {
+		if r <= 0x1f || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, normalized))
+}
+
+func paramHex(params map[string]interface{}, name string) ([]byte, error) {
+	value, ok := params[name].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing keystore param '%s'", name)
+	}
+	return hex.DecodeString(value)
+}
+
+func paramInt(params map[string]interface{}, name string) (int, error) {
+	switch value := params[name].(type) {
+	case float64:
+		return int(value), nil
+	case int:
+		return value, nil
+	default:
+		return 0, fmt.Errorf("missing keystore param '%s'", name)
+	}
+}
+
+func (module *KeystoreModule) decryptionKey(password string) ([]byte, error) {
+	salt, err := paramHex(module.Params, "salt")
+	if err != nil {
+		return nil, err
+	}
+	dklen, err := paramInt(module.Params, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	if dklen < 32 {
+		return nil, fmt.Errorf("keystore dklen must be at least 32, received %d", dklen)
+	}
+	switch Kdf(module.Function) {
+	case KdfScrypt:
+		n, err := paramInt(module.Params, "n")
+		if err != nil {
+			return nil, err
+		}
+		r, err := paramInt(module.Params, "r")
+		if err != nil {
+			return nil, err
+		}
+		p, err := paramInt(module.Params, "p")
+		if err != nil {
+			return nil, err
+		}
+		return scrypt.Key(processPassword(password), salt, n, r, p, dklen)
+	case KdfPbkdf2:
+		c, err := paramInt(module.Params, "c")
+		if err != nil {
+			return nil, err
+		}
+		if prf, _ := module.Params["prf"].(string); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported keystore prf '%s'", prf)
+		}
+		return pbkdf2.Key(processPassword(password), salt, c, dklen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("unsupported keystore kdf '%s'", module.Function)
+	}
+}
+
+func aes128Ctr(key []byte, iv []byte, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+func checksum(decryptionKey []byte, cipherMessage []byte) []byte {
+	digest := sha256.Sum256(append(append([]byte{}, decryptionKey[16:32]...), cipherMessage...))
+	return digest[:]
+}
+
+// NewKeystore encrypts the secret key into an EIP-2335 keystore
+func NewKeystore(sk *SecretKey, password string, kdf Kdf) (*Keystore, error) {
+	salt := make([]byte, 32)
+	iv := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	kdfModule := KeystoreModule{
+		Function: string(kdf),
+		Params: map[string]interface{}{
+			"dklen": 32,
+			"salt":  hex.EncodeToString(salt),
+		},
+	}
+	switch kdf {
+	case KdfScrypt:
+		kdfModule.Params["n"] = ScryptN
+		kdfModule.Params["r"] = 8
+		kdfModule.Params["p"] = 1
+	case KdfPbkdf2:
+		kdfModule.Params["c"] = Pbkdf2C
+		kdfModule.Params["prf"] = "hmac-sha256"
+	default:
+		return nil, fmt.Errorf("unsupported keystore kdf '%s'", kdf)
+	}
+	decryptionKey, err := kdfModule.decryptionKey(password)
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := aes128Ctr(decryptionKey, iv, sk.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keystore{
+		Crypto: KeystoreCrypto{
+			Kdf: kdfModule,
+			Checksum: KeystoreModule{
+				Function: "sha256",
+				Params:   map[string]interface{}{},
+				Message:  hex.EncodeToString(checksum(decryptionKey, cipherMessage)),
+			},
+			Cipher: KeystoreModule{
+				Function: "aes-128-ctr",
+				Params: map[string]interface{}{
+					"iv": hex.EncodeToString(iv),
+				},
+				Message: hex.EncodeToString(cipherMessage),
+			},
+		},
+		PubKey:  sk.PublicKeyHex(),
+		Path:    sk.Path,
+		UUID:    uuid.NewString(),
+		Version: 4,
+	}, nil
+}
+
+// Decrypt the secret key, checking the password against the checksum
+func (keystore *Keystore) Decrypt(password string) (*SecretKey, error) {
+	if keystore.Version != 4 {
+		return nil, fmt.Errorf("unsupported keystore version %d", keystore.Version)
+	}
+	if keystore.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("unsupported keystore checksum '%s'", keystore.Crypto.Checksum.Function)
+	}
+	if keystore.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("unsupported keystore cipher '%s'", keystore.Crypto.Cipher.Function)
+	}
+	decryptionKey, err := keystore.Crypto.Kdf.decryptionKey(password)
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := hex.DecodeString(keystore.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore cipher message: %v", err)
+	}
+	expectedChecksum, err := hex.DecodeString(keystore.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore checksum: %v", err)
+	}
+	if !bytes.Equal(checksum(decryptionKey, cipherMessage), expectedChecksum) {
+		return nil, fmt.Errorf("invalid keystore password")
+	}
+	iv, err := paramHex(keystore.Crypto.Cipher.Params, "iv")
+	if err != nil {
+		return nil, err
+	}
+	secret, err := aes128Ctr(decryptionKey, iv, cipherMessage)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := NewSecretKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	sk.Path = keystore.Path
+	if keystore.PubKey != "" && !strings.EqualFold(keystore.PubKey, sk.PublicKeyHex()) {
+		return nil, fmt.Errorf("keystore public key does not match the decrypted secret key")
+	}
+	return sk, nil
+}
@@ -0,0 +1,160 @@
+package validator_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/openweb3-io/crosschain/blockchain/evm/validator"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(s string) []byte {
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+func TestDeriveEIP2333(t *testing.T) {
+	require := require.New(t)
+	// test case 0 from EIP-2333
+	seed := mustHex("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	master, err := validator.DeriveMasterSK(seed)
+	require.NoError(err)
+	require.Equal("6083874454709270928345386274498605044986640685124978867557563392430687146096", new(big.Int).SetBytes(master.Bytes()).String())
+
+	child := validator.DeriveChildSK(master, 0)
+	require.Equal("20397789859736650942317412262472558107875392172444076792671091975210932703118", new(big.Int).SetBytes(child.Bytes()).String())
+	require.Equal("m/0", child.Path)
+
+	signing, err := validator.DeriveSigningKey(seed, 3)
+	require.NoError(err)
+	require.Equal("m/12381/3600/3/0/0", signing.Path)
+	byPath, err := validator.DerivePath(seed, "m/12381/3600/3/0/0")
+	require.NoError(err)
+	require.Equal(signing.Bytes(), byPath.Bytes())
+
+	_, err = validator.DeriveMasterSK(seed[:31])
+	require.ErrorContains(err, "at least 32 bytes")
+	_, err = validator.DerivePath(seed, "12381/3600")
+	require.ErrorContains(err, "must start with 'm'")
+}
+
+func TestSign(t *testing.T) {
+	require := require.New(t)
+	// from the eth2 bls spec tests
+	sk, err := validator.NewSecretKey(mustHex("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3"))
+	require.NoError(err)
+	message := make([]byte, 32)
+	sig, err := sk.Sign(message)
+	require.NoError(err)
+	require.Equal(
+		"b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55",
+		hex.EncodeToString(sig),
+	)
+	ok, err := validator.Verify(sk.PublicKey(), message, sig)
+	require.NoError(err)
+	require.True(ok)
+
+	message[0] = 1
+	ok, err = validator.Verify(sk.PublicKey(), message, sig)
+	require.NoError(err)
+	require.False(ok)
+
+	_, err = validator.NewSecretKey(make([]byte, 32))
+	require.Error(err)
+}
+
+func TestKeystore(t *testing.T) {
+	require := require.New(t)
+	// pbkdf2 test vector from EIP-2335
+	vector := `{
+		"crypto": {
+			"kdf": {
+				"function": "pbkdf2",
+				"params": {"dklen": 32, "c": 262144, "prf": "hmac-sha256", "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"},
+				"message": ""
+			},
+			"checksum": {"function": "sha256", "params": {}, "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"},
+			"cipher": {"function": "aes-128-ctr", "params": {"iv": "264daa3f303d7259501c93d997d84fe6"}, "message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"}
+		},
+		"description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+		"pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+		"path": "m/12381/60/0/0",
+		"uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+		"version": 4
+	}`
+	keystore := &validator.Keystore{}
+	require.NoError(json.Unmarshal([]byte(vector), keystore))
+	sk, err := keystore.Decrypt("𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑")
+	require.NoError(err)
+	require.Equal("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f", hex.EncodeToString(sk.Bytes()))
+	require.Equal("m/12381/60/0/0", sk.Path)
+
+	_, err = keystore.Decrypt("testpassword")
+	require.ErrorContains(err, "invalid keystore password")
+
+	// round trip, with a cheap kdf
+	defaultPbkdf2C, defaultScryptN := validator.Pbkdf2C, validator.ScryptN
+	defer func() {
+		validator.Pbkdf2C, validator.ScryptN = defaultPbkdf2C, defaultScryptN
+	}()
+	validator.Pbkdf2C = 16
+	validator.ScryptN = 16
+	for _, kdf := range []validator.Kdf{validator.KdfPbkdf2, validator.KdfScrypt} {
+		created, err := validator.NewKeystore(sk, "password\x7f", kdf)
+		require.NoError(err)
+		require.Equal(keystore.PubKey, created.PubKey)
+		bz, err := json.Marshal(created)
+		require.NoError(err)
+		decoded := &validator.Keystore{}
+		require.NoError(json.Unmarshal(bz, decoded))
+		// control codes are stripped from passwords
+		decrypted, err := decoded.Decrypt("password")
+		require.NoError(err)
+		require.Equal(sk.Bytes(), decrypted.Bytes())
+	}
+}
+
+func TestDepositData(t *testing.T) {
+	require := require.New(t)
+	sk, err := validator.NewSecretKey(mustHex("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3"))
+	require.NoError(err)
+	owner := common.HexToAddress("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	creds := validator.Eth1WithdrawalCredentials(owner)
+	require.Equal("0100000000000000000000003ad57b83b2e3dc5648f32e98e386935a9b10bb9f", hex.EncodeToString(creds))
+
+	// well known mainnet deposit domain
+	mainnet, err := validator.NetworkForChainId(1)
+	require.NoError(err)
+	require.Equal("03000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9", hex.EncodeToString(validator.ComputeDepositDomain(mainnet.GenesisForkVersion)))
+
+	holesky, err := validator.NetworkForChainId(17000)
+	require.NoError(err)
+	deposit, err := validator.NewDepositData(sk, creds, validator.DepositAmountGwei, holesky)
+	require.NoError(err)
+	require.Equal(sk.PublicKeyHex(), deposit.PubKey)
+	require.Equal("01017000", deposit.ForkVersion)
+	require.Equal("holesky", deposit.NetworkName)
+	require.EqualValues(32_000_000_000, deposit.Amount)
+
+	messageRoot := validator.DepositMessageRoot(sk.PublicKey(), creds, validator.DepositAmountGwei)
+	require.Equal(hex.EncodeToString(messageRoot), deposit.DepositMessageRoot)
+	sig, err := deposit.SignatureBytes()
+	require.NoError(err)
+	ok, err := validator.Verify(sk.PublicKey(), validator.SigningRoot(messageRoot, validator.ComputeDepositDomain(holesky.GenesisForkVersion)), sig)
+	require.NoError(err)
+	require.True(ok)
+
+	// signature is only valid for the network it was made for
+	ok, err = validator.Verify(sk.PublicKey(), validator.SigningRoot(messageRoot, validator.ComputeDepositDomain(mainnet.GenesisForkVersion)), sig)
+	require.NoError(err)
+	require.False(ok)
+
+	_, err = validator.NetworkForChainId(56)
+	require.Error(err)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	evmaddress "github.com/openweb3-io/crosschain/blockchain/evm/address"
 	evmbuilder "github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	evmclient "github.com/openweb3-io/crosschain/blockchain/evm/client"
 	evminput "github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
 	xc "github.com/openweb3-io/crosschain/types"
@@ -49,49 +50,90 @@ func parseInput(input xc.TxInput) (*TxInput, error) {
 	}
 }
 
+func parseEvmInput(input xc.TxInput) (*evminput.TxInput, error) {
+	legacyInput, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return (*evminput.TxInput)(legacyInput), nil
+}
+
 func (*LegacyEvmTxBuilder) BuildTxWithPayload(chain *xc.ChainConfig, to xc.Address, value xc.BigInt, data []byte, inputRaw xc.TxInput) (xc.Tx, error) {
 	address, err := evmaddress.FromHex(to)
 	if err != nil {
 		return nil, err
 	}
-	chainID := new(big.Int).SetInt64(chain.ChainID)
 	input, err := parseInput(inputRaw)
 	if err != nil {
 		return nil, err
 	}
-	// Protection from setting very high gas tip
-	// TODO
+	var chainID *big.Int = input.ChainId.Int()
+	if input.ChainId.Uint64() == 0 {
+		chainID = new(big.Int).SetInt64(chain.ChainID)
+	}
+
+	// Protection from setting very high gas price
+	gasPrice := LimitGasPrice(chain, input.GasPrice)
 
 	return &Tx{
-		EthTx: types.NewTransaction(
-			input.Nonce,
-			address,
-			value.Int(),
-			input.GasLimit,
-			input.GasPrice.Int(),
-			data,
-		),
-		Signer: types.LatestSignerForChainID(chainID),
+		EthTx: types.NewTx(&types.LegacyTx{
+			Nonce:    input.Nonce,
+			GasPrice: gasPrice.Int(),
+			Gas:      input.GasLimit,
+			To:       &address,
+			Value:    value.Int(),
+			Data:     data,
+		}),
+		// legacy txs are replay protected using EIP-155
+		Signer: types.NewEIP155Signer(chainID),
 	}, nil
 }
 
+// Keep the gas price within the chain's min/max gas price (in gwei), if configured.
+// Unlike EIP-1559 chains, the max applies to the whole gas price and not just the tip.
+func LimitGasPrice(chain *xc.ChainConfig, gasPrice xc.BigInt) xc.BigInt {
+	minGasPrice := evmclient.GasPriceGweiToWei(chain.ChainMinGasPrice)
+	if gasPrice.Cmp(&minGasPrice) < 0 {
+		gasPrice = minGasPrice
+	}
+	if chain.ChainMaxGasPrice > 0 {
+		maxGasPrice := evmclient.GasPriceGweiToWei(chain.ChainMaxGasPrice)
+		if gasPrice.Cmp(&maxGasPrice) > 0 {
+			gasPrice = maxGasPrice
+		}
+	}
+	return gasPrice
+}
+
 func (txBuilder TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
 	// type cast back to evm input, which is expected by the evm builder
-	inputEvm := (*evminput.TxInput)(input.(*TxInput))
+	inputEvm, err := parseEvmInput(input)
+	if err != nil {
+		return nil, err
+	}
 	return evmbuilder.TxBuilder(txBuilder).NewTransfer(args, inputEvm)
 }
 
 func (txBuilder TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
-	inputEvm := (*evminput.TxInput)(input.(*TxInput))
+	inputEvm, err := parseEvmInput(input)
+	if err != nil {
+		return nil, err
+	}
 	return evmbuilder.TxBuilder(txBuilder).NewNativeTransfer(args, inputEvm)
 }
 
 func (txBuilder TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
-	inputEvm := (*evminput.TxInput)(input.(*TxInput))
+	inputEvm, err := parseEvmInput(input)
+	if err != nil {
+		return nil, err
+	}
 	return evmbuilder.TxBuilder(txBuilder).NewTokenTransfer(args, inputEvm)
 }
 
 func (txBuilder TxBuilder) NewTask(args *xcbuilder.TransferArgs, input xc.TxInput) (xc.Tx, error) {
-	inputEvm := (*evminput.TxInput)(input.(*TxInput))
+	inputEvm, err := parseEvmInput(input)
+	if err != nil {
+		return nil, err
+	}
 	return evmbuilder.TxBuilder(txBuilder).NewTask(args, inputEvm)
 }
@@ -1,10 +1,16 @@
 package evm_legacy_test
 
 import (
-	"fmt"
+	"encoding/hex"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/openweb3-io/crosschain/blockchain/evm"
 	"github.com/openweb3-io/crosschain/blockchain/evm/builder"
+	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
+	evminput "github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
 	"github.com/openweb3-io/crosschain/blockchain/evm_legacy"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
 	xc "github.com/openweb3-io/crosschain/types"
@@ -19,25 +25,168 @@ func TestBuilderLegacyTransfer(t *testing.T) {
 	from := xc.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
 	to := xc.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
 	amount := xc.NewBigIntFromUint64(100)
+	asset := &xc.TokenAssetConfig{Contract: "0x55d398326f99059fF775485246999027B3197955", Decimals: 18}
 
 	args, err := xcbuilder.NewTransferArgs(from, to, amount)
 	require.NoError(t, err)
+	tokenArgs, err := xcbuilder.NewTransferArgs(from, to, amount, xcbuilder.WithAsset(asset))
+	require.NoError(t, err)
 
 	input := evm_legacy.NewTxInput()
+	input.GasPrice = builder.GweiToWei(1)
 
-	fmt.Println("--- ", input.GetBlockchain())
-	fmt.Printf("--- %T\n", input)
-
-	input.GasTipCap = builder.GweiToWei(evm_legacy.DefaultMaxTipCapGwei - 1)
 	trans, err := b.NewTransfer(args, input)
 	require.NoError(t, err)
 	require.NotNil(t, trans)
 
-	trans, err = b.NewTokenTransfer(args, input)
+	trans, err = b.NewTokenTransfer(tokenArgs, input)
 	require.NoError(t, err)
 	require.NotNil(t, trans)
 
 	trans, err = b.NewNativeTransfer(args, input)
 	require.NoError(t, err)
 	require.NotNil(t, trans)
+
+	// the evm input is accepted as well
+	trans, err = b.NewTransfer(args, evminput.NewTxInput())
+	require.NoError(t, err)
+	require.NotNil(t, trans)
+}
+
+func TestTransferSetsMaxGasPrice(t *testing.T) {
+	b, _ := evm_legacy.NewTxBuilder(&xc.ChainConfig{})
+
+	from := xc.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to := xc.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	amount := xc.NewBigIntFromUint64(100)
+
+	args, err := xcbuilder.NewTransferArgs(from, to, amount)
+	require.NoError(t, err)
+
+	input := evm_legacy.NewTxInput()
+
+	// no max by default, as the gas price includes the base fee
+	input.GasPrice = builder.GweiToWei(1000)
+	trans, err := b.NewTransfer(args, input)
+	require.NoError(t, err)
+	require.EqualValues(t, builder.GweiToWei(1000).Uint64(), trans.(*tx.Tx).EthTx.GasPrice().Uint64())
+
+	// 100 is used instead of 1000
+	b, _ = evm_legacy.NewTxBuilder(&xc.ChainConfig{ChainMaxGasPrice: 100})
+	trans, err = b.NewTransfer(args, input)
+	require.NoError(t, err)
+	require.EqualValues(t, builder.GweiToWei(100).Uint64(), trans.(*tx.Tx).EthTx.GasPrice().Uint64())
+
+	// 3 is used instead of 1
+	b, _ = evm_legacy.NewTxBuilder(&xc.ChainConfig{ChainMinGasPrice: 3})
+	input.GasPrice = builder.GweiToWei(1)
+	trans, err = b.NewTransfer(args, input)
+	require.NoError(t, err)
+	require.EqualValues(t, builder.GweiToWei(3).Uint64(), trans.(*tx.Tx).EthTx.GasPrice().Uint64())
+
+	// fractional gwei limits are kept
+	b, _ = evm_legacy.NewTxBuilder(&xc.ChainConfig{ChainMinGasPrice: 0.1, ChainMaxGasPrice: 0.5})
+	input.GasPrice = builder.GweiToWei(1)
+	trans, err = b.NewTransfer(args, input)
+	require.NoError(t, err)
+	require.EqualValues(t, 500_000_000, trans.(*tx.Tx).EthTx.GasPrice().Uint64())
+	input.GasPrice = xc.NewBigIntFromUint64(1)
+	trans, err = b.NewTransfer(args, input)
+	require.NoError(t, err)
+	require.EqualValues(t, 100_000_000, trans.(*tx.Tx).EthTx.GasPrice().Uint64())
+}
+
+func TestTransferIsEIP155(t *testing.T) {
+	priv, err := crypto.HexToECDSA("8e812436a0e3323166e1f0e8ba79e19e217b2c4a53c970d4cca0cfb1078979df")
+	require.NoError(t, err)
+	from := xc.Address(crypto.PubkeyToAddress(priv.PublicKey).String())
+	to := xc.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(100))
+
+	vectors := []struct {
+		name       string
+		configId   int64
+		inputId    uint64
+		expectedId uint64
+	}{
+		{name: "chain id from input", configId: 1, inputId: 56, expectedId: 56},
+		{name: "chain id from config", configId: 56, inputId: 0, expectedId: 56},
+	}
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			b, _ := evm_legacy.NewTxBuilder(&xc.ChainConfig{ChainID: v.configId})
+			input := evm_legacy.NewTxInput()
+			input.Nonce = 6
+			input.GasLimit = 21_000
+			input.GasPrice = builder.GweiToWei(3)
+			input.ChainId = xc.NewBigIntFromUint64(v.inputId)
+
+			trans, err := b.NewTransfer(args, input)
+			require.NoError(t, err)
+			ethTx := trans.(*tx.Tx).EthTx
+			require.EqualValues(t, types.LegacyTxType, ethTx.Type())
+			require.EqualValues(t, 6, ethTx.Nonce())
+			require.EqualValues(t, 21_000, ethTx.Gas())
+
+			sighashes, err := trans.Sighashes()
+			require.NoError(t, err)
+			require.Len(t, sighashes, 1)
+			sig, err := evm.NewLocalSigner(priv).Sign(sighashes[0])
+			require.NoError(t, err)
+			require.NoError(t, trans.AddSignatures(sig))
+
+			signed := trans.(*tx.Tx).EthTx
+			require.True(t, signed.Protected())
+			require.EqualValues(t, v.expectedId, signed.ChainId().Uint64())
+			sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(int64(v.expectedId))), signed)
+			require.NoError(t, err)
+			require.Equal(t, string(from), sender.String())
+		})
+	}
+}
+
+func TestTokenTransfer(t *testing.T) {
+	b, _ := evm_legacy.NewTxBuilder(&xc.ChainConfig{})
+	from := xc.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to := xc.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	contract := xc.ContractAddress("0x55d398326f99059fF775485246999027B3197955")
+
+	asset := &xc.TokenAssetConfig{Contract: contract, Decimals: 18}
+	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(100), xcbuilder.WithAsset(asset))
+	trans, err := b.NewTransfer(args, evm_legacy.NewTxInput())
+	require.NoError(t, err)
+
+	ethTx := trans.(*tx.Tx).EthTx
+	expected, err := builder.BuildERC20Payload(to, xc.NewBigIntFromUint64(100))
+	require.NoError(t, err)
+	require.EqualValues(t, types.LegacyTxType, ethTx.Type())
+	require.EqualValues(t, 0, ethTx.Value().Uint64())
+	require.Equal(t, string(contract), ethTx.To().String())
+	require.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(ethTx.Data()))
+}
+
+func TestNftTransfer(t *testing.T) {
+	b, _ := evm_legacy.NewTxBuilder(&xc.ChainConfig{})
+	from := xc.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
+	to := xc.Address("0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F")
+	contract := xc.ContractAddress("0x495f947276749Ce646f68AC8c248420045cb7b5e")
+
+	asset := &xc.TokenAssetConfig{Contract: contract, Standard: xc.TokenStandardErc721}
+	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset), xcbuilder.WithTokenId(xc.NewBigIntFromUint64(1234)))
+	trans, err := b.NewTransfer(args, evm_legacy.NewTxInput())
+	require.NoError(t, err)
+
+	ethTx := trans.(*tx.Tx).EthTx
+	require.EqualValues(t, types.LegacyTxType, ethTx.Type())
+	// safeTransferFrom(address,address,uint256)
+	require.Equal(t, "42842e0e", hex.EncodeToString(ethTx.Data()[:4]))
+}
+
+func TestSetGasFeePriority(t *testing.T) {
+	input := evm_legacy.NewTxInput()
+	input.GasPrice = builder.GweiToWei(10)
+
+	err := input.SetGasFeePriority(xc.Aggressive)
+	require.NoError(t, err)
+	require.EqualValues(t, builder.GweiToWei(15).Uint64(), input.GasPrice.Uint64())
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	evmaddress "github.com/openweb3-io/crosschain/blockchain/evm/address"
 	evmclient "github.com/openweb3-io/crosschain/blockchain/evm/client"
 	"github.com/openweb3-io/crosschain/blockchain/evm/tx"
 	evminput "github.com/openweb3-io/crosschain/blockchain/evm/tx_input"
@@ -11,6 +12,7 @@ import (
 	xclient "github.com/openweb3-io/crosschain/client"
 	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc "github.com/openweb3-io/crosschain/types"
+	"go.uber.org/zap"
 )
 
 type Client struct {
@@ -70,15 +72,22 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	}
 	result.Nonce = nonce
 
+	// chain ID, needed for EIP-155 replay protection
+	chainId, err := client.evmClient.EthClient.ChainID(ctx)
+	if err != nil {
+		return result, fmt.Errorf("could not lookup chain_id: %v", err)
+	}
+	result.ChainId = xc.BigInt(*chainId)
+
 	if nativeAsset.NoGasFees {
 		result.GasPrice = zero
 	} else {
 		// legacy gas fees
-		baseFee, err := client.evmClient.EthClient.SuggestGasPrice(ctx)
+		gasPrice, err := client.FetchGasPrice(ctx, args.GetFrom())
 		if err != nil {
 			return result, err
 		}
-		result.GasPrice = xc.BigInt(*baseFee).ApplyGasPriceMultiplier(nativeAsset)
+		result.GasPrice = gasPrice
 	}
 	builder, err := NewTxBuilder(client.evmClient.Chain)
 	if err != nil {
@@ -97,6 +106,38 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	return result, nil
 }
 
+// Legacy gas price oracle.  Uses the node's suggested gas price with the chain multiplier, kept within
+// the chain's min/max gas price, and bumped enough to replace any pending tx from the same address.
+func (client *Client) FetchGasPrice(ctx context.Context, from xc.Address) (xc.BigInt, error) {
+	nativeAsset := client.evmClient.Chain
+	suggested, err := client.evmClient.EthClient.SuggestGasPrice(ctx)
+	if err != nil {
+		return xc.BigInt{}, err
+	}
+	gasPrice := LimitGasPrice(nativeAsset, xc.BigInt(*suggested).ApplyGasPriceMultiplier(nativeAsset))
+
+	fromAddr, _ := evmaddress.FromHex(from)
+	pendingTxInfo, err := client.evmClient.TxPoolContentFrom(ctx, fromAddr)
+	if err != nil {
+		zap.S().Warn("could not see pending tx pool",
+			zap.String("from", string(from)),
+			zap.Error(err),
+		)
+	} else if pending, ok := pendingTxInfo.InfoFor(string(from)); ok {
+		// nodes require at least a 10% increase to replace a pending tx
+		minGasPrice := xc.MultiplyByFloat(xc.BigInt(*pending.GasPrice.ToInt()), 1.15)
+		if gasPrice.Cmp(&minGasPrice) < 0 {
+			zap.S().Debug("replacing gas price because of pending tx",
+				zap.String("from", string(from)),
+				zap.String("old-tx", pending.Hash),
+				zap.String("new-gas-price", minGasPrice.String()),
+			)
+			gasPrice = minGasPrice
+		}
+	}
+	return gasPrice, nil
+}
+
 func (client *Client) FetchLegacyTxInput(ctx context.Context, from xc.Address, to xc.Address, asset xc.IAsset) (xc.TxInput, error) {
 	// No way to pass the amount in the input using legacy interface, so we estimate using min amount.
 	args, _ := xcbuilder.NewTransferArgs(from, to, xc.NewBigIntFromUint64(1), xcbuilder.WithAsset(asset))
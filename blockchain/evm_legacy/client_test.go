@@ -18,6 +18,7 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestFetchTxInput(t *testing.T) {
+	from := xc.Address("0x724435CC1B2821362c2CD425F2744Bd7347bf299")
 
 	vectors := []struct {
 		name       string
@@ -25,6 +26,7 @@ func TestFetchTxInput(t *testing.T) {
 		val        *evm_legacy.TxInput
 		err        string
 		multiplier float64
+		maxGwei    float64
 	}{
 		// Send ether normal tx
 		{
@@ -32,13 +34,18 @@ func TestFetchTxInput(t *testing.T) {
 			resp: []string{
 				// eth_getTransactionCount
 				`"0x6"`,
+				// eth_chainId
+				`"0x38"`,
 				// eth_gasPrice
 				`"0xba43b7400"`,
+				// txpool_contentFrom
+				`{"pending":{},"queued":{}}`,
 				// eth_estimateGas
 				`"0x52e4"`,
 			},
 			val: &evm_legacy.TxInput{
 				Nonce:    6,
+				ChainId:  xc.NewBigIntFromUint64(56),
 				GasLimit: 21220,
 				GasPrice: xc.NewBigIntFromUint64(50000000000),
 			},
@@ -50,28 +57,71 @@ func TestFetchTxInput(t *testing.T) {
 			resp: []string{
 				// eth_getTransactionCount
 				`"0x6"`,
+				// eth_chainId
+				`"0x38"`,
 				// eth_gasPrice
 				`"0xba43b7400"`,
+				// txpool_contentFrom
+				`{"pending":{},"queued":{}}`,
 				// eth_estimateGas
 				`"0x52e4"`,
 			},
 			val: &evm_legacy.TxInput{
 				Nonce:    6,
+				ChainId:  xc.NewBigIntFromUint64(56),
 				GasLimit: 21220,
 				GasPrice: xc.NewBigIntFromUint64(100000000000),
 			},
 			err:        "",
 			multiplier: 2.0,
 		},
+		{
+			name: "fetchTxInput legacy replaces pending tx",
+			resp: []string{
+				`"0x6"`,
+				`"0x38"`,
+				`"0xba43b7400"`,
+				`{"pending":{"6":{"from":"0x724435CC1B2821362c2CD425F2744Bd7347bf299","hash":"0x01","gasPrice":"0x174876e800"}},"queued":{}}`,
+				`"0x52e4"`,
+			},
+			val: &evm_legacy.TxInput{
+				Nonce:    6,
+				ChainId:  xc.NewBigIntFromUint64(56),
+				GasLimit: 21220,
+				// 15% more than the pending 100 gwei
+				GasPrice: xc.NewBigIntFromUint64(115000000000),
+			},
+			err:        "",
+			multiplier: 1.0,
+		},
+		{
+			name: "fetchTxInput legacy max gas price",
+			resp: []string{
+				`"0x6"`,
+				`"0x38"`,
+				`"0xba43b7400"`,
+				`{"pending":{},"queued":{}}`,
+				`"0x52e4"`,
+			},
+			val: &evm_legacy.TxInput{
+				Nonce:    6,
+				ChainId:  xc.NewBigIntFromUint64(56),
+				GasLimit: 21220,
+				GasPrice: xc.NewBigIntFromUint64(20000000000),
+			},
+			err:        "",
+			multiplier: 1.0,
+			maxGwei:    20,
+		},
 	}
 	for _, v := range vectors {
 		fmt.Println("testing ", v.name)
 		server, close := testtypes.MockJSONRPC(t, v.resp)
 		defer close()
-		cfg := &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVMLegacy, URL: server.URL, ChainGasMultiplier: v.multiplier}
+		cfg := &xc.ChainConfig{Chain: xc.ETH, Blockchain: xc.BlockchainEVMLegacy, URL: server.URL, ChainGasMultiplier: v.multiplier, ChainMaxGasPrice: v.maxGwei}
 		client, err := evm_legacy.NewClient(cfg)
 		require.NoError(t, err)
-		input, err := client.FetchLegacyTxInput(context.Background(), xc.Address(""), xc.Address(""), nil)
+		input, err := client.FetchLegacyTxInput(context.Background(), from, xc.Address(""), nil)
 		require.NoError(t, err)
 		if v.err != "" {
 			require.Equal(t, evm_legacy.TxInput{}, input)
@@ -2,7 +2,10 @@ package builder
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 
+	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 	ata "github.com/gagliardetto/solana-go/programs/associated-token-account"
 	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
@@ -19,9 +22,25 @@ import (
 // Max number of token transfers we can fit in a solana transaction,
 // when there's also a create ATA included.
 const MaxTokenTransfers = 20
+
+// With the source accounts in lookup tables, the limit on how many accounts
+// a transaction can lock applies rather than its size.
+const MaxTokenTransfersWithLookupTables = 48
+
 const MaxAccountUnstakes = 20
 const MaxAccountWithdraws = 20
 
+// The most bytes a transaction can be, including its signatures
+const MaxTransactionSize = 1232
+
+type TransactionTooLargeError struct {
+	Size int
+}
+
+func (err *TransactionTooLargeError) Error() string {
+	return fmt.Sprintf("transaction is %d bytes, which exceeds the limit of %d bytes", err.Size, MaxTransactionSize)
+}
+
 type TxBuilder struct {
 	Chain *xc_types.ChainConfig
 }
@@ -118,25 +137,32 @@ func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input types.T
 			createAta,
 		)
 	}
+	memo := getMemo(args, txInput)
+	if memo != "" && !txInput.MemoRequired {
+		// otherwise the memo precedes each transfer
+		instructions = append(instructions, newMemoInstruction(memo, accountFrom))
+	}
 	if len(txInput.SourceTokenAccounts) <= 1 {
 		// just send 1 instruction using the single ATA
 		instructions = append(instructions,
-			token.NewTransferCheckedInstruction(
+			newTokenTransferInstructions(
+				txInput,
 				args.GetAmount().Uint64(),
 				uint8(decimals),
 				ataFrom,
 				accountContract,
 				ataTo,
 				accountFrom,
-				[]solana.PublicKey{},
-			).Build(),
+				memo,
+			)...,
 		)
 	} else {
 		// Sometimes tokens can get put into any number of auxiliary accounts.
 		// So we need to spend them like UTXO. Here we'll just send a solana
 		// instruction for each one until we've reached the target balance.
 		zero := types.NewBigIntFromUint64(0)
-		remainingBalanceToSend := args.GetAmount()
+		// copy the amount, as subtracting from it would otherwise change the args
+		remainingBalanceToSend := types.BigInt(*new(big.Int).Set(args.GetAmount().Int()))
 		for _, tokenAcc := range txInput.SourceTokenAccounts {
 			amountToSend := remainingBalanceToSend
 			if tokenAcc.Balance.Cmp(&remainingBalanceToSend) < 0 {
@@ -145,22 +171,23 @@ func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input types.T
 			}
 			amountToSendUint := amountToSend.Uint64()
 			instructions = append(instructions,
-				token.NewTransferCheckedInstruction(
+				newTokenTransferInstructions(
+					txInput,
 					amountToSendUint,
 					uint8(decimals),
 					tokenAcc.Account,
 					accountContract,
 					ataTo,
 					accountFrom,
-					[]solana.PublicKey{},
-				).Build(),
+					memo,
+				)...,
 			)
 			remainingBalanceToSend = remainingBalanceToSend.Sub(&amountToSend)
 			if remainingBalanceToSend.Cmp(&zero) <= 0 {
 				// we've spent enough from source accounts to meet target balance
 				break
 			}
-			if len(instructions) > MaxTokenTransfers {
+			if len(instructions) > GetMaxTokenTransfers(txInput) {
 				return nil, errors.New("cannot send total amount in single tx, try sending smaller amount")
 			}
 		}
@@ -180,15 +207,65 @@ func (b *TxBuilder) NewTokenTransfer(args *xcbuilder.TransferArgs, input types.T
 	return b.buildSolanaTx(instructions, accountFrom, txInput)
 }
 
+// The memo of the transfer arguments, or else the one set on the input
+func getMemo(args *xcbuilder.TransferArgs, txInput *tx_input.TxInput) string {
+	if memo, ok := args.GetMemo(); ok {
+		return memo
+	}
+	return txInput.LegacyMemo
+}
+
+func GetMaxTokenTransfers(txInput *tx_input.TxInput) int {
+	if len(txInput.AddressLookupTables) > 0 {
+		return MaxTokenTransfersWithLookupTables
+	}
+	return MaxTokenTransfers
+}
+
+// Builds a legacy transaction, or a v0 transaction if any of its accounts can be loaded from the input's lookup tables.
+// The compute unit limit is set when the input has one, and the durable nonce is advanced when the input uses one.
 func (txBuilder TxBuilder) buildSolanaTx(instructions []solana.Instruction, accountFrom solana.PublicKey, txInput *tx_input.TxInput) (*tx.Tx, error) {
+	recentBlockHash := txInput.RecentBlockHash
+	if nonce := txInput.DurableNonce; nonce != nil {
+		if !nonce.Authority.Equals(accountFrom) {
+			return nil, fmt.Errorf("the nonce authority %s must be the fee payer %s", nonce.Authority, accountFrom)
+		}
+		// the nonce must be advanced by the first instruction
+		instructions = append([]solana.Instruction{
+			system.NewAdvanceNonceAccountInstruction(nonce.Account, solana.SysVarRecentBlockHashesPubkey, nonce.Authority).Build(),
+		}, instructions...)
+		recentBlockHash = nonce.Nonce
+	}
+	if txInput.ComputeUnitLimit > 0 {
+		// appended so the indices of the other instructions are unchanged
+		instructions = append(instructions,
+			compute_budget.NewSetComputeUnitLimitInstruction(min(txInput.ComputeUnitLimit, tx_input.MaxComputeUnitLimit)).Build(),
+		)
+	}
+	options := []solana.TransactionOption{
+		solana.TransactionPayer(accountFrom),
+	}
+	if len(txInput.AddressLookupTables) > 0 {
+		options = append(options, solana.TransactionAddressTables(txInput.GetAddressTables()))
+	}
 	tx1, err := solana.NewTransaction(
 		instructions,
-		txInput.RecentBlockHash,
-		solana.TransactionPayer(accountFrom),
+		recentBlockHash,
+		options...,
 	)
 	if err != nil {
 		return nil, err
 	}
+	message, err := tx1.Message.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var signatureCount []byte
+	bin.EncodeCompactU16Length(&signatureCount, int(tx1.Message.Header.NumRequiredSignatures))
+	size := len(signatureCount) + int(tx1.Message.Header.NumRequiredSignatures)*solana.SignatureLength + len(message)
+	if size > MaxTransactionSize {
+		return nil, &TransactionTooLargeError{Size: size}
+	}
 	return &tx.Tx{
 		SolTx: tx1,
 	}, nil
@@ -207,28 +284,22 @@ func (b *TxBuilder) NewNativeTransfer(args *xcbuilder.TransferArgs, input types.
 		return nil, err
 	}
 
-	instructions := []solana.Instruction{
+	instructions := []solana.Instruction{}
+	if memo := getMemo(args, txInput); memo != "" {
+		instructions = append(instructions, newMemoInstruction(memo, accountFrom))
+	}
+	instructions = append(instructions,
 		system.NewTransferInstruction(
 			args.GetAmount().Int().Uint64(),
 			accountFrom,
 			accountTo,
 		).Build(),
-	}
+	)
 
 	prioprityFee := txInput.GetLimitedPrioritizationFee(b.Chain)
 	if prioprityFee > 0 {
 		instructions = append(instructions, compute_budget.NewSetComputeUnitPriceInstruction(prioprityFee).Build())
 	}
 
-	solTx, err := solana.NewTransaction(
-		instructions,
-		txInput.RecentBlockHash,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &tx.Tx{
-		SolTx: solTx,
-	}, nil
+	return b.buildSolanaTx(instructions, accountFrom, txInput)
 }
@@ -0,0 +1,154 @@
+package builder
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	"github.com/openweb3-io/crosschain/bridge/cctp"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// NewCctpBurn burns USDC from the sender's token account, to be minted to the mint recipient on the destination domain
+func (txBuilder TxBuilder) NewCctpBurn(args *xcbuilder.TransferArgs, input *tx_input.CctpBurnInput) (xc_types.Tx, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil || asset.GetContract() == "" {
+		return nil, fmt.Errorf("the usdc token contract is required to burn")
+	}
+	owner, err := solana.PublicKeyFromBase58(string(args.GetFrom()))
+	if err != nil {
+		return nil, err
+	}
+	mint, err := solana.PublicKeyFromBase58(string(asset.GetContract()))
+	if err != nil {
+		return nil, err
+	}
+	if input.MintRecipient.IsZero() {
+		return nil, fmt.Errorf("mint recipient is required")
+	}
+	if input.MessageSentEventKey == nil {
+		return nil, fmt.Errorf("a key for the message account is required")
+	}
+	burnTokenAccountStr, err := solana_types.FindAssociatedTokenAddress(string(args.GetFrom()), string(asset.GetContract()), input.TokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	burnTokenAccount := solana.MustPublicKeyFromBase58(burnTokenAccountStr)
+	tokenProgram := input.TokenProgram
+	if tokenProgram.IsZero() {
+		tokenProgram = solana.TokenProgramID
+	}
+
+	// amount u64, destination domain u32, mint recipient
+	data := solana_types.AnchorDiscriminator("deposit_for_burn")
+	data = binary.LittleEndian.AppendUint64(data, args.GetAmount().Uint64())
+	data = binary.LittleEndian.AppendUint32(data, input.DestinationDomain)
+	data = append(data, input.MintRecipient[:]...)
+
+	tokenMessengerMinter := solana_types.CctpTokenMessengerMinterProgramID
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(owner).SIGNER(),
+		solana.Meta(owner).SIGNER().WRITE(),
+		solana.Meta(solana_types.CctpSenderAuthorityPda()),
+		solana.Meta(burnTokenAccount).WRITE(),
+		solana.Meta(solana_types.CctpMessageTransmitterPda()).WRITE(),
+		solana.Meta(solana_types.CctpTokenMessengerPda()),
+		solana.Meta(solana_types.CctpRemoteTokenMessengerPda(input.DestinationDomain)),
+		solana.Meta(solana_types.CctpTokenMinterPda()),
+		solana.Meta(solana_types.CctpLocalTokenPda(mint)).WRITE(),
+		solana.Meta(mint).WRITE(),
+		solana.Meta(input.MessageSentEventKey.PublicKey()).SIGNER().WRITE(),
+		solana.Meta(solana_types.CctpMessageTransmitterProgramID),
+		solana.Meta(tokenMessengerMinter),
+		solana.Meta(tokenProgram),
+		solana.Meta(solana.SystemProgramID),
+		solana.Meta(solana_types.EventAuthorityPda(tokenMessengerMinter)),
+		solana.Meta(tokenMessengerMinter),
+	}
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+		solana.NewInstruction(tokenMessengerMinter, accounts, data),
+	}
+	tx, err := txBuilder.buildSolanaTx(instructions, owner, &input.TxInput)
+	if err != nil {
+		return nil, err
+	}
+	// The message is written to a new account, which must sign also
+	tx.AddTransientSigner(input.MessageSentEventKey)
+	return tx, nil
+}
+
+// NewCctpReceive receives an attested message with the message transmitter, minting the USDC
+func (txBuilder TxBuilder) NewCctpReceive(from xc_types.Address, message []byte, attestation []byte, input *tx_input.CctpReceiveInput) (xc_types.Tx, error) {
+	if len(attestation) == 0 {
+		return nil, fmt.Errorf("the message must be attested before it can be received")
+	}
+	msg, err := cctp.ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.DestinationDomain.IsSolana() {
+		return nil, fmt.Errorf("cctp message is for domain %d, not solana", msg.DestinationDomain)
+	}
+	burn, err := msg.BurnMessage()
+	if err != nil {
+		return nil, err
+	}
+	payer, err := solana.PublicKeyFromBase58(string(from))
+	if err != nil {
+		return nil, err
+	}
+	if input.LocalToken.IsZero() {
+		return nil, fmt.Errorf("the local token is required to receive")
+	}
+	tokenProgram := input.TokenProgram
+	if tokenProgram.IsZero() {
+		tokenProgram = solana.TokenProgramID
+	}
+
+	// message and attestation as vecs
+	data := solana_types.AnchorDiscriminator("receive_message")
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(message)))
+	data = append(data, message...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(attestation)))
+	data = append(data, attestation...)
+
+	sourceDomain := uint32(msg.SourceDomain)
+	messageTransmitter := solana_types.CctpMessageTransmitterProgramID
+	tokenMessengerMinter := solana_types.CctpTokenMessengerMinterProgramID
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(payer).SIGNER().WRITE(),
+		solana.Meta(payer).SIGNER(),
+		solana.Meta(solana_types.CctpMessageTransmitterAuthorityPda(tokenMessengerMinter)),
+		solana.Meta(solana_types.CctpMessageTransmitterPda()),
+		solana.Meta(solana_types.CctpUsedNoncesPda(sourceDomain, msg.SolanaFirstNonce())).WRITE(),
+		solana.Meta(tokenMessengerMinter),
+		solana.Meta(solana.SystemProgramID),
+		solana.Meta(solana_types.EventAuthorityPda(messageTransmitter)),
+		solana.Meta(messageTransmitter),
+		// passed on to the token messenger minter to mint
+		solana.Meta(solana_types.CctpTokenMessengerPda()),
+		solana.Meta(solana_types.CctpRemoteTokenMessengerPda(sourceDomain)),
+		solana.Meta(solana_types.CctpTokenMinterPda()).WRITE(),
+		solana.Meta(solana_types.CctpLocalTokenPda(input.LocalToken)).WRITE(),
+		solana.Meta(solana_types.CctpTokenPairPda(sourceDomain, burn.BurnToken)),
+		solana.Meta(solana.PublicKeyFromBytes(burn.MintRecipient[:])).WRITE(),
+		solana.Meta(solana_types.CctpCustodyPda(input.LocalToken)).WRITE(),
+		solana.Meta(tokenProgram),
+		solana.Meta(solana_types.EventAuthorityPda(tokenMessengerMinter)),
+		solana.Meta(tokenMessengerMinter),
+	}
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+		solana.NewInstruction(messageTransmitter, accounts, data),
+	}
+	return txBuilder.buildSolanaTx(instructions, payer, &input.TxInput)
+}
@@ -0,0 +1,105 @@
+package builder_test
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/solana/types"
+	"github.com/openweb3-io/crosschain/bridge/cctp"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCctpBurn(t *testing.T) {
+	require := require.New(t)
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	usdc := &xc_types.TokenAssetConfig{Contract: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", Decimals: 6}
+	args, _ := xcbuilder.NewTransferArgs(from, "0x3ad57b83B2E3dC5648F32e98e386935A9B10bb9F", xc_types.NewBigIntFromUint64(2_500_000), xcbuilder.WithAsset(usdc))
+
+	eventKey, _ := solana.NewRandomPrivateKey()
+	mintRecipient, _ := cctp.MintRecipient(cctp.DomainEthereum, args.GetTo(), "")
+	input := tx_input.NewCctpBurnInput()
+	input.RecentBlockHash = solana.MustHashFromBase58("DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK")
+	input.DestinationDomain = uint32(cctp.DomainEthereum)
+	input.MintRecipient = solana.PublicKeyFromBytes(mintRecipient[:])
+	input.MessageSentEventKey = eventKey
+
+	trans, err := txBuilder.NewCctpBurn(args, input)
+	require.NoError(err)
+	solTx := trans.(*Tx).SolTx
+	// the owner and the new message account sign
+	require.EqualValues(2, solTx.Message.Header.NumRequiredSignatures)
+	require.Len(solTx.Message.Instructions, 2)
+	instruction := solTx.Message.Instructions[1]
+	program, _ := solTx.Message.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+	require.Equal(types.CctpTokenMessengerMinterProgramID, program)
+
+	data := []byte(instruction.Data)
+	require.Equal(types.AnchorDiscriminator("deposit_for_burn"), data[:8])
+	require.EqualValues(2_500_000, binary.LittleEndian.Uint64(data[8:16]))
+	require.EqualValues(0, binary.LittleEndian.Uint32(data[16:20]))
+	require.Equal(mintRecipient[:], data[20:52])
+
+	burnAccount, _, _ := solana.FindAssociatedTokenAddress(solana.MustPublicKeyFromBase58(string(from)), solana.MustPublicKeyFromBase58(string(usdc.Contract)))
+	accounts, err := instruction.ResolveInstructionAccounts(&solTx.Message)
+	require.NoError(err)
+	require.Len(accounts, 17)
+	require.Equal(burnAccount, accounts[3].PublicKey)
+	require.Equal(types.CctpRemoteTokenMessengerPda(0), accounts[6].PublicKey)
+	require.Equal(eventKey.PublicKey(), accounts[10].PublicKey)
+	require.True(accounts[10].IsSigner)
+}
+
+func TestCctpReceive(t *testing.T) {
+	require := require.New(t)
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	payer := xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	usdc := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+
+	burnToken, _ := cctp.AddressBytes32(cctp.DomainEthereum, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	mintRecipient, _ := cctp.MintRecipient(cctp.DomainSolana, payer, xc_types.ContractAddress(usdc.String()))
+	burn := &cctp.BurnMessage{BurnToken: burnToken, MintRecipient: mintRecipient, Amount: big.NewInt(10)}
+	msg := &cctp.Message{
+		SourceDomain:      cctp.DomainEthereum,
+		DestinationDomain: cctp.DomainSolana,
+		Nonce:             6402,
+		Body:              burn.Bytes(),
+	}
+	input := tx_input.NewCctpReceiveInput()
+	input.RecentBlockHash = solana.MustHashFromBase58("DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK")
+	_, err := txBuilder.NewCctpReceive(payer, msg.Bytes(), []byte{1}, input)
+	require.ErrorContains(err, "local token is required")
+	input.LocalToken = usdc
+
+	trans, err := txBuilder.NewCctpReceive(payer, msg.Bytes(), []byte{1}, input)
+	require.NoError(err)
+	solTx := trans.(*Tx).SolTx
+	instruction := solTx.Message.Instructions[1]
+	program, _ := solTx.Message.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+	require.Equal(types.CctpMessageTransmitterProgramID, program)
+
+	data := []byte(instruction.Data)
+	require.Equal(types.AnchorDiscriminator("receive_message"), data[:8])
+	require.EqualValues(len(msg.Bytes()), binary.LittleEndian.Uint32(data[8:12]))
+	require.Equal(msg.Bytes(), data[12:12+len(msg.Bytes())])
+
+	accounts, err := instruction.ResolveInstructionAccounts(&solTx.Message)
+	require.NoError(err)
+	require.Len(accounts, 19)
+	// nonces 6401 to 12800 share an account
+	require.Equal(types.CctpUsedNoncesPda(0, 6401), accounts[4].PublicKey)
+	require.Equal(types.CctpTokenPairPda(0, burnToken), accounts[13].PublicKey)
+	require.Equal(solana.PublicKeyFromBytes(mintRecipient[:]), accounts[14].PublicKey)
+	require.True(accounts[14].IsWritable)
+
+	// the message must be for solana
+	msg.DestinationDomain = cctp.DomainBase
+	_, err = txBuilder.NewCctpReceive(payer, msg.Bytes(), []byte{1}, input)
+	require.ErrorContains(err, "not solana")
+}
@@ -0,0 +1,77 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+// returns the program of each instruction
+func getInstructionPrograms(t *testing.T, tx *Tx) []solana.PublicKey {
+	message := tx.SolTx.Message
+	programs := []solana.PublicKey{}
+	for _, ix := range message.Instructions {
+		program, err := message.Program(ix.ProgramIDIndex)
+		require.NoError(t, err)
+		programs = append(programs, program)
+	}
+	return programs
+}
+
+func TestNewNativeTransferWithMemo(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	args, err := xcbuilder.NewTransferArgs(
+		xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb"),
+		xc_types.Address("BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11"),
+		xc_types.NewBigIntFromUint64(1200000),
+		xcbuilder.WithMemo("deposit 1234"),
+	)
+	require.NoError(t, err)
+
+	tx, err := txBuilder.NewTransfer(args, &tx_input.TxInput{})
+	require.NoError(t, err)
+	require.Equal(t, []solana.PublicKey{solana.MemoProgramID, solana.SystemProgramID}, getInstructionPrograms(t, tx.(*Tx)))
+	require.Equal(t, "deposit 1234", tx.(*Tx).GetMemo())
+
+	// the memo may be set on the input instead
+	args, err = xcbuilder.NewTransferArgs(args.GetFrom(), args.GetTo(), args.GetAmount())
+	require.NoError(t, err)
+	input := &tx_input.TxInput{}
+	input.SetMemo("deposit 5678")
+	tx, err = txBuilder.NewTransfer(args, input)
+	require.NoError(t, err)
+	require.Equal(t, "deposit 5678", tx.(*Tx).GetMemo())
+
+	// no memo
+	tx, err = txBuilder.NewTransfer(args, &tx_input.TxInput{})
+	require.NoError(t, err)
+	require.Equal(t, []solana.PublicKey{solana.SystemProgramID}, getInstructionPrograms(t, tx.(*Tx)))
+	require.Equal(t, "", tx.(*Tx).GetMemo())
+}
+
+func TestNewTokenTransferWithMemo(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	input := &tx_input.TxInput{
+		TokenProgram: solana.TokenProgramID,
+		SourceTokenAccounts: []*tx_input.TokenAccount{
+			{Account: solana.MustPublicKeyFromBase58("6UwHtXqjvGCLsBvdSvNtbyFTQBALbGzuBhGkQrCvFHHB"), Balance: xc_types.NewBigIntFromUint64(600_000)},
+			{Account: solana.MustPublicKeyFromBase58("CiDwVBFgWV9E5MvXWoLgnEgn2hK7rJikbvfWavzAQz3"), Balance: xc_types.NewBigIntFromUint64(600_000)},
+		},
+	}
+	tx, err := txBuilder.NewTransfer(newToken2022TransferArgs(t, xcbuilder.WithMemo("deposit 1234")), input)
+	require.NoError(t, err)
+	// a single memo precedes the transfers
+	require.Equal(t, []solana.PublicKey{solana.MemoProgramID, solana.TokenProgramID, solana.TokenProgramID}, getInstructionPrograms(t, tx.(*Tx)))
+	require.Equal(t, "deposit 1234", tx.(*Tx).GetMemo())
+
+	// when the destination requires memos, each transfer is preceded by one
+	input.MemoRequired = true
+	tx, err = txBuilder.NewTransfer(newToken2022TransferArgs(t, xcbuilder.WithMemo("deposit 1234")), input)
+	require.NoError(t, err)
+	require.Equal(t, []solana.PublicKey{solana.MemoProgramID, solana.TokenProgramID, solana.MemoProgramID, solana.TokenProgramID}, getInstructionPrograms(t, tx.(*Tx)))
+}
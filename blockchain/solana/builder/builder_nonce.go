@@ -0,0 +1,44 @@
+package builder
+
+import (
+	"github.com/gagliardetto/solana-go"
+	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// NewCreateNonceAccount creates and initializes a durable nonce account, with the sender as its authority.
+func (txBuilder TxBuilder) NewCreateNonceAccount(from xc_types.Address, input *tx_input.CreateNonceAccountInput) (xc_types.Tx, error) {
+	authority, err := solana.PublicKeyFromBase58(string(from))
+	if err != nil {
+		return nil, err
+	}
+	nonceAccount := input.NonceKey.PublicKey()
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+		system.NewCreateAccountInstruction(
+			input.RentExemptBalance.Uint64(),
+			solana_types.NonceAccountSize,
+			solana.SystemProgramID,
+			authority,
+			nonceAccount,
+		).Build(),
+		system.NewInitializeNonceAccountInstruction(
+			authority,
+			nonceAccount,
+			solana.SysVarRecentBlockHashesPubkey,
+			solana.SysVarRentPubkey,
+		).Build(),
+	}
+	tx, err := txBuilder.buildSolanaTx(instructions, authority, &input.TxInput)
+	if err != nil {
+		return nil, err
+	}
+	// the new account must sign to be created
+	tx.AddTransientSigner(input.NonceKey)
+	return tx, nil
+}
@@ -0,0 +1,109 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCreateNonceAccount(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	nonceKey, _ := solana.NewRandomPrivateKey()
+
+	input := &tx_input.CreateNonceAccountInput{
+		TxInput: tx_input.TxInput{
+			RecentBlockHash:   solana.MustHashFromBase58("DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK"),
+			PrioritizationFee: xc_types.NewBigIntFromUint64(100000),
+		},
+		NonceKey:          nonceKey,
+		RentExemptBalance: xc_types.NewBigIntFromUint64(1447680),
+	}
+	tx, err := txBuilder.NewCreateNonceAccount(from, input)
+	require.NoError(t, err)
+
+	createAccounts := tx.(*Tx).GetCreateAccounts()
+	require.Len(t, createAccounts, 1)
+	require.Equal(t, nonceKey.PublicKey(), createAccounts[0].NewAccount)
+	require.EqualValues(t, 1447680, createAccounts[0].Lamports)
+
+	inits := tx.(*Tx).GetInitializeNonceAccounts()
+	require.Len(t, inits, 1)
+	require.Equal(t, solana.MustPublicKeyFromBase58(string(from)), *inits[0].Authorized)
+	require.Equal(t, nonceKey.PublicKey(), inits[0].GetNonceAccount().PublicKey)
+
+	// the nonce account signs along with the sender
+	err = tx.AddSignatures(make([]byte, 64))
+	require.NoError(t, err)
+	require.Len(t, tx.(*Tx).SolTx.Signatures, 2)
+}
+
+func TestNewTransferWithDurableNonce(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	to := xc_types.Address("BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11")
+	nonceAccount := solana.MustPublicKeyFromBase58("6UwHtXqjvGCLsBvdSvNtbyFTQBALbGzuBhGkQrCvFHHB")
+	nonce := solana.MustHashFromBase58("8opHzTAnfzRpPEx21XtnrVTX28YQuCpAjcn1PczScKh")
+
+	args, err := xcbuilder.NewTransferArgs(from, to, xc_types.NewBigIntFromUint64(1200000))
+	require.NoError(t, err)
+	input := &tx_input.TxInput{
+		RecentBlockHash:   solana.MustHashFromBase58("DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK"),
+		PrioritizationFee: xc_types.NewBigIntFromUint64(100),
+		ComputeUnitLimit:  1000,
+		DurableNonce: &tx_input.DurableNonce{
+			Account:   nonceAccount,
+			Authority: solana.MustPublicKeyFromBase58(string(from)),
+			Nonce:     nonce,
+		},
+	}
+	tx, err := txBuilder.NewTransfer(args, input)
+	require.NoError(t, err)
+	solTx := tx.(*Tx).SolTx
+
+	// the nonce is used in place of the block hash, and advanced first
+	require.Equal(t, nonce, solTx.Message.RecentBlockhash)
+	require.Len(t, solTx.Message.Instructions, 4)
+	program, err := solTx.Message.Program(solTx.Message.Instructions[0].ProgramIDIndex)
+	require.NoError(t, err)
+	require.Equal(t, solana.SystemProgramID, program)
+	advances := tx.(*Tx).GetAdvanceNonceAccounts()
+	require.Len(t, advances, 1)
+	require.Equal(t, nonceAccount, advances[0].GetNonceAccount().PublicKey)
+	require.Len(t, tx.(*Tx).GetSystemTransfers(), 1)
+
+	// the authority must be the sender, as it signs to advance the nonce
+	input.DurableNonce.Authority = solana.MustPublicKeyFromBase58(string(to))
+	_, err = txBuilder.NewTransfer(args, input)
+	require.ErrorContains(t, err, "nonce authority")
+}
+
+func TestParseNonceAccount(t *testing.T) {
+	raw := make([]byte, solana_types.NonceAccountSize)
+	raw[0] = 1 // current version
+	raw[4] = 1 // initialized
+	authority := solana.MustPublicKeyFromBase58("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	nonce := solana.MustHashFromBase58("8opHzTAnfzRpPEx21XtnrVTX28YQuCpAjcn1PczScKh")
+	copy(raw[8:40], authority[:])
+	copy(raw[40:72], nonce[:])
+	raw[72] = 0x88
+	raw[73] = 0x13 // 5000 lamports per signature
+
+	account, err := solana_types.ParseNonceAccount(raw)
+	require.NoError(t, err)
+	require.Equal(t, authority, account.AuthorizedPubkey)
+	require.Equal(t, nonce[:], account.Nonce[:])
+	require.EqualValues(t, 5000, account.FeeCalculator.LamportsPerSignature)
+
+	raw[4] = 0
+	_, err = solana_types.ParseNonceAccount(raw)
+	require.ErrorContains(t, err, "not initialized")
+	_, err = solana_types.ParseNonceAccount(raw[:40])
+	require.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package builder
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Token program instructions used to empty and close token accounts.  These are built directly as
+// the token accounts may belong to either token program.
+const (
+	tokenInstructionCloseAccount    = 9
+	tokenInstructionTransferChecked = 12
+	tokenInstructionBurnChecked     = 15
+)
+
+// The token accounts closed by a transaction and the rent they return
+type ReclaimedRent struct {
+	Accounts []solana.PublicKey
+	Lamports xc_types.BigInt
+}
+
+// NewReclaimRent closes token accounts of the sender to return their rent to it.  Dust is first transferred to
+// the dust destination's token account for the mint when the input has one, and is burned otherwise.  As many
+// accounts are closed as fit in a transaction, in the order of the input, and those closed are returned.
+func (txBuilder TxBuilder) NewReclaimRent(from xc_types.Address, input *tx_input.ReclaimRentInput) (xc_types.Tx, *ReclaimedRent, error) {
+	owner, err := solana.PublicKeyFromBase58(string(from))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(input.TokenAccounts) == 0 {
+		return nil, nil, fmt.Errorf("no token accounts to close")
+	}
+	instructions := []solana.Instruction{}
+	priorityFee := input.GetLimitedPrioritizationFee(txBuilder.Chain)
+	if priorityFee > 0 {
+		instructions = append(instructions, compute_budget.NewSetComputeUnitPriceInstruction(priorityFee).Build())
+	}
+
+	reclaimed := &ReclaimedRent{
+		Lamports: xc_types.NewBigIntFromUint64(0),
+	}
+	var reclaimTx *tx.Tx
+	for _, account := range input.TokenAccounts {
+		next := append([]solana.Instruction{}, instructions...)
+		if account.HasDust() {
+			if destination, ok := input.DustDestinationAccounts[account.Mint.String()]; ok {
+				next = append(next, newTransferCheckedInstruction(account, destination, owner))
+			} else {
+				next = append(next, newBurnCheckedInstruction(account, owner))
+			}
+		}
+		next = append(next, newCloseTokenAccountInstruction(account.TokenProgram, account.Account, owner, owner))
+
+		built, err := txBuilder.buildSolanaTx(next, owner, &input.TxInput)
+		if err != nil {
+			var tooLarge *TransactionTooLargeError
+			if errors.As(err, &tooLarge) && len(reclaimed.Accounts) > 0 {
+				// the rest are left for another transaction
+				break
+			}
+			return nil, nil, err
+		}
+		instructions = next
+		reclaimTx = built
+		reclaimed.Accounts = append(reclaimed.Accounts, account.Account)
+		reclaimed.Lamports = reclaimed.Lamports.Add(&account.Lamports)
+	}
+	return reclaimTx, reclaimed, nil
+}
+
+func newCloseTokenAccountInstruction(tokenProgram solana.PublicKey, account solana.PublicKey, destination solana.PublicKey, owner solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(
+		tokenProgram,
+		solana.AccountMetaSlice{
+			solana.Meta(account).WRITE(),
+			solana.Meta(destination).WRITE(),
+			solana.Meta(owner).SIGNER(),
+		},
+		[]byte{tokenInstructionCloseAccount},
+	)
+}
+
+func newBurnCheckedInstruction(account *tx_input.ReclaimableTokenAccount, owner solana.PublicKey) solana.Instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{tokenInstructionBurnChecked}, account.Balance.Uint64())
+	data = append(data, account.Decimals)
+	return solana.NewInstruction(
+		account.TokenProgram,
+		solana.AccountMetaSlice{
+			solana.Meta(account.Account).WRITE(),
+			solana.Meta(account.Mint).WRITE(),
+			solana.Meta(owner).SIGNER(),
+		},
+		data,
+	)
+}
+
+func newTransferCheckedInstruction(account *tx_input.ReclaimableTokenAccount, destination solana.PublicKey, owner solana.PublicKey) solana.Instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{tokenInstructionTransferChecked}, account.Balance.Uint64())
+	data = append(data, account.Decimals)
+	return solana.NewInstruction(
+		account.TokenProgram,
+		solana.AccountMetaSlice{
+			solana.Meta(account.Account).WRITE(),
+			solana.Meta(account.Mint),
+			solana.Meta(destination).WRITE(),
+			solana.Meta(owner).SIGNER(),
+		},
+		data,
+	)
+}
@@ -0,0 +1,105 @@
+package builder_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newReclaimableTokenAccount(balance uint64) *tx_input.ReclaimableTokenAccount {
+	account, _ := solana.NewRandomPrivateKey()
+	return &tx_input.ReclaimableTokenAccount{
+		Account:      account.PublicKey(),
+		Mint:         solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"),
+		TokenProgram: solana.TokenProgramID,
+		Balance:      xc_types.NewBigIntFromUint64(balance),
+		Decimals:     6,
+		Lamports:     xc_types.NewBigIntFromUint64(2039280),
+	}
+}
+
+// returns the instruction type of each token program instruction
+func getTokenInstructionTypes(t *testing.T, tx *Tx) []byte {
+	message := tx.SolTx.Message
+	types := []byte{}
+	for _, ix := range message.Instructions {
+		program, err := message.Program(ix.ProgramIDIndex)
+		require.NoError(t, err)
+		if program.Equals(solana.TokenProgramID) || program.Equals(solana.Token2022ProgramID) {
+			types = append(types, ix.Data[0])
+		}
+	}
+	return types
+}
+
+func TestNewReclaimRent(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+
+	empty := newReclaimableTokenAccount(0)
+	dust := newReclaimableTokenAccount(15)
+	wrappedSol := newReclaimableTokenAccount(5_000_000)
+	wrappedSol.IsNative = true
+	wrappedSol.Mint = solana.SolMint
+	wrappedSol.Lamports = xc_types.NewBigIntFromUint64(7_039_280)
+
+	input := &tx_input.ReclaimRentInput{
+		TxInput:       newStakeManagementTxInput(),
+		TokenAccounts: []*tx_input.ReclaimableTokenAccount{wrappedSol, empty, dust},
+	}
+	tx, reclaimed, err := txBuilder.NewReclaimRent(from, input)
+	require.NoError(t, err)
+	require.Equal(t, []solana.PublicKey{wrappedSol.Account, empty.Account, dust.Account}, reclaimed.Accounts)
+	require.EqualValues(t, 7_039_280+2*2039280, reclaimed.Lamports.Uint64())
+	// dust is burned before closing
+	require.Equal(t, []byte{9, 9, 15, 9}, getTokenInstructionTypes(t, tx.(*Tx)))
+	burn := tx.(*Tx).SolTx.Message.Instructions[3]
+	require.EqualValues(t, 15, binary.LittleEndian.Uint64(burn.Data[1:9]))
+	require.EqualValues(t, 6, burn.Data[9])
+
+	// dust is transferred when there's a destination
+	destination := solana.MustPublicKeyFromBase58("9BQ6j3ZL6QB5ugJ8XoTUy1trEtr1FpvyLhMkb6dDBVyL")
+	input.DustDestinationAccounts = map[string]solana.PublicKey{
+		dust.Mint.String(): destination,
+	}
+	tx, _, err = txBuilder.NewReclaimRent(from, input)
+	require.NoError(t, err)
+	require.Equal(t, []byte{9, 9, 12, 9}, getTokenInstructionTypes(t, tx.(*Tx)))
+	transfer := tx.(*Tx).SolTx.Message.Instructions[3]
+	require.Equal(t, destination, tx.(*Tx).SolTx.Message.AccountKeys[transfer.Accounts[2]])
+
+	input.TokenAccounts = nil
+	_, _, err = txBuilder.NewReclaimRent(from, input)
+	require.ErrorContains(t, err, "no token accounts")
+}
+
+func TestNewReclaimRentBatches(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+
+	input := &tx_input.ReclaimRentInput{
+		TxInput: newStakeManagementTxInput(),
+	}
+	for i := 0; i < 100; i++ {
+		input.TokenAccounts = append(input.TokenAccounts, newReclaimableTokenAccount(0))
+	}
+	tx, reclaimed, err := txBuilder.NewReclaimRent(from, input)
+	require.NoError(t, err)
+	// only some fit in a transaction
+	closed := len(reclaimed.Accounts)
+	require.Greater(t, closed, 10)
+	require.Less(t, closed, 100)
+	require.Len(t, getTokenInstructionTypes(t, tx.(*Tx)), closed)
+	require.EqualValues(t, uint64(closed)*2039280, reclaimed.Lamports.Uint64())
+	for i, account := range reclaimed.Accounts {
+		require.Equal(t, input.TokenAccounts[i].Account, account)
+	}
+	bz, err := tx.Serialize()
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(bz), builder.MaxTransactionSize)
+}
@@ -0,0 +1,214 @@
+package builder
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+const MaxAccountMerges = 20
+const MaxAccountAuthorizes = 20
+
+// Stake program instructions missing from the stake program library
+const (
+	stakeInstructionAuthorize = 1
+	stakeInstructionMerge     = 7
+	stakeInstructionMoveStake = 16
+)
+
+// The stake program's redelegate instruction was never activated, and moving stake requires the destination
+// to be inactive or delegated to the same validator.
+var ErrMoveToValidatorNotSupported = errors.New("moving stake to another validator is not supported, unstake it and stake to the new validator once it is inactive")
+
+var _ xcbuilder.StakeManagement = &TxBuilder{}
+
+func (txBuilder TxBuilder) ManageStake(args xcbuilder.StakeArgs, input xc_types.StakeManagementTxInput) (xc_types.Tx, error) {
+	// the sender/signer is the staking authority & withdraw authority
+	stakingAuth, err := solana.PublicKeyFromBase58(string(args.GetFrom()))
+	if err != nil {
+		return nil, err
+	}
+	switch input := input.(type) {
+	case *tx_input.MergeStakeInput:
+		return txBuilder.mergeStake(stakingAuth, input)
+	case *tx_input.SplitStakeInput:
+		return txBuilder.splitStake(args, stakingAuth, input)
+	case *tx_input.MoveStakeInput:
+		return txBuilder.moveStake(args, stakingAuth, input)
+	case *tx_input.AuthorizeStakeInput:
+		return txBuilder.authorizeStake(stakingAuth, input)
+	case *tx_input.CloseStakeInput:
+		return txBuilder.closeStake(stakingAuth, input)
+	default:
+		return nil, fmt.Errorf("unsupported stake management input %T", input)
+	}
+}
+
+func (txBuilder TxBuilder) mergeStake(stakingAuth solana.PublicKey, input *tx_input.MergeStakeInput) (*tx.Tx, error) {
+	if len(input.Sources) == 0 {
+		return nil, fmt.Errorf("no stake accounts found to merge")
+	}
+	if len(input.Sources) > MaxAccountMerges {
+		return nil, fmt.Errorf("cannot merge %d stake accounts in a single tx, the most is %d", len(input.Sources), MaxAccountMerges)
+	}
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+	}
+	for _, source := range input.Sources {
+		if source.Equals(input.Destination) {
+			return nil, fmt.Errorf("cannot merge stake account %s into itself", source)
+		}
+		instructions = append(instructions, newMergeStakeInstruction(input.Destination, source, stakingAuth))
+	}
+	return txBuilder.buildSolanaTx(instructions, stakingAuth, &input.TxInput)
+}
+
+func (txBuilder TxBuilder) splitStake(args xcbuilder.StakeArgs, stakingAuth solana.PublicKey, input *tx_input.SplitStakeInput) (*tx.Tx, error) {
+	amount := args.GetAmount().Uint64()
+	if amount < RentExemptLamportsThreshold {
+		return nil, fmt.Errorf("amount to split is below the rent exempt threshold (%s SOL)", RentExemptLamportsThresholdHuman)
+	}
+	newStakeAccount := input.StakingKey.PublicKey()
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+		// the new account must be rent exempt before stake is split into it
+		system.NewCreateAccountInstruction(input.RentExemptReserve.Uint64(), StakeAccountSize, solana.StakeProgramID, stakingAuth, newStakeAccount).Build(),
+		stake.NewSplitInstruction(amount, input.StakeAccount, newStakeAccount, stakingAuth).Build(),
+	}
+	tx, err := txBuilder.buildSolanaTx(instructions, stakingAuth, &input.TxInput)
+	if err != nil {
+		return nil, err
+	}
+	// The transient key behind the new stake account must sign the transaction also
+	tx.AddTransientSigner(input.StakingKey)
+	return tx, nil
+}
+
+func (txBuilder TxBuilder) moveStake(args xcbuilder.StakeArgs, stakingAuth solana.PublicKey, input *tx_input.MoveStakeInput) (*tx.Tx, error) {
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+	}
+	if input.Destination.IsZero() {
+		return nil, ErrMoveToValidatorNotSupported
+	}
+	amount := args.GetAmount().Uint64()
+	if amount == 0 {
+		return nil, fmt.Errorf("amount of stake to move is required")
+	}
+	instructions = append(instructions, newMoveStakeInstruction(input.StakeAccount, input.Destination, stakingAuth, amount))
+	return txBuilder.buildSolanaTx(instructions, stakingAuth, &input.TxInput)
+}
+
+func (txBuilder TxBuilder) authorizeStake(stakingAuth solana.PublicKey, input *tx_input.AuthorizeStakeInput) (*tx.Tx, error) {
+	var authorityType uint32
+	switch input.Authority {
+	case xc_types.StakeAuthorityStaker:
+		authorityType = 0
+	case xc_types.StakeAuthorityWithdrawer:
+		authorityType = 1
+	default:
+		return nil, fmt.Errorf("invalid stake authority type: %s", input.Authority)
+	}
+	if input.NewAuthority.IsZero() {
+		return nil, fmt.Errorf("new authority is required")
+	}
+	if len(input.StakeAccounts) == 0 {
+		return nil, fmt.Errorf("no stake accounts found to authorize")
+	}
+	if len(input.StakeAccounts) > MaxAccountAuthorizes {
+		return nil, fmt.Errorf("cannot authorize %d stake accounts in a single tx, the most is %d", len(input.StakeAccounts), MaxAccountAuthorizes)
+	}
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+	}
+	for _, stakeAccount := range input.StakeAccounts {
+		instructions = append(instructions, newAuthorizeStakeInstruction(stakeAccount, stakingAuth, input.NewAuthority, authorityType))
+	}
+	return txBuilder.buildSolanaTx(instructions, stakingAuth, &input.TxInput)
+}
+
+func (txBuilder TxBuilder) closeStake(stakingAuth solana.PublicKey, input *tx_input.CloseStakeInput) (*tx.Tx, error) {
+	if len(input.EligibleStakes) == 0 {
+		return nil, fmt.Errorf("no inactive stake accounts found to close")
+	}
+	if len(input.EligibleStakes) > MaxAccountWithdraws {
+		return nil, fmt.Errorf("cannot close %d stake accounts in a single tx, the most is %d", len(input.EligibleStakes), MaxAccountWithdraws)
+	}
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+	}
+	for _, stakeAccount := range input.EligibleStakes {
+		// withdrawing the entire balance closes the account
+		instructions = append(instructions,
+			stake.NewWithdrawInstruction(
+				stakeAccount.AmountInactive.Uint64(),
+				stakeAccount.StakeAccount,
+				stakingAuth,
+				stakingAuth,
+			).Build(),
+		)
+	}
+	return txBuilder.buildSolanaTx(instructions, stakingAuth, &input.TxInput)
+}
+
+func newMergeStakeInstruction(destination solana.PublicKey, source solana.PublicKey, stakingAuth solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(
+		solana.StakeProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(destination).WRITE(),
+			solana.Meta(source).WRITE(),
+			solana.Meta(solana.SysVarClockPubkey),
+			solana.Meta(solana.SysVarStakeHistoryPubkey),
+			solana.Meta(stakingAuth).SIGNER(),
+		},
+		binary.LittleEndian.AppendUint32(nil, stakeInstructionMerge),
+	)
+}
+
+func newMoveStakeInstruction(source solana.PublicKey, destination solana.PublicKey, stakingAuth solana.PublicKey, lamports uint64) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, stakeInstructionMoveStake)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	return solana.NewInstruction(
+		solana.StakeProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(source).WRITE(),
+			solana.Meta(destination).WRITE(),
+			solana.Meta(stakingAuth).SIGNER(),
+		},
+		data,
+	)
+}
+
+func newAuthorizeStakeInstruction(stakeAccount solana.PublicKey, stakingAuth solana.PublicKey, newAuthority solana.PublicKey, authorityType uint32) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, stakeInstructionAuthorize)
+	data = append(data, newAuthority[:]...)
+	data = binary.LittleEndian.AppendUint32(data, authorityType)
+	return solana.NewInstruction(
+		solana.StakeProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(stakeAccount).WRITE(),
+			solana.Meta(solana.SysVarClockPubkey),
+			solana.Meta(stakingAuth).SIGNER(),
+		},
+		data,
+	)
+}
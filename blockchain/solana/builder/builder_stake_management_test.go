@@ -0,0 +1,231 @@
+package builder_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+// returns the stake program instructions, as the type and the accounts
+func getStakeInstructions(t *testing.T, tx *Tx) ([]uint32, [][]solana.PublicKey) {
+	message := tx.SolTx.Message
+	types := []uint32{}
+	accounts := [][]solana.PublicKey{}
+	for _, ix := range message.Instructions {
+		program, err := message.Program(ix.ProgramIDIndex)
+		require.NoError(t, err)
+		if !program.Equals(solana.StakeProgramID) {
+			continue
+		}
+		types = append(types, binary.LittleEndian.Uint32(ix.Data[0:4]))
+		keys := []solana.PublicKey{}
+		for _, index := range ix.Accounts {
+			keys = append(keys, message.AccountKeys[index])
+		}
+		accounts = append(accounts, keys)
+	}
+	return types, accounts
+}
+
+const testValidator = "J2nUHEAgZFRyuJbFjdqPrAa9gyWDuc7hErtDQHPhsYRp"
+
+func newStakeManagementTxInput() tx_input.TxInput {
+	return tx_input.TxInput{
+		RecentBlockHash:   solana.MustHashFromBase58("DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK"),
+		PrioritizationFee: xc_types.NewBigIntFromUint64(100000),
+	}
+}
+
+func TestManageStakeMerge(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	args, err := xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(0), xcbuilder.WithValidator(testValidator))
+	require.NoError(t, err)
+
+	destination := solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC")
+	sources := []solana.PublicKey{
+		solana.MustPublicKeyFromBase58("GBrg73u6MuwgM6hXrBG9WYqKSrZ6jBr9kW9Nm3Ze5k68"),
+		solana.MustPublicKeyFromBase58("9BQ6j3ZL6QB5ugJ8XoTUy1trEtr1FpvyLhMkb6dDBVyL"),
+	}
+	input := &tx_input.MergeStakeInput{
+		TxInput:     newStakeManagementTxInput(),
+		Destination: destination,
+		Sources:     sources,
+	}
+	tx, err := txBuilder.ManageStake(args, input)
+	require.NoError(t, err)
+
+	types, accounts := getStakeInstructions(t, tx.(*Tx))
+	require.Equal(t, []uint32{7, 7}, types)
+	for i, source := range sources {
+		require.Equal(t, destination, accounts[i][0])
+		require.Equal(t, source, accounts[i][1])
+		require.Equal(t, solana.MustPublicKeyFromBase58(string(from)), accounts[i][4])
+	}
+
+	// can't merge into itself
+	input.Sources = append(input.Sources, destination)
+	_, err = txBuilder.ManageStake(args, input)
+	require.ErrorContains(t, err, "into itself")
+
+	// nothing to merge
+	input.Sources = nil
+	_, err = txBuilder.ManageStake(args, input)
+	require.ErrorContains(t, err, "no stake accounts")
+}
+
+func TestManageStakeSplit(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	amount := xc_types.NewBigIntFromUint64(5_000_000_000)
+	args, err := xcbuilder.NewStakeArgs(xc_types.SOL, from, amount, xcbuilder.WithValidator(testValidator))
+	require.NoError(t, err)
+
+	stakeKey, _ := solana.NewRandomPrivateKey()
+	input := &tx_input.SplitStakeInput{
+		TxInput:           newStakeManagementTxInput(),
+		StakeAccount:      solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC"),
+		StakingKey:        stakeKey,
+		RentExemptReserve: xc_types.NewBigIntFromUint64(2282880),
+	}
+	tx, err := txBuilder.ManageStake(args, input)
+	require.NoError(t, err)
+
+	createAccounts := tx.(*Tx).GetCreateAccounts()
+	require.Len(t, createAccounts, 1)
+	require.Equal(t, stakeKey.PublicKey(), createAccounts[0].NewAccount)
+	require.EqualValues(t, 2282880, createAccounts[0].Lamports)
+
+	splits := tx.(*Tx).GetSplitStakes()
+	require.Len(t, splits, 1)
+	require.Equal(t, amount.Uint64(), *splits[0].Lamports)
+	require.Equal(t, input.StakeAccount, splits[0].GetStakeAccount().PublicKey)
+	require.Equal(t, stakeKey.PublicKey(), splits[0].GetNewStakeAccount().PublicKey)
+
+	// too little to split
+	args, _ = xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(1000), xcbuilder.WithValidator(testValidator))
+	_, err = txBuilder.ManageStake(args, input)
+	require.ErrorContains(t, err, "rent exempt")
+}
+
+func TestManageStakeMove(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	stakeAccount := solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC")
+	destination := solana.MustPublicKeyFromBase58("GBrg73u6MuwgM6hXrBG9WYqKSrZ6jBr9kW9Nm3Ze5k68")
+
+	// moving into an existing stake account
+	args, err := xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(3_000_000_000),
+		xcbuilder.WithValidator(testValidator),
+		xcbuilder.WithStakeAccount(stakeAccount.String()),
+		xcbuilder.WithDestinationStakeAccount(destination.String()),
+	)
+	require.NoError(t, err)
+	tx, err := txBuilder.ManageStake(args, &tx_input.MoveStakeInput{
+		TxInput:      newStakeManagementTxInput(),
+		StakeAccount: stakeAccount,
+		Destination:  destination,
+	})
+	require.NoError(t, err)
+	types, accounts := getStakeInstructions(t, tx.(*Tx))
+	require.Equal(t, []uint32{16}, types)
+	require.Equal(t, []solana.PublicKey{stakeAccount, destination, solana.MustPublicKeyFromBase58(string(from))}, accounts[0])
+	data := tx.(*Tx).SolTx.Message.Instructions[1].Data
+	require.EqualValues(t, 3_000_000_000, binary.LittleEndian.Uint64(data[4:12]))
+
+	// there is no way to move stake to another validator
+	_, err = txBuilder.ManageStake(args, &tx_input.MoveStakeInput{
+		TxInput:      newStakeManagementTxInput(),
+		StakeAccount: stakeAccount,
+	})
+	require.ErrorIs(t, err, builder.ErrMoveToValidatorNotSupported)
+
+	// needs an amount to move
+	args, err = xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(0),
+		xcbuilder.WithValidator(testValidator),
+		xcbuilder.WithStakeAccount(stakeAccount.String()),
+		xcbuilder.WithDestinationStakeAccount(destination.String()),
+	)
+	require.NoError(t, err)
+	_, err = txBuilder.ManageStake(args, &tx_input.MoveStakeInput{
+		TxInput:      newStakeManagementTxInput(),
+		StakeAccount: stakeAccount,
+		Destination:  destination,
+	})
+	require.ErrorContains(t, err, "required")
+}
+
+func TestManageStakeAuthorize(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	newAuthority := solana.MustPublicKeyFromBase58("9BQ6j3ZL6QB5ugJ8XoTUy1trEtr1FpvyLhMkb6dDBVyL")
+	args, err := xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(0),
+		xcbuilder.WithValidator(testValidator),
+		xcbuilder.WithNewAuthority(xc_types.Address(newAuthority.String()), xc_types.StakeAuthorityWithdrawer),
+	)
+	require.NoError(t, err)
+
+	_, err = xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(0),
+		xcbuilder.WithValidator(testValidator),
+		xcbuilder.WithNewAuthority(xc_types.Address(newAuthority.String()), "owner"),
+	)
+	require.ErrorContains(t, err, "invalid stake authority")
+
+	stakeAccounts := []solana.PublicKey{
+		solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC"),
+		solana.MustPublicKeyFromBase58("GBrg73u6MuwgM6hXrBG9WYqKSrZ6jBr9kW9Nm3Ze5k68"),
+	}
+	tx, err := txBuilder.ManageStake(args, &tx_input.AuthorizeStakeInput{
+		TxInput:       newStakeManagementTxInput(),
+		StakeAccounts: stakeAccounts,
+		NewAuthority:  newAuthority,
+		Authority:     xc_types.StakeAuthorityWithdrawer,
+	})
+	require.NoError(t, err)
+	types, accounts := getStakeInstructions(t, tx.(*Tx))
+	require.Equal(t, []uint32{1, 1}, types)
+	for i, ix := range tx.(*Tx).SolTx.Message.Instructions[1:] {
+		require.Equal(t, stakeAccounts[i], accounts[i][0])
+		require.Equal(t, newAuthority[:], []byte(ix.Data[4:36]))
+		require.EqualValues(t, 1, binary.LittleEndian.Uint32(ix.Data[36:40]))
+	}
+}
+
+func TestManageStakeClose(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	args, err := xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(0), xcbuilder.WithValidator(testValidator))
+	require.NoError(t, err)
+
+	input := &tx_input.CloseStakeInput{
+		TxInput: newStakeManagementTxInput(),
+		EligibleStakes: []*tx_input.ExistingStake{
+			{
+				AmountInactive: xc_types.NewBigIntFromUint64(5_002_282_880),
+				StakeAccount:   solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC"),
+			},
+			{
+				AmountInactive: xc_types.NewBigIntFromUint64(2282880),
+				StakeAccount:   solana.MustPublicKeyFromBase58("GBrg73u6MuwgM6hXrBG9WYqKSrZ6jBr9kW9Nm3Ze5k68"),
+			},
+		},
+	}
+	tx, err := txBuilder.ManageStake(args, input)
+	require.NoError(t, err)
+	withdraws := tx.(*Tx).GetStakeWithdraws()
+	require.Len(t, withdraws, 2)
+	for i, withdraw := range withdraws {
+		require.Equal(t, input.EligibleStakes[i].AmountInactive.Uint64(), *withdraw.Lamports)
+		require.Equal(t, input.EligibleStakes[i].StakeAccount, withdraw.GetStakeAccount().PublicKey)
+	}
+
+	input.EligibleStakes = nil
+	_, err = txBuilder.ManageStake(args, input)
+	require.ErrorContains(t, err, "no inactive stake accounts")
+}
@@ -0,0 +1,175 @@
+package builder
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	ata "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+)
+
+// Deposit SOL into the stake pool, minting pool tokens into the sender's associated token account
+func (txBuilder TxBuilder) stakeStakePool(args xcbuilder.StakeArgs, input *tx_input.StakePoolStakeInput) (*tx.Tx, error) {
+	if owner, ok := args.GetStakeOwner(); ok && owner != args.GetFrom() {
+		return nil, fmt.Errorf("stake pools mint pool tokens to the sender, a different stake owner is not supported")
+	}
+	amount := args.GetAmount().Uint64()
+	if amount == 0 {
+		return nil, fmt.Errorf("amount to stake is required")
+	}
+	from, err := solana.PublicKeyFromBase58(string(args.GetFrom()))
+	if err != nil {
+		return nil, err
+	}
+	poolTokenAccount, err := stakePoolTokenAccount(from, &input.StakePoolAccounts)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+	}
+	if input.ShouldCreateATA {
+		createAta := ata.NewCreateInstruction(from, from, input.PoolMint).Build()
+		// Adjust the ata-create-account arguments:
+		// index 1 - associated token account
+		// index 5 - token program
+		createAta.Impl.(ata.Create).AccountMetaSlice[1].PublicKey = poolTokenAccount
+		createAta.Impl.(ata.Create).AccountMetaSlice[5].PublicKey = input.PoolTokenProgram
+		instructions = append(instructions, createAta)
+	}
+	instructions = append(instructions, newDepositSolInstruction(&input.StakePoolAccounts, from, poolTokenAccount, amount))
+	return txBuilder.buildSolanaTx(instructions, from, &input.TxInput)
+}
+
+// Burn pool tokens to withdraw SOL from the pool's reserve, or to withdraw stake into a new stake account
+func (txBuilder TxBuilder) unstakeStakePool(args xcbuilder.StakeArgs, input *tx_input.StakePoolUnstakeInput) (*tx.Tx, error) {
+	poolTokens := input.PoolTokens.Uint64()
+	if poolTokens == 0 {
+		return nil, fmt.Errorf("pool tokens to burn are required")
+	}
+	from, err := solana.PublicKeyFromBase58(string(args.GetFrom()))
+	if err != nil {
+		return nil, err
+	}
+	poolTokenAccount, err := stakePoolTokenAccount(from, &input.StakePoolAccounts)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := []solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+	}
+	if !input.WithdrawsStake() {
+		instructions = append(instructions, newWithdrawSolInstruction(&input.StakePoolAccounts, from, poolTokenAccount, poolTokens))
+		return txBuilder.buildSolanaTx(instructions, from, &input.TxInput)
+	}
+
+	newStakeAccount := input.StakingKey.PublicKey()
+	instructions = append(instructions,
+		// the stake is split into a new, uninitialized stake account
+		system.NewCreateAccountInstruction(input.RentExemptReserve.Uint64(), StakeAccountSize, solana.StakeProgramID, from, newStakeAccount).Build(),
+		newWithdrawStakeInstruction(&input.StakePoolAccounts, input.ValidatorList, input.ValidatorStakeAccount, newStakeAccount, from, poolTokenAccount, poolTokens),
+	)
+	tx, err := txBuilder.buildSolanaTx(instructions, from, &input.TxInput)
+	if err != nil {
+		return nil, err
+	}
+	// The transient key behind the new stake account must sign the transaction also
+	tx.AddTransientSigner(input.StakingKey)
+	return tx, nil
+}
+
+func stakePoolTokenAccount(owner solana.PublicKey, pool *tx_input.StakePoolAccounts) (solana.PublicKey, error) {
+	account, err := solana_types.FindAssociatedTokenAddress(owner.String(), pool.PoolMint.String(), pool.PoolTokenProgram)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	return solana.PublicKeyFromBase58(account)
+}
+
+func newStakePoolInstructionData(instruction uint8, amount uint64) []byte {
+	return binary.LittleEndian.AppendUint64([]byte{instruction}, amount)
+}
+
+func newDepositSolInstruction(pool *tx_input.StakePoolAccounts, from solana.PublicKey, poolTokenAccount solana.PublicKey, lamports uint64) solana.Instruction {
+	return solana.NewInstruction(
+		pool.Program,
+		solana.AccountMetaSlice{
+			solana.Meta(pool.StakePool).WRITE(),
+			solana.Meta(solana_types.StakePoolWithdrawAuthority(pool.Program, pool.StakePool)),
+			solana.Meta(pool.ReserveStake).WRITE(),
+			solana.Meta(from).WRITE().SIGNER(),
+			solana.Meta(poolTokenAccount).WRITE(),
+			solana.Meta(pool.ManagerFeeAccount).WRITE(),
+			// no referrer, so the referral fee goes back to the depositor
+			solana.Meta(poolTokenAccount).WRITE(),
+			solana.Meta(pool.PoolMint).WRITE(),
+			solana.Meta(solana.SystemProgramID),
+			solana.Meta(pool.PoolTokenProgram),
+		},
+		newStakePoolInstructionData(solana_types.StakePoolInstructionDepositSol, lamports),
+	)
+}
+
+func newWithdrawSolInstruction(pool *tx_input.StakePoolAccounts, from solana.PublicKey, poolTokenAccount solana.PublicKey, poolTokens uint64) solana.Instruction {
+	return solana.NewInstruction(
+		pool.Program,
+		solana.AccountMetaSlice{
+			solana.Meta(pool.StakePool).WRITE(),
+			solana.Meta(solana_types.StakePoolWithdrawAuthority(pool.Program, pool.StakePool)),
+			solana.Meta(from).SIGNER(),
+			solana.Meta(poolTokenAccount).WRITE(),
+			solana.Meta(pool.ReserveStake).WRITE(),
+			solana.Meta(from).WRITE(),
+			solana.Meta(pool.ManagerFeeAccount).WRITE(),
+			solana.Meta(pool.PoolMint).WRITE(),
+			solana.Meta(solana.SysVarClockPubkey),
+			solana.Meta(solana.SysVarStakeHistoryPubkey),
+			solana.Meta(solana.StakeProgramID),
+			solana.Meta(pool.PoolTokenProgram),
+		},
+		newStakePoolInstructionData(solana_types.StakePoolInstructionWithdrawSol, poolTokens),
+	)
+}
+
+func newWithdrawStakeInstruction(
+	pool *tx_input.StakePoolAccounts,
+	validatorList solana.PublicKey,
+	validatorStakeAccount solana.PublicKey,
+	newStakeAccount solana.PublicKey,
+	from solana.PublicKey,
+	poolTokenAccount solana.PublicKey,
+	poolTokens uint64,
+) solana.Instruction {
+	return solana.NewInstruction(
+		pool.Program,
+		solana.AccountMetaSlice{
+			solana.Meta(pool.StakePool).WRITE(),
+			solana.Meta(validatorList).WRITE(),
+			solana.Meta(solana_types.StakePoolWithdrawAuthority(pool.Program, pool.StakePool)),
+			solana.Meta(validatorStakeAccount).WRITE(),
+			solana.Meta(newStakeAccount).WRITE(),
+			// the sender becomes the staker and withdrawer of the new stake account
+			solana.Meta(from),
+			solana.Meta(from).SIGNER(),
+			solana.Meta(poolTokenAccount).WRITE(),
+			solana.Meta(pool.ManagerFeeAccount).WRITE(),
+			solana.Meta(pool.PoolMint).WRITE(),
+			solana.Meta(solana.SysVarClockPubkey),
+			solana.Meta(pool.PoolTokenProgram),
+			solana.Meta(solana.StakeProgramID),
+		},
+		newStakePoolInstructionData(solana_types.StakePoolInstructionWithdrawStake, poolTokens),
+	)
+}
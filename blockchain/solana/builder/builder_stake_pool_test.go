@@ -0,0 +1,206 @@
+package builder_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+var testStakePool = tx_input.StakePoolAccounts{
+	Program:           solana_types.StakePoolProgramID,
+	StakePool:         solana.MustPublicKeyFromBase58("Jito4APyf642JPZPx3hGc6WWJ8zPKtRbRs4P815Awbb"),
+	ReserveStake:      solana.MustPublicKeyFromBase58("BgKUXdS29YcHCFrPm5M8oLHiTzZaMDjsebggjoaQ6KFL"),
+	PoolMint:          solana.MustPublicKeyFromBase58("J1toso1uCk3RLmjorhTtrVwY9HJ7X8V9yYac6Y7kGCPn"),
+	ManagerFeeAccount: solana.MustPublicKeyFromBase58("feeeFLLsam6xZJFc6UQFrHqkvVt4jfmVvi2BRLkUZ4i"),
+	PoolTokenProgram:  solana.TokenProgramID,
+}
+
+// returns the data and accounts of the first instruction for the program
+func getProgramInstruction(t *testing.T, tx *Tx, program solana.PublicKey) ([]byte, []solana.PublicKey) {
+	message := tx.SolTx.Message
+	for _, ix := range message.Instructions {
+		ixProgram, err := message.Program(ix.ProgramIDIndex)
+		require.NoError(t, err)
+		if !ixProgram.Equals(program) {
+			continue
+		}
+		keys := []solana.PublicKey{}
+		for _, index := range ix.Accounts {
+			keys = append(keys, message.AccountKeys[index])
+		}
+		return ix.Data, keys
+	}
+	require.Fail(t, "no instruction for program", program.String())
+	return nil, nil
+}
+
+func TestStakePoolDeposit(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	amount := xc_types.NewBigIntFromUint64(2_000_000_000)
+	// no validator is needed for a liquid provider
+	args, err := xcbuilder.NewStakeArgs(xc_types.SOL, from, amount, xcbuilder.WithStakingProvider(xc_types.StakePool))
+	require.NoError(t, err)
+
+	input := &tx_input.StakePoolStakeInput{
+		TxInput:           newStakeManagementTxInput(),
+		StakePoolAccounts: testStakePool,
+	}
+	input.ShouldCreateATA = true
+	tx, err := txBuilder.Stake(args, input)
+	require.NoError(t, err)
+
+	poolTokenAccount, err := solana_types.FindAssociatedTokenAddress(string(from), testStakePool.PoolMint.String(), solana.TokenProgramID)
+	require.NoError(t, err)
+	_, ataAccounts := getProgramInstruction(t, tx.(*Tx), solana.SPLAssociatedTokenAccountProgramID)
+	require.Equal(t, poolTokenAccount, ataAccounts[1].String())
+
+	data, accounts := getProgramInstruction(t, tx.(*Tx), testStakePool.Program)
+	require.EqualValues(t, solana_types.StakePoolInstructionDepositSol, data[0])
+	require.Equal(t, amount.Uint64(), binary.LittleEndian.Uint64(data[1:9]))
+	require.Len(t, accounts, 10)
+	require.Equal(t, testStakePool.StakePool, accounts[0])
+	require.Equal(t, solana_types.StakePoolWithdrawAuthority(testStakePool.Program, testStakePool.StakePool), accounts[1])
+	require.Equal(t, testStakePool.ReserveStake, accounts[2])
+	require.Equal(t, string(from), accounts[3].String())
+	require.Equal(t, poolTokenAccount, accounts[4].String())
+	require.Equal(t, testStakePool.PoolMint, accounts[7])
+
+	// pool tokens can only be minted to the sender
+	args, err = xcbuilder.NewStakeArgs(xc_types.SOL, from, amount,
+		xcbuilder.WithStakingProvider(xc_types.StakePool),
+		xcbuilder.WithStakeOwner("9BQ6j3ZL6QB5ugJ8XoTUy1trEtr1FpvyLhMkb6dDBVyL"),
+	)
+	require.NoError(t, err)
+	_, err = txBuilder.Stake(args, input)
+	require.ErrorContains(t, err, "stake owner is not supported")
+}
+
+func TestStakePoolWithdraw(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	args, err := xcbuilder.NewStakeArgs(xc_types.SOL, from, xc_types.NewBigIntFromUint64(2_000_000_000), xcbuilder.WithStakingProvider(xc_types.StakePool))
+	require.NoError(t, err)
+
+	// from the reserve
+	input := &tx_input.StakePoolUnstakeInput{
+		TxInput:           newStakeManagementTxInput(),
+		StakePoolAccounts: testStakePool,
+		PoolTokens:        xc_types.NewBigIntFromUint64(1_800_000_000),
+	}
+	tx, err := txBuilder.Unstake(args, input)
+	require.NoError(t, err)
+	data, accounts := getProgramInstruction(t, tx.(*Tx), testStakePool.Program)
+	require.EqualValues(t, solana_types.StakePoolInstructionWithdrawSol, data[0])
+	require.EqualValues(t, 1_800_000_000, binary.LittleEndian.Uint64(data[1:9]))
+	require.Len(t, accounts, 12)
+	require.Equal(t, testStakePool.ReserveStake, accounts[4])
+	require.Equal(t, string(from), accounts[5].String())
+	require.Len(t, tx.(*Tx).GetCreateAccounts(), 0)
+
+	// from a validator
+	stakeKey, _ := solana.NewRandomPrivateKey()
+	input.ValidatorList = solana.MustPublicKeyFromBase58("3R3nGZpQs2aZo5FDQvd2MUQ6R7KhAPainds6uT6uE2mn")
+	input.ValidatorStakeAccount = solana.MustPublicKeyFromBase58("GBrg73u6MuwgM6hXrBG9WYqKSrZ6jBr9kW9Nm3Ze5k68")
+	input.StakingKey = stakeKey
+	input.RentExemptReserve = xc_types.NewBigIntFromUint64(2282880)
+	tx, err = txBuilder.Unstake(args, input)
+	require.NoError(t, err)
+
+	createAccounts := tx.(*Tx).GetCreateAccounts()
+	require.Len(t, createAccounts, 1)
+	require.Equal(t, stakeKey.PublicKey(), createAccounts[0].NewAccount)
+	data, accounts = getProgramInstruction(t, tx.(*Tx), testStakePool.Program)
+	require.EqualValues(t, solana_types.StakePoolInstructionWithdrawStake, data[0])
+	require.Len(t, accounts, 13)
+	require.Equal(t, input.ValidatorList, accounts[1])
+	require.Equal(t, input.ValidatorStakeAccount, accounts[3])
+	require.Equal(t, stakeKey.PublicKey(), accounts[4])
+	require.Equal(t, string(from), accounts[5].String())
+
+	input.PoolTokens = xc_types.NewBigIntFromUint64(0)
+	_, err = txBuilder.Unstake(args, input)
+	require.ErrorContains(t, err, "pool tokens")
+}
+
+func TestParseStakePool(t *testing.T) {
+	key := func(b byte) []byte {
+		return solana.PublicKeyFromBytes(append(make([]byte, 31), b)).Bytes()
+	}
+	u64 := func(v uint64) []byte {
+		return binary.LittleEndian.AppendUint64(nil, v)
+	}
+	fee := func(denominator, numerator uint64) []byte {
+		return append(u64(denominator), u64(numerator)...)
+	}
+	data := []byte{1}
+	// manager, staker, deposit authority, bump, validator list, reserve, mint, fee account, token program
+	data = append(data, key(1)...)
+	data = append(data, key(2)...)
+	data = append(data, key(3)...)
+	data = append(data, 255)
+	data = append(data, key(4)...)
+	data = append(data, key(5)...)
+	data = append(data, key(6)...)
+	data = append(data, key(7)...)
+	data = append(data, solana.TokenProgramID[:]...)
+	// total lamports, supply, last update epoch
+	data = append(data, u64(1_100)...)
+	data = append(data, u64(1_000)...)
+	data = append(data, u64(700)...)
+	// lockup
+	data = append(data, make([]byte, 48)...)
+	// epoch fee, with a next fee
+	data = append(data, fee(100, 4)...)
+	data = append(data, 1)
+	data = append(data, fee(100, 5)...)
+	// no preferred deposit validator, a preferred withdraw validator
+	data = append(data, 0, 1)
+	data = append(data, key(8)...)
+	// stake deposit and withdrawal fees, no next withdrawal fee, referral fee
+	data = append(data, fee(0, 0)...)
+	data = append(data, fee(1000, 1)...)
+	data = append(data, 0, 0)
+	// no sol deposit authority, fee, referral fee, sol withdraw authority, fee
+	data = append(data, 0)
+	data = append(data, fee(1000, 2)...)
+	data = append(data, 0, 1)
+	data = append(data, key(9)...)
+	data = append(data, fee(1000, 3)...)
+	// the rest is not parsed
+	data = append(data, make([]byte, 17)...)
+
+	pool, err := solana_types.ParseStakePool(data)
+	require.NoError(t, err)
+	require.Equal(t, key(4), pool.ValidatorList.Bytes())
+	require.Equal(t, key(5), pool.ReserveStake.Bytes())
+	require.Equal(t, key(6), pool.PoolMint.Bytes())
+	require.Equal(t, key(7), pool.ManagerFeeAccount.Bytes())
+	require.Equal(t, solana.TokenProgramID, pool.TokenProgramID)
+	require.EqualValues(t, 700, pool.LastUpdateEpoch)
+	require.Nil(t, pool.PreferredDepositValidator)
+	require.Equal(t, key(8), pool.PreferredWithdrawValidator.Bytes())
+	require.Equal(t, solana_types.StakePoolFee{Denominator: 1000, Numerator: 1}, pool.StakeWithdrawalFee)
+	require.Nil(t, pool.SolDepositAuthority)
+	require.Equal(t, solana_types.StakePoolFee{Denominator: 1000, Numerator: 2}, pool.SolDepositFee)
+	require.Equal(t, key(9), pool.SolWithdrawAuthority.Bytes())
+	require.Equal(t, solana_types.StakePoolFee{Denominator: 1000, Numerator: 3}, pool.SolWithdrawalFee)
+
+	// 1.1 lamports per pool token
+	require.EqualValues(t, 1_100, pool.PoolTokensToLamports(1_000))
+	require.EqualValues(t, 1_000, pool.LamportsToPoolTokens(1_100))
+	require.EqualValues(t, 909, pool.LamportsToPoolTokens(1_000))
+
+	_, err = solana_types.ParseStakePool(data[:100])
+	require.ErrorContains(t, err, "invalid stake pool")
+	data[0] = 2
+	_, err = solana_types.ParseStakePool(data)
+	require.ErrorContains(t, err, "not a stake pool")
+}
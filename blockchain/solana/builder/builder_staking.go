@@ -17,6 +17,9 @@ const RentExemptLamportsThresholdHuman = "0.003"
 const StakeAccountSize = 200
 
 func (txBuilder TxBuilder) Stake(args xcbuilder.StakeArgs, input xc_types.StakeTxInput) (xc_types.Tx, error) {
+	if poolInput, ok := input.(*tx_input.StakePoolStakeInput); ok {
+		return txBuilder.stakeStakePool(args, poolInput)
+	}
 	stakeInput, ok := input.(*tx_input.StakingInput)
 	if !ok {
 		return nil, fmt.Errorf("invalid input %T, expected %T", input, stakeInput)
@@ -66,6 +69,9 @@ func (txBuilder TxBuilder) Stake(args xcbuilder.StakeArgs, input xc_types.StakeT
 }
 
 func (txBuilder TxBuilder) Unstake(args xcbuilder.StakeArgs, input xc_types.UnstakeTxInput) (xc_types.Tx, error) {
+	if poolInput, ok := input.(*tx_input.StakePoolUnstakeInput); ok {
+		return txBuilder.unstakeStakePool(args, poolInput)
+	}
 	unstakeInput, ok := input.(*tx_input.UnstakingInput)
 	if !ok {
 		return nil, fmt.Errorf("invalid input %T, expected %T", input, unstakeInput)
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/gagliardetto/solana-go"
+	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
 	"github.com/gagliardetto/solana-go/programs/token"
 	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
 	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
@@ -45,6 +46,42 @@ func TestNewNativeTransfer(t *testing.T) {
 	require.Equal(t, uint16(0x2), solTx.Message.Instructions[0].ProgramIDIndex) // system tx
 }
 
+func TestNewNativeTransferWithComputeUnitLimit(t *testing.T) {
+	builder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	args, err := xcbuilder.NewTransferArgs(
+		xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb"),
+		xc_types.Address("BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11"),
+		xc_types.NewBigIntFromUint64(1200000),
+	)
+	require.NoError(t, err)
+
+	input := &tx_input.TxInput{
+		PrioritizationFee: xc_types.NewBigIntFromUint64(1000),
+		ComputeUnitLimit:  450,
+	}
+	tx, err := builder.NewNativeTransfer(args, input)
+	require.NoError(t, err)
+	solTx := tx.(*Tx).SolTx
+	require.Equal(t, 3, len(solTx.Message.Instructions))
+
+	// the limit is set last
+	program, err := solTx.Message.Program(solTx.Message.Instructions[2].ProgramIDIndex)
+	require.NoError(t, err)
+	require.Equal(t, solana.ComputeBudget, program)
+	limit, err := compute_budget.DecodeInstruction(nil, solTx.Message.Instructions[2].Data)
+	require.NoError(t, err)
+	require.EqualValues(t, 450, limit.Impl.(*compute_budget.SetComputeUnitLimit).Units)
+
+	// limited to the most a transaction can use
+	input.ComputeUnitLimit = 2_000_000
+	tx, err = builder.NewNativeTransfer(args, input)
+	require.NoError(t, err)
+	solTx = tx.(*Tx).SolTx
+	limit, err = compute_budget.DecodeInstruction(nil, solTx.Message.Instructions[2].Data)
+	require.NoError(t, err)
+	require.EqualValues(t, tx_input.MaxComputeUnitLimit, limit.Impl.(*compute_budget.SetComputeUnitLimit).Units)
+}
+
 func TestNewNativeTransferErr(t *testing.T) {
 
 	builder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
@@ -392,3 +429,84 @@ func TestNewTransferAsToken(t *testing.T) {
 		require.Equal(t, v.expectedSourceAccount, tokenTf.Accounts[0].PublicKey.String())
 	}
 }
+
+func TestNewTokenTransferWithLookupTables(t *testing.T) {
+	require := require.New(t)
+	contract := xc_types.ContractAddress("4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU")
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	from := xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	to := xc_types.Address("BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11")
+	// sending to a token account
+	ataTo := solana.MustPublicKeyFromBase58(string(to))
+
+	args, err := xcbuilder.NewTransferArgs(
+		from,
+		to,
+		xc_types.NewBigIntFromUint64(400),
+		xcbuilder.WithAsset(&xc_types.TokenAssetConfig{
+			Contract:    contract,
+			Decimals:    6,
+			ChainConfig: &xc_types.ChainConfig{},
+		}),
+	)
+	require.NoError(err)
+
+	// spending from 40 token accounts is too many for a legacy transaction
+	input := &TxInput{TokenProgram: solana.TokenProgramID, ToIsATA: true}
+	table := &tx_input.AddressLookupTable{
+		Account:   solana.MustPublicKeyFromBase58("8uJ3VKm1zeGHgmWnJZ6v1Wbs3Q2uvNuRcUBPvSeWLsjS"),
+		Addresses: solana.PublicKeySlice{ataTo, solana.MustPublicKeyFromBase58(string(contract))},
+	}
+	for i := 0; i < 40; i++ {
+		account := solana.NewWallet().PublicKey()
+		input.SourceTokenAccounts = append(input.SourceTokenAccounts, &tx_input.TokenAccount{
+			Account: account,
+			Balance: xc_types.NewBigIntFromUint64(10),
+		})
+		table.Addresses = append(table.Addresses, account)
+	}
+	_, err = txBuilder.NewTokenTransfer(args, input)
+	require.ErrorContains(err, "cannot send")
+
+	input.AddressLookupTables = []*tx_input.AddressLookupTable{table}
+	built, err := txBuilder.NewTokenTransfer(args, input)
+	require.NoError(err)
+	solTx := built.(*Tx).SolTx
+	require.Equal(solana.MessageVersionV0, solTx.Message.GetVersion())
+	require.Len(solTx.Message.AddressTableLookups, 1)
+	// the payer and the token program stay in the message
+	require.Len(solTx.Message.AccountKeys, 2)
+	require.Equal(41, solTx.Message.NumWritableLookups())
+	require.Equal(42, solTx.Message.NumLookups())
+
+	err = built.(*Tx).AddSignatures(make([]byte, 64))
+	require.NoError(err)
+	bz, err := built.Serialize()
+	require.NoError(err)
+	require.LessOrEqual(len(bz), builder.MaxTransactionSize)
+
+	// the instructions can be decoded from the serialized transaction with the addresses it loaded
+	parsed, err := solana.TransactionFromBytes(bz)
+	require.NoError(err)
+	require.Equal(solana.MessageVersionV0, parsed.Message.GetVersion())
+	parsedTx := tx.NewTxFrom(parsed)
+	require.Empty(parsedTx.GetTokenTransferCheckeds())
+	loaded, err := solTx.Message.GetAddressTableLookupAccounts()
+	require.NoError(err)
+	writable := solTx.Message.NumWritableLookups()
+	require.ErrorContains(parsedTx.SetLoadedAddresses(loaded[:writable], nil), "expected 42 loaded addresses")
+	require.NoError(parsedTx.SetLoadedAddresses(loaded[:writable], loaded[writable:]))
+	transfers := parsedTx.GetTokenTransferCheckeds()
+	require.Len(transfers, 40)
+	for i, transfer := range transfers {
+		require.EqualValues(10, *transfer.Amount)
+		require.Equal(input.SourceTokenAccounts[i].Account, transfer.GetSourceAccount().PublicKey)
+		require.Equal(ataTo, transfer.GetDestinationAccount().PublicKey)
+	}
+	require.Equal(built.Hash(), parsedTx.Hash())
+
+	// accounts that aren't in a table still count towards the size
+	table.Addresses = table.Addresses[:2]
+	_, err = txBuilder.NewTokenTransfer(args, input)
+	require.ErrorContains(err, "exceeds the limit of 1232 bytes")
+}
@@ -0,0 +1,109 @@
+package builder
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+)
+
+// Token-2022 instructions missing from the token program library
+const (
+	token2022TransferFeeExtension   = 26
+	token2022TransferCheckedWithFee = 1
+)
+
+// Returns the instructions to transfer from one token account, accounting for the Token-2022
+// extensions of the mint and destination:
+//   - the expected fee is included when the mint withholds a transfer fee
+//   - a memo precedes the transfer when the destination requires one
+//   - the accounts for the mint's transfer hook are appended
+func newTokenTransferInstructions(
+	txInput *tx_input.TxInput,
+	amount uint64,
+	decimals uint8,
+	source solana.PublicKey,
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+	memo string,
+) []solana.Instruction {
+	instructions := []solana.Instruction{}
+	if txInput.MemoRequired {
+		instructions = append(instructions, newMemoInstruction(memo, owner))
+	}
+
+	var transfer solana.Instruction
+	if txInput.TransferFee != nil {
+		transfer = newTransferCheckedWithFeeInstruction(
+			txInput.TokenProgram,
+			amount,
+			decimals,
+			txInput.TransferFee.GetFee(amount),
+			source,
+			mint,
+			destination,
+			owner,
+		)
+	} else {
+		transfer = token.NewTransferCheckedInstruction(
+			amount,
+			decimals,
+			source,
+			mint,
+			destination,
+			owner,
+			[]solana.PublicKey{},
+		).Build()
+	}
+	if len(txInput.TransferHookAccounts) > 0 {
+		accounts := transfer.Accounts()
+		for _, account := range txInput.TransferHookAccounts {
+			accounts = append(accounts, &solana.AccountMeta{
+				PublicKey:  account.Account,
+				IsSigner:   account.IsSigner,
+				IsWritable: account.IsWritable,
+			})
+		}
+		data, _ := transfer.Data()
+		transfer = solana.NewInstruction(transfer.ProgramID(), accounts, data)
+	}
+	return append(instructions, transfer)
+}
+
+func newTransferCheckedWithFeeInstruction(
+	tokenProgram solana.PublicKey,
+	amount uint64,
+	decimals uint8,
+	fee uint64,
+	source solana.PublicKey,
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+) solana.Instruction {
+	data := []byte{token2022TransferFeeExtension, token2022TransferCheckedWithFee}
+	data = binary.LittleEndian.AppendUint64(data, amount)
+	data = append(data, decimals)
+	data = binary.LittleEndian.AppendUint64(data, fee)
+	return solana.NewInstruction(
+		tokenProgram,
+		solana.AccountMetaSlice{
+			solana.Meta(source).WRITE(),
+			solana.Meta(mint),
+			solana.Meta(destination).WRITE(),
+			solana.Meta(owner).SIGNER(),
+		},
+		data,
+	)
+}
+
+func newMemoInstruction(memo string, signer solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(
+		solana.MemoProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(signer).SIGNER(),
+		},
+		[]byte(memo),
+	)
+}
@@ -0,0 +1,199 @@
+package builder_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newToken2022TransferArgs(t *testing.T, options ...xcbuilder.BuilderOption) *xcbuilder.TransferArgs {
+	options = append(options, xcbuilder.WithAsset(&xc_types.TokenAssetConfig{
+		Contract: "2b1kV6DkPAnxd5ixfnxCpjxmKwqjjaYmCZfHsFu24GXo",
+		Decimals: 6,
+	}))
+	args, err := xcbuilder.NewTransferArgs(
+		xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb"),
+		xc_types.Address("BWbmXj5ckAaWCAtzMZ97qnJhBAKegoXtgNrv9BUpAB11"),
+		xc_types.NewBigIntFromUint64(1_000_000),
+		options...,
+	)
+	require.NoError(t, err)
+	return args
+}
+
+func TestNewToken2022TransferWithFee(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	source := solana.MustPublicKeyFromBase58("6UwHtXqjvGCLsBvdSvNtbyFTQBALbGzuBhGkQrCvFHHB")
+	input := &tx_input.TxInput{
+		TokenProgram: solana.Token2022ProgramID,
+		SourceTokenAccounts: []*tx_input.TokenAccount{
+			{Account: source, Balance: xc_types.NewBigIntFromUint64(5_000_000)},
+		},
+		TransferFee: &solana_types.TransferFee{BasisPoints: 50, MaximumFee: 3000},
+	}
+	tx, err := txBuilder.NewTransfer(newToken2022TransferArgs(t), input)
+	require.NoError(t, err)
+
+	transfers := tx.(*Tx).GetTokenTransferCheckedWithFees()
+	require.Len(t, transfers, 1)
+	require.EqualValues(t, 1_000_000, transfers[0].Amount)
+	require.EqualValues(t, 6, transfers[0].Decimals)
+	// 0.5%, capped at the maximum
+	require.EqualValues(t, 3000, transfers[0].Fee)
+	require.Equal(t, source, transfers[0].Source)
+	require.Empty(t, tx.(*Tx).GetTokenTransferCheckeds())
+
+	input.TransferFee.MaximumFee = 10_000
+	tx, err = txBuilder.NewTransfer(newToken2022TransferArgs(t), input)
+	require.NoError(t, err)
+	require.EqualValues(t, 5000, tx.(*Tx).GetTokenTransferCheckedWithFees()[0].Fee)
+}
+
+func TestNewToken2022TransferWithMemoAndHook(t *testing.T) {
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	source := solana.MustPublicKeyFromBase58("6UwHtXqjvGCLsBvdSvNtbyFTQBALbGzuBhGkQrCvFHHB")
+	hookProgram := solana.MustPublicKeyFromBase58("DrWbQtYJGtsoRwzKqAbHKHKsCJJfpysudF39GBVFSxub")
+	extra := solana.MustPublicKeyFromBase58("CiDwVBFgWV9E5MvXWoLgnEgn2hK7rJikbvfWavzAQz3")
+	input := &tx_input.TxInput{
+		TokenProgram: solana.Token2022ProgramID,
+		SourceTokenAccounts: []*tx_input.TokenAccount{
+			{Account: source, Balance: xc_types.NewBigIntFromUint64(5_000_000)},
+		},
+		MemoRequired: true,
+		TransferHookAccounts: []*tx_input.TransferHookAccount{
+			{Account: extra, IsWritable: true},
+			{Account: hookProgram},
+		},
+	}
+	tx, err := txBuilder.NewTransfer(newToken2022TransferArgs(t, xcbuilder.WithMemo("invoice 42")), input)
+	require.NoError(t, err)
+	message := tx.(*Tx).SolTx.Message
+	require.Len(t, message.Instructions, 2)
+
+	// the memo precedes the transfer
+	program, err := message.Program(message.Instructions[0].ProgramIDIndex)
+	require.NoError(t, err)
+	require.Equal(t, solana.MemoProgramID, program)
+	require.Equal(t, "invoice 42", string(message.Instructions[0].Data))
+
+	transfers := tx.(*Tx).GetTokenTransferCheckeds()
+	require.Len(t, transfers, 1)
+	accounts, err := message.Instructions[1].ResolveInstructionAccounts(&message)
+	require.NoError(t, err)
+	require.Len(t, accounts, 6)
+	require.Equal(t, extra, accounts[4].PublicKey)
+	require.True(t, accounts[4].IsWritable)
+	require.Equal(t, hookProgram, accounts[5].PublicKey)
+	require.False(t, accounts[5].IsWritable)
+}
+
+func TestParseToken2022Extensions(t *testing.T) {
+	tlv := func(extensionType solana_types.TokenExtensionType, value []byte) []byte {
+		bz := binary.LittleEndian.AppendUint16(nil, uint16(extensionType))
+		bz = binary.LittleEndian.AppendUint16(bz, uint16(len(value)))
+		return append(bz, value...)
+	}
+	feeConfig := make([]byte, 72)
+	for _, fee := range []solana_types.TransferFee{{Epoch: 10, MaximumFee: 100, BasisPoints: 5}, {Epoch: 20, MaximumFee: 200, BasisPoints: 10}} {
+		feeConfig = binary.LittleEndian.AppendUint64(feeConfig, fee.Epoch)
+		feeConfig = binary.LittleEndian.AppendUint64(feeConfig, fee.MaximumFee)
+		feeConfig = binary.LittleEndian.AppendUint16(feeConfig, fee.BasisPoints)
+	}
+	hookProgram := solana.MustPublicKeyFromBase58("DrWbQtYJGtsoRwzKqAbHKHKsCJJfpysudF39GBVFSxub")
+	hook := append(make([]byte, 32), hookProgram[:]...)
+
+	mint := make([]byte, solana_types.TokenAccountLen)
+	mint = append(mint, 1) // mint account type
+	mint = append(mint, tlv(solana_types.ExtensionTransferFeeConfig, feeConfig)...)
+	mint = append(mint, tlv(solana_types.ExtensionTransferHook, hook)...)
+
+	extensions, err := solana_types.ParseTokenExtensions(mint)
+	require.NoError(t, err)
+	require.Len(t, extensions, 2)
+	config, err := solana_types.ParseTransferFeeConfig(extensions[solana_types.ExtensionTransferFeeConfig])
+	require.NoError(t, err)
+	require.EqualValues(t, 5, config.GetTransferFee(19).BasisPoints)
+	require.EqualValues(t, 10, config.GetTransferFee(20).BasisPoints)
+	program, err := solana_types.ParseTransferHook(extensions[solana_types.ExtensionTransferHook])
+	require.NoError(t, err)
+	require.Equal(t, hookProgram, program)
+
+	// no extensions on a plain mint
+	extensions, err = solana_types.ParseTokenExtensions(make([]byte, solana_types.MintAccountLen))
+	require.NoError(t, err)
+	require.Empty(t, extensions)
+
+	// a token account requiring memos
+	account := make([]byte, solana_types.TokenAccountLen)
+	account = append(account, 2) // token account type
+	account = append(account, tlv(solana_types.ExtensionMemoTransfer, []byte{1})...)
+	extensions, err = solana_types.ParseTokenExtensions(account)
+	require.NoError(t, err)
+	required, err := solana_types.ParseMemoTransfer(extensions[solana_types.ExtensionMemoTransfer])
+	require.NoError(t, err)
+	require.True(t, required)
+}
+
+func TestResolveTransferHookExtraAccounts(t *testing.T) {
+	hookProgram := solana.MustPublicKeyFromBase58("DrWbQtYJGtsoRwzKqAbHKHKsCJJfpysudF39GBVFSxub")
+	literal := solana.MustPublicKeyFromBase58("CiDwVBFgWV9E5MvXWoLgnEgn2hK7rJikbvfWavzAQz3")
+	accounts := solana.PublicKeySlice{
+		solana.PublicKey{1}, // source
+		solana.PublicKey{2}, // mint
+		solana.PublicKey{3}, // destination
+		solana.PublicKey{4}, // owner
+		solana.PublicKey{5}, // validation
+	}
+
+	metas := []byte{}
+	// a fixed account
+	metas = append(metas, 0)
+	metas = append(metas, literal[:]...)
+	metas = append(metas, 0, 1)
+	// a PDA of the hook from a literal and the owner
+	config := append([]byte{1, 7}, []byte("counter")...)
+	config = append(config, 3, 3)
+	metas = append(metas, 1)
+	metas = append(metas, append(config, make([]byte, 32-len(config))...)...)
+	metas = append(metas, 0, 1)
+	// a PDA of the fixed account from the amount and data of the source
+	config = []byte{2, 8, 8, 4, 0, 0, 2}
+	metas = append(metas, 128+5)
+	metas = append(metas, append(config, make([]byte, 32-len(config))...)...)
+	metas = append(metas, 0, 0)
+
+	value := binary.LittleEndian.AppendUint32(nil, 3)
+	value = append(value, metas...)
+	data := append([]byte{}, solana_types.TransferHookExecuteDiscriminator()...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(value)))
+	data = append(data, value...)
+
+	parsed, err := solana_types.ParseExtraAccountMetas(data)
+	require.NoError(t, err)
+	require.Len(t, parsed, 3)
+
+	executeData := binary.LittleEndian.AppendUint64(solana_types.TransferHookExecuteDiscriminator(), 1000)
+	resolved, err := solana_types.ResolveExtraAccountMetas(hookProgram, parsed, accounts, executeData, func(account solana.PublicKey) ([]byte, error) {
+		require.Equal(t, accounts[0], account)
+		return []byte{9, 9, 9}, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, resolved, 3)
+
+	require.Equal(t, literal, resolved[0].PublicKey)
+	require.True(t, resolved[0].IsWritable)
+
+	counter, _, _ := solana.FindProgramAddress([][]byte{[]byte("counter"), accounts[3][:]}, hookProgram)
+	require.Equal(t, counter, resolved[1].PublicKey)
+
+	amountPda, _, _ := solana.FindProgramAddress([][]byte{executeData[8:16], {9, 9}}, literal)
+	require.Equal(t, amountPda, resolved[2].PublicKey)
+	require.False(t, resolved[2].IsWritable)
+}
@@ -0,0 +1,235 @@
+package builder
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	compute_budget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	"github.com/openweb3-io/crosschain/bridge/wormhole"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// How many guardian signatures are verified per transaction, to stay under the transaction size limit
+const wormholeSignaturesPerTx = 7
+
+// Layout of the secp256k1 program's instruction data
+const (
+	secp256k1OffsetsLen = 11
+	// ethereum address, signature, recovery id
+	secp256k1SignatureLen = 20 + 64 + 1
+)
+
+// The secp256k1 instruction must immediately precede verify-signatures, after the compute budget instruction
+const wormholeSecp256k1InstructionIndex = 1
+
+// NewWormholeRedeem completes a token bridge transfer to solana.  Redeeming takes several transactions,
+// which must be submitted in order, each after the previous has confirmed:
+//   - the guardian signatures are verified into the signature set account, a batch per transaction
+//   - the VAA is posted to the core bridge
+//   - the transfer is completed with the token bridge, minting or releasing the tokens to the recipient
+func (txBuilder TxBuilder) NewWormholeRedeem(from xc_types.Address, vaaBytes []byte, input *tx_input.WormholeRedeemInput) ([]xc_types.Tx, error) {
+	vaa, err := wormhole.ParseVAA(vaaBytes)
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := wormhole.ParseTokenTransfer(vaa.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if !transfer.ToChain.IsSolana() {
+		return nil, fmt.Errorf("wormhole transfer is for chain %d, not solana", transfer.ToChain)
+	}
+	payer, err := solana.PublicKeyFromBase58(string(from))
+	if err != nil {
+		return nil, err
+	}
+	if input.SignatureSetKey == nil {
+		return nil, fmt.Errorf("a key for the signature set account is required")
+	}
+	if input.Mint.IsZero() {
+		return nil, fmt.Errorf("the mint is required to redeem")
+	}
+	if input.DurableNonce != nil {
+		// only the first transaction could use the nonce before it advances
+		return nil, fmt.Errorf("a durable nonce cannot be used to redeem, which takes several transactions")
+	}
+	guardians, err := vaa.RecoverGuardians()
+	if err != nil {
+		return nil, err
+	}
+
+	txs := []xc_types.Tx{}
+	for start := 0; start < len(vaa.Signatures); start += wormholeSignaturesPerTx {
+		end := start + wormholeSignaturesPerTx
+		if end > len(vaa.Signatures) {
+			end = len(vaa.Signatures)
+		}
+		batch := make([][20]byte, end-start)
+		for i := range batch {
+			copy(batch[i][:], guardians[start+i].Bytes())
+		}
+		secp256k1, signers, err := newWormholeSecp256k1Instruction(vaa, vaa.Signatures[start:end], batch)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := txBuilder.buildSolanaTx([]solana.Instruction{
+			compute_budget.NewSetComputeUnitPriceInstruction(
+				input.GetLimitedPrioritizationFee(txBuilder.Chain),
+			).Build(),
+			secp256k1,
+			txBuilder.newWormholeVerifySignaturesInstruction(payer, vaa, signers, input),
+		}, payer, &input.TxInput)
+		if err != nil {
+			return nil, err
+		}
+		// The signature set is created by the first batch and must sign every batch
+		tx.AddTransientSigner(input.SignatureSetKey)
+		txs = append(txs, tx)
+	}
+
+	tx, err := txBuilder.buildSolanaTx([]solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+		txBuilder.newWormholePostVaaInstruction(payer, vaa, input),
+	}, payer, &input.TxInput)
+	if err != nil {
+		return nil, err
+	}
+	txs = append(txs, tx)
+
+	tx, err = txBuilder.buildSolanaTx([]solana.Instruction{
+		compute_budget.NewSetComputeUnitPriceInstruction(
+			input.GetLimitedPrioritizationFee(txBuilder.Chain),
+		).Build(),
+		txBuilder.newWormholeCompleteTransferInstruction(payer, vaa, transfer, input),
+	}, payer, &input.TxInput)
+	if err != nil {
+		return nil, err
+	}
+	txs = append(txs, tx)
+	return txs, nil
+}
+
+// The secp256k1 program verifies each guardian signed the VAA, and the core bridge then checks its
+// instruction data against the guardian set.  Returns the position of each guardian in the instruction.
+func newWormholeSecp256k1Instruction(vaa *wormhole.VAA, signatures []wormhole.Signature, guardians [][20]byte) (solana.Instruction, [solana_types.WormholeMaxGuardians]int8, error) {
+	signers := [solana_types.WormholeMaxGuardians]int8{}
+	for i := range signers {
+		signers[i] = -1
+	}
+	count := len(signatures)
+	dataStart := 1 + count*secp256k1OffsetsLen
+	messageOffset := dataStart + count*secp256k1SignatureLen
+	// the program hashes the message again, which gives the digest the guardians signed
+	message := vaa.Hash()
+
+	data := []byte{uint8(count)}
+	for i, sig := range signatures {
+		if int(sig.GuardianIndex) >= solana_types.WormholeMaxGuardians {
+			return nil, signers, fmt.Errorf("invalid guardian index %d", sig.GuardianIndex)
+		}
+		signers[sig.GuardianIndex] = int8(i)
+		addressOffset := dataStart + i*secp256k1SignatureLen
+		data = binary.LittleEndian.AppendUint16(data, uint16(addressOffset+20))
+		data = append(data, wormholeSecp256k1InstructionIndex)
+		data = binary.LittleEndian.AppendUint16(data, uint16(addressOffset))
+		data = append(data, wormholeSecp256k1InstructionIndex)
+		data = binary.LittleEndian.AppendUint16(data, uint16(messageOffset))
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(message)))
+		data = append(data, wormholeSecp256k1InstructionIndex)
+	}
+	for i, sig := range signatures {
+		data = append(data, guardians[i][:]...)
+		data = append(data, sig.Signature[:]...)
+	}
+	data = append(data, message...)
+	return solana.NewInstruction(solana.Secp256k1ProgramID, solana.AccountMetaSlice{}, data), signers, nil
+}
+
+func (txBuilder TxBuilder) newWormholeVerifySignaturesInstruction(payer solana.PublicKey, vaa *wormhole.VAA, signers [solana_types.WormholeMaxGuardians]int8, input *tx_input.WormholeRedeemInput) solana.Instruction {
+	data := []byte{solana_types.WormholeVerifySignaturesInstruction}
+	for _, signer := range signers {
+		data = append(data, uint8(signer))
+	}
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(payer).SIGNER().WRITE(),
+		solana.Meta(solana_types.WormholeGuardianSetPda(input.Programs, vaa.GuardianSetIndex)),
+		solana.Meta(input.SignatureSetKey.PublicKey()).SIGNER().WRITE(),
+		solana.Meta(solana.SysVarInstructionsPubkey),
+		solana.Meta(solana.SysVarRentPubkey),
+		solana.Meta(solana.SystemProgramID),
+	}
+	return solana.NewInstruction(input.Programs.CoreBridge, accounts, data)
+}
+
+func (txBuilder TxBuilder) newWormholePostVaaInstruction(payer solana.PublicKey, vaa *wormhole.VAA, input *tx_input.WormholeRedeemInput) solana.Instruction {
+	// the VAA without its signatures, as borsh
+	data := []byte{solana_types.WormholePostVaaInstruction, vaa.Version}
+	data = binary.LittleEndian.AppendUint32(data, vaa.GuardianSetIndex)
+	data = binary.LittleEndian.AppendUint32(data, vaa.Timestamp)
+	data = binary.LittleEndian.AppendUint32(data, vaa.Nonce)
+	data = binary.LittleEndian.AppendUint16(data, uint16(vaa.EmitterChain))
+	data = append(data, vaa.EmitterAddress[:]...)
+	data = binary.LittleEndian.AppendUint64(data, vaa.Sequence)
+	data = append(data, vaa.ConsistencyLevel)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(vaa.Payload)))
+	data = append(data, vaa.Payload...)
+
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(solana_types.WormholeGuardianSetPda(input.Programs, vaa.GuardianSetIndex)),
+		solana.Meta(solana_types.WormholeBridgeConfigPda(input.Programs)),
+		solana.Meta(input.SignatureSetKey.PublicKey()),
+		solana.Meta(solana_types.WormholePostedVaaPda(input.Programs, vaa.Hash())).WRITE(),
+		solana.Meta(payer).SIGNER().WRITE(),
+		solana.Meta(solana.SysVarClockPubkey),
+		solana.Meta(solana.SysVarRentPubkey),
+		solana.Meta(solana.SystemProgramID),
+	}
+	return solana.NewInstruction(input.Programs.CoreBridge, accounts, data)
+}
+
+func (txBuilder TxBuilder) newWormholeCompleteTransferInstruction(payer solana.PublicKey, vaa *wormhole.VAA, transfer *wormhole.TokenTransfer, input *tx_input.WormholeRedeemInput) solana.Instruction {
+	programs := input.Programs
+	tokenProgram := input.TokenProgram
+	if tokenProgram.IsZero() {
+		tokenProgram = solana.TokenProgramID
+	}
+	emitterChain := uint16(vaa.EmitterChain)
+	to := solana.PublicKeyFromBytes(transfer.To[:])
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(payer).SIGNER().WRITE(),
+		solana.Meta(solana_types.WormholeTokenBridgeConfigPda(programs)),
+		solana.Meta(solana_types.WormholePostedVaaPda(programs, vaa.Hash())),
+		solana.Meta(solana_types.WormholeClaimPda(programs, emitterChain, vaa.EmitterAddress, vaa.Sequence)).WRITE(),
+		solana.Meta(solana_types.WormholeEndpointPda(programs, emitterChain, vaa.EmitterAddress)),
+		solana.Meta(to).WRITE(),
+		// any relayer fee goes to the recipient, as it's redeeming for itself
+		solana.Meta(to).WRITE(),
+	}
+	instruction := uint8(solana_types.WormholeCompleteNativeInstruction)
+	if input.Wrapped {
+		instruction = solana_types.WormholeCompleteWrappedInstruction
+		accounts = append(accounts,
+			solana.Meta(input.Mint).WRITE(),
+			solana.Meta(solana_types.WormholeWrappedMetaPda(programs, input.Mint)),
+			solana.Meta(solana_types.WormholeMintSignerPda(programs)),
+		)
+	} else {
+		accounts = append(accounts,
+			solana.Meta(solana_types.WormholeCustodyPda(programs, input.Mint)).WRITE(),
+			solana.Meta(input.Mint),
+			solana.Meta(solana_types.WormholeCustodySignerPda(programs)),
+		)
+	}
+	accounts = append(accounts,
+		solana.Meta(solana.SysVarRentPubkey),
+		solana.Meta(solana.SystemProgramID),
+		solana.Meta(programs.CoreBridge),
+		solana.Meta(tokenProgram),
+	)
+	return solana.NewInstruction(programs.TokenBridge, accounts, []byte{instruction})
+}
@@ -0,0 +1,156 @@
+package builder_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	"github.com/openweb3-io/crosschain/blockchain/solana/types"
+	"github.com/openweb3-io/crosschain/bridge/wormhole"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWormholeRedeem(t *testing.T) {
+	require := require.New(t)
+	txBuilder, _ := builder.NewTxBuilder(&xc_types.ChainConfig{})
+	payer := xc_types.Address("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	recipient := solana.MustPublicKeyFromBase58("9Wz2nLVfXVHk8mXXyX2ZhcHvJzq8ALSHWHHdHaeGxz4i")
+
+	tokenBridge, _ := wormhole.AddressBytes32(wormhole.ChainEthereum, "0x3ee18B2214AFF97000D974cf647E7C347E8fa585")
+	weth, _ := wormhole.AddressBytes32(wormhole.ChainEthereum, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	transfer := &wormhole.TokenTransfer{
+		PayloadID:    wormhole.PayloadTransfer,
+		Amount:       big.NewInt(1_000),
+		TokenAddress: weth,
+		TokenChain:   wormhole.ChainEthereum,
+		To:           recipient,
+		ToChain:      wormhole.ChainSolana,
+		Fee:          big.NewInt(0),
+	}
+	obs := &wormhole.Observation{
+		Timestamp:        1_700_000_000,
+		Nonce:            3,
+		EmitterChain:     wormhole.ChainEthereum,
+		EmitterAddress:   tokenBridge,
+		Sequence:         55,
+		ConsistencyLevel: 1,
+		Payload:          transfer.Bytes(),
+	}
+	keys := []*ecdsa.PrivateKey{}
+	for i := 0; i < 9; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+	}
+	vaa, err := wormhole.NewLocalGuardians(4, keys...).Sign(obs)
+	require.NoError(err)
+
+	programs := types.WormholeMainnetPrograms
+	signatureSetKey, _ := solana.NewRandomPrivateKey()
+	mint := types.WormholeWrappedMintPda(programs, uint16(wormhole.ChainEthereum), weth)
+	input := tx_input.NewWormholeRedeemInput()
+	input.RecentBlockHash = solana.MustHashFromBase58("DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK")
+	input.Programs = programs
+	input.Mint = mint
+	input.Wrapped = true
+	_, err = txBuilder.NewWormholeRedeem(payer, vaa.Bytes(), input)
+	require.ErrorContains(err, "signature set account is required")
+	input.SignatureSetKey = signatureSetKey
+
+	txs, err := txBuilder.NewWormholeRedeem(payer, vaa.Bytes(), input)
+	require.NoError(err)
+	// two batches of signatures, posting the vaa, then completing the transfer
+	require.Len(txs, 4)
+
+	for batch, signatures := range []int{7, 2} {
+		solTx := txs[batch].(*Tx).SolTx
+		// the payer and the signature set sign
+		require.EqualValues(2, solTx.Message.Header.NumRequiredSignatures)
+		require.Len(solTx.Message.Instructions, 3)
+		// fits in a packet with both signatures
+		message, _ := solTx.Message.MarshalBinary()
+		require.LessOrEqual(1+2*64+len(message), 1232)
+		secp256k1 := solTx.Message.Instructions[1]
+		program, _ := solTx.Message.ResolveProgramIDIndex(secp256k1.ProgramIDIndex)
+		require.Equal(solana.Secp256k1ProgramID, program)
+		data := []byte(secp256k1.Data)
+		require.EqualValues(signatures, data[0])
+		require.Len(data, 1+signatures*(11+85)+32)
+		require.Equal(vaa.Hash(), data[len(data)-32:])
+		// the first signer's address follows the offsets
+		first := 1 + signatures*11
+		require.Equal(crypto.PubkeyToAddress(keys[batch*7].PublicKey).Bytes(), data[first:first+20])
+		require.EqualValues(first+20, binary.LittleEndian.Uint16(data[1:3]))
+
+		verify := solTx.Message.Instructions[2]
+		program, _ = solTx.Message.ResolveProgramIDIndex(verify.ProgramIDIndex)
+		require.Equal(programs.CoreBridge, program)
+		data = []byte(verify.Data)
+		require.Len(data, 1+19)
+		require.EqualValues(types.WormholeVerifySignaturesInstruction, data[0])
+		for guardian := 0; guardian < 19; guardian++ {
+			expected := int8(-1)
+			if guardian >= batch*7 && guardian < batch*7+signatures {
+				expected = int8(guardian - batch*7)
+			}
+			require.Equal(expected, int8(data[1+guardian]), "guardian %d", guardian)
+		}
+		accounts, err := verify.ResolveInstructionAccounts(&solTx.Message)
+		require.NoError(err)
+		require.Equal(types.WormholeGuardianSetPda(programs, 4), accounts[1].PublicKey)
+		require.Equal(signatureSetKey.PublicKey(), accounts[2].PublicKey)
+		require.True(accounts[2].IsSigner)
+	}
+
+	solTx := txs[2].(*Tx).SolTx
+	post := solTx.Message.Instructions[1]
+	data := []byte(post.Data)
+	require.EqualValues(types.WormholePostVaaInstruction, data[0])
+	require.EqualValues(1, data[1])
+	require.EqualValues(4, binary.LittleEndian.Uint32(data[2:6]))
+	require.EqualValues(55, binary.LittleEndian.Uint64(data[48:56]))
+	require.Equal(obs.Payload, data[61:])
+	accounts, err := post.ResolveInstructionAccounts(&solTx.Message)
+	require.NoError(err)
+	postedVaa := types.WormholePostedVaaPda(programs, vaa.Hash())
+	require.Equal(postedVaa, accounts[3].PublicKey)
+
+	solTx = txs[3].(*Tx).SolTx
+	complete := solTx.Message.Instructions[1]
+	program, _ := solTx.Message.ResolveProgramIDIndex(complete.ProgramIDIndex)
+	require.Equal(programs.TokenBridge, program)
+	require.Equal([]byte{types.WormholeCompleteWrappedInstruction}, []byte(complete.Data))
+	accounts, err = complete.ResolveInstructionAccounts(&solTx.Message)
+	require.NoError(err)
+	require.Len(accounts, 14)
+	require.Equal(postedVaa, accounts[2].PublicKey)
+	require.Equal(types.WormholeClaimPda(programs, 2, tokenBridge, 55), accounts[3].PublicKey)
+	require.Equal(recipient, accounts[5].PublicKey)
+	require.Equal(mint, accounts[7].PublicKey)
+	require.True(accounts[7].IsWritable)
+
+	// tokens native to solana are released from custody
+	input.Wrapped = false
+	input.Mint = solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	txs, err = txBuilder.NewWormholeRedeem(payer, vaa.Bytes(), input)
+	require.NoError(err)
+	solTx = txs[3].(*Tx).SolTx
+	complete = solTx.Message.Instructions[1]
+	require.Equal([]byte{types.WormholeCompleteNativeInstruction}, []byte(complete.Data))
+	accounts, err = complete.ResolveInstructionAccounts(&solTx.Message)
+	require.NoError(err)
+	require.Equal(types.WormholeCustodyPda(programs, input.Mint), accounts[7].PublicKey)
+	require.Equal(input.Mint, accounts[8].PublicKey)
+
+	// the transfer must be for solana
+	transfer.ToChain = wormhole.ChainBase
+	obs.Payload = transfer.Bytes()
+	vaa, _ = wormhole.NewLocalGuardians(4, keys...).Sign(obs)
+	_, err = txBuilder.NewWormholeRedeem(payer, vaa.Bytes(), input)
+	require.ErrorContains(err, "not solana")
+}
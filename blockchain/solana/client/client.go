@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -9,6 +10,7 @@ import (
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
+	lookup "github.com/gagliardetto/solana-go/programs/address-lookup-table"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
 	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
@@ -39,6 +41,11 @@ func NewClient(cfg *xc.ChainConfig) (*Client, error) {
 	return &Client{cfg: cfg, client: client}, nil
 }
 
+// The underlying RPC client, for staking providers built on this client
+func (client *Client) RpcClient() *rpc.Client {
+	return client.client
+}
+
 func (client *Client) FetchBaseInput(ctx context.Context, fromAddr xc.Address) (*tx_input.TxInput, error) {
 	txInput := tx_input.NewTxInput()
 
@@ -52,35 +59,161 @@ func (client *Client) FetchBaseInput(ctx context.Context, fromAddr xc.Address) (
 	}
 	txInput.RecentBlockHash = recent.Value.Blockhash
 
+	if len(client.cfg.AddressLookupTables) > 0 {
+		tables := []solana.PublicKey{}
+		for _, table := range client.cfg.AddressLookupTables {
+			key, err := solana.PublicKeyFromBase58(table)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address lookup table '%s': %v", table, err)
+			}
+			tables = append(tables, key)
+		}
+		txInput.AddressLookupTables, err = client.FetchAddressLookupTables(ctx, tables...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return txInput, nil
 }
 
+// Fetch the addresses in lookup tables, skipping any tables that have been deactivated
+func (client *Client) FetchAddressLookupTables(ctx context.Context, tables ...solana.PublicKey) ([]*tx_input.AddressLookupTable, error) {
+	results := []*tx_input.AddressLookupTable{}
+	for _, table := range tables {
+		info, err := client.client.GetAccountInfo(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("could not lookup address lookup table %s: %v", table, err)
+		}
+		if !info.Value.Owner.Equals(solana_types.AddressLookupTableProgram) {
+			return nil, fmt.Errorf("%s is not an address lookup table", table)
+		}
+		state, err := lookup.DecodeAddressLookupTableState(info.Value.Data.GetBinary())
+		if err != nil {
+			return nil, fmt.Errorf("could not decode address lookup table %s: %v", table, err)
+		}
+		if !state.IsActive() {
+			continue
+		}
+		results = append(results, &tx_input.AddressLookupTable{
+			Account:   table,
+			Addresses: state.Addresses,
+		})
+	}
+	return results, nil
+}
+
 func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.TransferArgs) (xc.TxInput, error) {
 	txInput, err := client.FetchBaseInput(ctx, args.GetFrom())
 	if err != nil {
 		return nil, err
 	}
+	if nonceAccount, ok := args.GetNonceAccount(); ok {
+		txInput.DurableNonce, err = client.FetchDurableNonce(ctx, nonceAccount)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	asset, _ := args.GetAsset()
-	if asset == nil {
-		return txInput, nil
+	if _, ok := asset.(*xc.TokenAssetConfig); ok {
+		err = client.fetchTokenTransferInput(ctx, args, asset, txInput)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	txBuilder, err := builder.NewTxBuilder(client.cfg)
+	if err != nil {
+		return nil, err
 	}
+	// build the transaction to see which accounts it writes to
+	exampleTx, err := txBuilder.NewTransfer(args, txInput)
+	if err != nil {
+		return nil, err
+	}
+	writable, err := exampleTx.(*tx.Tx).SolTx.Message.Writable()
+	if err != nil {
+		return nil, err
+	}
+	fees, err := client.client.GetRecentPrioritizationFees(ctx, writable)
+	if err != nil {
+		return txInput, fmt.Errorf("could not lookup priority fees: %v", err)
+	}
+	recentFees := []uint64{}
+	for _, fee := range fees {
+		recentFees = append(recentFees, fee.PrioritizationFee)
+	}
+	txInput.SetRecentPrioritizationFees(recentFees, client.cfg)
+
+	// rebuild with the priority fee, and limit the compute units to what the transaction needs
+	exampleTx, err = txBuilder.NewTransfer(args, txInput)
+	if err != nil {
+		return nil, err
+	}
+	units, err := client.SimulateComputeUnits(ctx, exampleTx.(*tx.Tx).SolTx)
+	if err != nil {
+		// leave the default limit
+		logrus.WithError(err).Warn("could not simulate transaction to estimate compute units")
+	} else {
+		txInput.ComputeUnitLimit = GetComputeUnitLimit(units)
+	}
+
+	return txInput, nil
+}
+
+// Accounts can change before a transaction lands, so the compute unit limit
+// leaves some headroom over the units consumed in simulation.
+const ComputeUnitLimitMargin = 1.2
+
+func GetComputeUnitLimit(unitsConsumed uint64) uint32 {
+	limit := uint64(float64(unitsConsumed) * ComputeUnitLimitMargin)
+	if limit > tx_input.MaxComputeUnitLimit {
+		limit = tx_input.MaxComputeUnitLimit
+	}
+	return uint32(limit)
+}
+
+// Simulate an unsigned transaction to find how many compute units it consumes
+func (client *Client) SimulateComputeUnits(ctx context.Context, solTx *solana.Transaction) (uint64, error) {
+	simTx := *solTx
+	simTx.Signatures = make([]solana.Signature, solTx.Message.Header.NumRequiredSignatures)
+	resp, err := client.client.SimulateTransactionWithOpts(ctx, &simTx, &rpc.SimulateTransactionOpts{
+		SigVerify:              false,
+		ReplaceRecentBlockhash: true,
+		Commitment:             rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if resp.Value == nil {
+		return 0, errors.New("no simulation result")
+	}
+	if resp.Value.Err != nil {
+		return 0, fmt.Errorf("simulation failed: %v", resp.Value.Err)
+	}
+	if resp.Value.UnitsConsumed == nil {
+		return 0, errors.New("simulation did not report the compute units consumed")
+	}
+	return *resp.Value.UnitsConsumed, nil
+}
 
+func (client *Client) fetchTokenTransferInput(ctx context.Context, args *xcbuilder.TransferArgs, asset xc.IAsset, txInput *tx_input.TxInput) error {
 	mint, err := solana.PublicKeyFromBase58(string(asset.GetContract()))
 	if err != nil {
-		return nil, errors.Wrapf(err, "invalid mint address: %s", string(asset.GetContract()))
+		return errors.Wrapf(err, "invalid mint address: %s", string(asset.GetContract()))
 	}
 
 	mintInfo, err := client.client.GetAccountInfo(ctx, mint)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	txInput.TokenProgram = mintInfo.Value.Owner
 
 	// get account info - check if to is an owner or ata
 	accountTo, err := solana.PublicKeyFromBase58(string(args.GetTo()))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Determine if destination is a token account or not by
@@ -97,22 +230,33 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	if !txInput.ToIsATA {
 		ataToStr, err := solana_types.FindAssociatedTokenAddress(string(args.GetTo()), string(asset.GetContract()), mintInfo.Value.Owner)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		ataTo = solana.MustPublicKeyFromBase58(ataToStr)
 	}
 
-	_, err = client.client.GetAccountInfo(ctx, ataTo)
+	toInfo, err := client.client.GetAccountInfo(ctx, ataTo)
 	if err != nil {
 		// if the ATA doesn't exist yet, we will create when sending tokens
 		txInput.ShouldCreateATA = true
+	} else if txInput.TokenProgram.Equals(solana.Token2022ProgramID) {
+		extensions, err := solana_types.ParseTokenExtensions(toInfo.Value.Data.GetBinary())
+		if err != nil {
+			return fmt.Errorf("could not parse extensions of token account %s: %v", ataTo, err)
+		}
+		if memoTransfer, ok := extensions[solana_types.ExtensionMemoTransfer]; ok {
+			txInput.MemoRequired, err = solana_types.ParseMemoTransfer(memoTransfer)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Fetch all token accounts as if they are utxo
 	if asset.GetContract() != "" {
 		tokenAccounts, err := client.GetTokenAccountsByOwner(ctx, string(args.GetFrom()), string(asset.GetContract()))
 		if err != nil {
-			return nil, err
+			return err
 		}
 		zero := xc.NewBigIntFromInt64(0)
 
@@ -129,46 +273,103 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 		sort.Slice(txInput.SourceTokenAccounts, func(i, j int) bool {
 			return txInput.SourceTokenAccounts[i].Balance.Cmp(&txInput.SourceTokenAccounts[j].Balance) > 0
 		})
-		if len(txInput.SourceTokenAccounts) > builder.MaxTokenTransfers {
-			txInput.SourceTokenAccounts = txInput.SourceTokenAccounts[:builder.MaxTokenTransfers]
+		if max := builder.GetMaxTokenTransfers(txInput); len(txInput.SourceTokenAccounts) > max {
+			txInput.SourceTokenAccounts = txInput.SourceTokenAccounts[:max]
 		}
 
 		if len(tokenAccounts) == 0 {
 			// no balance
-			return nil, errors.New("no balance to send solana token")
+			return errors.New("no balance to send solana token")
 		}
 	}
 
-	// fetch priority fee info
-	accountsToLock := solana.PublicKeySlice{}
-	accountsToLock = append(accountsToLock, mint)
-	fees, err := client.client.GetRecentPrioritizationFees(ctx, accountsToLock)
+	if txInput.TokenProgram.Equals(solana.Token2022ProgramID) {
+		return client.setMintExtensionsInput(ctx, args, mint, mintInfo.Value.Data.GetBinary(), ataTo, txInput)
+	}
+	return nil
+}
+
+// Set the input for the Token-2022 extensions of the mint that change how it's transferred
+func (client *Client) setMintExtensionsInput(ctx context.Context, args *xcbuilder.TransferArgs, mint solana.PublicKey, mintData []byte, destination solana.PublicKey, txInput *tx_input.TxInput) error {
+	extensions, err := solana_types.ParseTokenExtensions(mintData)
 	if err != nil {
-		return txInput, fmt.Errorf("could not lookup priority fees: %v", err)
+		return fmt.Errorf("could not parse extensions of mint %s: %v", mint, err)
 	}
-	priority_fee_count := uint64(0)
-	// start with 100 min priority fee, then average in the recent priority fees paid.
-	priority_fee_sum := uint64(100)
-	for _, fee := range fees {
-		if fee.PrioritizationFee > 0 {
-			priority_fee_sum += fee.PrioritizationFee
-			priority_fee_count += 1
+
+	if feeConfigData, ok := extensions[solana_types.ExtensionTransferFeeConfig]; ok {
+		feeConfig, err := solana_types.ParseTransferFeeConfig(feeConfigData)
+		if err != nil {
+			return err
+		}
+		epoch, err := client.client.GetEpochInfo(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			return fmt.Errorf("could not lookup epoch: %v", err)
 		}
+		fee := feeConfig.GetTransferFee(epoch.Epoch)
+		txInput.TransferFee = &fee
 	}
-	if priority_fee_count > 0 {
-		txInput.PrioritizationFee = xc.NewBigIntFromUint64(
-			priority_fee_sum / priority_fee_count,
+
+	if hookData, ok := extensions[solana_types.ExtensionTransferHook]; ok {
+		hookProgram, err := solana_types.ParseTransferHook(hookData)
+		if err != nil {
+			return err
+		}
+		if hookProgram.IsZero() {
+			return nil
+		}
+		owner, err := solana.PublicKeyFromBase58(string(args.GetFrom()))
+		if err != nil {
+			return err
+		}
+		if len(txInput.SourceTokenAccounts) == 0 {
+			return errors.New("no balance to send solana token")
+		}
+		// the extra accounts may depend on the source, so only one source can be used
+		if len(txInput.SourceTokenAccounts) > 1 {
+			txInput.SourceTokenAccounts = txInput.SourceTokenAccounts[:1]
+		}
+		source := txInput.SourceTokenAccounts[0].Account
+
+		validation := solana_types.TransferHookExtraAccountMetasPda(hookProgram, mint)
+		validationInfo, err := client.client.GetAccountInfo(ctx, validation)
+		if err != nil {
+			return fmt.Errorf("could not lookup extra accounts of transfer hook %s: %v", hookProgram, err)
+		}
+		metas, err := solana_types.ParseExtraAccountMetas(validationInfo.Value.Data.GetBinary())
+		if err != nil {
+			return err
+		}
+		executeData := binary.LittleEndian.AppendUint64(solana_types.TransferHookExecuteDiscriminator(), args.GetAmount().Uint64())
+		resolved, err := solana_types.ResolveExtraAccountMetas(
+			hookProgram,
+			metas,
+			solana.PublicKeySlice{source, mint, destination, owner, validation},
+			executeData,
+			func(account solana.PublicKey) ([]byte, error) {
+				info, err := client.client.GetAccountInfo(ctx, account)
+				if err != nil {
+					return nil, fmt.Errorf("could not lookup account %s for transfer hook: %v", account, err)
+				}
+				return info.Value.Data.GetBinary(), nil
+			},
 		)
-	} else {
-		// default 100
-		txInput.PrioritizationFee = xc.NewBigIntFromUint64(
-			100,
+		if err != nil {
+			return fmt.Errorf("could not resolve extra accounts of transfer hook %s: %v", hookProgram, err)
+		}
+		for _, account := range resolved {
+			txInput.TransferHookAccounts = append(txInput.TransferHookAccounts, &tx_input.TransferHookAccount{
+				Account:    account.PublicKey,
+				IsSigner:   account.IsSigner,
+				IsWritable: account.IsWritable,
+			})
+		}
+		// token-2022 also needs the hook program and its validation account to invoke it
+		txInput.TransferHookAccounts = append(txInput.TransferHookAccounts,
+			&tx_input.TransferHookAccount{Account: hookProgram},
+			&tx_input.TransferHookAccount{Account: validation},
 		)
 	}
-	// apply multiplier
-	txInput.PrioritizationFee = txInput.PrioritizationFee.ApplyGasPriceMultiplier(client.cfg)
-
-	return txInput, nil
+	return nil
 }
 
 func (a *Client) EstimateGasFee(ctx context.Context, _tx xc.Tx) (*xc.BigInt, error) {
@@ -307,6 +508,15 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 	}
 	tx := tx.NewTxFrom(solTx)
 	meta := res.Meta
+	if err := tx.SetLoadedAddresses(meta.LoadedAddresses.Writable, meta.LoadedAddresses.ReadOnly); err != nil {
+		return nil, err
+	}
+	tx.SetInnerInstructions(meta.InnerInstructions)
+	accountKeys, err := solTx.Message.GetAllKeys()
+	if err != nil {
+		return nil, err
+	}
+	tokenBalanceAccounts := getTokenBalanceAccounts(meta, accountKeys)
 	if res.BlockTime != nil {
 		result.BlockTime = res.BlockTime.Time().Unix()
 	}
@@ -374,16 +584,8 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 	}
 	for _, instr := range tx.GetTokenTransferCheckeds() {
 		from := instr.GetOwnerAccount().PublicKey.String()
-		toTokenAccount := instr.GetDestinationAccount().PublicKey
 		contract := xc.ContractAddress(instr.GetMintAccount().PublicKey.String())
-		to := xc.Address(toTokenAccount.String())
-		// Solana doesn't keep full historical state, so we can't rely on always being able to lookup the account.
-		tokenAccountInfo, err := client.LookupTokenAccount(ctx, toTokenAccount)
-		if err != nil {
-			logrus.WithError(err).Warn("failed to lookup token account")
-		} else {
-			to = xc.Address(tokenAccountInfo.Parsed.Info.Owner)
-		}
+		to, _ := client.lookupTokenAccountOwner(ctx, instr.GetDestinationAccount().PublicKey, tokenBalanceAccounts)
 
 		amount := xc.NewBigIntFromUint64(*instr.Amount)
 		sources = append(sources, &xc.LegacyTxInfoEndpoint{
@@ -392,25 +594,29 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 			ContractAddress: contract,
 		})
 		dests = append(dests, &xc.LegacyTxInfoEndpoint{
-			Address:         xc.Address(to),
+			Address:         to,
 			Amount:          amount,
 			ContractAddress: contract,
 		})
 	}
+	for _, instr := range tx.GetTokenTransferCheckedWithFees() {
+		to, _ := client.lookupTokenAccountOwner(ctx, instr.Destination, tokenBalanceAccounts)
+		contract := xc.ContractAddress(instr.Mint.String())
+		sources = append(sources, &xc.LegacyTxInfoEndpoint{
+			Address:         xc.Address(instr.Owner.String()),
+			Amount:          xc.NewBigIntFromUint64(instr.Amount),
+			ContractAddress: contract,
+		})
+		// the fee is withheld in the destination account
+		dests = append(dests, &xc.LegacyTxInfoEndpoint{
+			Address:         to,
+			Amount:          xc.NewBigIntFromUint64(instr.Amount - instr.Fee),
+			ContractAddress: contract,
+		})
+	}
 	for _, instr := range tx.GetTokenTransfers() {
 		from := instr.GetOwnerAccount().PublicKey.String()
-		toTokenAccount := instr.GetDestinationAccount().PublicKey
-		tokenAccountInfo, err := client.LookupTokenAccount(ctx, toTokenAccount)
-
-		to := xc.Address(toTokenAccount.String())
-		contract := xc.ContractAddress("")
-		// Solana doesn't keep full historical state, so we can't rely on always being able to lookup the account.
-		if err != nil {
-			logrus.WithError(err).Warn("failed to lookup token account")
-		} else {
-			to = xc.Address(tokenAccountInfo.Parsed.Info.Owner)
-			contract = xc.ContractAddress(tokenAccountInfo.Parsed.Info.Mint)
-		}
+		to, contract := client.lookupTokenAccountOwner(ctx, instr.GetDestinationAccount().PublicKey, tokenBalanceAccounts)
 
 		amount := xc.NewBigIntFromUint64(*instr.Amount)
 		sources = append(sources, &xc.LegacyTxInfoEndpoint{
@@ -419,11 +625,21 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 			ContractAddress: contract,
 		})
 		dests = append(dests, &xc.LegacyTxInfoEndpoint{
-			Address:         xc.Address(to),
+			Address:         to,
 			Amount:          amount,
 			ContractAddress: contract,
 		})
 	}
+	// movements made by instructions that aren't decoded are found from the balances
+	undecodedSources, undecodedDests := getUndecodedMovements(meta, accountKeys, tokenBalanceAccounts, sources, dests)
+	sources = append(sources, undecodedSources...)
+	dests = append(dests, undecodedDests...)
+	if memo := tx.GetMemo(); memo != "" {
+		for _, dest := range dests {
+			dest.Memo = memo
+		}
+	}
+
 	for _, instr := range tx.GetDelegateStake() {
 		xcStake := &xcclient.Stake{
 			Account:   instr.GetStakeAccount().PublicKey.String(),
@@ -459,6 +675,10 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (
 		result.AddStakeEvent(xcStake)
 	}
 
+	for _, message := range client.fetchCctpMessages(ctx, tx) {
+		result.AddMessage(xc.CrossChainProtocolCctp, xc.Address(solana_types.CctpMessageTransmitterProgramID.String()), message)
+	}
+
 	if len(sources) > 0 {
 		result.From = sources[0].Address
 	}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	"github.com/openweb3-io/crosschain/bridge/cctp"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+)
+
+var _ cctp.Endpoint = &Client{}
+
+// Index of the message account in a deposit-for-burn instruction
+const cctpMessageSentAccountIndex = 10
+
+// Fetch the input to burn USDC for the recipient on the destination domain
+func (client *Client) FetchCctpBurnInput(ctx context.Context, args *xcbuilder.TransferArgs, destinationDomain cctp.Domain) (*tx_input.CctpBurnInput, error) {
+	asset, _ := args.GetAsset()
+	if asset == nil || asset.GetContract() == "" {
+		return nil, fmt.Errorf("the usdc token contract is required to burn")
+	}
+	if destinationDomain.IsSolana() {
+		return nil, fmt.Errorf("cannot burn to the same domain %d", destinationDomain)
+	}
+	mintRecipient, err := cctp.MintRecipient(destinationDomain, args.GetTo(), "")
+	if err != nil {
+		return nil, err
+	}
+	txInput, err := client.FetchBaseInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	mint, err := solana.PublicKeyFromBase58(string(asset.GetContract()))
+	if err != nil {
+		return nil, fmt.Errorf("invalid mint address '%s': %v", asset.GetContract(), err)
+	}
+	mintInfo, err := client.client.GetAccountInfo(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+	txInput.TokenProgram = mintInfo.Value.Owner
+
+	eventKey, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	burnInput := tx_input.NewCctpBurnInput()
+	burnInput.TxInput = *txInput
+	burnInput.DestinationDomain = uint32(destinationDomain)
+	burnInput.MintRecipient = solana.PublicKeyFromBytes(mintRecipient[:])
+	burnInput.MessageSentEventKey = eventKey
+	return burnInput, nil
+}
+
+// Fetch the input to receive an attested message on solana
+func (client *Client) FetchCctpReceiveInput(ctx context.Context, from xc.Address, message []byte) (*tx_input.CctpReceiveInput, error) {
+	msg, err := cctp.ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.DestinationDomain.IsSolana() {
+		return nil, fmt.Errorf("cctp message is for domain %d, not solana", msg.DestinationDomain)
+	}
+	burn, err := msg.BurnMessage()
+	if err != nil {
+		return nil, err
+	}
+	received, err := client.IsCctpMessageReceived(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if received {
+		return nil, fmt.Errorf("cctp message from domain %d with nonce %d has already been received", msg.SourceDomain, msg.Nonce)
+	}
+
+	tokenPair := solana_types.CctpTokenPairPda(uint32(msg.SourceDomain), burn.BurnToken)
+	tokenPairInfo, err := client.client.GetAccountInfo(ctx, tokenPair)
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup the cctp token pair for domain %d: %v", msg.SourceDomain, err)
+	}
+	localToken, err := solana_types.ParseCctpTokenPairLocalToken(tokenPairInfo.Value.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+	mintRecipient := solana.PublicKeyFromBytes(burn.MintRecipient[:])
+	if _, err := client.client.GetAccountInfo(ctx, mintRecipient); err != nil {
+		return nil, fmt.Errorf("could not lookup mint recipient token account %s: %v", mintRecipient, err)
+	}
+	mintInfo, err := client.client.GetAccountInfo(ctx, localToken)
+	if err != nil {
+		return nil, err
+	}
+
+	txInput, err := client.FetchBaseInput(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	txInput.TokenProgram = mintInfo.Value.Owner
+	receiveInput := tx_input.NewCctpReceiveInput()
+	receiveInput.TxInput = *txInput
+	receiveInput.LocalToken = localToken
+	return receiveInput, nil
+}
+
+// Check if the message has been received on solana
+func (client *Client) IsCctpMessageReceived(ctx context.Context, msg *cctp.Message) (bool, error) {
+	usedNonces := solana_types.CctpUsedNoncesPda(uint32(msg.SourceDomain), msg.SolanaFirstNonce())
+	info, err := client.client.GetAccountInfo(ctx, usedNonces)
+	if errors.Is(err, rpc.ErrNotFound) {
+		// created on the first receive
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not fetch used cctp nonces: %v", err)
+	}
+	return solana_types.IsCctpNonceUsed(info.Value.Data.GetBinary(), msg.Nonce)
+}
+
+// Lookup the messages sent by deposit-for-burn instructions in the transaction.  The message accounts may be
+// closed to reclaim their rent, after which the message can only be recovered from the attestation service.
+func (client *Client) fetchCctpMessages(ctx context.Context, solTx *tx.Tx) [][]byte {
+	messages := [][]byte{}
+	if solTx.SolTx == nil {
+		return messages
+	}
+	message := solTx.SolTx.Message
+	discriminator := solana_types.AnchorDiscriminator("deposit_for_burn")
+	for _, instruction := range message.Instructions {
+		program, err := message.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+		if err != nil || !program.Equals(solana_types.CctpTokenMessengerMinterProgramID) {
+			continue
+		}
+		if len(instruction.Data) < len(discriminator) || string(instruction.Data[:len(discriminator)]) != string(discriminator) {
+			continue
+		}
+		if len(instruction.Accounts) <= cctpMessageSentAccountIndex {
+			continue
+		}
+		eventAccount, err := message.Account(instruction.Accounts[cctpMessageSentAccountIndex])
+		if err != nil {
+			continue
+		}
+		info, err := client.client.GetAccountInfo(ctx, eventAccount)
+		if err != nil {
+			logrus.WithError(err).WithField("account", eventAccount.String()).Warn("failed to lookup cctp message account")
+			continue
+		}
+		bz, err := solana_types.ParseCctpMessageSent(info.Value.Data.GetBinary())
+		if err != nil {
+			logrus.WithError(err).WithField("account", eventAccount.String()).Warn("failed to parse cctp message account")
+			continue
+		}
+		messages = append(messages, bz)
+	}
+	return messages
+}
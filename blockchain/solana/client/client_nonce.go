@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Fetch the current nonce of a durable nonce account, to use in place of a recent block hash
+func (client *Client) FetchDurableNonce(ctx context.Context, nonceAccount xc.Address) (*tx_input.DurableNonce, error) {
+	account, err := solana.PublicKeyFromBase58(string(nonceAccount))
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce account '%s': %v", nonceAccount, err)
+	}
+	info, err := client.client.GetAccountInfoWithOpts(ctx, account, &rpc.GetAccountInfoOpts{
+		Commitment: rpc.CommitmentFinalized,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup nonce account %s: %v", account, err)
+	}
+	if !info.Value.Owner.Equals(solana.SystemProgramID) {
+		return nil, fmt.Errorf("%s is not a nonce account", account)
+	}
+	nonce, err := solana_types.ParseNonceAccount(info.Value.Data.GetBinary())
+	if err != nil {
+		return nil, fmt.Errorf("could not decode nonce account %s: %v", account, err)
+	}
+	return &tx_input.DurableNonce{
+		Account:   account,
+		Authority: nonce.AuthorizedPubkey,
+		Nonce:     solana.Hash(nonce.Nonce),
+	}, nil
+}
+
+// Fetch the input to create a new durable nonce account, owned by the sender
+func (client *Client) FetchCreateNonceAccountInput(ctx context.Context, from xc.Address) (*tx_input.CreateNonceAccountInput, error) {
+	txInput, err := client.FetchBaseInput(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	authority, err := solana.PublicKeyFromBase58(string(from))
+	if err != nil {
+		return nil, err
+	}
+	rent, err := client.client.GetMinimumBalanceForRentExemption(ctx, solana_types.NonceAccountSize, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup rent exemption: %v", err)
+	}
+	fees, err := client.client.GetRecentPrioritizationFees(ctx, solana.PublicKeySlice{authority})
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup priority fees: %v", err)
+	}
+	recentFees := []uint64{}
+	for _, fee := range fees {
+		recentFees = append(recentFees, fee.PrioritizationFee)
+	}
+	txInput.SetRecentPrioritizationFees(recentFees, client.cfg)
+
+	nonceKey, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &tx_input.CreateNonceAccountInput{
+		TxInput:           *txInput,
+		NonceKey:          nonceKey,
+		RentExemptBalance: xc.NewBigIntFromUint64(rent),
+	}, nil
+}
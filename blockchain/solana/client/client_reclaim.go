@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Which token accounts to close when reclaiming rent
+type ReclaimRentOptions struct {
+	// Accounts holding at most this amount, in the token's smallest unit, are closed after their dust is
+	// burned or transferred.  By default only empty accounts are closed.
+	MaxDust xc.BigInt
+	// Dust is transferred to this owner's token accounts rather than burned.  Accounts with dust
+	// in a token the destination has no account for are left open.
+	DustDestination xc.Address
+}
+
+// Fetch the input to close the token accounts of the sender that are empty, or only hold dust.
+func (client *Client) FetchReclaimRentInput(ctx context.Context, from xc.Address, options ReclaimRentOptions) (*tx_input.ReclaimRentInput, error) {
+	owner, err := solana.PublicKeyFromBase58(string(from))
+	if err != nil {
+		return nil, err
+	}
+	input := &tx_input.ReclaimRentInput{}
+	if options.DustDestination != "" {
+		input.DustDestinationAccounts = map[string]solana.PublicKey{}
+	}
+	// whether the dust destination has an account for each mint
+	destinationChecked := map[string]bool{}
+
+	for _, program := range []solana.PublicKey{solana.TokenProgramID, solana.Token2022ProgramID} {
+		tokenAccounts, err := client.client.GetTokenAccountsByOwner(ctx, owner,
+			&rpc.GetTokenAccountsConfig{ProgramId: &program},
+			&rpc.GetTokenAccountsOpts{Commitment: rpc.CommitmentFinalized, Encoding: "jsonParsed"},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch token accounts of %s: %v", owner, err)
+		}
+		for _, tokenAccount := range tokenAccounts.Value {
+			info, err := solana_types.ParseRpcData(tokenAccount.Account.Data)
+			if err != nil {
+				return nil, err
+			}
+			parsed := info.Parsed.Info
+			if parsed.State == "frozen" || (parsed.CloseAuthority != "" && parsed.CloseAuthority != owner.String()) {
+				continue
+			}
+			if parsed.GetWithheldTransferFees() > 0 {
+				// the fees must be harvested to the mint first
+				continue
+			}
+			mint, err := solana.PublicKeyFromBase58(parsed.Mint)
+			if err != nil {
+				return nil, err
+			}
+			account := &tx_input.ReclaimableTokenAccount{
+				Account:      tokenAccount.Pubkey,
+				Mint:         mint,
+				TokenProgram: program,
+				Balance:      xc.NewBigIntFromStr(parsed.TokenAmount.Amount),
+				Decimals:     uint8(parsed.TokenAmount.Decimals),
+				Lamports:     xc.NewBigIntFromUint64(tokenAccount.Account.Lamports),
+				IsNative:     parsed.IsNative,
+			}
+			if account.HasDust() {
+				if account.Balance.Cmp(&options.MaxDust) > 0 {
+					continue
+				}
+				if options.DustDestination != "" {
+					checked, ok := destinationChecked[parsed.Mint]
+					if !ok {
+						checked, err = client.setDustDestinationAccount(ctx, options.DustDestination, mint, program, input)
+						if err != nil {
+							return nil, err
+						}
+						destinationChecked[parsed.Mint] = checked
+					}
+					if !checked {
+						continue
+					}
+				}
+			}
+			input.TokenAccounts = append(input.TokenAccounts, account)
+		}
+	}
+	if len(input.TokenAccounts) == 0 {
+		return nil, fmt.Errorf("no token accounts of %s can be closed", owner)
+	}
+	// reclaim the most rent first
+	sort.SliceStable(input.TokenAccounts, func(i, j int) bool {
+		return input.TokenAccounts[i].Lamports.Cmp(&input.TokenAccounts[j].Lamports) > 0
+	})
+
+	txInput, err := client.FetchBaseInput(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := client.client.GetRecentPrioritizationFees(ctx, solana.PublicKeySlice{owner})
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup priority fees: %v", err)
+	}
+	recentFees := []uint64{}
+	for _, fee := range fees {
+		recentFees = append(recentFees, fee.PrioritizationFee)
+	}
+	txInput.SetRecentPrioritizationFees(recentFees, client.cfg)
+	input.TxInput = *txInput
+	return input, nil
+}
+
+// Looks up the dust destination's associated token account for the mint, returning false if it has none
+func (client *Client) setDustDestinationAccount(ctx context.Context, destination xc.Address, mint solana.PublicKey, program solana.PublicKey, input *tx_input.ReclaimRentInput) (bool, error) {
+	ataStr, err := solana_types.FindAssociatedTokenAddress(string(destination), mint.String(), program)
+	if err != nil {
+		return false, err
+	}
+	ata := solana.MustPublicKeyFromBase58(ataStr)
+	_, err = client.client.GetAccountInfo(ctx, ata)
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not lookup token account %s: %v", ata, err)
+	}
+	input.DustDestinationAccounts[mint.String()] = ata
+	return true, nil
+}
@@ -0,0 +1,269 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+var _ xclient.StakeManagementClient = &Client{}
+
+func (client *Client) FetchStakeManagementInput(ctx context.Context, args xcbuilder.StakeArgs, operation xc_types.StakeOperation) (xc_types.StakeManagementTxInput, error) {
+	stakeAccounts, err := client.GetStakeAccounts(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	epochInfo, err := client.client.GetEpochInfo(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, err
+	}
+	txInput, err := client.FetchBaseInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	// Set default fee for now
+	txInput.PrioritizationFee = xc_types.NewBigIntFromUint64(100000)
+
+	switch operation {
+	case xc_types.StakeMerge:
+		return client.fetchMergeStakeInput(args, txInput, stakeAccounts, epochInfo.Epoch)
+	case xc_types.StakeSplit:
+		return client.fetchSplitStakeInput(ctx, args, txInput, stakeAccounts, epochInfo.Epoch)
+	case xc_types.StakeMove:
+		return client.fetchMoveStakeInput(ctx, args, txInput, stakeAccounts)
+	case xc_types.StakeAuthorize:
+		return client.fetchAuthorizeStakeInput(args, txInput, stakeAccounts)
+	case xc_types.StakeClose:
+		return client.fetchCloseStakeInput(args, txInput, stakeAccounts, epochInfo.Epoch)
+	default:
+		return nil, fmt.Errorf("unsupported stake operation: %s", operation)
+	}
+}
+
+// Stake accounts can be merged when they're both inactive, both active on the same validator,
+// or both activating on the same validator in the same epoch.
+func mergeGroup(stake *parsedStakeAccount, epoch uint64) (string, bool) {
+	delegation := stake.StakeAccount.Parsed.Info.Stake.Delegation
+	switch stake.StakeAccount.GetState(epoch) {
+	case xclient.Inactive:
+		return string(xclient.Inactive), true
+	case xclient.Active:
+		return fmt.Sprintf("%s/%s", xclient.Active, delegation.Voter), true
+	case xclient.Activating:
+		return fmt.Sprintf("%s/%s/%s", xclient.Activating, delegation.Voter, delegation.ActivationEpoch), true
+	default:
+		// deactivating stake can't be merged
+		return "", false
+	}
+}
+
+func (client *Client) fetchMergeStakeInput(args xcbuilder.StakeArgs, txInput *tx_input.TxInput, stakeAccounts []*parsedStakeAccount, epoch uint64) (*tx_input.MergeStakeInput, error) {
+	validator, _ := args.GetValidator()
+	destination, hasDestination := args.GetDestinationStakeAccount()
+
+	groups := map[string][]*parsedStakeAccount{}
+	destinationGroup := ""
+	for _, stake := range stakeAccounts {
+		group, ok := mergeGroup(stake, epoch)
+		if !ok {
+			continue
+		}
+		if hasDestination && stake.Account.Pubkey.String() == destination {
+			destinationGroup = group
+		} else if stake.StakeAccount.Parsed.Info.Stake.Delegation.Voter != validator && group != string(xclient.Inactive) {
+			continue
+		}
+		groups[group] = append(groups[group], stake)
+	}
+	if hasDestination && destinationGroup == "" {
+		return nil, fmt.Errorf("destination stake account %s is not found or cannot be merged", destination)
+	}
+
+	// without a destination, merge the group with the most accounts into its largest account
+	if !hasDestination {
+		for group, accounts := range groups {
+			if len(accounts) > len(groups[destinationGroup]) || (len(accounts) == len(groups[destinationGroup]) && group < destinationGroup) {
+				destinationGroup = group
+			}
+		}
+	}
+	accounts := groups[destinationGroup]
+	if len(accounts) < 2 {
+		return nil, fmt.Errorf("no stake accounts found to merge for validator: %s", validator)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Account.Account.Lamports > accounts[j].Account.Account.Lamports
+	})
+	if !hasDestination {
+		destination = accounts[0].Account.Pubkey.String()
+	}
+
+	input := &tx_input.MergeStakeInput{
+		TxInput:     *txInput,
+		Destination: solana.MustPublicKeyFromBase58(destination),
+	}
+	for _, stake := range accounts {
+		if stake.Account.Pubkey.Equals(input.Destination) {
+			continue
+		}
+		if len(input.Sources) >= builder.MaxAccountMerges {
+			break
+		}
+		input.Sources = append(input.Sources, stake.Account.Pubkey)
+	}
+	return input, nil
+}
+
+func (client *Client) fetchSplitStakeInput(ctx context.Context, args xcbuilder.StakeArgs, txInput *tx_input.TxInput, stakeAccounts []*parsedStakeAccount, epoch uint64) (*tx_input.SplitStakeInput, error) {
+	validator, _ := args.GetValidator()
+	amount := args.GetAmount().Uint64()
+	inputAccount, hasAccount := args.GetStakeAccount()
+
+	var source *parsedStakeAccount
+	for _, stake := range stakeAccounts {
+		if hasAccount {
+			if stake.Account.Pubkey.String() == inputAccount {
+				source = stake
+				break
+			}
+			continue
+		}
+		state := stake.StakeAccount.GetState(epoch)
+		if stake.StakeAccount.Parsed.Info.Stake.Delegation.Voter != validator || state == xclient.Deactivating || state == xclient.Inactive {
+			continue
+		}
+		// use the smallest account that can cover the amount, leaving some for rent
+		if stake.Account.Account.Lamports > amount+builder.RentExemptLamportsThreshold {
+			if source == nil || stake.Account.Account.Lamports < source.Account.Account.Lamports {
+				source = stake
+			}
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("no stake account found to split %d from for validator: %s", amount, validator)
+	}
+	rent, err := client.client.GetMinimumBalanceForRentExemption(ctx, builder.StakeAccountSize, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &tx_input.SplitStakeInput{
+		TxInput:           *txInput,
+		StakeAccount:      source.Account.Pubkey,
+		StakingKey:        privKey,
+		RentExemptReserve: xc_types.NewBigIntFromUint64(rent),
+	}, nil
+}
+
+func (client *Client) fetchMoveStakeInput(ctx context.Context, args xcbuilder.StakeArgs, txInput *tx_input.TxInput, stakeAccounts []*parsedStakeAccount) (*tx_input.MoveStakeInput, error) {
+	inputAccount, ok := args.GetStakeAccount()
+	if !ok {
+		return nil, errors.New("stake account to move is required")
+	}
+	var source *parsedStakeAccount
+	for _, stake := range stakeAccounts {
+		if stake.Account.Pubkey.String() == inputAccount {
+			source = stake
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("stake account not found: %s", inputAccount)
+	}
+	input := &tx_input.MoveStakeInput{
+		TxInput:      *txInput,
+		StakeAccount: source.Account.Pubkey,
+	}
+
+	destination, ok := args.GetDestinationStakeAccount()
+	if !ok {
+		return nil, builder.ErrMoveToValidatorNotSupported
+	}
+	var err error
+	input.Destination, err = solana.PublicKeyFromBase58(destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination stake account: %v", err)
+	}
+	return input, nil
+}
+
+func (client *Client) fetchAuthorizeStakeInput(args xcbuilder.StakeArgs, txInput *tx_input.TxInput, stakeAccounts []*parsedStakeAccount) (*tx_input.AuthorizeStakeInput, error) {
+	newAuthority, ok := args.GetNewAuthority()
+	if !ok {
+		return nil, errors.New("new authority is required")
+	}
+	authorityType, _ := args.GetAuthorityType()
+	newAuthorityPub, err := solana.PublicKeyFromBase58(string(newAuthority))
+	if err != nil {
+		return nil, fmt.Errorf("invalid new authority: %v", err)
+	}
+	validator, _ := args.GetValidator()
+	inputAccount, hasAccount := args.GetStakeAccount()
+
+	input := &tx_input.AuthorizeStakeInput{
+		TxInput:      *txInput,
+		NewAuthority: newAuthorityPub,
+		Authority:    authorityType,
+	}
+	for _, stake := range stakeAccounts {
+		if hasAccount {
+			if stake.Account.Pubkey.String() != inputAccount {
+				continue
+			}
+		} else if stake.StakeAccount.Parsed.Info.Stake.Delegation.Voter != validator {
+			continue
+		}
+		input.StakeAccounts = append(input.StakeAccounts, stake.Account.Pubkey)
+	}
+	if len(input.StakeAccounts) == 0 {
+		return nil, fmt.Errorf("no stake accounts found to authorize for validator: %s", validator)
+	}
+	return input, nil
+}
+
+func (client *Client) fetchCloseStakeInput(args xcbuilder.StakeArgs, txInput *tx_input.TxInput, stakeAccounts []*parsedStakeAccount, epoch uint64) (*tx_input.CloseStakeInput, error) {
+	validator, _ := args.GetValidator()
+	inputAccount, hasAccount := args.GetStakeAccount()
+
+	input := &tx_input.CloseStakeInput{
+		TxInput: *txInput,
+	}
+	for _, stake := range stakeAccounts {
+		if hasAccount && stake.Account.Pubkey.String() != inputAccount {
+			continue
+		}
+		if stake.StakeAccount.Parsed.Info.Stake.Delegation.Voter != validator {
+			continue
+		}
+		if stake.StakeAccount.GetState(epoch) != xclient.Inactive {
+			// only inactive accounts can be closed
+			continue
+		}
+		input.EligibleStakes = append(input.EligibleStakes, &tx_input.ExistingStake{
+			ActivationEpoch:   xc_types.NewBigIntFromStr(stake.StakeAccount.Parsed.Info.Stake.Delegation.ActivationEpoch),
+			DeactivationEpoch: xc_types.NewBigIntFromStr(stake.StakeAccount.Parsed.Info.Stake.Delegation.DeactivationEpoch),
+			AmountActive:      xc_types.NewBigIntFromUint64(0),
+			// the entire balance, including the rent reserve and any rewards
+			AmountInactive: xc_types.NewBigIntFromUint64(stake.Account.Account.Lamports),
+			StakeAccount:   stake.Account.Pubkey,
+		})
+		if len(input.EligibleStakes) >= builder.MaxAccountWithdraws {
+			break
+		}
+	}
+	if len(input.EligibleStakes) == 0 {
+		return nil, fmt.Errorf("no inactive stake accounts found to close for validator: %s", validator)
+	}
+	return input, nil
+}
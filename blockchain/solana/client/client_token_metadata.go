@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xcclient "github.com/openweb3-io/crosschain/client"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+var _ xcclient.TokenMetadataClient = &Client{}
+
+// Read the decimals of a mint, and its name and symbol from its Metaplex metadata if it has any
+func (client *Client) FetchTokenMetadata(ctx context.Context, contract xc.ContractAddress) (*xcclient.TokenMetadata, error) {
+	mint, err := solana.PublicKeyFromBase58(string(contract))
+	if err != nil {
+		return nil, err
+	}
+	mintInfo, err := client.client.GetAccountInfo(ctx, mint)
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup mint %s: %v", mint, err)
+	}
+	owner := mintInfo.Value.Owner
+	if !owner.Equals(solana.TokenProgramID) && !owner.Equals(solana.Token2022ProgramID) {
+		return nil, fmt.Errorf("%s is not a mint, it is owned by %s", mint, owner)
+	}
+	decimals, err := solana_types.ParseMintDecimals(mintInfo.Value.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := xcclient.NewTokenMetadata(contract, "", "", decimals)
+	metadataInfo, err := client.client.GetAccountInfo(ctx, solana_types.MetaplexMetadataPda(mint))
+	if errors.Is(err, rpc.ErrNotFound) {
+		return metadata, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup metadata of mint %s: %v", mint, err)
+	}
+	metadata.Name, metadata.Symbol, err = solana_types.ParseMetaplexMetadata(metadataInfo.Value.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/sirupsen/logrus"
+)
+
+// An address's holding of an asset, where the contract is empty for SOL
+type assetHolding struct {
+	Address  xc.Address
+	Contract xc.ContractAddress
+}
+
+// The token accounts whose balances are reported alongside a transaction, with their owner and mint.
+// This includes accounts that were closed by the transaction, which can no longer be looked up.
+func getTokenBalanceAccounts(meta *rpc.TransactionMeta, accountKeys solana.PublicKeySlice) map[solana.PublicKey]rpc.TokenBalance {
+	accounts := map[solana.PublicKey]rpc.TokenBalance{}
+	for _, balances := range [][]rpc.TokenBalance{meta.PreTokenBalances, meta.PostTokenBalances} {
+		for _, balance := range balances {
+			if int(balance.AccountIndex) < len(accountKeys) {
+				accounts[accountKeys[balance.AccountIndex]] = balance
+			}
+		}
+	}
+	return accounts
+}
+
+// Returns the owner and mint of a token account, preferring what was reported alongside the transaction.
+// The token account itself is returned as the owner if it can't be found.
+func (client *Client) lookupTokenAccountOwner(ctx context.Context, tokenAccount solana.PublicKey, tokenBalanceAccounts map[solana.PublicKey]rpc.TokenBalance) (xc.Address, xc.ContractAddress) {
+	if balance, ok := tokenBalanceAccounts[tokenAccount]; ok && balance.Owner != nil {
+		return xc.Address(balance.Owner.String()), xc.ContractAddress(balance.Mint.String())
+	}
+	// Solana doesn't keep full historical state, so we can't rely on always being able to lookup the account.
+	tokenAccountInfo, err := client.LookupTokenAccount(ctx, tokenAccount)
+	if err != nil {
+		logrus.WithError(err).Warn("failed to lookup token account")
+		return xc.Address(tokenAccount.String()), ""
+	}
+	return xc.Address(tokenAccountInfo.Parsed.Info.Owner), xc.ContractAddress(tokenAccountInfo.Parsed.Info.Mint)
+}
+
+// Returns a source or destination for each debit or credit shown by the balances before and after the
+// transaction that the decoded movements don't account for, such as from instructions that aren't decoded.
+// The lamports of token accounts are left out, as they are either rent or wrapped SOL that is reported
+// as a token.
+func getUndecodedMovements(
+	meta *rpc.TransactionMeta,
+	accountKeys solana.PublicKeySlice,
+	tokenBalanceAccounts map[solana.PublicKey]rpc.TokenBalance,
+	sources []*xc.LegacyTxInfoEndpoint,
+	dests []*xc.LegacyTxInfoEndpoint,
+) (debits []*xc.LegacyTxInfoEndpoint, credits []*xc.LegacyTxInfoEndpoint) {
+	holdings := []assetHolding{}
+	changes := map[assetHolding]*big.Int{}
+	addChange := func(holding assetHolding, amount *big.Int, credit bool) {
+		change, ok := changes[holding]
+		if !ok {
+			holdings = append(holdings, holding)
+			change = new(big.Int)
+			changes[holding] = change
+		}
+		if credit {
+			change.Add(change, amount)
+		} else {
+			change.Sub(change, amount)
+		}
+	}
+
+	for i, account := range accountKeys {
+		if i >= len(meta.PreBalances) || i >= len(meta.PostBalances) {
+			break
+		}
+		if _, ok := tokenBalanceAccounts[account]; ok {
+			continue
+		}
+		holding := assetHolding{Address: xc.Address(account.String())}
+		addChange(holding, new(big.Int).SetUint64(meta.PostBalances[i]), true)
+		addChange(holding, new(big.Int).SetUint64(meta.PreBalances[i]), false)
+		if i == 0 {
+			// the fee payer
+			addChange(holding, new(big.Int).SetUint64(meta.Fee), true)
+		}
+	}
+	for _, balances := range []struct {
+		balances []rpc.TokenBalance
+		credit   bool
+	}{{meta.PostTokenBalances, true}, {meta.PreTokenBalances, false}} {
+		for _, balance := range balances.balances {
+			if int(balance.AccountIndex) >= len(accountKeys) || balance.UiTokenAmount == nil {
+				continue
+			}
+			owner := accountKeys[balance.AccountIndex]
+			if balance.Owner != nil {
+				owner = *balance.Owner
+			}
+			holding := assetHolding{Address: xc.Address(owner.String()), Contract: xc.ContractAddress(balance.Mint.String())}
+			amount := xc.NewBigIntFromStr(balance.UiTokenAmount.Amount)
+			addChange(holding, amount.Int(), balances.credit)
+		}
+	}
+
+	// take away what the decoded movements explain
+	for _, endpoints := range []struct {
+		endpoints []*xc.LegacyTxInfoEndpoint
+		credit    bool
+	}{{dests, false}, {sources, true}} {
+		for _, endpoint := range endpoints.endpoints {
+			holding := assetHolding{Address: endpoint.Address, Contract: endpoint.ContractAddress}
+			if _, ok := changes[holding]; ok {
+				addChange(holding, endpoint.Amount.Int(), endpoints.credit)
+			}
+		}
+	}
+
+	debits = []*xc.LegacyTxInfoEndpoint{}
+	credits = []*xc.LegacyTxInfoEndpoint{}
+	for _, holding := range holdings {
+		undecoded := changes[holding]
+		endpoint := &xc.LegacyTxInfoEndpoint{
+			Address:         holding.Address,
+			Amount:          xc.BigInt(*new(big.Int).Abs(undecoded)),
+			ContractAddress: holding.Contract,
+		}
+		switch undecoded.Sign() {
+		case 1:
+			credits = append(credits, endpoint)
+		case -1:
+			debits = append(debits, endpoint)
+		}
+	}
+	return debits, credits
+}
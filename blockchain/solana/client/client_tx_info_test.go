@@ -0,0 +1,256 @@
+package client_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/openweb3-io/crosschain/blockchain/solana/client"
+	testtypes "github.com/openweb3-io/crosschain/testutil/types"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTxInfoInnerInstructions(t *testing.T) {
+	newKey := func() solana.PublicKey {
+		key, _ := solana.NewRandomPrivateKey()
+		return key.PublicKey()
+	}
+	user := newKey()
+	userUsdcAccount := newKey()
+	userBonkAccount := newKey()
+	pool := newKey()
+	poolUsdcAccount := newKey()
+	dexProgram := newKey()
+	usdcMint := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	bonkMint := solana.MustPublicKeyFromBase58("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB8pPB263")
+
+	// a swap on a dex, which moves funds by invoking the system and token programs
+	swap := solana.NewInstruction(dexProgram, solana.AccountMetaSlice{
+		solana.Meta(user).SIGNER().WRITE(),
+		solana.Meta(userUsdcAccount).WRITE(),
+		solana.Meta(userBonkAccount).WRITE(),
+		solana.Meta(pool).WRITE(),
+		solana.Meta(poolUsdcAccount).WRITE(),
+		solana.Meta(bonkMint).WRITE(),
+		solana.Meta(solana.TokenProgramID),
+		solana.Meta(solana.SystemProgramID),
+	}, []byte{1})
+	solTx, err := solana.NewTransaction([]solana.Instruction{swap}, solana.Hash{}, solana.TransactionPayer(user))
+	require.NoError(t, err)
+	solTx.Signatures = []solana.Signature{{}}
+	txBase64, err := solTx.ToBase64()
+	require.NoError(t, err)
+	index := func(key solana.PublicKey) uint16 {
+		i, err := solTx.Message.GetAccountIndex(key)
+		require.NoError(t, err)
+		return i
+	}
+
+	lamports := make([]uint64, len(solTx.Message.AccountKeys))
+	preBalances := append([]uint64{}, lamports...)
+	postBalances := append([]uint64{}, lamports...)
+	preBalances[index(user)] = 10_000_000
+	postBalances[index(user)] = 10_000_000 - 5000 + 5_000_000
+	preBalances[index(pool)] = 50_000_000
+	postBalances[index(pool)] = 45_000_000
+
+	tokenBalance := func(account solana.PublicKey, owner solana.PublicKey, mint solana.PublicKey, amount string) map[string]interface{} {
+		return map[string]interface{}{
+			"accountIndex":  index(account),
+			"owner":         owner.String(),
+			"mint":          mint.String(),
+			"uiTokenAmount": map[string]interface{}{"amount": amount, "decimals": 6},
+		}
+	}
+	meta := map[string]interface{}{
+		"err":          nil,
+		"fee":          5000,
+		"preBalances":  preBalances,
+		"postBalances": postBalances,
+		"innerInstructions": []map[string]interface{}{{
+			"index": 0,
+			"instructions": []map[string]interface{}{
+				{
+					// token transfer of usdc to the pool
+					"programIdIndex": index(solana.TokenProgramID),
+					"accounts":       []uint16{index(userUsdcAccount), index(poolUsdcAccount), index(user)},
+					"data":           solana.Base58(binary.LittleEndian.AppendUint64([]byte{3}, 1_000_000)).String(),
+				},
+				{
+					// system transfer of sol from the pool
+					"programIdIndex": index(solana.SystemProgramID),
+					"accounts":       []uint16{index(pool), index(user)},
+					"data":           solana.Base58(binary.LittleEndian.AppendUint64([]byte{2, 0, 0, 0}, 5_000_000)).String(),
+				},
+				{
+					// mint to, which isn't decoded
+					"programIdIndex": index(solana.TokenProgramID),
+					"accounts":       []uint16{index(bonkMint), index(userBonkAccount), index(pool)},
+					"data":           solana.Base58(binary.LittleEndian.AppendUint64([]byte{7}, 42)).String(),
+				},
+			},
+		}},
+		"preTokenBalances": []map[string]interface{}{
+			tokenBalance(userUsdcAccount, user, usdcMint, "3000000"),
+			tokenBalance(poolUsdcAccount, pool, usdcMint, "0"),
+			tokenBalance(userBonkAccount, user, bonkMint, "0"),
+		},
+		"postTokenBalances": []map[string]interface{}{
+			tokenBalance(userUsdcAccount, user, usdcMint, "2000000"),
+			tokenBalance(poolUsdcAccount, pool, usdcMint, "1000000"),
+			tokenBalance(userBonkAccount, user, bonkMint, "42"),
+		},
+		"loadedAddresses": map[string]interface{}{"writable": []string{}, "readonly": []string{}},
+	}
+	txResponse, err := json.Marshal(map[string]interface{}{
+		"slot":        300,
+		"blockTime":   1700000000,
+		"transaction": []string{txBase64, "base64"},
+		"meta":        meta,
+	})
+	require.NoError(t, err)
+
+	server, close := testtypes.MockJSONRPC(t, []string{
+		string(txResponse),
+		`{"context":{"slot":310},"value":{"blockhash":"DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK","lastValidBlockHeight":100}}`,
+	})
+	defer close()
+	client, _ := client.NewClient(&xc_types.ChainConfig{URL: server.URL, Chain: xc_types.SOL})
+
+	info, err := client.FetchLegacyTxInfo(context.Background(), xc_types.TxHash(solTx.Signatures[0].String()))
+	require.NoError(t, err)
+	require.EqualValues(t, 10, info.Confirmations)
+	require.Equal(t, []*xc_types.LegacyTxInfoEndpoint{
+		{Address: xc_types.Address(pool.String()), Amount: xc_types.NewBigIntFromUint64(5_000_000)},
+		{Address: xc_types.Address(user.String()), Amount: xc_types.NewBigIntFromUint64(1_000_000), ContractAddress: xc_types.ContractAddress(usdcMint.String())},
+	}, info.Sources)
+	require.Equal(t, []*xc_types.LegacyTxInfoEndpoint{
+		{Address: xc_types.Address(user.String()), Amount: xc_types.NewBigIntFromUint64(5_000_000)},
+		// the owner of the destination token account is known from the token balances
+		{Address: xc_types.Address(pool.String()), Amount: xc_types.NewBigIntFromUint64(1_000_000), ContractAddress: xc_types.ContractAddress(usdcMint.String())},
+		// the minted tokens are only found from the balances
+		{Address: xc_types.Address(user.String()), Amount: xc_types.NewBigIntFromUint64(42), ContractAddress: xc_types.ContractAddress(bonkMint.String())},
+	}, info.Destinations)
+}
+
+func TestFetchTxInfoMemo(t *testing.T) {
+	user := solana.MustPublicKeyFromBase58("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	exchange := solana.MustPublicKeyFromBase58("GBrg73u6MuwgM6hXrBG9WYqKSrZ6jBr9kW9Nm3Ze5k68")
+
+	solTx, err := solana.NewTransaction([]solana.Instruction{
+		solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{solana.Meta(user).SIGNER()}, []byte("order 7")),
+		system.NewTransferInstruction(1_000_000, user, exchange).Build(),
+	}, solana.Hash{}, solana.TransactionPayer(user))
+	require.NoError(t, err)
+	solTx.Signatures = []solana.Signature{{}}
+	txBase64, err := solTx.ToBase64()
+	require.NoError(t, err)
+
+	preBalances := make([]uint64, len(solTx.Message.AccountKeys))
+	postBalances := make([]uint64, len(solTx.Message.AccountKeys))
+	userIndex, _ := solTx.Message.GetAccountIndex(user)
+	exchangeIndex, _ := solTx.Message.GetAccountIndex(exchange)
+	preBalances[userIndex] = 10_000_000
+	postBalances[userIndex] = 10_000_000 - 1_000_000 - 5000
+	postBalances[exchangeIndex] = 1_000_000
+
+	txResponse, err := json.Marshal(map[string]interface{}{
+		"slot":        300,
+		"blockTime":   1700000000,
+		"transaction": []string{txBase64, "base64"},
+		"meta": map[string]interface{}{
+			"err":               nil,
+			"fee":               5000,
+			"preBalances":       preBalances,
+			"postBalances":      postBalances,
+			"innerInstructions": []interface{}{},
+			"preTokenBalances":  []interface{}{},
+			"postTokenBalances": []interface{}{},
+			"loadedAddresses":   map[string]interface{}{"writable": []string{}, "readonly": []string{}},
+		},
+	})
+	require.NoError(t, err)
+
+	server, close := testtypes.MockJSONRPC(t, []string{
+		string(txResponse),
+		`{"context":{"slot":310},"value":{"blockhash":"DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK","lastValidBlockHeight":100}}`,
+	})
+	defer close()
+	client, _ := client.NewClient(&xc_types.ChainConfig{URL: server.URL, Chain: xc_types.SOL})
+
+	info, err := client.FetchLegacyTxInfo(context.Background(), xc_types.TxHash(solTx.Signatures[0].String()))
+	require.NoError(t, err)
+	require.Equal(t, []*xc_types.LegacyTxInfoEndpoint{
+		{Address: xc_types.Address(user.String()), Amount: xc_types.NewBigIntFromUint64(1_000_000)},
+	}, info.Sources)
+	// the memo is reported on the destinations
+	require.Equal(t, []*xc_types.LegacyTxInfoEndpoint{
+		{Address: xc_types.Address(exchange.String()), Amount: xc_types.NewBigIntFromUint64(1_000_000), Memo: "order 7"},
+	}, info.Destinations)
+}
+
+func TestFetchTxInfoStake(t *testing.T) {
+	user := solana.MustPublicKeyFromBase58("83wDqn8DFg5oh1WetQJwcyZySjxGkxWVKf3p39T6GMQH")
+	stakeAccount := solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC")
+	validator := solana.MustPublicKeyFromBase58("5ZWgXcyqrrNpQHCme5SdC5hCeYb2o3fEJhF7Gok3bTVN")
+	amount := uint64(2_000_000_000)
+
+	// creating the stake account isn't decoded as a transfer
+	solTx, err := solana.NewTransaction([]solana.Instruction{
+		system.NewCreateAccountInstruction(amount, 200, solana.StakeProgramID, user, stakeAccount).Build(),
+		stake.NewInitializeInstruction(user, user, stakeAccount).Build(),
+		stake.NewDelegateStakeInstruction(validator, user, stakeAccount).Build(),
+	}, solana.Hash{}, solana.TransactionPayer(user))
+	require.NoError(t, err)
+	solTx.Signatures = []solana.Signature{{}, {}}
+	txBase64, err := solTx.ToBase64()
+	require.NoError(t, err)
+
+	preBalances := make([]uint64, len(solTx.Message.AccountKeys))
+	postBalances := make([]uint64, len(solTx.Message.AccountKeys))
+	userIndex, _ := solTx.Message.GetAccountIndex(user)
+	stakeIndex, _ := solTx.Message.GetAccountIndex(stakeAccount)
+	preBalances[userIndex] = 10_000_000_000
+	postBalances[userIndex] = 10_000_000_000 - amount - 10000
+	postBalances[stakeIndex] = amount
+
+	txResponse, err := json.Marshal(map[string]interface{}{
+		"slot":        300,
+		"blockTime":   1700000000,
+		"transaction": []string{txBase64, "base64"},
+		"meta": map[string]interface{}{
+			"err":               nil,
+			"fee":               10000,
+			"preBalances":       preBalances,
+			"postBalances":      postBalances,
+			"innerInstructions": []interface{}{},
+			"preTokenBalances":  []interface{}{},
+			"postTokenBalances": []interface{}{},
+			"loadedAddresses":   map[string]interface{}{"writable": []string{}, "readonly": []string{}},
+		},
+	})
+	require.NoError(t, err)
+
+	server, close := testtypes.MockJSONRPC(t, []string{
+		string(txResponse),
+		`{"context":{"slot":310},"value":{"blockhash":"DvLEyV2GHk86K5GojpqnRsvhfMF5kdZomKMnhVpvHyqK","lastValidBlockHeight":100}}`,
+	})
+	defer close()
+	client, _ := client.NewClient(&xc_types.ChainConfig{URL: server.URL, Chain: xc_types.SOL})
+
+	info, err := client.FetchLegacyTxInfo(context.Background(), xc_types.TxHash(solTx.Signatures[0].String()))
+	require.NoError(t, err)
+	// the balances show both sides of the movement into the stake account
+	require.Equal(t, []*xc_types.LegacyTxInfoEndpoint{
+		{Address: xc_types.Address(user.String()), Amount: xc_types.NewBigIntFromUint64(amount)},
+	}, info.Sources)
+	require.Equal(t, []*xc_types.LegacyTxInfoEndpoint{
+		{Address: xc_types.Address(stakeAccount.String()), Amount: xc_types.NewBigIntFromUint64(amount)},
+	}, info.Destinations)
+	require.Len(t, info.GetStakeEvents(), 1)
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	"github.com/openweb3-io/crosschain/bridge/wormhole"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+var _ wormhole.Endpoint = &Client{}
+
+func (client *Client) wormholePrograms() solana_types.WormholePrograms {
+	return solana_types.WormholeProgramsForNetwork(client.cfg.Network)
+}
+
+// Fetch the input to redeem a signed VAA on solana
+func (client *Client) FetchWormholeRedeemInput(ctx context.Context, from xc.Address, vaaBytes []byte) (*tx_input.WormholeRedeemInput, error) {
+	vaa, err := wormhole.ParseVAA(vaaBytes)
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := wormhole.ParseTokenTransfer(vaa.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if !transfer.ToChain.IsSolana() {
+		return nil, fmt.Errorf("wormhole transfer is for chain %d, not solana", transfer.ToChain)
+	}
+	completed, err := client.IsWormholeTransferCompleted(ctx, vaa)
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		return nil, fmt.Errorf("wormhole transfer %s has already been redeemed", vaa.ID())
+	}
+	programs := client.wormholePrograms()
+	if err := client.checkWormholeGuardians(ctx, programs, vaa); err != nil {
+		return nil, err
+	}
+
+	// tokens native to solana are released from custody, and others are minted as wrapped tokens
+	wrapped := !transfer.TokenChain.IsSolana()
+	mint := solana.PublicKeyFromBytes(transfer.TokenAddress[:])
+	if wrapped {
+		mint = solana_types.WormholeWrappedMintPda(programs, uint16(transfer.TokenChain), transfer.TokenAddress)
+	}
+	mintInfo, err := client.client.GetAccountInfo(ctx, mint)
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup mint %s: %v", mint, err)
+	}
+	to := solana.PublicKeyFromBytes(transfer.To[:])
+	if _, err := client.client.GetAccountInfo(ctx, to); err != nil {
+		return nil, fmt.Errorf("could not lookup recipient token account %s: %v", to, err)
+	}
+
+	txInput, err := client.FetchBaseInput(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	txInput.TokenProgram = mintInfo.Value.Owner
+	signatureSetKey, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	redeemInput := tx_input.NewWormholeRedeemInput()
+	redeemInput.TxInput = *txInput
+	redeemInput.Programs = programs
+	redeemInput.SignatureSetKey = signatureSetKey
+	redeemInput.Mint = mint
+	redeemInput.Wrapped = wrapped
+	return redeemInput, nil
+}
+
+// The core bridge only accepts signatures from the guardian set the VAA was signed by, which may have expired
+func (client *Client) checkWormholeGuardians(ctx context.Context, programs solana_types.WormholePrograms, vaa *wormhole.VAA) error {
+	guardianSet := solana_types.WormholeGuardianSetPda(programs, vaa.GuardianSetIndex)
+	info, err := client.client.GetAccountInfo(ctx, guardianSet)
+	if err != nil {
+		return fmt.Errorf("could not lookup wormhole guardian set %d: %v", vaa.GuardianSetIndex, err)
+	}
+	keys, err := solana_types.ParseWormholeGuardianSet(info.Value.Data.GetBinary())
+	if err != nil {
+		return err
+	}
+	guardians, err := vaa.RecoverGuardians()
+	if err != nil {
+		return err
+	}
+	for i, sig := range vaa.Signatures {
+		index := int(sig.GuardianIndex)
+		if index >= len(keys) || !bytes.Equal(keys[index][:], guardians[i].Bytes()) {
+			return fmt.Errorf("signature %d is not from guardian %d of guardian set %d", i, index, vaa.GuardianSetIndex)
+		}
+	}
+	return nil
+}
+
+// Check if the transfer has been redeemed on solana
+func (client *Client) IsWormholeTransferCompleted(ctx context.Context, vaa *wormhole.VAA) (bool, error) {
+	claim := solana_types.WormholeClaimPda(client.wormholePrograms(), uint16(vaa.EmitterChain), vaa.EmitterAddress, vaa.Sequence)
+	info, err := client.client.GetAccountInfo(ctx, claim)
+	if errors.Is(err, rpc.ErrNotFound) {
+		// created when the transfer is completed
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not fetch wormhole claim: %v", err)
+	}
+	data := info.Value.Data.GetBinary()
+	return len(data) > 0 && data[0] != 0, nil
+}
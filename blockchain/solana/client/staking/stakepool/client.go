@@ -0,0 +1,248 @@
+package stakepool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/openweb3-io/crosschain/blockchain/solana/builder"
+	solanaclient "github.com/openweb3-io/crosschain/blockchain/solana/client"
+	"github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xcbuilder "github.com/openweb3-io/crosschain/builder"
+	xcclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Client for liquid staking with a pool on the SPL stake-pool program, e.g. Jito.  The pool is the chain's
+// configured stake contract.  Staked SOL is held as the pool's token, which is valued at the pool's exchange rate.
+type Client struct {
+	rpcClient *solanaclient.Client
+	chain     *xc_types.ChainConfig
+}
+
+var _ xcclient.StakingClient = &Client{}
+
+func NewClient(rpcClient *solanaclient.Client, chain *xc_types.ChainConfig) (xcclient.StakingClient, error) {
+	return &Client{rpcClient, chain}, nil
+}
+
+type stakePool struct {
+	solana_types.StakePool
+	Address solana.PublicKey
+	// The stake-pool program the pool is deployed on
+	Program solana.PublicKey
+}
+
+func poolAccounts(pool *stakePool) tx_input.StakePoolAccounts {
+	return tx_input.StakePoolAccounts{
+		Program:           pool.Program,
+		StakePool:         pool.Address,
+		ReserveStake:      pool.ReserveStake,
+		PoolMint:          pool.PoolMint,
+		ManagerFeeAccount: pool.ManagerFeeAccount,
+		PoolTokenProgram:  pool.TokenProgramID,
+	}
+}
+
+func (cli *Client) FetchStakePool(ctx context.Context) (*stakePool, error) {
+	if cli.chain.Staking.StakeContract == "" {
+		return nil, fmt.Errorf("no stake pool is configured for %s", cli.chain.Chain)
+	}
+	address, err := solana.PublicKeyFromBase58(cli.chain.Staking.StakeContract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stake pool address: %v", err)
+	}
+	info, err := cli.rpcClient.RpcClient().GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch stake pool %s: %v", address, err)
+	}
+	pool, err := solana_types.ParseStakePool(info.Value.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+	return &stakePool{
+		StakePool: *pool,
+		Address:   address,
+		Program:   info.Value.Owner,
+	}, nil
+}
+
+// Deposits and withdrawals fail until the pool has been updated for the current epoch
+func (cli *Client) checkUpdated(ctx context.Context, pool *stakePool) error {
+	epochInfo, err := cli.rpcClient.RpcClient().GetEpochInfo(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return err
+	}
+	if pool.LastUpdateEpoch < epochInfo.Epoch {
+		return fmt.Errorf("stake pool %s has not been updated for epoch %d", pool.Address, epochInfo.Epoch)
+	}
+	return nil
+}
+
+func (cli *Client) FetchPoolTokenBalance(ctx context.Context, pool *stakePool, owner xc_types.Address) (uint64, error) {
+	tokenAccounts, err := cli.rpcClient.GetTokenAccountsByOwner(ctx, string(owner), pool.PoolMint.String())
+	if err != nil {
+		return 0, fmt.Errorf("could not fetch pool token balance: %v", err)
+	}
+	balance := uint64(0)
+	for _, account := range tokenAccounts {
+		balance += xc_types.NewBigIntFromStr(account.Info.Parsed.Info.TokenAmount.Amount).Uint64()
+	}
+	return balance, nil
+}
+
+// Pool tokens are active stake, valued in SOL at the pool's exchange rate
+func (cli *Client) FetchStakeBalance(ctx context.Context, args xcclient.StakedBalanceArgs) ([]*xcclient.StakedBalance, error) {
+	pool, err := cli.FetchStakePool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	poolTokens, err := cli.FetchPoolTokenBalance(ctx, pool, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	return []*xcclient.StakedBalance{
+		xcclient.NewStakedBalances(xcclient.StakedBalanceState{
+			Active: xc_types.NewBigIntFromUint64(pool.PoolTokensToLamports(poolTokens)),
+		}, "", pool.Address.String()),
+	}, nil
+}
+
+func (cli *Client) FetchStakingInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.StakeTxInput, error) {
+	pool, err := cli.FetchStakePool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pool.SolDepositAuthority != nil {
+		return nil, fmt.Errorf("stake pool %s only accepts deposits signed by %s", pool.Address, pool.SolDepositAuthority)
+	}
+	if err = cli.checkUpdated(ctx, pool); err != nil {
+		return nil, err
+	}
+	txInput, err := cli.rpcClient.FetchBaseInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	// Set default fee for now
+	txInput.PrioritizationFee = xc_types.NewBigIntFromUint64(100000)
+
+	stakingInput := &tx_input.StakePoolStakeInput{
+		TxInput:           *txInput,
+		StakePoolAccounts: poolAccounts(pool),
+	}
+	poolTokenAccount, err := solana_types.FindAssociatedTokenAddress(string(args.GetFrom()), pool.PoolMint.String(), pool.TokenProgramID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = cli.rpcClient.RpcClient().GetAccountInfo(ctx, solana.MustPublicKeyFromBase58(poolTokenAccount))
+	if err != nil {
+		// the pool tokens are minted into the sender's associated token account, created if needed
+		stakingInput.ShouldCreateATA = true
+	}
+	return stakingInput, nil
+}
+
+// SOL is withdrawn from the pool's reserve when possible, otherwise stake is withdrawn from the preferred validator,
+// or the validator with the most stake.  Withdrawn stake is left delegated in a new stake account of the sender,
+// which can be unstaked natively.
+func (cli *Client) FetchUnstakingInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.UnstakeTxInput, error) {
+	pool, err := cli.FetchStakePool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = cli.checkUpdated(ctx, pool); err != nil {
+		return nil, err
+	}
+	amount := args.GetAmount().Uint64()
+	poolTokens := pool.LamportsToPoolTokens(amount)
+	if pool.PoolTokensToLamports(poolTokens) < amount {
+		// round up so at least the amount is unstaked, before fees
+		poolTokens += 1
+	}
+	balance, err := cli.FetchPoolTokenBalance(ctx, pool, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	if balance < poolTokens {
+		return nil, fmt.Errorf("pool token balance %d is less than the %d needed to unstake %d", balance, poolTokens, amount)
+	}
+
+	txInput, err := cli.rpcClient.FetchBaseInput(ctx, args.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	// Set default fee for now
+	txInput.PrioritizationFee = xc_types.NewBigIntFromUint64(100000)
+	unstakingInput := &tx_input.StakePoolUnstakeInput{
+		TxInput:           *txInput,
+		StakePoolAccounts: poolAccounts(pool),
+		PoolTokens:        xc_types.NewBigIntFromUint64(poolTokens),
+	}
+
+	rent, err := cli.rpcClient.RpcClient().GetMinimumBalanceForRentExemption(ctx, builder.StakeAccountSize, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, err
+	}
+	if pool.SolWithdrawAuthority == nil {
+		reserve, err := cli.rpcClient.RpcClient().GetBalance(ctx, pool.ReserveStake, rpc.CommitmentFinalized)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch stake pool reserve: %v", err)
+		}
+		// the reserve must stay rent exempt
+		if reserve.Value >= amount+rent {
+			return unstakingInput, nil
+		}
+	}
+
+	validator, err := cli.findWithdrawValidator(ctx, pool, amount+rent)
+	if err != nil {
+		return nil, err
+	}
+	unstakingInput.ValidatorList = pool.ValidatorList
+	unstakingInput.ValidatorStakeAccount = solana_types.StakePoolValidatorStakeAccount(pool.Program, pool.Address, validator.VoteAccount, validator.ValidatorSeedSuffix)
+	unstakingInput.RentExemptReserve = xc_types.NewBigIntFromUint64(rent)
+	unstakingInput.StakingKey, err = solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return unstakingInput, nil
+}
+
+// The pool only allows withdrawing from its preferred withdraw validator when one is set
+func (cli *Client) findWithdrawValidator(ctx context.Context, pool *stakePool, lamports uint64) (*solana_types.StakePoolValidator, error) {
+	info, err := cli.rpcClient.RpcClient().GetAccountInfo(ctx, pool.ValidatorList)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch stake pool validators: %v", err)
+	}
+	validators, err := solana_types.ParseStakePoolValidatorList(info.Value.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+	var found *solana_types.StakePoolValidator
+	for _, validator := range validators {
+		if validator.Status != solana_types.StakePoolValidatorActive {
+			continue
+		}
+		if pool.PreferredWithdrawValidator != nil {
+			if validator.VoteAccount.Equals(*pool.PreferredWithdrawValidator) {
+				found = validator
+				break
+			}
+			continue
+		}
+		if found == nil || validator.ActiveStakeLamports > found.ActiveStakeLamports {
+			found = validator
+		}
+	}
+	if found == nil || found.ActiveStakeLamports < lamports {
+		return nil, fmt.Errorf("stake pool %s does not have enough stake to withdraw %d", pool.Address, lamports)
+	}
+	return found, nil
+}
+
+func (cli *Client) FetchWithdrawInput(ctx context.Context, args xcbuilder.StakeArgs) (xc_types.WithdrawTxInput, error) {
+	return nil, errors.New("stake pools are withdrawn from when unstaking, any stake accounts received can be withdrawn natively")
+}
@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -21,6 +22,8 @@ type Tx struct {
 	ParsedSolTx      *rpc.ParsedTransaction
 	inputSignatures  []types.TxSignature
 	transientSigners []solana.PrivateKey
+	// Instructions invoked by programs (CPIs) while the transaction was executed
+	innerInstructions []rpc.InnerInstruction
 }
 
 func (tx *Tx) Hash() types.TxHash {
@@ -97,6 +100,89 @@ func NewTxFrom(solTx *solana.Transaction) *Tx {
 	return tx
 }
 
+// Versioned transactions load some of their accounts from address lookup tables.  The accounts that
+// were loaded are reported alongside the transaction, and are needed to decode its instructions.
+func (tx *Tx) SetLoadedAddresses(writable solana.PublicKeySlice, readonly solana.PublicKeySlice) error {
+	if tx.SolTx == nil {
+		return errors.New("transaction not initialized")
+	}
+	message := &tx.SolTx.Message
+	if len(message.AddressTableLookups) == 0 {
+		return nil
+	}
+	if message.NumWritableLookups() != len(writable) || message.NumLookups()-message.NumWritableLookups() != len(readonly) {
+		return fmt.Errorf("expected %d loaded addresses but got %d", message.NumLookups(), len(writable)+len(readonly))
+	}
+	// rebuild as much of each table as the transaction uses; writable accounts are loaded first
+	tables := map[solana.PublicKey]solana.PublicKeySlice{}
+	for _, lookup := range message.AddressTableLookups {
+		table := tables[lookup.AccountKey]
+		for _, index := range lookup.WritableIndexes {
+			table = setTableAddress(table, index, writable[0])
+			writable = writable[1:]
+		}
+		tables[lookup.AccountKey] = table
+	}
+	for _, lookup := range message.AddressTableLookups {
+		table := tables[lookup.AccountKey]
+		for _, index := range lookup.ReadonlyIndexes {
+			table = setTableAddress(table, index, readonly[0])
+			readonly = readonly[1:]
+		}
+		tables[lookup.AccountKey] = table
+	}
+	return message.SetAddressTables(tables)
+}
+
+// Programs may move funds by invoking other programs, which is only visible in the inner instructions
+// reported alongside the transaction.  Transfers made this way are then included by the transfer getters.
+func (tx *Tx) SetInnerInstructions(innerInstructions []rpc.InnerInstruction) {
+	tx.innerInstructions = innerInstructions
+}
+
+// The top level instructions of the transaction, followed by the inner instructions if requested
+func (tx Tx) instructions(includeInner bool) []solana.CompiledInstruction {
+	if tx.SolTx == nil {
+		return []solana.CompiledInstruction{}
+	}
+	instructions := append([]solana.CompiledInstruction{}, tx.SolTx.Message.Instructions...)
+	if includeInner {
+		for _, inner := range tx.innerInstructions {
+			instructions = append(instructions, inner.Instructions...)
+		}
+	}
+	return instructions
+}
+
+func setTableAddress(table solana.PublicKeySlice, index uint8, address solana.PublicKey) solana.PublicKeySlice {
+	for len(table) <= int(index) {
+		table = append(table, solana.PublicKey{})
+	}
+	table[index] = address
+	return table
+}
+
+// The original memo program, which is still in use
+var MemoV1ProgramID = solana.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+// GetMemo returns the text of the first memo instruction, including those invoked by other programs
+func (tx Tx) GetMemo() string {
+	if tx.SolTx == nil {
+		return ""
+	}
+	message := tx.SolTx.Message
+	for _, instruction := range tx.instructions(true) {
+		program, err := message.Account(instruction.ProgramIDIndex)
+		if err != nil {
+			continue
+		}
+		if program.Equals(solana.MemoProgramID) || program.Equals(MemoV1ProgramID) {
+			return string(instruction.Data)
+		}
+	}
+	return ""
+}
+
 type SolanaInstruction interface {
 	Obtain(def *bin.VariantDefinition) (typeID bin.TypeID, typeName string, impl interface{})
 }
@@ -104,16 +190,18 @@ type SolanaInstruction interface {
 func getall[T any, Y SolanaInstruction](
 	decoder func(accounts []*solana.AccountMeta, data []byte) (Y, error),
 	solanaProgram solana.PublicKey,
-	solTx *solana.Transaction,
+	tx Tx,
+	includeInner bool,
 ) []T {
 	results := []T{}
-	if solTx == nil {
+	if tx.SolTx == nil {
 		return []T{}
 	}
-	message := solTx.Message
+	message := tx.SolTx.Message
 
-	for _, instruction := range message.Instructions {
-		program, err := message.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+	for _, instruction := range tx.instructions(includeInner) {
+		// programs invoked by other programs may be loaded from address tables
+		program, err := message.Account(instruction.ProgramIDIndex)
 		if err != nil {
 			continue
 		}
@@ -145,8 +233,8 @@ type CreateAccountLikeInstruction struct {
 
 func (tx Tx) GetCreateAccounts() []*CreateAccountLikeInstruction {
 	results := []*CreateAccountLikeInstruction{}
-	creates := getall[*system.CreateAccount](system.DecodeInstruction, solana.SystemProgramID, tx.SolTx)
-	seeds := getall[*system.CreateAccountWithSeed](system.DecodeInstruction, solana.SystemProgramID, tx.SolTx)
+	creates := getall[*system.CreateAccount](system.DecodeInstruction, solana.SystemProgramID, tx, false)
+	seeds := getall[*system.CreateAccountWithSeed](system.DecodeInstruction, solana.SystemProgramID, tx, false)
 	for _, acc := range creates {
 		results = append(results, &CreateAccountLikeInstruction{
 			NewAccount: acc.GetNewAccount().PublicKey,
@@ -162,40 +250,92 @@ func (tx Tx) GetCreateAccounts() []*CreateAccountLikeInstruction {
 	return results
 }
 
+func (tx Tx) GetAdvanceNonceAccounts() []*system.AdvanceNonceAccount {
+	return getall[*system.AdvanceNonceAccount](system.DecodeInstruction, solana.SystemProgramID, tx, false)
+}
+
+func (tx Tx) GetInitializeNonceAccounts() []*system.InitializeNonceAccount {
+	return getall[*system.InitializeNonceAccount](system.DecodeInstruction, solana.SystemProgramID, tx, false)
+}
+
 func (tx Tx) GetDelegateStake() []*stake.DelegateStake {
-	return getall[*stake.DelegateStake](stake.DecodeInstruction, solana.StakeProgramID, tx.SolTx)
+	return getall[*stake.DelegateStake](stake.DecodeInstruction, solana.StakeProgramID, tx, false)
 }
 
 func (tx Tx) GetDeactivateStakes() []*stake.Deactivate {
-	return getall[*stake.Deactivate](stake.DecodeInstruction, solana.StakeProgramID, tx.SolTx)
+	return getall[*stake.Deactivate](stake.DecodeInstruction, solana.StakeProgramID, tx, false)
 }
 
 func (tx Tx) GetSplitStakes() []*stake.Split {
-	return getall[*stake.Split](stake.DecodeInstruction, solana.StakeProgramID, tx.SolTx)
+	return getall[*stake.Split](stake.DecodeInstruction, solana.StakeProgramID, tx, false)
 }
 
 func (tx Tx) GetStakeWithdraws() []*stake.Withdraw {
-	return getall[*stake.Withdraw](stake.DecodeInstruction, solana.StakeProgramID, tx.SolTx)
+	return getall[*stake.Withdraw](stake.DecodeInstruction, solana.StakeProgramID, tx, true)
 }
 
 func (tx Tx) GetSystemTransfers() []*system.Transfer {
-	return getall[*system.Transfer](system.DecodeInstruction, solana.SystemProgramID, tx.SolTx)
+	return getall[*system.Transfer](system.DecodeInstruction, solana.SystemProgramID, tx, true)
 }
 
 func (tx Tx) GetVoteWithdraws() []*vote.Withdraw {
-	return getall[*vote.Withdraw](vote.DecodeInstruction, solana.VoteProgramID, tx.SolTx)
+	return getall[*vote.Withdraw](vote.DecodeInstruction, solana.VoteProgramID, tx, true)
 }
 
 func (tx Tx) GetTokenTransferCheckeds() []*token.TransferChecked {
 	return append(
-		getall[*token.TransferChecked](token.DecodeInstruction, solana.TokenProgramID, tx.SolTx),
-		getall[*token.TransferChecked](token.DecodeInstruction, solana.Token2022ProgramID, tx.SolTx)...,
+		getall[*token.TransferChecked](token.DecodeInstruction, solana.TokenProgramID, tx, true),
+		getall[*token.TransferChecked](token.DecodeInstruction, solana.Token2022ProgramID, tx, true)...,
 	)
 }
 
+// A Token-2022 transfer that states the fee the mint withholds from the amount
+type TransferCheckedWithFee struct {
+	Source      solana.PublicKey
+	Mint        solana.PublicKey
+	Destination solana.PublicKey
+	Owner       solana.PublicKey
+	Amount      uint64
+	Decimals    uint8
+	Fee         uint64
+}
+
+func (tx Tx) GetTokenTransferCheckedWithFees() []*TransferCheckedWithFee {
+	results := []*TransferCheckedWithFee{}
+	if tx.SolTx == nil {
+		return results
+	}
+	message := tx.SolTx.Message
+	for _, instruction := range tx.instructions(true) {
+		program, err := message.Account(instruction.ProgramIDIndex)
+		if err != nil || !program.Equals(solana.Token2022ProgramID) {
+			continue
+		}
+		// transfer fee extension, transfer checked with fee
+		data := instruction.Data
+		if len(data) != 19 || data[0] != 26 || data[1] != 1 {
+			continue
+		}
+		accs, err := instruction.ResolveInstructionAccounts(&message)
+		if err != nil || len(accs) < 4 {
+			continue
+		}
+		results = append(results, &TransferCheckedWithFee{
+			Source:      accs[0].PublicKey,
+			Mint:        accs[1].PublicKey,
+			Destination: accs[2].PublicKey,
+			Owner:       accs[3].PublicKey,
+			Amount:      binary.LittleEndian.Uint64(data[2:10]),
+			Decimals:    data[10],
+			Fee:         binary.LittleEndian.Uint64(data[11:19]),
+		})
+	}
+	return results
+}
+
 func (tx Tx) GetTokenTransfers() []*token.Transfer {
 	return append(
-		getall[*token.Transfer](token.DecodeInstruction, solana.TokenProgramID, tx.SolTx),
-		getall[*token.Transfer](token.DecodeInstruction, solana.Token2022ProgramID, tx.SolTx)...,
+		getall[*token.Transfer](token.DecodeInstruction, solana.TokenProgramID, tx, true),
+		getall[*token.Transfer](token.DecodeInstruction, solana.Token2022ProgramID, tx, true)...,
 	)
 }
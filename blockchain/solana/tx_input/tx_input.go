@@ -1,9 +1,12 @@
 package tx_input
 
 import (
+	"sort"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	"github.com/openweb3-io/crosschain/factory/blockchains/registry"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/shopspring/decimal"
 )
@@ -22,14 +25,58 @@ type TxInput struct {
 	ShouldCreateATA     bool             `json:"should_create_ata,omitempty"`
 	SourceTokenAccounts []*TokenAccount  `json:"source_token_accounts,omitempty"`
 	PrioritizationFee   xc_types.BigInt  `json:"prioritization_fee,omitempty"`
-	Timestamp           int64            `json:"timestamp,omitempty"`
+	// The priority fee to pay for each gas fee priority, from the fees recently paid to lock the same accounts
+	PrioritizationFees map[xc_types.GasFeePriority]xc_types.BigInt `json:"prioritization_fees,omitempty"`
+	// Optional; the compute units the transaction is limited to, as estimated by simulating it
+	ComputeUnitLimit uint32 `json:"compute_unit_limit,omitempty"`
+	Timestamp        int64  `json:"timestamp,omitempty"`
+	// Optional; accounts in these tables are loaded by index, which makes for smaller (v0) transactions
+	AddressLookupTables []*AddressLookupTable `json:"address_lookup_tables,omitempty"`
+	// Optional; used instead of the recent block hash so the transaction can be signed later
+	DurableNonce *DurableNonce `json:"durable_nonce,omitempty"`
+
+	// Token-2022 extensions
+	// The fee the mint withholds from transfers in the current epoch
+	TransferFee *solana_types.TransferFee `json:"transfer_fee,omitempty"`
+	// The destination requires a memo to precede incoming transfers
+	MemoRequired bool `json:"memo_required,omitempty"`
+	// Accounts the mint's transfer hook program needs, to append to the transfer
+	TransferHookAccounts []*TransferHookAccount `json:"transfer_hook_accounts,omitempty"`
+
+	// Optional; used when the transfer arguments have no memo
+	LegacyMemo string `json:"memo,omitempty"`
+}
+
+var _ xc_types.TxInput = &TxInput{}
+var _ xc_types.TxInputWithMemo = &TxInput{}
+
+func init() {
+	registry.RegisterTxBaseInput(&TxInput{})
+	registry.RegisterTxVariantInput(&StakingInput{})
+	registry.RegisterTxVariantInput(&UnstakingInput{})
+	registry.RegisterTxVariantInput(&WithdrawInput{})
+	registry.RegisterTxVariantInput(&MergeStakeInput{})
+	registry.RegisterTxVariantInput(&SplitStakeInput{})
+	registry.RegisterTxVariantInput(&MoveStakeInput{})
+	registry.RegisterTxVariantInput(&AuthorizeStakeInput{})
+	registry.RegisterTxVariantInput(&CloseStakeInput{})
+	registry.RegisterTxVariantInput(&StakePoolStakeInput{})
+	registry.RegisterTxVariantInput(&StakePoolUnstakeInput{})
 }
 
 func (input *TxInput) GetBlockchain() xc_types.Blockchain {
 	return xc_types.BlockchainSolana
 }
 
+func (input *TxInput) SetMemo(memo string) {
+	input.LegacyMemo = memo
+}
+
 func (input *TxInput) SetGasFeePriority(other xc_types.GasFeePriority) error {
+	if fee, ok := input.PrioritizationFees[other]; ok {
+		input.PrioritizationFee = fee
+		return nil
+	}
 	multiplier, err := other.GetDefault()
 	if err != nil {
 		return err
@@ -39,11 +86,64 @@ func (input *TxInput) SetGasFeePriority(other xc_types.GasFeePriority) error {
 	return nil
 }
 
+// The percentile of recently paid priority fees to pay for each gas fee priority
+var PrioritizationFeePercentiles = map[xc_types.GasFeePriority]int{
+	xc_types.Low:            25,
+	xc_types.Market:         50,
+	xc_types.Aggressive:     75,
+	xc_types.VeryAggressive: 95,
+}
+
+// Minimum priority fee to pay, in microlamports
+const MinPrioritizationFee = 100
+
+// Set the priority fees from those recently paid to lock the transaction's writable accounts,
+// defaulting to the market priority.
+func (input *TxInput) SetRecentPrioritizationFees(recentFees []uint64, chain *xc_types.ChainConfig) {
+	fees := make([]uint64, len(recentFees))
+	copy(fees, recentFees)
+	sort.Slice(fees, func(i, j int) bool {
+		return fees[i] < fees[j]
+	})
+	input.PrioritizationFees = map[xc_types.GasFeePriority]xc_types.BigInt{}
+	for priority, percentile := range PrioritizationFeePercentiles {
+		fee := uint64(MinPrioritizationFee)
+		if len(fees) > 0 {
+			fee = max(fee, fees[(len(fees)-1)*percentile/100])
+		}
+		input.PrioritizationFees[priority] = xc_types.NewBigIntFromUint64(fee).ApplyGasPriceMultiplier(chain)
+	}
+	input.PrioritizationFee = input.PrioritizationFees[xc_types.Market]
+}
+
 type TokenAccount struct {
 	Account solana.PublicKey `json:"account,omitempty"`
 	Balance xc_types.BigInt  `json:"balance,omitempty"`
 }
 
+type TransferHookAccount struct {
+	Account    solana.PublicKey `json:"account"`
+	IsSigner   bool             `json:"is_signer,omitempty"`
+	IsWritable bool             `json:"is_writable,omitempty"`
+}
+
+type AddressLookupTable struct {
+	Account   solana.PublicKey      `json:"account"`
+	Addresses solana.PublicKeySlice `json:"addresses"`
+}
+
+// The lookup tables keyed by their account, as used to build a transaction
+func (input *TxInput) GetAddressTables() map[solana.PublicKey]solana.PublicKeySlice {
+	tables := map[solana.PublicKey]solana.PublicKeySlice{}
+	for _, table := range input.AddressLookupTables {
+		tables[table.Account] = table.Addresses
+	}
+	return tables
+}
+
+// The most compute units a transaction can be limited to
+const MaxComputeUnitLimit = 1_400_000
+
 // Solana recent-block-hash timeout margin
 const SafetyTimeoutMargin = (5 * time.Minute)
 
@@ -66,7 +166,10 @@ func (input *TxInput) GetLimitedPrioritizationFee(chain *xc_types.ChainConfig) u
 
 func (input *TxInput) IndependentOf(other xc_types.TxInput) (independent bool) {
 	// no conflicts on solana as txs are easily parallelizeable through
-	// the recent-block-hash mechanism.
+	// the recent-block-hash mechanism, unless they use the same durable nonce.
+	if oldInput, ok := other.(*TxInput); ok {
+		return !input.DurableNonce.SameNonce(oldInput.DurableNonce)
+	}
 	return true
 }
 
@@ -77,6 +180,13 @@ func (input *TxInput) SafeFromDoubleSend(others ...xc_types.TxInput) (safe bool)
 	for _, other := range others {
 		oldInput, ok := other.(*TxInput)
 		if ok {
+			if oldInput.DurableNonce != nil {
+				// the old tx does not expire, so it's only safe if it's spending the same nonce
+				if !input.DurableNonce.SameNonce(oldInput.DurableNonce) {
+					return false
+				}
+				continue
+			}
 			diff := input.Timestamp - oldInput.Timestamp
 			// solana blockhash lasts only ~1 minute -> we'll require a 5 min period
 			// and different hash to consider it safe from double-send.
@@ -0,0 +1,35 @@
+package tx_input
+
+import (
+	"github.com/gagliardetto/solana-go"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Input for burning USDC on solana, to be minted on another CCTP domain
+type CctpBurnInput struct {
+	TxInput
+	DestinationDomain uint32 `json:"destination_domain"`
+	// The recipient on the destination domain, as 32 bytes
+	MintRecipient solana.PublicKey `json:"mint_recipient"`
+	// The new account the message is written to
+	MessageSentEventKey solana.PrivateKey `json:"message_sent_event_key"`
+}
+
+var _ xc_types.TxInput = &CctpBurnInput{}
+
+func NewCctpBurnInput() *CctpBurnInput {
+	return &CctpBurnInput{}
+}
+
+// Input for receiving an attested CCTP message on solana
+type CctpReceiveInput struct {
+	TxInput
+	// The mint of the token that the message mints
+	LocalToken solana.PublicKey `json:"local_token"`
+}
+
+var _ xc_types.TxInput = &CctpReceiveInput{}
+
+func NewCctpReceiveInput() *CctpReceiveInput {
+	return &CctpReceiveInput{}
+}
@@ -0,0 +1,30 @@
+package tx_input
+
+import (
+	"github.com/gagliardetto/solana-go"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// A durable nonce is used in place of a recent block hash, so the transaction does not expire
+// until the nonce is advanced.  The authority must sign to advance it.
+type DurableNonce struct {
+	Account   solana.PublicKey `json:"account"`
+	Authority solana.PublicKey `json:"authority"`
+	Nonce     solana.Hash      `json:"nonce"`
+}
+
+// Only one transaction can land per nonce value, as landing advances it
+func (nonce *DurableNonce) SameNonce(other *DurableNonce) bool {
+	if nonce == nil || other == nil {
+		return false
+	}
+	return nonce.Account.Equals(other.Account) && nonce.Nonce.Equals(other.Nonce)
+}
+
+type CreateNonceAccountInput struct {
+	TxInput
+	// The new nonce account to create
+	NonceKey solana.PrivateKey `json:"nonce_key"`
+	// Minimum balance for the nonce account to be rent exempt
+	RentExemptBalance xc_types.BigInt `json:"rent_exempt_balance"`
+}
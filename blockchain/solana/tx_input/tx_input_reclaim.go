@@ -0,0 +1,32 @@
+package tx_input
+
+import (
+	"github.com/gagliardetto/solana-go"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// A token account that can be closed to reclaim the rent it holds
+type ReclaimableTokenAccount struct {
+	Account      solana.PublicKey `json:"account"`
+	Mint         solana.PublicKey `json:"mint"`
+	TokenProgram solana.PublicKey `json:"token_program"`
+	// Dust left in the account, which must be burned or transferred before it can be closed
+	Balance  xc_types.BigInt `json:"balance"`
+	Decimals uint8           `json:"decimals"`
+	// The lamports returned when the account is closed
+	Lamports xc_types.BigInt `json:"lamports"`
+	// Wrapped SOL accounts are closed with their balance, as it is held in lamports
+	IsNative bool `json:"is_native,omitempty"`
+}
+
+func (account *ReclaimableTokenAccount) HasDust() bool {
+	return !account.IsNative && account.Balance.Sign() > 0
+}
+
+type ReclaimRentInput struct {
+	TxInput
+	// The accounts to close, largest first
+	TokenAccounts []*ReclaimableTokenAccount `json:"token_accounts"`
+	// Token accounts of the dust destination, by mint, when dust is being transferred
+	DustDestinationAccounts map[string]solana.PublicKey `json:"dust_destination_accounts,omitempty"`
+}
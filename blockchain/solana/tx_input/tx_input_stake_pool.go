@@ -0,0 +1,60 @@
+package tx_input
+
+import (
+	"github.com/gagliardetto/solana-go"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// The accounts of a pool on the SPL stake-pool program
+type StakePoolAccounts struct {
+	// The stake-pool program the pool is deployed on
+	Program           solana.PublicKey `json:"program"`
+	StakePool         solana.PublicKey `json:"stake_pool"`
+	ReserveStake      solana.PublicKey `json:"reserve_stake"`
+	PoolMint          solana.PublicKey `json:"pool_mint"`
+	ManagerFeeAccount solana.PublicKey `json:"manager_fee_account"`
+	PoolTokenProgram  solana.PublicKey `json:"pool_token_program"`
+}
+
+// Stake by depositing SOL into a stake pool, which mints pool tokens to the sender
+type StakePoolStakeInput struct {
+	TxInput
+	StakePoolAccounts
+}
+
+var _ xc_types.TxVariantInput = &StakePoolStakeInput{}
+var _ xc_types.StakeTxInput = &StakePoolStakeInput{}
+
+func (*StakePoolStakeInput) Staking() {}
+
+func (*StakePoolStakeInput) GetVariant() xc_types.TxVariantInputType {
+	return xc_types.NewStakingInputType(xc_types.BlockchainSolana, string(xc_types.StakePool))
+}
+
+// Unstake by burning pool tokens.  SOL is withdrawn from the pool's reserve when it can cover the amount,
+// otherwise stake is split off a validator stake account of the pool into a new stake account of the sender.
+type StakePoolUnstakeInput struct {
+	TxInput
+	StakePoolAccounts
+	// The pool tokens to burn for the amount of SOL being unstaked
+	PoolTokens xc_types.BigInt `json:"pool_tokens"`
+
+	// Set when withdrawing stake rather than SOL
+	ValidatorList         solana.PublicKey  `json:"validator_list,omitempty"`
+	ValidatorStakeAccount solana.PublicKey  `json:"validator_stake_account,omitempty"`
+	StakingKey            solana.PrivateKey `json:"staking_key,omitempty"`
+	RentExemptReserve     xc_types.BigInt   `json:"rent_exempt_reserve,omitempty"`
+}
+
+var _ xc_types.TxVariantInput = &StakePoolUnstakeInput{}
+var _ xc_types.UnstakeTxInput = &StakePoolUnstakeInput{}
+
+func (*StakePoolUnstakeInput) Unstaking() {}
+
+func (*StakePoolUnstakeInput) GetVariant() xc_types.TxVariantInputType {
+	return xc_types.NewUnstakingInputType(xc_types.BlockchainSolana, string(xc_types.StakePool))
+}
+
+func (input *StakePoolUnstakeInput) WithdrawsStake() bool {
+	return !input.ValidatorStakeAccount.IsZero()
+}
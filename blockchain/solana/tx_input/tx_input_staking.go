@@ -61,3 +61,82 @@ func (*WithdrawInput) Withdrawing() {}
 func (*WithdrawInput) GetVariant() xc_types.TxVariantInputType {
 	return xc_types.NewWithdrawingInputType(xc_types.BlockchainSolana, string(xc_types.Native))
 }
+
+// Merges the source stake accounts into the destination, which must all be in the same state
+type MergeStakeInput struct {
+	TxInput
+	Destination solana.PublicKey   `json:"destination"`
+	Sources     []solana.PublicKey `json:"sources"`
+}
+
+var _ xc_types.StakeManagementTxInput = &MergeStakeInput{}
+
+func (*MergeStakeInput) ManagingStake() xc_types.StakeOperation { return xc_types.StakeMerge }
+
+func (*MergeStakeInput) GetVariant() xc_types.TxVariantInputType {
+	return xc_types.NewStakeManagementInputType(xc_types.BlockchainSolana, xc_types.StakeMerge, string(xc_types.Native))
+}
+
+// Splits an amount of stake into a new account
+type SplitStakeInput struct {
+	TxInput
+	StakeAccount solana.PublicKey `json:"stake_account"`
+	// The new staking account to create
+	StakingKey solana.PrivateKey `json:"staking_key"`
+	// The new account is funded to be rent exempt before the split
+	RentExemptReserve xc_types.BigInt `json:"rent_exempt_reserve"`
+}
+
+var _ xc_types.StakeManagementTxInput = &SplitStakeInput{}
+
+func (*SplitStakeInput) ManagingStake() xc_types.StakeOperation { return xc_types.StakeSplit }
+
+func (*SplitStakeInput) GetVariant() xc_types.TxVariantInputType {
+	return xc_types.NewStakeManagementInputType(xc_types.BlockchainSolana, xc_types.StakeSplit, string(xc_types.Native))
+}
+
+// Moves an amount of active stake into the destination account, which must be delegated to the same
+// validator or be inactive.
+type MoveStakeInput struct {
+	TxInput
+	StakeAccount solana.PublicKey `json:"stake_account"`
+	Destination  solana.PublicKey `json:"destination"`
+}
+
+var _ xc_types.StakeManagementTxInput = &MoveStakeInput{}
+
+func (*MoveStakeInput) ManagingStake() xc_types.StakeOperation { return xc_types.StakeMove }
+
+func (*MoveStakeInput) GetVariant() xc_types.TxVariantInputType {
+	return xc_types.NewStakeManagementInputType(xc_types.BlockchainSolana, xc_types.StakeMove, string(xc_types.Native))
+}
+
+// Sets a new stake or withdraw authority on stake accounts
+type AuthorizeStakeInput struct {
+	TxInput
+	StakeAccounts []solana.PublicKey      `json:"stake_accounts"`
+	NewAuthority  solana.PublicKey        `json:"new_authority"`
+	Authority     xc_types.StakeAuthority `json:"authority"`
+}
+
+var _ xc_types.StakeManagementTxInput = &AuthorizeStakeInput{}
+
+func (*AuthorizeStakeInput) ManagingStake() xc_types.StakeOperation { return xc_types.StakeAuthorize }
+
+func (*AuthorizeStakeInput) GetVariant() xc_types.TxVariantInputType {
+	return xc_types.NewStakeManagementInputType(xc_types.BlockchainSolana, xc_types.StakeAuthorize, string(xc_types.Native))
+}
+
+// Withdraws the entire balance of inactive stake accounts, which closes them
+type CloseStakeInput struct {
+	TxInput
+	EligibleStakes []*ExistingStake `json:"eligible_stakes"`
+}
+
+var _ xc_types.StakeManagementTxInput = &CloseStakeInput{}
+
+func (*CloseStakeInput) ManagingStake() xc_types.StakeOperation { return xc_types.StakeClose }
+
+func (*CloseStakeInput) GetVariant() xc_types.TxVariantInputType {
+	return xc_types.NewStakeManagementInputType(xc_types.BlockchainSolana, xc_types.StakeClose, string(xc_types.Native))
+}
@@ -79,6 +79,63 @@ func TestTxInputConflicts(t *testing.T) {
 			independent:     true,
 			doubleSpendSafe: false,
 		},
+		{
+			// the same durable nonce can only be used once
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{1}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{5}, Nonce: solana.Hash{6}},
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()/2),
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{5}, Nonce: solana.Hash{6}},
+			},
+			independent:     false,
+			doubleSpendSafe: true,
+		},
+		{
+			// a durable nonce does not expire
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{1}),
+				Timestamp:       startTime,
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()) - 1,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{5}, Nonce: solana.Hash{6}},
+			},
+			independent:     true,
+			doubleSpendSafe: false,
+		},
+		{
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{1}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{5}, Nonce: solana.Hash{7}},
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()) - 1,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{5}, Nonce: solana.Hash{6}},
+			},
+			independent:     true,
+			doubleSpendSafe: false,
+		},
+		{
+			// an old recent block hash still expires
+			newInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{1}),
+				Timestamp:       startTime,
+				DurableNonce:    &DurableNonce{Account: solana.PublicKey{5}, Nonce: solana.Hash{6}},
+			},
+			oldInput: &TxInput{
+				RecentBlockHash: solana.Hash([32]byte{2}),
+				Timestamp:       startTime - int64(SafetyTimeoutMargin.Seconds()) - 1,
+			},
+			independent:     true,
+			doubleSpendSafe: true,
+		},
 	}
 	for i, v := range vectors {
 		newBz, _ := json.Marshal(v.newInput)
@@ -99,3 +156,45 @@ func TestTxInputConflicts(t *testing.T) {
 		)
 	}
 }
+
+func TestSetRecentPrioritizationFees(t *testing.T) {
+	chain := &xc_types.ChainConfig{}
+	input := NewTxInput()
+	recent := []uint64{}
+	for i := 100; i > 0; i-- {
+		recent = append(recent, uint64(i*1000))
+	}
+	input.SetRecentPrioritizationFees(recent, chain)
+	// market priority by default
+	require.EqualValues(t, 50_000, input.PrioritizationFee.Uint64())
+	// the recent fees are not reordered
+	require.EqualValues(t, 100_000, recent[0])
+
+	for priority, expected := range map[xc_types.GasFeePriority]uint64{
+		xc_types.Low:            25_000,
+		xc_types.Market:         50_000,
+		xc_types.Aggressive:     75_000,
+		xc_types.VeryAggressive: 95_000,
+	} {
+		input.SetRecentPrioritizationFees(recent, chain)
+		require.NoError(t, input.SetGasFeePriority(priority))
+		require.EqualValues(t, expected, input.PrioritizationFee.Uint64(), priority)
+	}
+
+	// custom multipliers apply to the market fee
+	input.SetRecentPrioritizationFees(recent, chain)
+	require.NoError(t, input.SetGasFeePriority(xc_types.GasFeePriority("3")))
+	require.EqualValues(t, 150_000, input.PrioritizationFee.Uint64())
+
+	// the chain multiplier applies to every priority
+	input.SetRecentPrioritizationFees(recent, &xc_types.ChainConfig{ChainGasMultiplier: 2})
+	require.EqualValues(t, 50_000, input.PrioritizationFees[xc_types.Low].Uint64())
+	require.EqualValues(t, 100_000, input.PrioritizationFee.Uint64())
+
+	// no recent fees, or free slots, fall back to the minimum
+	input.SetRecentPrioritizationFees([]uint64{}, chain)
+	require.EqualValues(t, MinPrioritizationFee, input.PrioritizationFee.Uint64())
+	input.SetRecentPrioritizationFees([]uint64{6000, 0, 0, 0, 5000}, chain)
+	require.EqualValues(t, MinPrioritizationFee, input.PrioritizationFees[xc_types.Low].Uint64())
+	require.EqualValues(t, 5000, input.PrioritizationFees[xc_types.VeryAggressive].Uint64())
+}
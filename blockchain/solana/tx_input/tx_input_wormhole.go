@@ -0,0 +1,24 @@
+package tx_input
+
+import (
+	"github.com/gagliardetto/solana-go"
+	solana_types "github.com/openweb3-io/crosschain/blockchain/solana/types"
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// Input for redeeming a signed wormhole VAA with the token bridge on solana
+type WormholeRedeemInput struct {
+	TxInput
+	Programs solana_types.WormholePrograms `json:"programs"`
+	// The new account the guardian signatures are verified into before the VAA can be posted
+	SignatureSetKey solana.PrivateKey `json:"signature_set_key"`
+	// The mint the transfer redeems, which is a wrapped mint for tokens bridged from other chains
+	Mint    solana.PublicKey `json:"mint"`
+	Wrapped bool             `json:"wrapped,omitempty"`
+}
+
+var _ xc_types.TxInput = &WormholeRedeemInput{}
+
+func NewWormholeRedeemInput() *WormholeRedeemInput {
+	return &WormholeRedeemInput{}
+}
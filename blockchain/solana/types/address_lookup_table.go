@@ -0,0 +1,5 @@
+package types
+
+import "github.com/gagliardetto/solana-go"
+
+var AddressLookupTableProgram = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
@@ -0,0 +1,131 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// CCTP programs, which have the same address on mainnet and devnet
+var CctpMessageTransmitterProgramID = solana.MustPublicKeyFromBase58("CCTPmbSD7gX1bxKPAmg77w8oFzNFpaQiQUWD43TKaecd")
+var CctpTokenMessengerMinterProgramID = solana.MustPublicKeyFromBase58("CCTPiPYPc6AsJuwueEnWgSmucamXDZwBd53dQ11YiKX3")
+
+// AnchorDiscriminator is the prefix anchor programs identify an instruction by
+func AnchorDiscriminator(instruction string) []byte {
+	hash := sha256.Sum256([]byte("global:" + instruction))
+	return hash[:8]
+}
+
+func findPda(program solana.PublicKey, seeds ...[]byte) solana.PublicKey {
+	pda, _, err := solana.FindProgramAddress(seeds, program)
+	if err != nil {
+		// only fails if seeds are too long, which none of ours are
+		panic(err)
+	}
+	return pda
+}
+
+func domainSeed(domain uint32) []byte {
+	return []byte(strconv.FormatUint(uint64(domain), 10))
+}
+
+func CctpMessageTransmitterPda() solana.PublicKey {
+	return findPda(CctpMessageTransmitterProgramID, []byte("message_transmitter"))
+}
+
+func CctpMessageTransmitterAuthorityPda(receiver solana.PublicKey) solana.PublicKey {
+	return findPda(CctpMessageTransmitterProgramID, []byte("message_transmitter_authority"), receiver[:])
+}
+
+func CctpUsedNoncesPda(sourceDomain uint32, firstNonce uint64) solana.PublicKey {
+	// domains with more than one digit are delimited so the seeds can't collide
+	delimiter := []byte{}
+	if sourceDomain >= 11 {
+		delimiter = []byte("-")
+	}
+	return findPda(CctpMessageTransmitterProgramID, []byte("used_nonces"), domainSeed(sourceDomain), delimiter, []byte(strconv.FormatUint(firstNonce, 10)))
+}
+
+func CctpSenderAuthorityPda() solana.PublicKey {
+	return findPda(CctpTokenMessengerMinterProgramID, []byte("sender_authority"))
+}
+
+func CctpTokenMessengerPda() solana.PublicKey {
+	return findPda(CctpTokenMessengerMinterProgramID, []byte("token_messenger"))
+}
+
+func CctpRemoteTokenMessengerPda(domain uint32) solana.PublicKey {
+	return findPda(CctpTokenMessengerMinterProgramID, []byte("remote_token_messenger"), domainSeed(domain))
+}
+
+func CctpTokenMinterPda() solana.PublicKey {
+	return findPda(CctpTokenMessengerMinterProgramID, []byte("token_minter"))
+}
+
+func CctpLocalTokenPda(mint solana.PublicKey) solana.PublicKey {
+	return findPda(CctpTokenMessengerMinterProgramID, []byte("local_token"), mint[:])
+}
+
+func CctpTokenPairPda(remoteDomain uint32, remoteToken [32]byte) solana.PublicKey {
+	return findPda(CctpTokenMessengerMinterProgramID, []byte("token_pair"), domainSeed(remoteDomain), remoteToken[:])
+}
+
+func CctpCustodyPda(mint solana.PublicKey) solana.PublicKey {
+	return findPda(CctpTokenMessengerMinterProgramID, []byte("custody"), mint[:])
+}
+
+// Authority anchor programs emit events through
+func EventAuthorityPda(program solana.PublicKey) solana.PublicKey {
+	return findPda(program, []byte("__event_authority"))
+}
+
+const anchorAccountDiscriminatorLen = 8
+
+// ParseCctpMessageSent returns the message from the account a deposit-for-burn writes it to
+func ParseCctpMessageSent(data []byte) ([]byte, error) {
+	// discriminator, rent payer, then the message as a vec
+	offset := anchorAccountDiscriminatorLen + solana.PublicKeyLength
+	if len(data) < offset+4 {
+		return nil, fmt.Errorf("invalid cctp message sent account length %d", len(data))
+	}
+	length := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
+	offset += 4
+	if len(data) < offset+length {
+		return nil, fmt.Errorf("invalid cctp message length %d", length)
+	}
+	return append([]byte{}, data[offset:offset+length]...), nil
+}
+
+// ParseCctpTokenPairLocalToken returns the local mint of a token pair account
+func ParseCctpTokenPairLocalToken(data []byte) (solana.PublicKey, error) {
+	// discriminator, remote domain, remote token, then the local token
+	offset := anchorAccountDiscriminatorLen + 4 + 32
+	if len(data) < offset+solana.PublicKeyLength {
+		return solana.PublicKey{}, fmt.Errorf("invalid cctp token pair account length %d", len(data))
+	}
+	return solana.PublicKeyFromBytes(data[offset : offset+solana.PublicKeyLength]), nil
+}
+
+// IsCctpNonceUsed checks a nonce against a used-nonces account
+func IsCctpNonceUsed(data []byte, nonce uint64) (bool, error) {
+	// discriminator, remote domain, first nonce, then a bitmap of the nonces
+	offset := anchorAccountDiscriminatorLen + 4
+	if len(data) < offset+8 {
+		return false, fmt.Errorf("invalid cctp used nonces account length %d", len(data))
+	}
+	firstNonce := binary.LittleEndian.Uint64(data[offset : offset+8])
+	offset += 8
+	if nonce < firstNonce {
+		return false, fmt.Errorf("nonce %d is before the first nonce %d of the account", nonce, firstNonce)
+	}
+	index := nonce - firstNonce
+	word := offset + int(index/64)*8
+	if len(data) < word+8 {
+		return false, fmt.Errorf("nonce %d is past the end of the used nonces account", nonce)
+	}
+	bits := binary.LittleEndian.Uint64(data[word : word+8])
+	return bits&(1<<(index%64)) != 0, nil
+}
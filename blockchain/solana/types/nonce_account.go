@@ -0,0 +1,32 @@
+package types
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// The size of a durable nonce account
+const NonceAccountSize = 80
+
+const (
+	NonceAccountUninitialized uint32 = 0
+	NonceAccountInitialized   uint32 = 1
+)
+
+// Parse the data of a nonce account, which must be initialized to hold a nonce
+func ParseNonceAccount(data []byte) (*system.NonceAccount, error) {
+	if len(data) != NonceAccountSize {
+		return nil, fmt.Errorf("nonce account should be %d bytes but is %d", NonceAccountSize, len(data))
+	}
+	account := &system.NonceAccount{}
+	err := bin.NewBinDecoder(data).Decode(account)
+	if err != nil {
+		return nil, err
+	}
+	if account.State != NonceAccountInitialized {
+		return nil, fmt.Errorf("nonce account is not initialized")
+	}
+	return account, nil
+}
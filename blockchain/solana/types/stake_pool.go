@@ -0,0 +1,238 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// The SPL stake-pool program.  Some pools (e.g. Jito) are deployed under it, others
+// run their own deployment, so the owner of the pool account should be used.
+var StakePoolProgramID = solana.MustPublicKeyFromBase58("SPoo1Ku8WFXoNDMHPsrGSTSG1Y47rzgn41SLUNakuHy")
+
+const stakePoolAccountType = 1
+
+// Stake pool instructions
+const (
+	StakePoolInstructionWithdrawStake = 10
+	StakePoolInstructionDepositSol    = 14
+	StakePoolInstructionWithdrawSol   = 16
+)
+
+type StakePoolFee struct {
+	Denominator uint64
+	Numerator   uint64
+}
+
+// The fields of a stake pool that are needed to deposit and withdraw
+type StakePool struct {
+	Manager               solana.PublicKey
+	Staker                solana.PublicKey
+	StakeDepositAuthority solana.PublicKey
+	StakeWithdrawBumpSeed uint8
+	ValidatorList         solana.PublicKey
+	ReserveStake          solana.PublicKey
+	PoolMint              solana.PublicKey
+	ManagerFeeAccount     solana.PublicKey
+	TokenProgramID        solana.PublicKey
+	TotalLamports         uint64
+	PoolTokenSupply       uint64
+	LastUpdateEpoch       uint64
+
+	PreferredDepositValidator  *solana.PublicKey
+	PreferredWithdrawValidator *solana.PublicKey
+
+	StakeWithdrawalFee StakePoolFee
+	// Deposits and withdrawals of SOL need these authorities to sign when they're set
+	SolDepositAuthority  *solana.PublicKey
+	SolDepositFee        StakePoolFee
+	SolWithdrawAuthority *solana.PublicKey
+	SolWithdrawalFee     StakePoolFee
+}
+
+type borshReader struct {
+	decoder *bin.Decoder
+	err     error
+}
+
+func (r *borshReader) pubkey() solana.PublicKey {
+	if r.err != nil {
+		return solana.PublicKey{}
+	}
+	var bz []byte
+	bz, r.err = r.decoder.ReadNBytes(32)
+	if r.err != nil {
+		return solana.PublicKey{}
+	}
+	return solana.PublicKeyFromBytes(bz)
+}
+
+func (r *borshReader) u8() uint8 {
+	if r.err != nil {
+		return 0
+	}
+	var v uint8
+	v, r.err = r.decoder.ReadUint8()
+	return v
+}
+
+func (r *borshReader) u32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	var v uint32
+	v, r.err = r.decoder.ReadUint32(binary.LittleEndian)
+	return v
+}
+
+func (r *borshReader) u64() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	var v uint64
+	v, r.err = r.decoder.ReadUint64(binary.LittleEndian)
+	return v
+}
+
+func (r *borshReader) optionalPubkey() *solana.PublicKey {
+	if r.u8() == 0 {
+		return nil
+	}
+	key := r.pubkey()
+	return &key
+}
+
+func (r *borshReader) fee() StakePoolFee {
+	return StakePoolFee{Denominator: r.u64(), Numerator: r.u64()}
+}
+
+// A fee to take effect in a future epoch, which is skipped
+func (r *borshReader) futureFee() {
+	if r.u8() != 0 {
+		r.fee()
+	}
+}
+
+func ParseStakePool(data []byte) (*StakePool, error) {
+	r := &borshReader{decoder: bin.NewBorshDecoder(data)}
+	if accountType := r.u8(); r.err == nil && accountType != stakePoolAccountType {
+		return nil, fmt.Errorf("not a stake pool account, account type is %d", accountType)
+	}
+	pool := &StakePool{}
+	pool.Manager = r.pubkey()
+	pool.Staker = r.pubkey()
+	pool.StakeDepositAuthority = r.pubkey()
+	pool.StakeWithdrawBumpSeed = r.u8()
+	pool.ValidatorList = r.pubkey()
+	pool.ReserveStake = r.pubkey()
+	pool.PoolMint = r.pubkey()
+	pool.ManagerFeeAccount = r.pubkey()
+	pool.TokenProgramID = r.pubkey()
+	pool.TotalLamports = r.u64()
+	pool.PoolTokenSupply = r.u64()
+	pool.LastUpdateEpoch = r.u64()
+	// lockup
+	r.u64()
+	r.u64()
+	r.pubkey()
+	// epoch fee, and the next one
+	r.fee()
+	r.futureFee()
+	pool.PreferredDepositValidator = r.optionalPubkey()
+	pool.PreferredWithdrawValidator = r.optionalPubkey()
+	// stake deposit fee
+	r.fee()
+	pool.StakeWithdrawalFee = r.fee()
+	r.futureFee()
+	// stake referral fee
+	r.u8()
+	pool.SolDepositAuthority = r.optionalPubkey()
+	pool.SolDepositFee = r.fee()
+	// sol referral fee
+	r.u8()
+	pool.SolWithdrawAuthority = r.optionalPubkey()
+	pool.SolWithdrawalFee = r.fee()
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid stake pool account: %v", r.err)
+	}
+	return pool, nil
+}
+
+// Convert lamports into pool tokens at the pool's exchange rate, rounding down
+func (pool *StakePool) LamportsToPoolTokens(lamports uint64) uint64 {
+	if pool.TotalLamports == 0 || pool.PoolTokenSupply == 0 {
+		return lamports
+	}
+	result := new(big.Int).Mul(new(big.Int).SetUint64(lamports), new(big.Int).SetUint64(pool.PoolTokenSupply))
+	return result.Div(result, new(big.Int).SetUint64(pool.TotalLamports)).Uint64()
+}
+
+// Convert pool tokens into lamports at the pool's exchange rate, rounding down
+func (pool *StakePool) PoolTokensToLamports(poolTokens uint64) uint64 {
+	if pool.PoolTokenSupply == 0 {
+		return poolTokens
+	}
+	result := new(big.Int).Mul(new(big.Int).SetUint64(poolTokens), new(big.Int).SetUint64(pool.TotalLamports))
+	return result.Div(result, new(big.Int).SetUint64(pool.PoolTokenSupply)).Uint64()
+}
+
+// The authority that holds the pool's stake and mints its tokens
+func StakePoolWithdrawAuthority(program solana.PublicKey, pool solana.PublicKey) solana.PublicKey {
+	return findPda(program, pool[:], []byte("withdraw"))
+}
+
+// The stake account the pool delegates to a validator
+func StakePoolValidatorStakeAccount(program solana.PublicKey, pool solana.PublicKey, voteAccount solana.PublicKey, seed uint32) solana.PublicKey {
+	seeds := [][]byte{voteAccount[:], pool[:]}
+	if seed != 0 {
+		seeds = append(seeds, binary.LittleEndian.AppendUint32(nil, seed))
+	}
+	return findPda(program, seeds...)
+}
+
+const (
+	StakePoolValidatorActive uint8 = 0
+)
+
+type StakePoolValidator struct {
+	ActiveStakeLamports    uint64
+	TransientStakeLamports uint64
+	ValidatorSeedSuffix    uint32
+	Status                 uint8
+	VoteAccount            solana.PublicKey
+}
+
+const stakePoolValidatorListAccountType = 2
+
+// Parse the validators of a pool from its validator list account
+func ParseStakePoolValidatorList(data []byte) ([]*StakePoolValidator, error) {
+	r := &borshReader{decoder: bin.NewBorshDecoder(data)}
+	if accountType := r.u8(); r.err == nil && accountType != stakePoolValidatorListAccountType {
+		return nil, fmt.Errorf("not a validator list account, account type is %d", accountType)
+	}
+	// max validators
+	r.u32()
+	count := r.u32()
+	validators := []*StakePoolValidator{}
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		validator := &StakePoolValidator{}
+		validator.ActiveStakeLamports = r.u64()
+		validator.TransientStakeLamports = r.u64()
+		// last update epoch and transient seed suffix
+		r.u64()
+		r.u64()
+		// unused
+		r.u32()
+		validator.ValidatorSeedSuffix = r.u32()
+		validator.Status = r.u8()
+		validator.VoteAccount = r.pubkey()
+		validators = append(validators, validator)
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid validator list account: %v", r.err)
+	}
+	return validators, nil
+}
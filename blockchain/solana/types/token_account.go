@@ -25,6 +25,14 @@ type TokenAccountInfoParsedInfo struct {
 	Owner       string                                `json:"owner"`
 	State       string                                `json:"state"`
 	TokenAmount TokenAccountInfoParsedInfoTokenAmount `json:"tokenAmount"`
+	// Set when someone other than the owner may close the account
+	CloseAuthority string `json:"closeAuthority,omitempty"`
+	// Token-2022 extensions
+	Extensions []TokenAccountInfoParsedExtension `json:"extensions,omitempty"`
+}
+type TokenAccountInfoParsedExtension struct {
+	Extension string          `json:"extension"`
+	State     json.RawMessage `json:"state"`
 }
 type TokenAccountInfoParsedInfoTokenAmount struct {
 	Amount       string  `json:"amount"`
@@ -33,6 +41,22 @@ type TokenAccountInfoParsedInfoTokenAmount struct {
 	UinAmountStr string  `json:"uiAmountString"`
 }
 
+// Transfer fees withheld in a Token-2022 account, which must be harvested before it can be closed
+func (info *TokenAccountInfoParsedInfo) GetWithheldTransferFees() uint64 {
+	for _, extension := range info.Extensions {
+		if extension.Extension != "transferFeeAmount" {
+			continue
+		}
+		var state struct {
+			WithheldAmount uint64 `json:"withheldAmount"`
+		}
+		if err := json.Unmarshal(extension.State, &state); err == nil {
+			return state.WithheldAmount
+		}
+	}
+	return 0
+}
+
 // Parse from json data returned by solana client.
 // Note the client request must use `Encoding:   "jsonParsed"` option.
 func ParseRpcData[T TokenAccountInfo](data *rpc.DataBytesOrJSON) (T, error) {
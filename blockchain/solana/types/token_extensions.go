@@ -0,0 +1,268 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Token-2022 accounts are padded to the size of a token account, followed by
+// the account type and then each extension as type-length-value.
+const TokenAccountLen = 165
+
+type TokenExtensionType uint16
+
+const (
+	ExtensionTransferFeeConfig TokenExtensionType = 1
+	ExtensionMemoTransfer      TokenExtensionType = 8
+	ExtensionTransferHook      TokenExtensionType = 14
+)
+
+// Returns the value of each extension on a Token-2022 mint or token account
+func ParseTokenExtensions(data []byte) (map[TokenExtensionType][]byte, error) {
+	extensions := map[TokenExtensionType][]byte{}
+	if len(data) <= TokenAccountLen {
+		// no extensions
+		return extensions, nil
+	}
+	// skip the account type
+	tlv := data[TokenAccountLen+1:]
+	for len(tlv) >= 4 {
+		extensionType := TokenExtensionType(binary.LittleEndian.Uint16(tlv[0:2]))
+		length := int(binary.LittleEndian.Uint16(tlv[2:4]))
+		if extensionType == 0 {
+			// the rest is uninitialized
+			break
+		}
+		if len(tlv) < 4+length {
+			return nil, fmt.Errorf("token extension %d is %d bytes but only %d remain", extensionType, length, len(tlv)-4)
+		}
+		extensions[extensionType] = tlv[4 : 4+length]
+		tlv = tlv[4+length:]
+	}
+	return extensions, nil
+}
+
+type TransferFee struct {
+	Epoch       uint64 `json:"epoch"`
+	MaximumFee  uint64 `json:"maximum_fee"`
+	BasisPoints uint16 `json:"basis_points"`
+}
+
+// The fee withheld from the transferred amount, rounded up
+func (fee *TransferFee) GetFee(amount uint64) uint64 {
+	if fee.BasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	// avoid overflowing the multiplication
+	quotient, remainder := amount/10_000, amount%10_000
+	total := quotient*uint64(fee.BasisPoints) + (remainder*uint64(fee.BasisPoints)+9_999)/10_000
+	if total > fee.MaximumFee {
+		return fee.MaximumFee
+	}
+	return total
+}
+
+type TransferFeeConfig struct {
+	Older TransferFee
+	Newer TransferFee
+}
+
+// The newer fee takes effect from its epoch
+func (config *TransferFeeConfig) GetTransferFee(epoch uint64) TransferFee {
+	if epoch >= config.Newer.Epoch {
+		return config.Newer
+	}
+	return config.Older
+}
+
+func ParseTransferFeeConfig(data []byte) (*TransferFeeConfig, error) {
+	// config authority, withdraw authority, withheld amount, then the older and newer fees
+	if len(data) != 108 {
+		return nil, fmt.Errorf("transfer fee config should be 108 bytes but is %d", len(data))
+	}
+	parseFee := func(bz []byte) TransferFee {
+		return TransferFee{
+			Epoch:       binary.LittleEndian.Uint64(bz[0:8]),
+			MaximumFee:  binary.LittleEndian.Uint64(bz[8:16]),
+			BasisPoints: binary.LittleEndian.Uint16(bz[16:18]),
+		}
+	}
+	return &TransferFeeConfig{
+		Older: parseFee(data[72:90]),
+		Newer: parseFee(data[90:108]),
+	}, nil
+}
+
+// Whether a token account requires a memo to precede incoming transfers
+func ParseMemoTransfer(data []byte) (bool, error) {
+	if len(data) != 1 {
+		return false, fmt.Errorf("memo transfer should be 1 byte but is %d", len(data))
+	}
+	return data[0] != 0, nil
+}
+
+// Returns the program called on each transfer, if any
+func ParseTransferHook(data []byte) (solana.PublicKey, error) {
+	// authority, then the program
+	if len(data) != 64 {
+		return solana.PublicKey{}, fmt.Errorf("transfer hook should be 64 bytes but is %d", len(data))
+	}
+	return solana.PublicKeyFromBytes(data[32:64]), nil
+}
+
+// The account that lists the extra accounts a transfer hook program needs
+func TransferHookExtraAccountMetasPda(hookProgram solana.PublicKey, mint solana.PublicKey) solana.PublicKey {
+	return findPda(hookProgram, []byte("extra-account-metas"), mint[:])
+}
+
+// The discriminator of the transfer hook execute instruction
+func TransferHookExecuteDiscriminator() []byte {
+	hash := sha256.Sum256([]byte("spl-transfer-hook-interface:execute"))
+	return hash[:8]
+}
+
+const extraAccountMetaLen = 35
+
+type ExtraAccountMeta struct {
+	Discriminator uint8
+	AddressConfig [32]byte
+	IsSigner      bool
+	IsWritable    bool
+}
+
+// Parse the extra account metas for the execute instruction, from the hook's validation account
+func ParseExtraAccountMetas(data []byte) ([]*ExtraAccountMeta, error) {
+	discriminator := TransferHookExecuteDiscriminator()
+	for len(data) >= 12 {
+		length := int(binary.LittleEndian.Uint32(data[8:12]))
+		if len(data) < 12+length {
+			return nil, fmt.Errorf("extra account metas are %d bytes but only %d remain", length, len(data)-12)
+		}
+		if string(data[:8]) != string(discriminator) {
+			data = data[12+length:]
+			continue
+		}
+		value := data[12 : 12+length]
+		if len(value) < 4 {
+			return nil, fmt.Errorf("extra account metas are missing their count")
+		}
+		count := int(binary.LittleEndian.Uint32(value[0:4]))
+		if len(value) < 4+count*extraAccountMetaLen {
+			return nil, fmt.Errorf("expected %d extra account metas", count)
+		}
+		metas := []*ExtraAccountMeta{}
+		for i := 0; i < count; i++ {
+			bz := value[4+i*extraAccountMetaLen : 4+(i+1)*extraAccountMetaLen]
+			meta := &ExtraAccountMeta{
+				Discriminator: bz[0],
+				IsSigner:      bz[33] != 0,
+				IsWritable:    bz[34] != 0,
+			}
+			copy(meta.AddressConfig[:], bz[1:33])
+			metas = append(metas, meta)
+		}
+		return metas, nil
+	}
+	return nil, fmt.Errorf("no extra account metas for the execute instruction")
+}
+
+// Resolve the extra accounts from their metas.  Accounts are the accounts of the execute instruction
+// so far: source, mint, destination, owner and the validation account, and data is its instruction data.
+// Seeds may refer to an account's data, which is looked up with getAccountData.
+func ResolveExtraAccountMetas(
+	hookProgram solana.PublicKey,
+	metas []*ExtraAccountMeta,
+	accounts solana.PublicKeySlice,
+	data []byte,
+	getAccountData func(solana.PublicKey) ([]byte, error),
+) (solana.AccountMetaSlice, error) {
+	accounts = append(solana.PublicKeySlice{}, accounts...)
+	resolved := solana.AccountMetaSlice{}
+	for _, meta := range metas {
+		var address solana.PublicKey
+		switch {
+		case meta.Discriminator == 0:
+			address = solana.PublicKeyFromBytes(meta.AddressConfig[:])
+		case meta.Discriminator == 1 || meta.Discriminator >= 128:
+			program := hookProgram
+			if meta.Discriminator >= 128 {
+				index := int(meta.Discriminator - 128)
+				if index >= len(accounts) {
+					return nil, fmt.Errorf("extra account refers to program at unknown account %d", index)
+				}
+				program = accounts[index]
+			}
+			seeds, err := resolveExtraAccountSeeds(meta.AddressConfig[:], accounts, data, getAccountData)
+			if err != nil {
+				return nil, err
+			}
+			address, _, err = solana.FindProgramAddress(seeds, program)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported extra account discriminator %d", meta.Discriminator)
+		}
+		accounts = append(accounts, address)
+		resolved = append(resolved, &solana.AccountMeta{
+			PublicKey:  address,
+			IsSigner:   meta.IsSigner,
+			IsWritable: meta.IsWritable,
+		})
+	}
+	return resolved, nil
+}
+
+func resolveExtraAccountSeeds(config []byte, accounts solana.PublicKeySlice, data []byte, getAccountData func(solana.PublicKey) ([]byte, error)) ([][]byte, error) {
+	seeds := [][]byte{}
+	for len(config) > 0 && config[0] != 0 {
+		switch config[0] {
+		case 1:
+			// literal
+			if len(config) < 2 || len(config) < 2+int(config[1]) {
+				return nil, fmt.Errorf("invalid literal seed")
+			}
+			seeds = append(seeds, config[2:2+int(config[1])])
+			config = config[2+int(config[1]):]
+		case 2:
+			// instruction data
+			if len(config) < 3 {
+				return nil, fmt.Errorf("invalid instruction data seed")
+			}
+			start, length := int(config[1]), int(config[2])
+			if len(data) < start+length {
+				return nil, fmt.Errorf("instruction data seed is out of range")
+			}
+			seeds = append(seeds, data[start:start+length])
+			config = config[3:]
+		case 3:
+			// account key
+			if len(config) < 2 || int(config[1]) >= len(accounts) {
+				return nil, fmt.Errorf("invalid account key seed")
+			}
+			seeds = append(seeds, accounts[config[1]][:])
+			config = config[2:]
+		case 4:
+			// account data
+			if len(config) < 4 || int(config[1]) >= len(accounts) {
+				return nil, fmt.Errorf("invalid account data seed")
+			}
+			accountData, err := getAccountData(accounts[config[1]])
+			if err != nil {
+				return nil, err
+			}
+			start, length := int(config[2]), int(config[3])
+			if len(accountData) < start+length {
+				return nil, fmt.Errorf("account data seed is out of range")
+			}
+			seeds = append(seeds, accountData[start:start+length])
+			config = config[4:]
+		default:
+			return nil, fmt.Errorf("unsupported seed type %d", config[0])
+		}
+	}
+	return seeds, nil
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var MetaplexTokenMetadataProgram = solana.MustPublicKeyFromBase58("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+
+// Layout of an SPL mint account; token-2022 mints share it, followed by their extensions
+const (
+	mintDecimalsOffset = 44
+	MintAccountLen     = 82
+)
+
+// The account with the Metaplex metadata of a mint
+func MetaplexMetadataPda(mint solana.PublicKey) solana.PublicKey {
+	return findPda(MetaplexTokenMetadataProgram, []byte("metadata"), MetaplexTokenMetadataProgram[:], mint[:])
+}
+
+func ParseMintDecimals(data []byte) (int32, error) {
+	if len(data) < MintAccountLen {
+		return 0, fmt.Errorf("invalid mint account length %d", len(data))
+	}
+	return int32(data[mintDecimalsOffset]), nil
+}
+
+// ParseMetaplexMetadata returns the name and symbol from a Metaplex metadata account
+func ParseMetaplexMetadata(data []byte) (string, string, error) {
+	// key, update authority, mint
+	offset := 1 + 32 + 32
+	name, offset, err := parseBorshString(data, offset)
+	if err != nil {
+		return "", "", err
+	}
+	symbol, _, err := parseBorshString(data, offset)
+	if err != nil {
+		return "", "", err
+	}
+	return name, symbol, nil
+}
+
+// Metaplex pads its strings with null bytes to a fixed length
+func parseBorshString(data []byte, offset int) (string, int, error) {
+	if len(data) < offset+4 {
+		return "", offset, fmt.Errorf("invalid metaplex metadata length %d", len(data))
+	}
+	length := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
+	offset += 4
+	if len(data) < offset+length {
+		return "", offset, fmt.Errorf("invalid metaplex metadata string length %d", length)
+	}
+	value := strings.TrimRight(string(data[offset:offset+length]), "\x00")
+	return value, offset + length, nil
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+type WormholePrograms struct {
+	CoreBridge  solana.PublicKey `json:"core_bridge"`
+	TokenBridge solana.PublicKey `json:"token_bridge"`
+}
+
+var WormholeMainnetPrograms = WormholePrograms{
+	CoreBridge:  solana.MustPublicKeyFromBase58("worm2ZoG2kUd4vFXhvjh93UUH596ayRfgQ2MgjNMTth"),
+	TokenBridge: solana.MustPublicKeyFromBase58("wormDTUJ6AWPNvk59vGQbDvGJmqbDTdgWgAqcLBCgUb"),
+}
+
+var WormholeDevnetPrograms = WormholePrograms{
+	CoreBridge:  solana.MustPublicKeyFromBase58("3u8hJUVTA4jH1wYAyUur7FFZVQ8H635K3tSHHF4ssjQ5"),
+	TokenBridge: solana.MustPublicKeyFromBase58("DZnkkTmCiFWfYTfT41X3Rd1kDgozqzxWaHqsw6W4x2oe"),
+}
+
+// Unlike CCTP, wormhole is deployed at different addresses on mainnet and devnet
+func WormholeProgramsForNetwork(network string) WormholePrograms {
+	if network == "mainnet" {
+		return WormholeMainnetPrograms
+	}
+	return WormholeDevnetPrograms
+}
+
+// Instructions of the (non-anchor) wormhole programs are identified by their first byte
+const (
+	WormholePostVaaInstruction          = 2
+	WormholeVerifySignaturesInstruction = 7
+
+	WormholeCompleteNativeInstruction  = 2
+	WormholeCompleteWrappedInstruction = 3
+)
+
+// The most guardians a guardian set can have
+const WormholeMaxGuardians = 19
+
+func WormholeBridgeConfigPda(programs WormholePrograms) solana.PublicKey {
+	return findPda(programs.CoreBridge, []byte("Bridge"))
+}
+
+func WormholeGuardianSetPda(programs WormholePrograms, index uint32) solana.PublicKey {
+	return findPda(programs.CoreBridge, []byte("GuardianSet"), binary.BigEndian.AppendUint32(nil, index))
+}
+
+// The account a VAA is posted to, by the keccak hash of its body
+func WormholePostedVaaPda(programs WormholePrograms, bodyHash []byte) solana.PublicKey {
+	return findPda(programs.CoreBridge, []byte("PostedVAA"), bodyHash)
+}
+
+func WormholeTokenBridgeConfigPda(programs WormholePrograms) solana.PublicKey {
+	return findPda(programs.TokenBridge, []byte("config"))
+}
+
+// The account that marks a transfer as completed
+func WormholeClaimPda(programs WormholePrograms, emitterChain uint16, emitterAddress [32]byte, sequence uint64) solana.PublicKey {
+	return findPda(
+		programs.TokenBridge,
+		emitterAddress[:],
+		binary.BigEndian.AppendUint16(nil, emitterChain),
+		binary.BigEndian.AppendUint64(nil, sequence),
+	)
+}
+
+// The registration of the token bridge on another chain
+func WormholeEndpointPda(programs WormholePrograms, emitterChain uint16, emitterAddress [32]byte) solana.PublicKey {
+	return findPda(programs.TokenBridge, binary.BigEndian.AppendUint16(nil, emitterChain), emitterAddress[:])
+}
+
+func WormholeCustodyPda(programs WormholePrograms, mint solana.PublicKey) solana.PublicKey {
+	return findPda(programs.TokenBridge, mint[:])
+}
+
+func WormholeCustodySignerPda(programs WormholePrograms) solana.PublicKey {
+	return findPda(programs.TokenBridge, []byte("custody_signer"))
+}
+
+// The mint of a token bridged from another chain
+func WormholeWrappedMintPda(programs WormholePrograms, tokenChain uint16, tokenAddress [32]byte) solana.PublicKey {
+	return findPda(programs.TokenBridge, []byte("wrapped"), binary.BigEndian.AppendUint16(nil, tokenChain), tokenAddress[:])
+}
+
+func WormholeWrappedMetaPda(programs WormholePrograms, mint solana.PublicKey) solana.PublicKey {
+	return findPda(programs.TokenBridge, []byte("meta"), mint[:])
+}
+
+func WormholeMintSignerPda(programs WormholePrograms) solana.PublicKey {
+	return findPda(programs.TokenBridge, []byte("mint_signer"))
+}
+
+// ParseWormholeGuardianSet returns the ethereum addresses of the guardians in a guardian set account
+func ParseWormholeGuardianSet(data []byte) ([][20]byte, error) {
+	// index, then the keys as a vec
+	offset := 4
+	if len(data) < offset+4 {
+		return nil, fmt.Errorf("invalid wormhole guardian set account length %d", len(data))
+	}
+	count := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
+	offset += 4
+	if count > WormholeMaxGuardians || len(data) < offset+count*20 {
+		return nil, fmt.Errorf("invalid wormhole guardian set with %d keys", count)
+	}
+	keys := make([][20]byte, count)
+	for i := range keys {
+		copy(keys[i][:], data[offset:offset+20])
+		offset += 20
+	}
+	return keys, nil
+}
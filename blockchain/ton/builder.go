@@ -2,6 +2,7 @@ package ton
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 
 	tonaddress "github.com/openweb3-io/crosschain/blockchain/ton/address"
@@ -49,6 +50,21 @@ func (b *TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc_types.TxI
 	asset, _ := args.GetAsset()
 	memo, _ := args.GetMemo()
 
+	var comment *cell.Cell
+	if memo != "" {
+		if memoSigner, ok := args.GetMemoSigner(); ok {
+			if len(txInput.RecipientPublicKey) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("the public key of %s is required to encrypt the memo", args.GetTo())
+			}
+			comment, err = wallet.CreateEncryptedCommentCell(ctx, memo, commentSender(fromAddr), memoSigner, txInput.RecipientPublicKey)
+		} else {
+			comment, err = wallet.CreateCommentCell(memo)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if asset != nil && asset.GetContract() != "" {
 		tokenAddr, err := tonaddress.ParseAddress(txInput.TokenWallet, "")
 		if err != nil {
@@ -68,23 +84,20 @@ func (b *TxBuilder) NewTransfer(args *xcbuilder.TransferArgs, input xc_types.TxI
 			maxJettonFee = remainingTonBal
 		}
 
-		message, err = BuildJettonTransfer(
+		message, err = BuildJettonTransferWithPayload(
 			uint64(txInput.Timestamp),
 			fromAddr,
 			tokenAddr,
 			toAddr,
 			amountTlb,
 			tlb.FromNanoTON(maxJettonFee.Int()),
-			memo,
+			comment,
 		)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		message, err = BuildTransfer(toAddr, tlb.FromNanoTON(args.GetAmount().Int()), memo)
-		if err != nil {
-			return nil, errors.Wrap(err, "BuildTransfer failed")
-		}
+		message = BuildTransferWithPayload(toAddr, tlb.FromNanoTON(args.GetAmount().Int()), comment)
 	}
 
 	seqnoFetcher := func(ctx context.Context, subWallet uint32) (uint32, error) {
@@ -118,7 +131,12 @@ func BuildTransfer(
 		}
 	}
 
-	return wallet.SimpleMessageAutoBounce(to, amount, body), nil
+	return BuildTransferWithPayload(to, amount, body), nil
+}
+
+// BuildTransferWithPayload is BuildTransfer with a prepared body, such as an encrypted comment
+func BuildTransferWithPayload(to *address.Address, amount tlb.Coins, body *cell.Cell) *wallet.Message {
+	return wallet.SimpleMessageAutoBounce(to, amount, body)
 }
 
 func BuildJettonTransfer(
@@ -138,6 +156,20 @@ func BuildJettonTransfer(
 		}
 	}
 
+	return BuildJettonTransferWithPayload(randomInt, from, jettonWalletAddress, to, amount, maxFee, body)
+}
+
+// BuildJettonTransferWithPayload is BuildJettonTransfer with a prepared forward payload, such as an
+// encrypted comment
+func BuildJettonTransferWithPayload(
+	randomInt uint64,
+	from *address.Address,
+	jettonWalletAddress *address.Address,
+	to *address.Address,
+	amount tlb.Coins,
+	maxFee tlb.Coins,
+	body *cell.Cell,
+) (_ *wallet.Message, err error) {
 	amountForwardTON := tlb.MustFromTON("0.01")
 
 	tokenBody, err := tlb.ToCell(jetton.TransferPayload{
@@ -166,3 +198,36 @@ func ParseComment(body *cell.Cell) (string, bool) {
 	}
 	return "", false
 }
+
+// ParseEncryptedComment decrypts a comment encrypted by the sender, if either the sender or
+// recipient key is one of the given keys.
+func ParseEncryptedComment(body *cell.Cell, sender *address.Address, keys []ed25519.PrivateKey) (string, bool) {
+	if body == nil || sender == nil || len(keys) == 0 {
+		return "", false
+	}
+	l := body.BeginParse()
+	if val, err := l.LoadUInt(32); err != nil || val != wallet.EncryptedCommentOpcode {
+		return "", false
+	}
+	// the comment is prefixed with the xor of the sender and recipient public keys
+	xorKey, err := l.LoadSlice(256)
+	if err != nil {
+		return "", false
+	}
+	for _, key := range keys {
+		theirKey := make(ed25519.PublicKey, ed25519.PublicKeySize)
+		ourKey := key.Public().(ed25519.PublicKey)
+		for i := range theirKey {
+			theirKey[i] = xorKey[i] ^ ourKey[i]
+		}
+		if comment, err := wallet.DecryptCommentCell(body, commentSender(sender), key, theirKey); err == nil {
+			return string(comment), true
+		}
+	}
+	return "", false
+}
+
+// Encrypted comments are salted with the sender's address in its bounceable mainnet form
+func commentSender(addr *address.Address) *address.Address {
+	return addr.Bounce(true).Testnet(false)
+}
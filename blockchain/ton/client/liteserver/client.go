@@ -33,6 +33,8 @@ import (
 type Client struct {
 	cfg    *xc_types.ChainConfig
 	Client *_ton.APIClient
+	// keys of our wallets, to decrypt the encrypted comments they send or receive
+	memoKeys []ed25519.PrivateKey
 }
 
 var _ xcclient.IClient = &Client{}
@@ -50,7 +52,12 @@ func NewClient(cfg *xc_types.ChainConfig) (*Client, error) {
 	}
 	client := _ton.NewAPIClient(c)
 
-	return &Client{cfg, client}, nil
+	return &Client{cfg: cfg, Client: client}, nil
+}
+
+// AddMemoKey lets tx-info decrypt the comments sent to or from the wallet of the key
+func (client *Client) AddMemoKey(key ed25519.PrivateKey) {
+	client.memoKeys = append(client.memoKeys, key)
 }
 
 func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.TransferArgs) (xc_types.TxInput, error) {
@@ -91,6 +98,16 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	}
 
 	memo, _ := args.GetMemo()
+	if _, ok := args.GetMemoSigner(); ok && memo != "" {
+		toAddr, err := address.ParseAddr(string(args.GetTo()))
+		if err != nil {
+			return input, err
+		}
+		input.RecipientPublicKey, err = wallet.GetPublicKey(ctx, client.Client, toAddr)
+		if err != nil {
+			return input, fmt.Errorf("could not get public key of %s to encrypt the memo: %v", args.GetTo(), err)
+		}
+	}
 
 	asset, _ := args.GetAsset()
 	if asset != nil && asset.GetContract() != "" {
@@ -368,6 +385,9 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc_types.TxH
 			memo := ""
 
 			memo = intMsg.Comment()
+			if memo == "" {
+				memo, _ = ton.ParseEncryptedComment(intMsg.Body, intMsg.SrcAddr, client.memoKeys)
+			}
 
 			if intMsg.DstAddr != nil && intMsg.DstAddr.String() != "" && intMsg.Amount.Nano().Int64() != 0 {
 				// addr, err := client.substituteOrParse(addrBook, *)
@@ -0,0 +1,77 @@
+package liteserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/xssnick/tonutils-go/address"
+)
+
+// Fetch the input for a batch transfer from a highload v3 wallet, using the given query ID.  The query ID
+// must not have been processed by the wallet recently.
+func (client *Client) FetchBatchTransferInput(ctx context.Context, from xc_types.Address, outputs []*ton.BatchTransferOutput, queryID ton.HighloadQueryID) (*ton.TxInput, error) {
+	b, err := client.Client.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fromAddr, err := address.ParseAddr(string(from))
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedClient := client.Client.WaitForBlock(b.SeqNo)
+	acc, err := wrappedClient.GetAccount(ctx, b, fromAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	timeoutResp, err := wrappedClient.RunGetMethod(ctx, b, fromAddr, "get_timeout")
+	if err != nil {
+		return nil, fmt.Errorf("could not get highload wallet timeout: %v", err)
+	}
+	timeout, err := timeoutResp.Int(0)
+	if err != nil {
+		return nil, err
+	}
+	processedResp, err := wrappedClient.RunGetMethod(ctx, b, fromAddr, "processed?", uint64(queryID), 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not check highload query %d: %v", queryID, err)
+	}
+	processed, err := processedResp.Int(0)
+	if err != nil {
+		return nil, err
+	}
+	if processed.Sign() != 0 {
+		return nil, fmt.Errorf("highload query %d has already been processed", queryID)
+	}
+
+	balance := acc.State.Balance.Nano()
+
+	input := &ton.TxInput{
+		Timestamp:       time.Now().Unix(),
+		AccountStatus:   ton.AccountStatus(acc.State.Status),
+		TonBalance:      xc_types.BigInt(*balance),
+		HighloadQueryID: queryID,
+		HighloadTimeout: uint32(timeout.Uint64()),
+		TokenWallets:    map[xc_types.ContractAddress]xc_types.Address{},
+	}
+	for _, output := range outputs {
+		if output.Asset == nil || output.Asset.GetContract() == "" {
+			continue
+		}
+		contract := output.Asset.GetContract()
+		if _, ok := input.TokenWallets[contract]; ok {
+			continue
+		}
+		input.TokenWallets[contract], err = client.GetJettonWallet(ctx, from, contract)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return input, nil
+}
@@ -0,0 +1,35 @@
+package liteserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	xcclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/ton/jetton"
+)
+
+var _ xcclient.TokenMetadataClient = &Client{}
+
+// Read the metadata from the content of a jetton master
+func (client *Client) FetchTokenMetadata(ctx context.Context, contract xc_types.ContractAddress) (*xcclient.TokenMetadata, error) {
+	jettonAddr, err := address.ParseAddr(string(contract))
+	if err != nil {
+		return nil, err
+	}
+	data, err := jetton.NewJettonMasterClient(client.Client, jettonAddr).GetJettonData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch jetton data of %s: %v", contract, err)
+	}
+	metadata, err := ton.ParseJettonContent(ctx, data.Content)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := metadata.GetDecimals()
+	if err != nil {
+		return nil, err
+	}
+	return xcclient.NewTokenMetadata(contract, metadata.Name, metadata.Symbol, decimals), nil
+}
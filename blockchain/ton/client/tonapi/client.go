@@ -58,6 +58,8 @@ type JettonTransferPayload struct {
 type Client struct {
 	cfg    *xc_types.ChainConfig
 	Client *_tonapi.Client
+	// keys of our wallets, to decrypt the encrypted comments they send or receive
+	memoKeys []ed25519.PrivateKey
 }
 
 var _ xcclient.IClient = &Client{}
@@ -76,7 +78,12 @@ func NewClient(cfg *xc_types.ChainConfig) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{cfg, tonApi}, nil
+	return &Client{cfg: cfg, Client: tonApi}, nil
+}
+
+// AddMemoKey lets tx-info decrypt the comments sent to or from the wallet of the key
+func (client *Client) AddMemoKey(key ed25519.PrivateKey) {
+	client.memoKeys = append(client.memoKeys, key)
 }
 
 func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.TransferArgs) (xc_types.TxInput, error) {
@@ -103,6 +110,12 @@ func (client *Client) FetchTransferInput(ctx context.Context, args *xcbuilder.Tr
 	}
 
 	memo, _ := args.GetMemo()
+	if _, ok := args.GetMemoSigner(); ok && memo != "" {
+		input.RecipientPublicKey, err = client.getPublicKey(ctx, args.GetTo())
+		if err != nil {
+			return input, fmt.Errorf("could not get public key of %s to encrypt the memo: %v", args.GetTo(), err)
+		}
+	}
 
 	asset, _ := args.GetAsset()
 	if asset != nil && asset.GetContract() != "" {
@@ -389,6 +402,8 @@ func (client *Client) FetchLegacyTxInfo(ctx context.Context, txHash xc_types.TxH
 				_ = json.Unmarshal(msg.DecodedBody, &body)
 
 				memo = string(body.Text)
+			} else if msg.DecodedOpName.Value == "encrypted_text_comment" && msg.Source.IsSet() {
+				memo = client.parseEncryptedComment(msg.RawBody.Value, msg.Source.Value.Address)
 			}
 
 			if msg.Destination.IsSet() && msg.Destination.Value.Address != "" && msg.Value != 0 {
@@ -616,6 +631,9 @@ func (client *Client) ParseJetton(ctx context.Context, c *cell.Cell, tokenWallet
 	if err != nil {
 		return nil, nil, false, err
 	}
+	if encrypted, ok := ton.ParseEncryptedComment(jettonTfMaybe.ForwardPayload, ownerAddr, client.memoKeys); ok {
+		memo = encrypted
+	}
 
 	chain := client.cfg.Chain
 	amount := xc_types.BigInt(*jettonTfMaybe.Amount.Nano())
@@ -0,0 +1,79 @@
+package tonapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	_tonapi "github.com/tonkeeper/tonapi-go"
+)
+
+// Fetch the input for a batch transfer from a highload v3 wallet, using the given query ID.  The query ID
+// must not have been processed by the wallet recently.
+func (client *Client) FetchBatchTransferInput(ctx context.Context, from xc_types.Address, outputs []*ton.BatchTransferOutput, queryID ton.HighloadQueryID) (*ton.TxInput, error) {
+	acc, err := client.Client.GetAccount(ctx, _tonapi.GetAccountParams{
+		AccountID: string(from),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := client.runGetMethodInt(ctx, from, "get_timeout")
+	if err != nil {
+		return nil, fmt.Errorf("could not get highload wallet timeout: %v", err)
+	}
+	processed, err := client.runGetMethodInt(ctx, from, "processed?", fmt.Sprint(uint32(queryID)), "0")
+	if err != nil {
+		return nil, fmt.Errorf("could not check highload query %d: %v", queryID, err)
+	}
+	if processed.Sign() != 0 {
+		return nil, fmt.Errorf("highload query %d has already been processed", queryID)
+	}
+
+	input := &ton.TxInput{
+		Timestamp:       time.Now().Unix(),
+		AccountStatus:   ton.AccountStatus(acc.Status),
+		TonBalance:      xc_types.NewBigIntFromInt64(acc.GetBalance()),
+		HighloadQueryID: queryID,
+		HighloadTimeout: uint32(timeout.Uint64()),
+		TokenWallets:    map[xc_types.ContractAddress]xc_types.Address{},
+	}
+	for _, output := range outputs {
+		if output.Asset == nil || output.Asset.GetContract() == "" {
+			continue
+		}
+		contract := output.Asset.GetContract()
+		if _, ok := input.TokenWallets[contract]; ok {
+			continue
+		}
+		input.TokenWallets[contract], err = client.GetJettonWallet(ctx, from, contract)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return input, nil
+}
+
+// Runs a get method that returns a number
+func (client *Client) runGetMethodInt(ctx context.Context, addr xc_types.Address, method string, args ...string) (*big.Int, error) {
+	res, err := client.Client.ExecGetMethodForBlockchainAccount(ctx, _tonapi.ExecGetMethodForBlockchainAccountParams{
+		AccountID:  string(addr),
+		MethodName: method,
+		Args:       args,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !res.Success || len(res.Stack) == 0 {
+		return nil, fmt.Errorf("%s failed with exit code %d", method, res.ExitCode)
+	}
+	value, ok := new(big.Int).SetString(res.Stack[0].Num.Value, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s result: %s", method, res.Stack[0].Num.Value)
+	}
+	return value, nil
+}
@@ -0,0 +1,46 @@
+package tonapi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	tonaddress "github.com/openweb3-io/crosschain/blockchain/ton/address"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// Returns the public key of a wallet, for encrypting comments to it
+func (client *Client) getPublicKey(ctx context.Context, addr xc_types.Address) (ed25519.PublicKey, error) {
+	key, err := client.runGetMethodInt(ctx, addr, "get_public_key")
+	if err != nil {
+		return nil, err
+	}
+	b := key.Bytes()
+	if len(b) > ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key of %s", addr)
+	}
+	pubKey := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(pubKey[ed25519.PublicKeySize-len(b):], b)
+	return pubKey, nil
+}
+
+// Decrypts a comment from the hex encoded message body, if it was sent to or from one of our wallets
+func (client *Client) parseEncryptedComment(rawBody string, sender string) string {
+	bodyBytes, err := hex.DecodeString(rawBody)
+	if err != nil {
+		return ""
+	}
+	body, err := cell.FromBOC(bodyBytes)
+	if err != nil {
+		return ""
+	}
+	senderAddr, err := tonaddress.ParseAddress(xc_types.Address(sender), "")
+	if err != nil {
+		return ""
+	}
+	memo, _ := ton.ParseEncryptedComment(body, senderAddr, client.memoKeys)
+	return memo
+}
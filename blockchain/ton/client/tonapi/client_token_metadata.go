@@ -0,0 +1,33 @@
+package tonapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/blockchain/ton"
+	xcclient "github.com/openweb3-io/crosschain/client"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	_tonapi "github.com/tonkeeper/tonapi-go"
+)
+
+var _ xcclient.TokenMetadataClient = &Client{}
+
+// Read the metadata of a jetton, which tonapi resolves from its content
+func (client *Client) FetchTokenMetadata(ctx context.Context, contract xc_types.ContractAddress) (*xcclient.TokenMetadata, error) {
+	info, err := client.Client.GetJettonInfo(ctx, _tonapi.GetJettonInfoParams{
+		AccountID: string(contract),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch jetton info of %s: %v", contract, err)
+	}
+	metadata := &ton.JettonMetadata{
+		Name:     info.Metadata.Name,
+		Symbol:   info.Metadata.Symbol,
+		Decimals: info.Metadata.Decimals,
+	}
+	decimals, err := metadata.GetDecimals()
+	if err != nil {
+		return nil, err
+	}
+	return xcclient.NewTokenMetadata(contract, metadata.Name, metadata.Symbol, decimals), nil
+}
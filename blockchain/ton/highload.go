@@ -0,0 +1,149 @@
+package ton
+
+import (
+	"context"
+	"fmt"
+
+	tonaddress "github.com/openweb3-io/crosschain/blockchain/ton/address"
+	"github.com/openweb3-io/crosschain/blockchain/ton/tx"
+	"github.com/openweb3-io/crosschain/blockchain/ton/wallet"
+	xc_types "github.com/openweb3-io/crosschain/types"
+	"github.com/pkg/errors"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+)
+
+// The query ID of a highload v3 wallet is 23 bits, made of a 13 bit shift and a 10 bit bit number.
+// The wallet only accepts each ID once within its timeout, so payouts should iterate through them.
+type HighloadQueryID uint32
+
+const (
+	MaxHighloadShift     = 1<<13 - 1
+	MaxHighloadBitNumber = 1<<10 - 2
+	// the last query ID is reserved by the wallet for emergencies
+	MaxHighloadQueryID = HighloadQueryID(MaxHighloadShift<<10 | MaxHighloadBitNumber)
+)
+
+// The most messages a highload v3 wallet can send in a transaction
+const MaxHighloadMessages = 254 * 254
+
+func NewHighloadQueryID(shift uint32, bitNumber uint32) (HighloadQueryID, error) {
+	if shift > MaxHighloadShift {
+		return 0, fmt.Errorf("highload query shift %d exceeds %d", shift, MaxHighloadShift)
+	}
+	if bitNumber > MaxHighloadBitNumber {
+		return 0, fmt.Errorf("highload query bit number %d exceeds %d", bitNumber, MaxHighloadBitNumber)
+	}
+	return HighloadQueryID(shift<<10 | bitNumber), nil
+}
+
+func (id HighloadQueryID) Shift() uint32 {
+	return uint32(id) >> 10
+}
+
+func (id HighloadQueryID) BitNumber() uint32 {
+	return uint32(id) & (1<<10 - 1)
+}
+
+// Next returns the following query ID, wrapping around to 0 after the last.  An ID may be reused once
+// the timeout has passed twice since it was last processed.
+func (id HighloadQueryID) Next() HighloadQueryID {
+	if id >= MaxHighloadQueryID {
+		return 0
+	}
+	if id.BitNumber() >= MaxHighloadBitNumber {
+		return HighloadQueryID((id.Shift() + 1) << 10)
+	}
+	return id + 1
+}
+
+// A recipient of a batch transfer.  The asset is TON when it has no contract.
+type BatchTransferOutput struct {
+	To     xc_types.Address
+	Amount xc_types.BigInt
+	Asset  xc_types.IAsset
+	Memo   string
+}
+
+// NewBatchTransfer pays each output from a highload v3 wallet in a single transaction, using the query ID and
+// timeout of the input.  Jettons are sent from the sender's jetton wallets in the input.
+func (b *TxBuilder) NewBatchTransfer(from xc_types.Address, outputs []*BatchTransferOutput, input xc_types.TxInput) (xc_types.Tx, error) {
+	ctx := context.Background()
+
+	txInput := input.(*TxInput)
+	if !txInput.IsHighload() {
+		return nil, errors.New("batch transfers require a highload wallet timeout")
+	}
+	if len(outputs) == 0 {
+		return nil, errors.New("no outputs to transfer to")
+	}
+	if len(outputs) > MaxHighloadMessages {
+		return nil, fmt.Errorf("cannot send more than %d outputs in a transaction", MaxHighloadMessages)
+	}
+	fromAddr, err := address.ParseAddr(string(from))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid TON address %s", from)
+	}
+
+	messages := []*wallet.Message{}
+	for _, output := range outputs {
+		toAddr, err := address.ParseAddr(string(output.To))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid TON to address: %s", output.To)
+		}
+		toAddr = toAddr.Bounce(false)
+
+		var message *wallet.Message
+		if output.Asset != nil && output.Asset.GetContract() != "" {
+			contract := output.Asset.GetContract()
+			tokenWallet, ok := txInput.TokenWallets[contract]
+			if !ok {
+				return nil, fmt.Errorf("no TON token wallet for %s", contract)
+			}
+			tokenAddr, err := tonaddress.ParseAddress(tokenWallet, "")
+			if err != nil {
+				return nil, fmt.Errorf("invalid TON token address %s: %v", tokenWallet, err)
+			}
+			amountTlb, err := tlb.FromNano(output.Amount.Int(), int(output.Asset.GetDecimals()))
+			if err != nil {
+				return nil, err
+			}
+			message, err = BuildJettonTransfer(
+				uint64(txInput.Timestamp),
+				fromAddr,
+				tokenAddr,
+				toAddr,
+				amountTlb,
+				// as with single transfers, at most 0.05 TON is spent per jetton transfer
+				tlb.MustFromTON("0.05"),
+				output.Memo,
+			)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			message, err = BuildTransfer(toAddr, tlb.FromNanoTON(output.Amount.Int()), output.Memo)
+			if err != nil {
+				return nil, errors.Wrap(err, "BuildTransfer failed")
+			}
+		}
+		messages = append(messages, message)
+	}
+
+	w, err := wallet.FromAddress(nil, fromAddr, wallet.ConfigHighloadV3{
+		MessageTTL: txInput.HighloadTimeout,
+		MessageBuilder: func(ctx context.Context, subWalletId uint32) (uint32, int64, error) {
+			return uint32(txInput.HighloadQueryID), txInput.Timestamp, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cellBuilder, err := w.BuildMessages(ctx, false, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.NewHighloadTx(fromAddr, cellBuilder), nil
+}
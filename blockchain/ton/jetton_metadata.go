@@ -0,0 +1,109 @@
+package ton
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xssnick/tonutils-go/ton/nft"
+)
+
+// TEP-64: jettons without a decimals attribute use the same decimals as TON
+const DefaultJettonDecimals = 9
+
+// Off chain metadata is hosted anywhere, so don't wait on it indefinitely
+var JettonMetadataClient = &http.Client{
+	Timeout: 15 * time.Second,
+}
+
+type JettonMetadata struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals string `json:"decimals"`
+}
+
+// Off chain metadata may have its decimals as a number rather than a string
+func (metadata *JettonMetadata) UnmarshalJSON(data []byte) error {
+	type jettonMetadata JettonMetadata
+	var raw struct {
+		jettonMetadata
+		Decimals json.RawMessage `json:"decimals"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*metadata = JettonMetadata(raw.jettonMetadata)
+	metadata.Decimals = strings.Trim(string(raw.Decimals), `"`)
+	return nil
+}
+
+func (metadata *JettonMetadata) GetDecimals() (int32, error) {
+	if metadata.Decimals == "" {
+		return DefaultJettonDecimals, nil
+	}
+	decimals, err := strconv.ParseInt(metadata.Decimals, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid jetton decimals '%s': %v", metadata.Decimals, err)
+	}
+	return int32(decimals), nil
+}
+
+// ParseJettonContent reads the metadata of a jetton master's content.  Metadata stored off chain
+// is downloaded, and attributes stored on chain take precedence over it.
+func ParseJettonContent(ctx context.Context, content nft.ContentAny) (*JettonMetadata, error) {
+	switch content := content.(type) {
+	case *nft.ContentOnchain:
+		return jettonMetadataOnchain(content, &JettonMetadata{}), nil
+	case *nft.ContentSemichain:
+		metadata, err := FetchJettonMetadata(ctx, content.URI)
+		if err != nil {
+			return nil, err
+		}
+		return jettonMetadataOnchain(&content.ContentOnchain, metadata), nil
+	case *nft.ContentOffchain:
+		return FetchJettonMetadata(ctx, content.URI)
+	default:
+		return nil, fmt.Errorf("unsupported jetton content %T", content)
+	}
+}
+
+func jettonMetadataOnchain(content *nft.ContentOnchain, metadata *JettonMetadata) *JettonMetadata {
+	if name := content.GetAttribute("name"); name != "" {
+		metadata.Name = name
+	}
+	if symbol := content.GetAttribute("symbol"); symbol != "" {
+		metadata.Symbol = symbol
+	}
+	if decimals := content.GetAttribute("decimals"); decimals != "" {
+		metadata.Decimals = decimals
+	}
+	return metadata
+}
+
+// Download off chain jetton metadata, which is json hosted over http or ipfs
+func FetchJettonMetadata(ctx context.Context, uri string) (*JettonMetadata, error) {
+	if strings.HasPrefix(uri, "ipfs://") {
+		uri = "https://ipfs.io/ipfs/" + strings.TrimPrefix(uri, "ipfs://")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := JettonMetadataClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch jetton metadata from %s: %v", uri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch jetton metadata from %s: status %d", uri, resp.StatusCode)
+	}
+	metadata := &JettonMetadata{}
+	if err := json.NewDecoder(resp.Body).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("could not parse jetton metadata from %s: %v", uri, err)
+	}
+	return metadata, nil
+}
@@ -16,6 +16,8 @@ type Tx struct {
 	CellBuilder     *cell.Builder
 	ExternalMessage *tlb.ExternalMessage
 	signatures      []xc_types.TxSignature
+	// Highload wallets expect the signed payload as a reference rather than inline
+	payloadByRef bool
 }
 
 func (tx *Tx) Serialize() ([]byte, error) {
@@ -62,8 +64,13 @@ func (tx *Tx) AddSignatures(sigs ...xc_types.TxSignature) error {
 	}
 
 	tx.signatures = sigs
-	msg := cell.BeginCell().MustStoreSlice(sigs[0], 512).MustStoreBuilder(tx.CellBuilder).EndCell()
-	tx.ExternalMessage.Body = msg
+	msg := cell.BeginCell().MustStoreSlice(sigs[0], 512)
+	if tx.payloadByRef {
+		msg = msg.MustStoreRef(tx.CellBuilder.EndCell())
+	} else {
+		msg = msg.MustStoreBuilder(tx.CellBuilder)
+	}
+	tx.ExternalMessage.Body = msg.EndCell()
 	return nil
 }
 
@@ -86,6 +93,13 @@ func NewTx(fromAddr *address.Address, cellBuilder *cell.Builder, stateInitMaybe
 	}
 }
 
+// A transaction from a highload v3 wallet, which must already be deployed
+func NewHighloadTx(fromAddr *address.Address, cellBuilder *cell.Builder) *Tx {
+	tx := NewTx(fromAddr, cellBuilder, nil)
+	tx.payloadByRef = true
+	return tx
+}
+
 // Normal to hex as it doesn't have any special characters
 func Normalize(txhash string) string {
 	txhash = strings.TrimPrefix(txhash, "0x")
@@ -1,14 +1,19 @@
 package tx_test
 
 import (
+	"crypto/ed25519"
 	"encoding/hex"
 	"testing"
 
 	"github.com/openweb3-io/crosschain/blockchain/ton"
+	tontx "github.com/openweb3-io/crosschain/blockchain/ton/tx"
+	"github.com/openweb3-io/crosschain/blockchain/ton/wallet"
 	xcbuilder "github.com/openweb3-io/crosschain/builder"
 	"github.com/openweb3-io/crosschain/types"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
 )
 
 func TestNativeTx(t *testing.T) {
@@ -113,3 +118,151 @@ func TestTokenTx(t *testing.T) {
 		hex.EncodeToString(bz))
 
 }
+
+func TestHighloadBatchTx(t *testing.T) {
+	builder, err := ton.NewTxBuilder(&xc_types.ChainConfig{Chain: xc_types.TON, Decimals: 9})
+	require.NoError(t, err)
+
+	from := xc_types.Address("EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2")
+	to := xc_types.Address("0QChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc48Jm")
+	contractAddress := xc_types.ContractAddress("kQAiboDEv_qRrcEdrYdwbVLNOXBHwShFbtKGbQVJ2OKxY_Di")
+	outputs := []*ton.BatchTransferOutput{
+		{To: to, Amount: xc_types.NewBigIntFromUint64(10)},
+		{To: to, Amount: xc_types.NewBigIntFromUint64(20), Memo: "withdrawal 2"},
+		{To: to, Amount: xc_types.NewBigIntFromUint64(30), Asset: &xc_types.TokenAssetConfig{
+			Chain:    xc_types.TON,
+			Decimals: 6,
+			Contract: contractAddress,
+		}},
+	}
+	queryID, err := ton.NewHighloadQueryID(3, 17)
+	require.NoError(t, err)
+	input := &ton.TxInput{
+		Timestamp:       1700000000,
+		HighloadQueryID: queryID,
+		HighloadTimeout: 3600,
+	}
+
+	// the sender's jetton wallet is needed
+	_, err = builder.NewBatchTransfer(from, outputs, input)
+	require.ErrorContains(t, err, "no TON token wallet")
+	input.TokenWallets = map[xc_types.ContractAddress]xc_types.Address{
+		contractAddress: "EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2",
+	}
+	tx, err := builder.NewBatchTransfer(from, outputs, input)
+	require.NoError(t, err)
+
+	// subwallet, messages, mode, query ID, created at, and timeout
+	payload := tx.(*tontx.Tx).CellBuilder.EndCell().BeginParse()
+	require.EqualValues(t, wallet.DefaultSubwallet, payload.MustLoadUInt(32))
+	packed := payload.MustLoadRef()
+	require.EqualValues(t, wallet.PayGasSeparately+wallet.IgnoreErrors, payload.MustLoadUInt(8))
+	require.EqualValues(t, 3<<10|17, payload.MustLoadUInt(23))
+	require.EqualValues(t, 1700000000, payload.MustLoadUInt(64))
+	require.EqualValues(t, 3600, payload.MustLoadUInt(22))
+
+	// the messages are packed into an internal message to the wallet itself
+	var internal tlb.InternalMessage
+	require.NoError(t, tlb.LoadFromCell(&internal, packed))
+	require.Equal(t, string(from), internal.DstAddr.String())
+	body := internal.Body.BeginParse()
+	require.EqualValues(t, 0xae42e5a4, body.MustLoadUInt(32))
+	require.EqualValues(t, 3<<10|17, body.MustLoadUInt(64))
+	actions := 0
+	for list := body.MustLoadRef(); list.RefsNum() > 0; list = list.MustLoadRef() {
+		actions++
+	}
+	require.Equal(t, 3, actions)
+
+	// the signed payload is referenced by the external message
+	hashes, err := tx.Sighashes()
+	require.NoError(t, err)
+	require.Equal(t, []xc_types.TxDataToSign{tx.(*tontx.Tx).CellBuilder.EndCell().Hash()}, hashes)
+	require.NoError(t, tx.AddSignatures(xc_types.TxSignature(make([]byte, 64))))
+	signed := tx.(*tontx.Tx).ExternalMessage.Body
+	require.EqualValues(t, 512, signed.BitsSize())
+	require.Equal(t, []byte(hashes[0]), signed.MustPeekRef(0).Hash())
+	bz, err := tx.Serialize()
+	require.NoError(t, err)
+	require.NotEmpty(t, bz)
+
+	// a regular wallet input can't be used
+	_, err = builder.NewBatchTransfer(from, outputs, &ton.TxInput{})
+	require.ErrorContains(t, err, "highload")
+}
+
+func TestHighloadQueryID(t *testing.T) {
+	id, err := ton.NewHighloadQueryID(5, ton.MaxHighloadBitNumber)
+	require.NoError(t, err)
+	require.EqualValues(t, 6<<10, id.Next())
+	require.EqualValues(t, 6, id.Next().Shift())
+	require.EqualValues(t, 0, id.Next().BitNumber())
+	require.EqualValues(t, 0, ton.MaxHighloadQueryID.Next())
+
+	_, err = ton.NewHighloadQueryID(ton.MaxHighloadShift+1, 0)
+	require.Error(t, err)
+	_, err = ton.NewHighloadQueryID(0, ton.MaxHighloadBitNumber+1)
+	require.Error(t, err)
+}
+
+func TestHighloadTxInputConflicts(t *testing.T) {
+	input := &ton.TxInput{HighloadQueryID: 7, HighloadTimeout: 3600, Timestamp: 1700000000, Seq: 1}
+	sameQuery := &ton.TxInput{HighloadQueryID: 7, HighloadTimeout: 3600, Timestamp: 1700000000, Seq: 2}
+	laterQuery := &ton.TxInput{HighloadQueryID: 7, HighloadTimeout: 3600, Timestamp: 1700000100}
+	otherQuery := &ton.TxInput{HighloadQueryID: 8, HighloadTimeout: 3600, Timestamp: 1700000000, Seq: 1}
+	regular := &ton.TxInput{Seq: 1}
+
+	// the seqno isn't used by highload wallets
+	require.False(t, input.IndependentOf(sameQuery))
+	require.True(t, input.IndependentOf(otherQuery))
+	require.False(t, input.IndependentOf(regular))
+
+	require.True(t, input.SafeFromDoubleSend(sameQuery))
+	require.False(t, input.SafeFromDoubleSend(otherQuery))
+	require.False(t, input.SafeFromDoubleSend(laterQuery))
+	require.False(t, input.SafeFromDoubleSend(regular))
+	require.False(t, regular.SafeFromDoubleSend(input))
+	require.True(t, regular.SafeFromDoubleSend(&ton.TxInput{Seq: 1}))
+}
+
+func TestEncryptedMemoTx(t *testing.T) {
+	builder, err := ton.NewTxBuilder(&xc_types.ChainConfig{Chain: xc_types.TON, Decimals: 9})
+	require.NoError(t, err)
+
+	senderKey := ed25519.NewKeyFromSeed(make([]byte, 32))
+	recipientKey := ed25519.NewKeyFromSeed([]byte("0123456789abcdef0123456789abcdef"))
+	otherKey := ed25519.NewKeyFromSeed([]byte("fedcba9876543210fedcba9876543210"))
+
+	from := xc_types.Address("EQAjflEZ_6KgKMxPlcnKN1ZoUvHdTT6hVwTW95EGVQfeSha2")
+	to := xc_types.Address("0QChotyiAtSPqs0BbPD851Mys9_LdMVM7N-atsFYvUMc48Jm")
+	args, err := xcbuilder.NewTransferArgs(from, to, xc_types.NewBigIntFromUint64(10),
+		xcbuilder.WithEncryptedMemo("deposit 42", ton.NewLocalSigner(senderKey)),
+	)
+	require.NoError(t, err)
+
+	// the recipient's public key is needed
+	_, err = builder.NewTransfer(args, &ton.TxInput{})
+	require.ErrorContains(t, err, "public key")
+
+	input := &ton.TxInput{RecipientPublicKey: recipientKey.Public().(ed25519.PublicKey)}
+	tx, err := builder.NewTransfer(args, input)
+	require.NoError(t, err)
+
+	// subwallet, valid until, seqno, op, and the mode of the message
+	payload := tx.(*tontx.Tx).CellBuilder.EndCell().BeginParse()
+	payload.MustLoadUInt(32 + 32 + 32 + 8 + 8)
+	var internal tlb.InternalMessage
+	require.NoError(t, tlb.LoadFromCell(&internal, payload.MustLoadRef()))
+	_, ok := ton.ParseComment(internal.Body)
+	require.False(t, ok)
+
+	// either side can decrypt, however the sender address is formatted
+	sender := address.MustParseAddr(string(from)).Bounce(false)
+	for _, key := range []ed25519.PrivateKey{recipientKey, senderKey} {
+		memo, ok := ton.ParseEncryptedComment(internal.Body, sender, []ed25519.PrivateKey{otherKey, key})
+		require.True(t, ok)
+		require.Equal(t, "deposit 42", memo)
+	}
+	_, ok = ton.ParseEncryptedComment(internal.Body, sender, []ed25519.PrivateKey{otherKey})
+	require.False(t, ok)
+}
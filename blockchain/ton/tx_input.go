@@ -22,6 +22,16 @@ type TxInput struct {
 	TokenWallet     xc_types.Address
 	EstimatedMaxFee xc_types.BigInt
 	TonBalance      xc_types.BigInt
+	// The recipient's wallet public key, for encrypting the memo
+	RecipientPublicKey []byte `json:"recipient_public_key,omitempty"`
+
+	// Highload v3 wallets replace the seqno with a query ID, which the wallet rejects if it has
+	// already processed it within the timeout (seconds).  Timestamp is the query's creation time.
+	HighloadQueryID HighloadQueryID `json:"highload_query_id,omitempty"`
+	HighloadTimeout uint32          `json:"highload_timeout,omitempty"`
+	// The sender's jetton wallet for each jetton in a batch transfer.  Batch transfers send a fixed
+	// amount of TON with each jetton transfer, so they don't use EstimatedMaxFee.
+	TokenWallets map[xc_types.ContractAddress]xc_types.Address `json:"token_wallets,omitempty"`
 }
 
 func NewTxInput() *TxInput {
@@ -43,10 +53,19 @@ func (input *TxInput) SetGasFeePriority(other xc_types.GasFeePriority) error {
 	return nil
 }
 
+// Whether the input is for a highload v3 wallet
+func (input *TxInput) IsHighload() bool {
+	return input.HighloadTimeout > 0
+}
+
 func (input *TxInput) IndependentOf(other xc_types.TxInput) (independent bool) {
-	// different sequence means independence
-	if evmOther, ok := other.(*TxInput); ok {
-		return evmOther.Seq != input.Seq
+	if tonOther, ok := other.(*TxInput); ok {
+		if input.IsHighload() || tonOther.IsHighload() {
+			// different query ID means independence
+			return input.IsHighload() && tonOther.IsHighload() && tonOther.HighloadQueryID != input.HighloadQueryID
+		}
+		// different sequence means independence
+		return tonOther.Seq != input.Seq
 	}
 	return
 }
@@ -56,9 +75,21 @@ func (input *TxInput) SafeFromDoubleSend(others ...xc_types.TxInput) (safe bool)
 	}
 	// all same sequence means no double send
 	for _, other := range others {
+		tonOther, ok := other.(*TxInput)
+		if !ok {
+			return false
+		}
+		if input.IsHighload() != tonOther.IsHighload() {
+			return false
+		}
 		if input.IndependentOf(other) {
 			return false
 		}
+		// A highload wallet forgets a query ID some time after its timeout, so a query created later
+		// with the same ID could still be processed again.
+		if input.IsHighload() && tonOther.Timestamp != input.Timestamp {
+			return false
+		}
 	}
 	// sequence all same - we're safe
 	return true
@@ -24,6 +24,7 @@ import (
 )
 
 var _ xcclient.IClient = &Client{}
+var _ xcclient.TokenMetadataClient = &Client{}
 
 const TRANSFER_EVENT_HASH_HEX = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
 const TX_TIMEOUT = 2 * time.Hour
@@ -89,6 +90,18 @@ func (a *Client) FetchBalance(ctx context.Context, address xc_types.Address) (*x
 	return &balance, nil
 }
 
+// Read the TRC-20 metadata of a token
+func (a *Client) FetchTokenMetadata(ctx context.Context, contract xc_types.ContractAddress) (*xcclient.TokenMetadata, error) {
+	decimals, err := a.client.TRC20GetDecimals(string(contract))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch decimals of token %s: %v", contract, err)
+	}
+	// name and symbol are optional
+	name, _ := a.client.TRC20GetName(string(contract))
+	symbol, _ := a.client.TRC20GetSymbol(string(contract))
+	return xcclient.NewTokenMetadata(contract, name, symbol, int32(decimals.Int64())), nil
+}
+
 func (a *Client) FetchBalanceForAsset(ctx context.Context, address xc_types.Address, contractAddress xc_types.ContractAddress) (*xc_types.BigInt, error) {
 	balance, err := a.client.TRC20ContractBalance(string(address), string(contractAddress))
 	if err != nil {
@@ -0,0 +1,156 @@
+package cctp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const IrisMainnetURL = "https://iris-api.circle.com"
+const IrisTestnetURL = "https://iris-api-sandbox.circle.com"
+
+type AttestationStatus string
+
+const (
+	AttestationPending  AttestationStatus = "pending_confirmations"
+	AttestationComplete AttestationStatus = "complete"
+)
+
+type Attestation struct {
+	Status      AttestationStatus `json:"status"`
+	Attestation hexutil.Bytes     `json:"attestation,omitempty"`
+}
+
+func (att *Attestation) IsComplete() bool {
+	return att.Status == AttestationComplete && len(att.Attestation) > 0
+}
+
+// Attester looks up the attestation needed to receive a message on the destination domain.  Circle's
+// attestation service is used normally, but a local stand-in can be swapped in for devnets and testing.
+type Attester interface {
+	FetchAttestation(ctx context.Context, msg *Message) (*Attestation, error)
+}
+
+// Client for Circle's attestation service ("iris")
+type IrisClient struct {
+	URL  string
+	Http *http.Client
+}
+
+var _ Attester = &IrisClient{}
+
+func NewIrisClient(url string) *IrisClient {
+	return &IrisClient{
+		URL:  strings.TrimSuffix(url, "/"),
+		Http: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func NewIrisClientForNetwork(network string) *IrisClient {
+	if network == "mainnet" {
+		return NewIrisClient(IrisMainnetURL)
+	}
+	return NewIrisClient(IrisTestnetURL)
+}
+
+func (client *IrisClient) FetchAttestation(ctx context.Context, msg *Message) (*Attestation, error) {
+	url := fmt.Sprintf("%s/v1/attestations/%s", client.URL, msg.HashHex())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch cctp attestation: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		// the service hasn't observed the message yet
+		return &Attestation{Status: AttestationPending}, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch cctp attestation: status %d", res.StatusCode)
+	}
+	var response struct {
+		Status      AttestationStatus `json:"status"`
+		Attestation string            `json:"attestation"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid cctp attestation response: %v", err)
+	}
+	attestation := &Attestation{Status: response.Status}
+	if response.Status == AttestationComplete {
+		attestation.Attestation, err = hexutil.Decode(response.Attestation)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cctp attestation '%s': %v", response.Attestation, err)
+		}
+	}
+	return attestation, nil
+}
+
+// LocalAttester attests to messages with its own keys, for use against a message transmitter
+// that's been configured with them as attesters.
+type LocalAttester struct {
+	Keys []*ecdsa.PrivateKey
+}
+
+var _ Attester = &LocalAttester{}
+
+func NewLocalAttester(keys ...*ecdsa.PrivateKey) *LocalAttester {
+	return &LocalAttester{keys}
+}
+
+func (attester *LocalAttester) FetchAttestation(ctx context.Context, msg *Message) (*Attestation, error) {
+	return attester.Attest(msg)
+}
+
+// Attest concatenates a signature over the message hash from each key.  The message transmitter
+// requires the signatures to be ordered by signer address.
+func (attester *LocalAttester) Attest(msg *Message) (*Attestation, error) {
+	keys := append([]*ecdsa.PrivateKey{}, attester.Keys...)
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && crypto.PubkeyToAddress(keys[j].PublicKey).Cmp(crypto.PubkeyToAddress(keys[j-1].PublicKey)) < 0; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+	hash := msg.Hash()
+	attestation := []byte{}
+	for _, key := range keys {
+		sig, err := crypto.Sign(hash, key)
+		if err != nil {
+			return nil, err
+		}
+		// the message transmitter expects ethereum style recovery ids
+		sig[64] += 27
+		attestation = append(attestation, sig...)
+	}
+	return &Attestation{AttestationComplete, attestation}, nil
+}
+
+// RecoverAttesters returns the address of each signer of an attestation
+func RecoverAttesters(msg *Message, attestation []byte) ([]string, error) {
+	if len(attestation) == 0 || len(attestation)%65 != 0 {
+		return nil, fmt.Errorf("invalid cctp attestation length %d", len(attestation))
+	}
+	hash := msg.Hash()
+	signers := []string{}
+	for i := 0; i < len(attestation); i += 65 {
+		sig := append([]byte{}, attestation[i:i+65]...)
+		if sig[64] >= 27 {
+			sig[64] -= 27
+		}
+		pub, err := crypto.SigToPub(hash, sig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cctp attestation signature: %v", err)
+		}
+		signers = append(signers, crypto.PubkeyToAddress(*pub).String())
+	}
+	return signers, nil
+}
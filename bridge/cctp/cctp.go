@@ -0,0 +1,223 @@
+package cctp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Domain is Circle's identifier for a chain that CCTP is deployed on
+type Domain uint32
+
+const (
+	DomainEthereum  Domain = 0
+	DomainAvalanche Domain = 1
+	DomainOptimism  Domain = 2
+	DomainArbitrum  Domain = 3
+	DomainNoble     Domain = 4
+	DomainSolana    Domain = 5
+	DomainBase      Domain = 6
+	DomainPolygon   Domain = 7
+)
+
+// CCTP domains of EVM chains, by chain id.  Testnets share the domain of their mainnet.
+var EvmDomains = map[int64]Domain{
+	1:        DomainEthereum,
+	11155111: DomainEthereum,
+	43114:    DomainAvalanche,
+	43113:    DomainAvalanche,
+	10:       DomainOptimism,
+	11155420: DomainOptimism,
+	42161:    DomainArbitrum,
+	421614:   DomainArbitrum,
+	8453:     DomainBase,
+	84532:    DomainBase,
+	137:      DomainPolygon,
+	80002:    DomainPolygon,
+}
+
+func DomainForChain(chain *xc.ChainConfig) (Domain, error) {
+	switch chain.Blockchain {
+	case xc.BlockchainSolana:
+		return DomainSolana, nil
+	case xc.BlockchainEVM:
+		if domain, ok := EvmDomains[chain.ChainID]; ok {
+			return domain, nil
+		}
+		return 0, fmt.Errorf("cctp is not supported on %s (chain_id %d)", chain.Chain, chain.ChainID)
+	}
+	return 0, fmt.Errorf("cctp is not supported on %s", chain.Chain)
+}
+
+func (domain Domain) IsSolana() bool {
+	return domain == DomainSolana
+}
+
+// The only message version that's supported
+const MessageVersion = 0
+
+// Max number of nonces tracked by a single used-nonces account on solana
+const SolanaMaxNonces = 6400
+
+const messageHeaderLen = 116
+const burnMessageLen = 132
+
+// Message is a CCTP message, which is emitted on the source domain and received on the destination
+type Message struct {
+	Version           uint32
+	SourceDomain      Domain
+	DestinationDomain Domain
+	Nonce             uint64
+	Sender            [32]byte
+	Recipient         [32]byte
+	DestinationCaller [32]byte
+	Body              []byte
+}
+
+func ParseMessage(bz []byte) (*Message, error) {
+	if len(bz) < messageHeaderLen {
+		return nil, fmt.Errorf("invalid cctp message length %d", len(bz))
+	}
+	msg := &Message{
+		Version:           binary.BigEndian.Uint32(bz[0:4]),
+		SourceDomain:      Domain(binary.BigEndian.Uint32(bz[4:8])),
+		DestinationDomain: Domain(binary.BigEndian.Uint32(bz[8:12])),
+		Nonce:             binary.BigEndian.Uint64(bz[12:20]),
+		Body:              append([]byte{}, bz[messageHeaderLen:]...),
+	}
+	copy(msg.Sender[:], bz[20:52])
+	copy(msg.Recipient[:], bz[52:84])
+	copy(msg.DestinationCaller[:], bz[84:116])
+	if msg.Version != MessageVersion {
+		return nil, fmt.Errorf("unsupported cctp message version %d", msg.Version)
+	}
+	return msg, nil
+}
+
+func (msg *Message) Bytes() []byte {
+	bz := make([]byte, messageHeaderLen, messageHeaderLen+len(msg.Body))
+	binary.BigEndian.PutUint32(bz[0:4], msg.Version)
+	binary.BigEndian.PutUint32(bz[4:8], uint32(msg.SourceDomain))
+	binary.BigEndian.PutUint32(bz[8:12], uint32(msg.DestinationDomain))
+	binary.BigEndian.PutUint64(bz[12:20], msg.Nonce)
+	copy(bz[20:52], msg.Sender[:])
+	copy(bz[52:84], msg.Recipient[:])
+	copy(bz[84:116], msg.DestinationCaller[:])
+	return append(bz, msg.Body...)
+}
+
+// Hash is what the attestation is looked up by and signed over
+func (msg *Message) Hash() []byte {
+	return crypto.Keccak256(msg.Bytes())
+}
+
+func (msg *Message) HashHex() string {
+	return "0x" + hex.EncodeToString(msg.Hash())
+}
+
+// The key that the message transmitter on EVM chains records the nonce as used under
+func (msg *Message) SourceAndNonce() [32]byte {
+	bz := make([]byte, 12)
+	binary.BigEndian.PutUint32(bz[0:4], uint32(msg.SourceDomain))
+	binary.BigEndian.PutUint64(bz[4:12], msg.Nonce)
+	var key [32]byte
+	copy(key[:], crypto.Keccak256(bz))
+	return key
+}
+
+// The first nonce of the used-nonces account on solana that tracks this message
+func (msg *Message) SolanaFirstNonce() uint64 {
+	return ((msg.Nonce-1)/SolanaMaxNonces)*SolanaMaxNonces + 1
+}
+
+func (msg *Message) BurnMessage() (*BurnMessage, error) {
+	return ParseBurnMessage(msg.Body)
+}
+
+// BurnMessage is the body of a message sent by the token messenger to mint on the destination
+type BurnMessage struct {
+	Version       uint32
+	BurnToken     [32]byte
+	MintRecipient [32]byte
+	Amount        *big.Int
+	MessageSender [32]byte
+}
+
+func ParseBurnMessage(bz []byte) (*BurnMessage, error) {
+	if len(bz) != burnMessageLen {
+		return nil, fmt.Errorf("invalid cctp burn message length %d", len(bz))
+	}
+	burn := &BurnMessage{
+		Version: binary.BigEndian.Uint32(bz[0:4]),
+		Amount:  new(big.Int).SetBytes(bz[68:100]),
+	}
+	copy(burn.BurnToken[:], bz[4:36])
+	copy(burn.MintRecipient[:], bz[36:68])
+	copy(burn.MessageSender[:], bz[100:132])
+	return burn, nil
+}
+
+func (burn *BurnMessage) Bytes() []byte {
+	bz := make([]byte, burnMessageLen)
+	binary.BigEndian.PutUint32(bz[0:4], burn.Version)
+	copy(bz[4:36], burn.BurnToken[:])
+	copy(bz[36:68], burn.MintRecipient[:])
+	burn.Amount.FillBytes(bz[68:100])
+	copy(bz[100:132], burn.MessageSender[:])
+	return bz
+}
+
+// AddressBytes32 converts an address on the domain to the 32 bytes CCTP uses for addresses
+func AddressBytes32(domain Domain, addr xc.Address) ([32]byte, error) {
+	var bz [32]byte
+	if domain.IsSolana() {
+		pubkey, err := solana.PublicKeyFromBase58(string(addr))
+		if err != nil {
+			return bz, fmt.Errorf("invalid solana address '%s': %v", addr, err)
+		}
+		copy(bz[:], pubkey[:])
+		return bz, nil
+	}
+	if !common.IsHexAddress(string(addr)) {
+		return bz, fmt.Errorf("invalid evm address '%s'", addr)
+	}
+	copy(bz[12:], common.HexToAddress(string(addr)).Bytes())
+	return bz, nil
+}
+
+// AddressFromBytes32 converts 32 bytes from a CCTP message back to an address on the domain
+func AddressFromBytes32(domain Domain, bz [32]byte) xc.Address {
+	if domain.IsSolana() {
+		return xc.Address(solana.PublicKeyFromBytes(bz[:]).String())
+	}
+	return xc.Address(common.BytesToAddress(bz[12:]).String())
+}
+
+// MintRecipient is where the destination domain mints to.  This is the recipient itself on EVM chains,
+// but on solana it's the recipient's associated token account for the destination token.
+func MintRecipient(domain Domain, recipient xc.Address, destinationToken xc.ContractAddress) ([32]byte, error) {
+	if !domain.IsSolana() {
+		return AddressBytes32(domain, recipient)
+	}
+	var bz [32]byte
+	owner, err := solana.PublicKeyFromBase58(string(recipient))
+	if err != nil {
+		return bz, fmt.Errorf("invalid solana address '%s': %v", recipient, err)
+	}
+	mint, err := solana.PublicKeyFromBase58(string(destinationToken))
+	if err != nil {
+		return bz, fmt.Errorf("invalid solana token '%s': %v", destinationToken, err)
+	}
+	ata, _, err := solana.FindAssociatedTokenAddress(owner, mint)
+	if err != nil {
+		return bz, err
+	}
+	copy(bz[:], ata[:])
+	return bz, nil
+}
@@ -0,0 +1,204 @@
+package cctp_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+	"github.com/openweb3-io/crosschain/bridge/cctp"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newBurnMessage(t *testing.T, nonce uint64) *cctp.Message {
+	sender, err := cctp.AddressBytes32(cctp.DomainEthereum, "0x273b437645Ba723299d07B1BdFFcf508bE64771f")
+	require.NoError(t, err)
+	burnToken, err := cctp.AddressBytes32(cctp.DomainEthereum, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	require.NoError(t, err)
+	mintRecipient, err := cctp.MintRecipient(cctp.DomainSolana, "Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb", "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	require.NoError(t, err)
+	burn := &cctp.BurnMessage{
+		BurnToken:     burnToken,
+		MintRecipient: mintRecipient,
+		Amount:        big.NewInt(2_500_000),
+		MessageSender: sender,
+	}
+	return &cctp.Message{
+		SourceDomain:      cctp.DomainEthereum,
+		DestinationDomain: cctp.DomainSolana,
+		Nonce:             nonce,
+		Sender:            sender,
+		Recipient:         sender,
+		Body:              burn.Bytes(),
+	}
+}
+
+func TestMessage(t *testing.T) {
+	require := require.New(t)
+	msg := newBurnMessage(t, 6401)
+
+	bz := msg.Bytes()
+	require.Len(bz, 116+132)
+	parsed, err := cctp.ParseMessage(bz)
+	require.NoError(err)
+	require.Equal(msg, parsed)
+	require.Equal(crypto.Keccak256(bz), parsed.Hash())
+	require.EqualValues(6401, parsed.SolanaFirstNonce())
+
+	burn, err := parsed.BurnMessage()
+	require.NoError(err)
+	require.EqualValues(2_500_000, burn.Amount.Int64())
+	require.EqualValues("0x273b437645Ba723299d07B1BdFFcf508bE64771f", cctp.AddressFromBytes32(cctp.DomainEthereum, burn.MessageSender))
+
+	// solana mints to the recipient's token account
+	ata, _, err := solana.FindAssociatedTokenAddress(
+		solana.MustPublicKeyFromBase58("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb"),
+		solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"),
+	)
+	require.NoError(err)
+	require.EqualValues(ata.String(), cctp.AddressFromBytes32(cctp.DomainSolana, burn.MintRecipient))
+
+	// evm marks the nonce used under keccak(source domain, nonce)
+	key := parsed.SourceAndNonce()
+	require.Equal(crypto.Keccak256([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x19, 0x01}), key[:])
+
+	_, err = cctp.ParseMessage(bz[:100])
+	require.ErrorContains(err, "invalid cctp message length")
+	_, err = cctp.AddressBytes32(cctp.DomainEthereum, "not-an-address")
+	require.Error(err)
+}
+
+func TestAttestation(t *testing.T) {
+	require := require.New(t)
+	msg := newBurnMessage(t, 1)
+
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	attester := cctp.NewLocalAttester(key1, key2)
+	attestation, err := attester.Attest(msg)
+	require.NoError(err)
+	require.True(attestation.IsComplete())
+	require.Len(attestation.Attestation, 130)
+	signers, err := cctp.RecoverAttesters(msg, attestation.Attestation)
+	require.NoError(err)
+	require.Len(signers, 2)
+	require.ElementsMatch([]string{crypto.PubkeyToAddress(key1.PublicKey).String(), crypto.PubkeyToAddress(key2.PublicKey).String()}, signers)
+	// ordered by signer
+	require.Negative(common.HexToAddress(signers[0]).Cmp(common.HexToAddress(signers[1])))
+
+	responses := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal("/v1/attestations/"+msg.HashHex(), r.URL.Path)
+		responses++
+		switch responses {
+		case 1:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Message hash not found"}`)
+		case 2:
+			fmt.Fprint(w, `{"attestation":"PENDING","status":"pending_confirmations"}`)
+		default:
+			fmt.Fprint(w, `{"attestation":"0x0102","status":"complete"}`)
+		}
+	}))
+	defer server.Close()
+	iris := cctp.NewIrisClient(server.URL + "/")
+	for i := 0; i < 2; i++ {
+		attestation, err = iris.FetchAttestation(context.Background(), msg)
+		require.NoError(err)
+		require.False(attestation.IsComplete())
+	}
+	attestation, err = iris.FetchAttestation(context.Background(), msg)
+	require.NoError(err)
+	require.True(attestation.IsComplete())
+	require.EqualValues([]byte{1, 2}, attestation.Attestation)
+}
+
+type mockEndpoint struct {
+	txs      map[xc.TxHash]*xc.LegacyTxInfo
+	received bool
+}
+
+func (endpoint *mockEndpoint) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (*xc.LegacyTxInfo, error) {
+	info, ok := endpoint.txs[txHash]
+	if !ok {
+		return nil, fmt.Errorf("tx %s not found", txHash)
+	}
+	return info, nil
+}
+
+func (endpoint *mockEndpoint) IsCctpMessageReceived(ctx context.Context, msg *cctp.Message) (bool, error) {
+	return endpoint.received, nil
+}
+
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	msg := newBurnMessage(t, 77)
+	key, _ := crypto.GenerateKey()
+
+	burnTx := &xc.LegacyTxInfo{}
+	source := &mockEndpoint{txs: map[xc.TxHash]*xc.LegacyTxInfo{"0xburn": burnTx}}
+	destination := &mockEndpoint{txs: map[xc.TxHash]*xc.LegacyTxInfo{}}
+	tracker := cctp.NewTracker(cctp.NewLocalAttester(key), map[cctp.Domain]cctp.Endpoint{
+		cctp.DomainEthereum: source,
+		cctp.DomainSolana:   destination,
+	})
+	tracker.MinConfirmations = 2
+	transfer := cctp.NewTransfer(xc.ETH, cctp.DomainEthereum, "0xburn", xc.SOL, cctp.DomainSolana)
+
+	// pending
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(cctp.StatusBurning, transfer.Status)
+	_, err := transfer.GetMessage()
+	require.Error(err)
+
+	// mined but not confirmed enough
+	burnTx.BlockIndex = 100
+	burnTx.Confirmations = 1
+	burnTx.AddMessage(xc.CrossChainProtocolCctp, "0x0a992d191DEeC32aFe36203Ad87D7d289a738F81", msg.Bytes())
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(cctp.StatusBurning, transfer.Status)
+	require.Error(transfer.SetReceiveTx("early"))
+
+	// the local attester attests immediately
+	burnTx.Confirmations = 2
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(cctp.StatusAttested, transfer.Status)
+	require.EqualValues(77, transfer.Nonce)
+	require.Equal("2500000", transfer.Amount.String())
+	require.EqualValues("0x273b437645Ba723299d07B1BdFFcf508bE64771f", transfer.Sender)
+	require.Equal(msg.HashHex(), transfer.MessageHash)
+	signers, err := cctp.RecoverAttesters(msg, transfer.Attestation)
+	require.NoError(err)
+	require.Equal([]string{crypto.PubkeyToAddress(key.PublicKey).String()}, signers)
+
+	// a failed receive can be retried
+	destination.txs["receive1"] = &xc.LegacyTxInfo{BlockIndex: 5, Status: xc.TxStatusFailure, Error: "out of gas"}
+	require.NoError(transfer.SetReceiveTx("receive1"))
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(cctp.StatusAttested, transfer.Status)
+	require.Contains(transfer.Error, "out of gas")
+
+	destination.txs["receive2"] = &xc.LegacyTxInfo{}
+	require.NoError(transfer.SetReceiveTx("receive2"))
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(cctp.StatusReceiving, transfer.Status)
+	destination.txs["receive2"].BlockIndex = 6
+	destination.received = true
+	require.NoError(tracker.Wait(ctx, transfer, cctp.StatusComplete, 0))
+	require.Equal(cctp.StatusComplete, transfer.Status)
+	require.EqualValues("receive2", transfer.Destination.TxHash)
+
+	// a burn without a message to the destination fails
+	source.txs["0xother"] = &xc.LegacyTxInfo{BlockIndex: 100, Confirmations: 10}
+	other := cctp.NewTransfer(xc.ETH, cctp.DomainEthereum, "0xother", xc.SOL, cctp.DomainSolana)
+	require.NoError(tracker.Update(ctx, other))
+	require.Equal(cctp.StatusFailed, other.Status)
+	require.Contains(other.Error, "no cctp burn")
+}
@@ -0,0 +1,283 @@
+package cctp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3-io/crosschain/bridge/tracking"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+type Status string
+
+const (
+	// The burn has been submitted on the source domain
+	StatusBurning Status = "burning"
+	// The burn is confirmed and the message is waiting on an attestation
+	StatusAttesting Status = "attesting"
+	// The message is attested and can be received on the destination domain
+	StatusAttested Status = "attested"
+	// The receive has been submitted on the destination domain
+	StatusReceiving Status = "receiving"
+	StatusComplete  Status = "complete"
+	StatusFailed    Status = "failed"
+)
+
+var lifecycle = &tracking.Lifecycle[Status]{
+	Order: map[Status]int{
+		StatusBurning:   0,
+		StatusAttesting: 1,
+		StatusAttested:  2,
+		StatusReceiving: 3,
+		StatusComplete:  4,
+		StatusFailed:    4,
+	},
+	Complete: StatusComplete,
+	Failed:   StatusFailed,
+}
+
+// Reached reports if a transfer in this status has made it to the other status (or finished)
+func (status Status) Reached(other Status) bool {
+	return lifecycle.Reached(status, other)
+}
+
+func (status Status) IsFinal() bool {
+	return lifecycle.IsFinal(status)
+}
+
+// Leg is the transaction on one side of a transfer
+type Leg struct {
+	Chain         xc.NativeAsset `json:"chain"`
+	Domain        Domain         `json:"domain"`
+	TxHash        xc.TxHash      `json:"tx_hash,omitempty"`
+	Confirmations int64          `json:"confirmations,omitempty"`
+}
+
+// Transfer tracks a USDC transfer across both legs: the burn on the source domain, and
+// the receive (mint) on the destination domain.  It's serializable so it can be persisted between updates.
+type Transfer struct {
+	Status      Status `json:"status"`
+	Source      Leg    `json:"source"`
+	Destination Leg    `json:"destination"`
+
+	// Set once the burn message is found
+	Nonce         uint64        `json:"nonce,omitempty"`
+	Amount        xc.BigInt     `json:"amount"`
+	Sender        xc.Address    `json:"sender,omitempty"`
+	MintRecipient xc.Address    `json:"mint_recipient,omitempty"`
+	Message       hexutil.Bytes `json:"message,omitempty"`
+	MessageHash   string        `json:"message_hash,omitempty"`
+
+	Attestation hexutil.Bytes `json:"attestation,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+func NewTransfer(sourceChain xc.NativeAsset, sourceDomain Domain, burnTxHash xc.TxHash, destinationChain xc.NativeAsset, destinationDomain Domain) *Transfer {
+	return &Transfer{
+		Status:      StatusBurning,
+		Source:      Leg{Chain: sourceChain, Domain: sourceDomain, TxHash: burnTxHash},
+		Destination: Leg{Chain: destinationChain, Domain: destinationDomain},
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (transfer *Transfer) GetMessage() (*Message, error) {
+	if len(transfer.Message) == 0 {
+		return nil, fmt.Errorf("the cctp message has not been found yet")
+	}
+	return ParseMessage(transfer.Message)
+}
+
+// SetReceiveTx records the receive tx once it's submitted on the destination domain
+func (transfer *Transfer) SetReceiveTx(txHash xc.TxHash) error {
+	if transfer.Status != StatusAttested && transfer.Status != StatusReceiving {
+		return fmt.Errorf("cannot receive a cctp transfer that is %s", transfer.Status)
+	}
+	transfer.Destination.TxHash = txHash
+	transfer.Destination.Confirmations = 0
+	transfer.setStatus(StatusReceiving)
+	return nil
+}
+
+func (transfer *Transfer) setStatus(status Status) {
+	transfer.Status = status
+	transfer.UpdatedAt = time.Now()
+}
+
+func (transfer *Transfer) fail(format string, args ...interface{}) {
+	transfer.Error = fmt.Sprintf(format, args...)
+	transfer.setStatus(StatusFailed)
+}
+
+// Endpoint is what's needed from a chain client to track its side of a transfer
+type Endpoint interface {
+	tracking.TxFetcher
+	IsCctpMessageReceived(ctx context.Context, msg *Message) (bool, error)
+}
+
+type Tracker struct {
+	Endpoints map[Domain]Endpoint
+	Attester  Attester
+	// Confirmations of the burn to wait for before looking for the attestation
+	MinConfirmations int64
+}
+
+func NewTracker(attester Attester, endpoints map[Domain]Endpoint) *Tracker {
+	return &Tracker{
+		Endpoints: endpoints,
+		Attester:  attester,
+	}
+}
+
+func (tracker *Tracker) endpoint(domain Domain) (Endpoint, error) {
+	endpoint, ok := tracker.Endpoints[domain]
+	if !ok {
+		return nil, fmt.Errorf("no client for cctp domain %d", domain)
+	}
+	return endpoint, nil
+}
+
+// Update advances the transfer as far as it can go without submitting a transaction.  Errors are only
+// returned for failures to lookup state, which can be retried; terminal failures set the transfer as failed.
+func (tracker *Tracker) Update(ctx context.Context, transfer *Transfer) error {
+	return tracker.machine().Update(ctx, transfer)
+}
+
+// Wait updates the transfer every interval until it reaches the status or finishes
+func (tracker *Tracker) Wait(ctx context.Context, transfer *Transfer, status Status, interval time.Duration) error {
+	return tracker.machine().Wait(ctx, transfer, status, interval)
+}
+
+func (tracker *Tracker) machine() *tracking.Machine[Status, *Transfer] {
+	return &tracking.Machine[Status, *Transfer]{
+		Name:      "cctp",
+		Lifecycle: lifecycle,
+		Steps: map[Status]tracking.Step[*Transfer]{
+			StatusBurning:   tracker.updateBurn,
+			StatusAttesting: tracker.updateAttestation,
+			StatusAttested:  tracker.updateReceived,
+			StatusReceiving: tracker.updateReceive,
+		},
+		Status: func(transfer *Transfer) Status {
+			return transfer.Status
+		},
+	}
+}
+
+func (tracker *Tracker) updateBurn(ctx context.Context, transfer *Transfer) error {
+	source, err := tracker.endpoint(transfer.Source.Domain)
+	if err != nil {
+		return err
+	}
+	info, err := source.FetchLegacyTxInfo(ctx, transfer.Source.TxHash)
+	if err != nil {
+		return err
+	}
+	if info.Status == xc.TxStatusFailure {
+		transfer.fail("burn failed: %s", info.Error)
+		return nil
+	}
+	if info.BlockIndex == 0 {
+		// still pending
+		return nil
+	}
+	transfer.Source.Confirmations = info.Confirmations
+	if transfer.Source.Confirmations < tracker.MinConfirmations {
+		return nil
+	}
+	for _, payload := range info.Messages {
+		if payload.Protocol != xc.CrossChainProtocolCctp {
+			continue
+		}
+		msg, err := ParseMessage(payload.Payload)
+		if err != nil || msg.DestinationDomain != transfer.Destination.Domain {
+			continue
+		}
+		burn, err := msg.BurnMessage()
+		if err != nil {
+			// not from the token messenger
+			continue
+		}
+		transfer.Message = msg.Bytes()
+		transfer.MessageHash = msg.HashHex()
+		transfer.Nonce = msg.Nonce
+		transfer.Amount = xc.BigInt(*burn.Amount)
+		transfer.Sender = AddressFromBytes32(msg.SourceDomain, burn.MessageSender)
+		transfer.MintRecipient = AddressFromBytes32(msg.DestinationDomain, burn.MintRecipient)
+		break
+	}
+	if len(transfer.Message) == 0 {
+		transfer.fail("no cctp burn to domain %d found in %s", transfer.Destination.Domain, transfer.Source.TxHash)
+		return nil
+	}
+	transfer.setStatus(StatusAttesting)
+	return nil
+}
+
+func (tracker *Tracker) updateAttestation(ctx context.Context, transfer *Transfer) error {
+	msg, err := transfer.GetMessage()
+	if err != nil {
+		return err
+	}
+	attestation, err := tracker.Attester.FetchAttestation(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if !attestation.IsComplete() {
+		return nil
+	}
+	transfer.Attestation = attestation.Attestation
+	transfer.setStatus(StatusAttested)
+	return nil
+}
+
+// Anyone may relay an attested message, so it may have been received without us
+func (tracker *Tracker) updateReceived(ctx context.Context, transfer *Transfer) error {
+	destination, err := tracker.endpoint(transfer.Destination.Domain)
+	if err != nil {
+		return err
+	}
+	msg, err := transfer.GetMessage()
+	if err != nil {
+		return err
+	}
+	received, err := destination.IsCctpMessageReceived(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if received {
+		transfer.setStatus(StatusComplete)
+	}
+	return nil
+}
+
+func (tracker *Tracker) updateReceive(ctx context.Context, transfer *Transfer) error {
+	destination, err := tracker.endpoint(transfer.Destination.Domain)
+	if err != nil {
+		return err
+	}
+	info, err := destination.FetchLegacyTxInfo(ctx, transfer.Destination.TxHash)
+	if err != nil {
+		return err
+	}
+	if info.BlockIndex == 0 && info.Status != xc.TxStatusFailure {
+		// still pending
+		return nil
+	}
+	transfer.Destination.Confirmations = info.Confirmations
+	if err := tracker.updateReceived(ctx, transfer); err != nil {
+		return err
+	}
+	if transfer.Status == StatusComplete {
+		return nil
+	}
+	if info.Status == xc.TxStatusFailure {
+		// the attestation is still valid, so the receive can be retried with a new tx
+		transfer.Error = fmt.Sprintf("receive failed: %s", info.Error)
+		transfer.setStatus(StatusAttested)
+	}
+	return nil
+}
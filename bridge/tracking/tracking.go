@@ -0,0 +1,77 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// Lifecycle orders the statuses of a bridge transfer, which is sent on the source chain, proven off chain
+// (attested or signed), and then completed on the destination chain.
+type Lifecycle[S ~string] struct {
+	// Position of each status; statuses may share a position
+	Order    map[S]int
+	Complete S
+	Failed   S
+}
+
+// Reached reports if a transfer in the status has made it to the other status (or finished)
+func (lifecycle *Lifecycle[S]) Reached(status S, other S) bool {
+	return lifecycle.Order[status] >= lifecycle.Order[other]
+}
+
+func (lifecycle *Lifecycle[S]) IsFinal(status S) bool {
+	return status == lifecycle.Complete || status == lifecycle.Failed
+}
+
+// TxFetcher is what's needed from a chain client to follow the transactions on either side of a transfer
+type TxFetcher interface {
+	FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (*xc.LegacyTxInfo, error)
+}
+
+// Step advances a transfer out of its current status, if it can.  Errors are only returned for failures
+// to lookup state, which can be retried; terminal failures set the transfer as failed.
+type Step[T any] func(ctx context.Context, transfer T) error
+
+// Machine runs the step for a transfer's status until it stops changing
+type Machine[S ~string, T any] struct {
+	// Used in errors, e.g. "cctp"
+	Name      string
+	Lifecycle *Lifecycle[S]
+	Steps     map[S]Step[T]
+	Status    func(transfer T) S
+}
+
+// Update advances the transfer as far as it can go without submitting a transaction
+func (machine *Machine[S, T]) Update(ctx context.Context, transfer T) error {
+	for !machine.Lifecycle.IsFinal(machine.Status(transfer)) {
+		before := machine.Status(transfer)
+		step, ok := machine.Steps[before]
+		if !ok {
+			return fmt.Errorf("unknown %s transfer status '%s'", machine.Name, before)
+		}
+		if err := step(ctx, transfer); err != nil || machine.Status(transfer) == before {
+			return err
+		}
+	}
+	return nil
+}
+
+// Wait updates the transfer every interval until it reaches the status or finishes
+func (machine *Machine[S, T]) Wait(ctx context.Context, transfer T, status S, interval time.Duration) error {
+	for {
+		if err := machine.Update(ctx, transfer); err != nil {
+			return err
+		}
+		if machine.Lifecycle.Reached(machine.Status(transfer), status) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package tracking_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openweb3-io/crosschain/bridge/tracking"
+	"github.com/stretchr/testify/require"
+)
+
+type status string
+
+type transfer struct {
+	status status
+	steps  int
+}
+
+func TestMachine(t *testing.T) {
+	require := require.New(t)
+	lifecycle := &tracking.Lifecycle[status]{
+		Order:    map[status]int{"sent": 0, "proven": 1, "complete": 2, "failed": 2},
+		Complete: "complete",
+		Failed:   "failed",
+	}
+	require.True(lifecycle.Reached("proven", "sent"))
+	require.True(lifecycle.Reached("failed", "proven"))
+	require.False(lifecycle.Reached("sent", "proven"))
+	require.True(lifecycle.IsFinal("failed"))
+	require.False(lifecycle.IsFinal("proven"))
+
+	proven := false
+	machine := &tracking.Machine[status, *transfer]{
+		Name:      "test",
+		Lifecycle: lifecycle,
+		Steps: map[status]tracking.Step[*transfer]{
+			"sent": func(ctx context.Context, transfer *transfer) error {
+				transfer.steps++
+				transfer.status = "proven"
+				return nil
+			},
+			"proven": func(ctx context.Context, transfer *transfer) error {
+				transfer.steps++
+				if proven {
+					transfer.status = "complete"
+				}
+				return nil
+			},
+		},
+		Status: func(transfer *transfer) status {
+			return transfer.status
+		},
+	}
+
+	// advances until a step doesn't change the status
+	tr := &transfer{status: "sent"}
+	require.NoError(machine.Update(context.Background(), tr))
+	require.Equal(status("proven"), tr.status)
+	require.Equal(2, tr.steps)
+
+	require.NoError(machine.Wait(context.Background(), tr, "proven", 0))
+	proven = true
+	require.NoError(machine.Wait(context.Background(), tr, "complete", 0))
+	require.Equal(status("complete"), tr.status)
+
+	// nothing runs once final
+	steps := tr.steps
+	require.NoError(machine.Update(context.Background(), tr))
+	require.Equal(steps, tr.steps)
+
+	err := machine.Update(context.Background(), &transfer{status: "unknown"})
+	require.ErrorContains(err, "unknown test transfer status 'unknown'")
+}
@@ -0,0 +1,127 @@
+package wormhole
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const GuardianMainnetURL = "https://api.wormholescan.io"
+const GuardianTestnetURL = "https://api.testnet.wormholescan.io"
+
+// Guardians looks up the signed VAA for a published message.  The guardian network's REST API is used
+// normally, but a local stand-in can be swapped in for devnets and testing.
+type Guardians interface {
+	// Returns nil if the message has not been signed yet
+	FetchVAA(ctx context.Context, obs *Observation) (*VAA, error)
+}
+
+// Client for the guardian REST API, which is also served by indexers like wormholescan
+type GuardianClient struct {
+	URL  string
+	Http *http.Client
+}
+
+var _ Guardians = &GuardianClient{}
+
+func NewGuardianClient(url string) *GuardianClient {
+	return &GuardianClient{
+		URL:  strings.TrimSuffix(url, "/"),
+		Http: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func NewGuardianClientForNetwork(network string) *GuardianClient {
+	if network == "mainnet" {
+		return NewGuardianClient(GuardianMainnetURL)
+	}
+	return NewGuardianClient(GuardianTestnetURL)
+}
+
+func (client *GuardianClient) FetchVAA(ctx context.Context, obs *Observation) (*VAA, error) {
+	url := fmt.Sprintf("%s/v1/signed_vaa/%s", client.URL, obs.ID())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch wormhole vaa: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		// not enough guardians have signed yet
+		return nil, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch wormhole vaa: status %d", res.StatusCode)
+	}
+	var response struct {
+		VaaBytes string `json:"vaaBytes"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid wormhole vaa response: %v", err)
+	}
+	bz, err := base64.StdEncoding.DecodeString(response.VaaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wormhole vaa '%s': %v", response.VaaBytes, err)
+	}
+	vaa, err := ParseVAA(bz)
+	if err != nil {
+		return nil, err
+	}
+	if vaa.ID() != obs.ID() {
+		return nil, fmt.Errorf("wormhole vaa is for %s, not %s", vaa.ID(), obs.ID())
+	}
+	// the ID doesn't cover the payload, so the guardians must have signed exactly what we observed
+	if !bytes.Equal(vaa.Observation.Bytes(), obs.Bytes()) {
+		return nil, fmt.Errorf("wormhole vaa %s doesn't match the observed message", obs.ID())
+	}
+	return vaa, nil
+}
+
+// LocalGuardians sign observations with their own keys, for use against a core bridge that's been
+// configured with them as the guardian set.
+type LocalGuardians struct {
+	GuardianSetIndex uint32
+	// Ordered by guardian index
+	Keys []*ecdsa.PrivateKey
+}
+
+var _ Guardians = &LocalGuardians{}
+
+func NewLocalGuardians(guardianSetIndex uint32, keys ...*ecdsa.PrivateKey) *LocalGuardians {
+	return &LocalGuardians{guardianSetIndex, keys}
+}
+
+func (guardians *LocalGuardians) FetchVAA(ctx context.Context, obs *Observation) (*VAA, error) {
+	return guardians.Sign(obs)
+}
+
+// Sign the observation with every guardian
+func (guardians *LocalGuardians) Sign(obs *Observation) (*VAA, error) {
+	vaa := &VAA{
+		Version:          1,
+		GuardianSetIndex: guardians.GuardianSetIndex,
+		Observation:      *obs,
+	}
+	digest := obs.Digest()
+	for i, key := range guardians.Keys {
+		sig, err := crypto.Sign(digest, key)
+		if err != nil {
+			return nil, err
+		}
+		signature := Signature{GuardianIndex: uint8(i)}
+		copy(signature.Signature[:], sig)
+		vaa.Signatures = append(vaa.Signatures, signature)
+	}
+	return vaa, nil
+}
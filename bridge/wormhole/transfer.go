@@ -0,0 +1,289 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/openweb3-io/crosschain/bridge/tracking"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+type Status string
+
+const (
+	// The transfer has been submitted on the source chain
+	StatusSending Status = "sending"
+	// The transfer is confirmed and the message is waiting on the guardians to sign it
+	StatusSigning Status = "signing"
+	// The VAA is signed and can be redeemed on the destination chain
+	StatusSigned Status = "signed"
+	// The redemption has been submitted on the destination chain
+	StatusRedeeming Status = "redeeming"
+	StatusComplete  Status = "complete"
+	StatusFailed    Status = "failed"
+)
+
+var lifecycle = &tracking.Lifecycle[Status]{
+	Order: map[Status]int{
+		StatusSending:   0,
+		StatusSigning:   1,
+		StatusSigned:    2,
+		StatusRedeeming: 3,
+		StatusComplete:  4,
+		StatusFailed:    4,
+	},
+	Complete: StatusComplete,
+	Failed:   StatusFailed,
+}
+
+// Reached reports if a transfer in this status has made it to the other status (or finished)
+func (status Status) Reached(other Status) bool {
+	return lifecycle.Reached(status, other)
+}
+
+func (status Status) IsFinal() bool {
+	return lifecycle.IsFinal(status)
+}
+
+// Leg is the transaction on one side of a transfer.  Redeeming on solana takes several
+// transactions, in which case the last one (that completes the transfer) is tracked.
+type Leg struct {
+	Chain         xc.NativeAsset `json:"chain"`
+	WormholeChain ChainID        `json:"wormhole_chain"`
+	TxHash        xc.TxHash      `json:"tx_hash,omitempty"`
+	Confirmations int64          `json:"confirmations,omitempty"`
+}
+
+// Transfer tracks a token bridge transfer across both legs: the transfer on the source chain, and
+// the redemption on the destination chain.  It's serializable so it can be persisted between updates.
+type Transfer struct {
+	Status      Status `json:"status"`
+	Source      Leg    `json:"source"`
+	Destination Leg    `json:"destination"`
+
+	// Set once the published message is found
+	MessageID   string        `json:"message_id,omitempty"`
+	Sequence    uint64        `json:"sequence,omitempty"`
+	Amount      xc.BigInt     `json:"amount"`
+	Recipient   xc.Address    `json:"recipient,omitempty"`
+	Observation hexutil.Bytes `json:"observation,omitempty"`
+
+	VAA       hexutil.Bytes `json:"vaa,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func NewTransfer(sourceChain xc.NativeAsset, sourceWormholeChain ChainID, txHash xc.TxHash, destinationChain xc.NativeAsset, destinationWormholeChain ChainID) *Transfer {
+	return &Transfer{
+		Status:      StatusSending,
+		Source:      Leg{Chain: sourceChain, WormholeChain: sourceWormholeChain, TxHash: txHash},
+		Destination: Leg{Chain: destinationChain, WormholeChain: destinationWormholeChain},
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (transfer *Transfer) GetObservation() (*Observation, error) {
+	if len(transfer.Observation) == 0 {
+		return nil, fmt.Errorf("the wormhole message has not been found yet")
+	}
+	return ParseObservation(transfer.Observation)
+}
+
+func (transfer *Transfer) GetVAA() (*VAA, error) {
+	if len(transfer.VAA) == 0 {
+		return nil, fmt.Errorf("the wormhole vaa has not been signed yet")
+	}
+	return ParseVAA(transfer.VAA)
+}
+
+// SetRedeemTx records the redemption tx once it's submitted on the destination chain
+func (transfer *Transfer) SetRedeemTx(txHash xc.TxHash) error {
+	if transfer.Status != StatusSigned && transfer.Status != StatusRedeeming {
+		return fmt.Errorf("cannot redeem a wormhole transfer that is %s", transfer.Status)
+	}
+	transfer.Destination.TxHash = txHash
+	transfer.Destination.Confirmations = 0
+	transfer.setStatus(StatusRedeeming)
+	return nil
+}
+
+func (transfer *Transfer) setStatus(status Status) {
+	transfer.Status = status
+	transfer.UpdatedAt = time.Now()
+}
+
+func (transfer *Transfer) fail(format string, args ...interface{}) {
+	transfer.Error = fmt.Sprintf(format, args...)
+	transfer.setStatus(StatusFailed)
+}
+
+// Endpoint is what's needed from a chain client to track its side of a transfer
+type Endpoint interface {
+	tracking.TxFetcher
+	IsWormholeTransferCompleted(ctx context.Context, vaa *VAA) (bool, error)
+}
+
+type Tracker struct {
+	Endpoints map[ChainID]Endpoint
+	Guardians Guardians
+	// Confirmations of the transfer to wait for before looking for the VAA
+	MinConfirmations int64
+}
+
+func NewTracker(guardians Guardians, endpoints map[ChainID]Endpoint) *Tracker {
+	return &Tracker{
+		Endpoints: endpoints,
+		Guardians: guardians,
+	}
+}
+
+func (tracker *Tracker) endpoint(chain ChainID) (Endpoint, error) {
+	endpoint, ok := tracker.Endpoints[chain]
+	if !ok {
+		return nil, fmt.Errorf("no client for wormhole chain %d", chain)
+	}
+	return endpoint, nil
+}
+
+// Update advances the transfer as far as it can go without submitting a transaction.  Errors are only
+// returned for failures to lookup state, which can be retried; terminal failures set the transfer as failed.
+func (tracker *Tracker) Update(ctx context.Context, transfer *Transfer) error {
+	return tracker.machine().Update(ctx, transfer)
+}
+
+// Wait updates the transfer every interval until it reaches the status or finishes
+func (tracker *Tracker) Wait(ctx context.Context, transfer *Transfer, status Status, interval time.Duration) error {
+	return tracker.machine().Wait(ctx, transfer, status, interval)
+}
+
+func (tracker *Tracker) machine() *tracking.Machine[Status, *Transfer] {
+	return &tracking.Machine[Status, *Transfer]{
+		Name:      "wormhole",
+		Lifecycle: lifecycle,
+		Steps: map[Status]tracking.Step[*Transfer]{
+			StatusSending:   tracker.updateSend,
+			StatusSigning:   tracker.updateVAA,
+			StatusSigned:    tracker.updateCompleted,
+			StatusRedeeming: tracker.updateRedeem,
+		},
+		Status: func(transfer *Transfer) Status {
+			return transfer.Status
+		},
+	}
+}
+
+func (tracker *Tracker) updateSend(ctx context.Context, transfer *Transfer) error {
+	source, err := tracker.endpoint(transfer.Source.WormholeChain)
+	if err != nil {
+		return err
+	}
+	info, err := source.FetchLegacyTxInfo(ctx, transfer.Source.TxHash)
+	if err != nil {
+		return err
+	}
+	if info.Status == xc.TxStatusFailure {
+		transfer.fail("transfer failed: %s", info.Error)
+		return nil
+	}
+	if info.BlockIndex == 0 {
+		// still pending
+		return nil
+	}
+	transfer.Source.Confirmations = info.Confirmations
+	if transfer.Source.Confirmations < tracker.MinConfirmations {
+		return nil
+	}
+	for _, payload := range info.Messages {
+		if payload.Protocol != xc.CrossChainProtocolWormhole {
+			continue
+		}
+		obs, err := ParseObservation(payload.Payload)
+		if err != nil || obs.EmitterChain != transfer.Source.WormholeChain {
+			continue
+		}
+		tokenTransfer, err := ParseTokenTransfer(obs.Payload)
+		if err != nil || tokenTransfer.ToChain != transfer.Destination.WormholeChain {
+			// not from the token bridge
+			continue
+		}
+		transfer.Observation = obs.Bytes()
+		transfer.MessageID = obs.ID().String()
+		transfer.Sequence = obs.Sequence
+		transfer.Amount = xc.BigInt(*tokenTransfer.Amount)
+		transfer.Recipient = AddressFromBytes32(tokenTransfer.ToChain, tokenTransfer.To)
+		break
+	}
+	if len(transfer.Observation) == 0 {
+		transfer.fail("no wormhole transfer to chain %d found in %s", transfer.Destination.WormholeChain, transfer.Source.TxHash)
+		return nil
+	}
+	transfer.setStatus(StatusSigning)
+	return nil
+}
+
+func (tracker *Tracker) updateVAA(ctx context.Context, transfer *Transfer) error {
+	obs, err := transfer.GetObservation()
+	if err != nil {
+		return err
+	}
+	vaa, err := tracker.Guardians.FetchVAA(ctx, obs)
+	if err != nil {
+		return err
+	}
+	if vaa == nil {
+		return nil
+	}
+	transfer.VAA = vaa.Bytes()
+	transfer.setStatus(StatusSigned)
+	return nil
+}
+
+// Anyone may redeem a signed VAA, so it may have been completed without us
+func (tracker *Tracker) updateCompleted(ctx context.Context, transfer *Transfer) error {
+	destination, err := tracker.endpoint(transfer.Destination.WormholeChain)
+	if err != nil {
+		return err
+	}
+	vaa, err := transfer.GetVAA()
+	if err != nil {
+		return err
+	}
+	completed, err := destination.IsWormholeTransferCompleted(ctx, vaa)
+	if err != nil {
+		return err
+	}
+	if completed {
+		transfer.setStatus(StatusComplete)
+	}
+	return nil
+}
+
+func (tracker *Tracker) updateRedeem(ctx context.Context, transfer *Transfer) error {
+	destination, err := tracker.endpoint(transfer.Destination.WormholeChain)
+	if err != nil {
+		return err
+	}
+	info, err := destination.FetchLegacyTxInfo(ctx, transfer.Destination.TxHash)
+	if err != nil {
+		return err
+	}
+	if info.BlockIndex == 0 && info.Status != xc.TxStatusFailure {
+		// still pending
+		return nil
+	}
+	transfer.Destination.Confirmations = info.Confirmations
+	if err := tracker.updateCompleted(ctx, transfer); err != nil {
+		return err
+	}
+	if transfer.Status == StatusComplete {
+		return nil
+	}
+	if info.Status == xc.TxStatusFailure {
+		// the VAA is still valid, so the redemption can be retried with a new tx
+		transfer.Error = fmt.Sprintf("redeem failed: %s", info.Error)
+		transfer.setStatus(StatusSigned)
+	}
+	return nil
+}
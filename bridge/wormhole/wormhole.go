@@ -0,0 +1,290 @@
+package wormhole
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+	xc "github.com/openweb3-io/crosschain/types"
+)
+
+// ChainID is wormhole's identifier for a chain
+type ChainID uint16
+
+const (
+	ChainSolana    ChainID = 1
+	ChainEthereum  ChainID = 2
+	ChainBsc       ChainID = 4
+	ChainPolygon   ChainID = 5
+	ChainAvalanche ChainID = 6
+	ChainFantom    ChainID = 10
+	ChainCelo      ChainID = 14
+	ChainArbitrum  ChainID = 23
+	ChainOptimism  ChainID = 24
+	ChainBase      ChainID = 30
+	ChainSepolia   ChainID = 10002
+)
+
+// Wormhole chain ids of EVM chains, by EVM chain id
+var EvmChainIds = map[int64]ChainID{
+	1:        ChainEthereum,
+	56:       ChainBsc,
+	137:      ChainPolygon,
+	43114:    ChainAvalanche,
+	250:      ChainFantom,
+	42220:    ChainCelo,
+	42161:    ChainArbitrum,
+	10:       ChainOptimism,
+	8453:     ChainBase,
+	11155111: ChainSepolia,
+}
+
+func ChainIDForChain(chain *xc.ChainConfig) (ChainID, error) {
+	switch chain.Blockchain {
+	case xc.BlockchainSolana:
+		return ChainSolana, nil
+	case xc.BlockchainEVM:
+		if id, ok := EvmChainIds[chain.ChainID]; ok {
+			return id, nil
+		}
+		return 0, fmt.Errorf("wormhole is not supported on %s (chain_id %d)", chain.Chain, chain.ChainID)
+	}
+	return 0, fmt.Errorf("wormhole is not supported on %s", chain.Chain)
+}
+
+func (id ChainID) IsSolana() bool {
+	return id == ChainSolana
+}
+
+// AddressBytes32 converts an address on the chain to the 32 bytes wormhole uses for addresses
+func AddressBytes32(chain ChainID, addr xc.Address) ([32]byte, error) {
+	var bz [32]byte
+	if chain.IsSolana() {
+		pubkey, err := solana.PublicKeyFromBase58(string(addr))
+		if err != nil {
+			return bz, fmt.Errorf("invalid solana address '%s': %v", addr, err)
+		}
+		copy(bz[:], pubkey[:])
+		return bz, nil
+	}
+	if !common.IsHexAddress(string(addr)) {
+		return bz, fmt.Errorf("invalid evm address '%s'", addr)
+	}
+	copy(bz[12:], common.HexToAddress(string(addr)).Bytes())
+	return bz, nil
+}
+
+// AddressFromBytes32 converts 32 bytes from a wormhole message back to an address on the chain
+func AddressFromBytes32(chain ChainID, bz [32]byte) xc.Address {
+	if chain.IsSolana() {
+		return xc.Address(solana.PublicKeyFromBytes(bz[:]).String())
+	}
+	return xc.Address(common.BytesToAddress(bz[12:]).String())
+}
+
+const observationHeaderLen = 51
+
+// Observation is a message published to the wormhole core bridge, which the guardians sign to form a VAA.
+// Serialized, it's the body of the VAA.
+type Observation struct {
+	Timestamp        uint32
+	Nonce            uint32
+	EmitterChain     ChainID
+	EmitterAddress   [32]byte
+	Sequence         uint64
+	ConsistencyLevel uint8
+	Payload          []byte
+}
+
+func ParseObservation(bz []byte) (*Observation, error) {
+	if len(bz) < observationHeaderLen {
+		return nil, fmt.Errorf("invalid wormhole observation length %d", len(bz))
+	}
+	obs := &Observation{
+		Timestamp:        binary.BigEndian.Uint32(bz[0:4]),
+		Nonce:            binary.BigEndian.Uint32(bz[4:8]),
+		EmitterChain:     ChainID(binary.BigEndian.Uint16(bz[8:10])),
+		Sequence:         binary.BigEndian.Uint64(bz[42:50]),
+		ConsistencyLevel: bz[50],
+		Payload:          append([]byte{}, bz[observationHeaderLen:]...),
+	}
+	copy(obs.EmitterAddress[:], bz[10:42])
+	return obs, nil
+}
+
+func (obs *Observation) Bytes() []byte {
+	bz := make([]byte, observationHeaderLen, observationHeaderLen+len(obs.Payload))
+	binary.BigEndian.PutUint32(bz[0:4], obs.Timestamp)
+	binary.BigEndian.PutUint32(bz[4:8], obs.Nonce)
+	binary.BigEndian.PutUint16(bz[8:10], uint16(obs.EmitterChain))
+	copy(bz[10:42], obs.EmitterAddress[:])
+	binary.BigEndian.PutUint64(bz[42:50], obs.Sequence)
+	bz[50] = obs.ConsistencyLevel
+	return append(bz, obs.Payload...)
+}
+
+// Hash of the body, which the solana core bridge derives the posted VAA account from
+func (obs *Observation) Hash() []byte {
+	return crypto.Keccak256(obs.Bytes())
+}
+
+// Digest is what the guardians sign, and what EVM token bridges track completed transfers by
+func (obs *Observation) Digest() []byte {
+	return crypto.Keccak256(obs.Hash())
+}
+
+func (obs *Observation) ID() MessageID {
+	return MessageID{obs.EmitterChain, obs.EmitterAddress, obs.Sequence}
+}
+
+// MessageID uniquely identifies a published message
+type MessageID struct {
+	EmitterChain   ChainID
+	EmitterAddress [32]byte
+	Sequence       uint64
+}
+
+func (id MessageID) String() string {
+	return fmt.Sprintf("%d/%s/%d", id.EmitterChain, hex.EncodeToString(id.EmitterAddress[:]), id.Sequence)
+}
+
+type Signature struct {
+	GuardianIndex uint8
+	Signature     [65]byte
+}
+
+const vaaSignatureLen = 66
+
+// VAA is a verified action approval: an observation signed by a quorum of the guardians
+type VAA struct {
+	Version          uint8
+	GuardianSetIndex uint32
+	Signatures       []Signature
+	Observation
+}
+
+func ParseVAA(bz []byte) (*VAA, error) {
+	if len(bz) < 6 {
+		return nil, fmt.Errorf("invalid vaa length %d", len(bz))
+	}
+	vaa := &VAA{
+		Version:          bz[0],
+		GuardianSetIndex: binary.BigEndian.Uint32(bz[1:5]),
+	}
+	if vaa.Version != 1 {
+		return nil, fmt.Errorf("unsupported vaa version %d", vaa.Version)
+	}
+	count := int(bz[5])
+	offset := 6
+	if len(bz) < offset+count*vaaSignatureLen {
+		return nil, fmt.Errorf("invalid vaa length %d for %d signatures", len(bz), count)
+	}
+	for i := 0; i < count; i++ {
+		sig := Signature{GuardianIndex: bz[offset]}
+		copy(sig.Signature[:], bz[offset+1:offset+vaaSignatureLen])
+		vaa.Signatures = append(vaa.Signatures, sig)
+		offset += vaaSignatureLen
+	}
+	obs, err := ParseObservation(bz[offset:])
+	if err != nil {
+		return nil, err
+	}
+	vaa.Observation = *obs
+	return vaa, nil
+}
+
+func (vaa *VAA) Bytes() []byte {
+	bz := []byte{vaa.Version}
+	bz = binary.BigEndian.AppendUint32(bz, vaa.GuardianSetIndex)
+	bz = append(bz, uint8(len(vaa.Signatures)))
+	for _, sig := range vaa.Signatures {
+		bz = append(bz, sig.GuardianIndex)
+		bz = append(bz, sig.Signature[:]...)
+	}
+	return append(bz, vaa.Observation.Bytes()...)
+}
+
+// RecoverGuardians returns the address of the guardian behind each signature
+func (vaa *VAA) RecoverGuardians() ([]common.Address, error) {
+	digest := vaa.Digest()
+	guardians := []common.Address{}
+	for _, sig := range vaa.Signatures {
+		pub, err := crypto.SigToPub(digest, sig.Signature[:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature from guardian %d: %v", sig.GuardianIndex, err)
+		}
+		guardians = append(guardians, crypto.PubkeyToAddress(*pub))
+	}
+	return guardians, nil
+}
+
+const (
+	PayloadTransfer            = 1
+	PayloadTransferWithPayload = 3
+)
+
+const transferLen = 133
+
+// TokenTransfer is the payload of a token bridge transfer
+type TokenTransfer struct {
+	PayloadID    uint8
+	Amount       *big.Int
+	TokenAddress [32]byte
+	TokenChain   ChainID
+	To           [32]byte
+	ToChain      ChainID
+	// The relayer fee, only on plain transfers
+	Fee *big.Int
+	// The sender and payload, only on transfers with a payload
+	FromAddress [32]byte
+	Payload     []byte
+}
+
+func ParseTokenTransfer(bz []byte) (*TokenTransfer, error) {
+	if len(bz) < transferLen {
+		return nil, fmt.Errorf("invalid wormhole transfer length %d", len(bz))
+	}
+	transfer := &TokenTransfer{
+		PayloadID:  bz[0],
+		Amount:     new(big.Int).SetBytes(bz[1:33]),
+		TokenChain: ChainID(binary.BigEndian.Uint16(bz[65:67])),
+		ToChain:    ChainID(binary.BigEndian.Uint16(bz[99:101])),
+	}
+	copy(transfer.TokenAddress[:], bz[33:65])
+	copy(transfer.To[:], bz[67:99])
+	switch transfer.PayloadID {
+	case PayloadTransfer:
+		if len(bz) != transferLen {
+			return nil, fmt.Errorf("invalid wormhole transfer length %d", len(bz))
+		}
+		transfer.Fee = new(big.Int).SetBytes(bz[101:133])
+	case PayloadTransferWithPayload:
+		copy(transfer.FromAddress[:], bz[101:133])
+		transfer.Payload = append([]byte{}, bz[133:]...)
+	default:
+		return nil, fmt.Errorf("not a wormhole token transfer (payload %d)", transfer.PayloadID)
+	}
+	return transfer, nil
+}
+
+func (transfer *TokenTransfer) Bytes() []byte {
+	bz := make([]byte, transferLen)
+	bz[0] = transfer.PayloadID
+	transfer.Amount.FillBytes(bz[1:33])
+	copy(bz[33:65], transfer.TokenAddress[:])
+	binary.BigEndian.PutUint16(bz[65:67], uint16(transfer.TokenChain))
+	copy(bz[67:99], transfer.To[:])
+	binary.BigEndian.PutUint16(bz[99:101], uint16(transfer.ToChain))
+	if transfer.PayloadID == PayloadTransferWithPayload {
+		copy(bz[101:133], transfer.FromAddress[:])
+		return append(bz, transfer.Payload...)
+	}
+	if transfer.Fee != nil {
+		transfer.Fee.FillBytes(bz[101:133])
+	}
+	return bz
+}
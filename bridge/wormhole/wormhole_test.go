@@ -0,0 +1,215 @@
+package wormhole_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/openweb3-io/crosschain/bridge/wormhole"
+	xc "github.com/openweb3-io/crosschain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newObservation(t *testing.T, sequence uint64) *wormhole.Observation {
+	tokenBridge, err := wormhole.AddressBytes32(wormhole.ChainEthereum, "0x3ee18B2214AFF97000D974cf647E7C347E8fa585")
+	require.NoError(t, err)
+	token, err := wormhole.AddressBytes32(wormhole.ChainEthereum, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	require.NoError(t, err)
+	to, err := wormhole.AddressBytes32(wormhole.ChainSolana, "Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb")
+	require.NoError(t, err)
+	transfer := &wormhole.TokenTransfer{
+		PayloadID:    wormhole.PayloadTransfer,
+		Amount:       big.NewInt(1_500_000),
+		TokenAddress: token,
+		TokenChain:   wormhole.ChainEthereum,
+		To:           to,
+		ToChain:      wormhole.ChainSolana,
+		Fee:          big.NewInt(0),
+	}
+	return &wormhole.Observation{
+		Timestamp:        1_700_000_000,
+		Nonce:            42,
+		EmitterChain:     wormhole.ChainEthereum,
+		EmitterAddress:   tokenBridge,
+		Sequence:         sequence,
+		ConsistencyLevel: 1,
+		Payload:          transfer.Bytes(),
+	}
+}
+
+func TestVAA(t *testing.T) {
+	require := require.New(t)
+	obs := newObservation(t, 183_345)
+
+	bz := obs.Bytes()
+	require.Len(bz, 51+133)
+	parsed, err := wormhole.ParseObservation(bz)
+	require.NoError(err)
+	require.Equal(obs, parsed)
+	require.Equal(crypto.Keccak256(crypto.Keccak256(bz)), parsed.Digest())
+	require.Equal("2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/183345", parsed.ID().String())
+
+	transfer, err := wormhole.ParseTokenTransfer(parsed.Payload)
+	require.NoError(err)
+	require.EqualValues(1_500_000, transfer.Amount.Int64())
+	require.EqualValues(0, transfer.Fee.Int64())
+	require.EqualValues("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb", wormhole.AddressFromBytes32(transfer.ToChain, transfer.To))
+	require.EqualValues("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", wormhole.AddressFromBytes32(transfer.TokenChain, transfer.TokenAddress))
+
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	vaa, err := wormhole.NewLocalGuardians(4, key1, key2).Sign(obs)
+	require.NoError(err)
+	vaaBytes := vaa.Bytes()
+	require.Len(vaaBytes, 6+2*66+len(bz))
+	parsedVaa, err := wormhole.ParseVAA(vaaBytes)
+	require.NoError(err)
+	require.Equal(vaa, parsedVaa)
+	require.EqualValues(4, parsedVaa.GuardianSetIndex)
+	guardians, err := parsedVaa.RecoverGuardians()
+	require.NoError(err)
+	require.Equal(crypto.PubkeyToAddress(key1.PublicKey), guardians[0])
+	require.Equal(crypto.PubkeyToAddress(key2.PublicKey), guardians[1])
+
+	_, err = wormhole.ParseVAA(vaaBytes[:100])
+	require.ErrorContains(err, "invalid vaa length")
+	_, err = wormhole.ParseTokenTransfer([]byte{1, 2, 3})
+	require.ErrorContains(err, "invalid wormhole transfer length")
+	_, err = wormhole.ParseTokenTransfer(append([]byte{2}, make([]byte, 132)...))
+	require.ErrorContains(err, "not a wormhole token transfer")
+}
+
+func TestGuardianClient(t *testing.T) {
+	require := require.New(t)
+	obs := newObservation(t, 7)
+	key, _ := crypto.GenerateKey()
+	vaa, err := wormhole.NewLocalGuardians(0, key).Sign(obs)
+	require.NoError(err)
+	other, err := wormhole.NewLocalGuardians(0, key).Sign(newObservation(t, 8))
+	require.NoError(err)
+	tampered := newObservation(t, 7)
+	tampered.Payload[len(tampered.Payload)-1] ^= 1
+	forged, err := wormhole.NewLocalGuardians(0, key).Sign(tampered)
+	require.NoError(err)
+
+	responses := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal("/v1/signed_vaa/"+obs.ID().String(), r.URL.Path)
+		responses++
+		switch responses {
+		case 1:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":5,"message":"requested VAA not found in store"}`)
+		case 2:
+			fmt.Fprintf(w, `{"vaaBytes":"%s"}`, base64.StdEncoding.EncodeToString(other.Bytes()))
+		case 3:
+			fmt.Fprintf(w, `{"vaaBytes":"%s"}`, base64.StdEncoding.EncodeToString(forged.Bytes()))
+		default:
+			fmt.Fprintf(w, `{"vaaBytes":"%s"}`, base64.StdEncoding.EncodeToString(vaa.Bytes()))
+		}
+	}))
+	defer server.Close()
+	guardians := wormhole.NewGuardianClient(server.URL + "/")
+
+	fetched, err := guardians.FetchVAA(context.Background(), obs)
+	require.NoError(err)
+	require.Nil(fetched)
+	_, err = guardians.FetchVAA(context.Background(), obs)
+	require.ErrorContains(err, "not 2/")
+	_, err = guardians.FetchVAA(context.Background(), obs)
+	require.ErrorContains(err, "doesn't match")
+	fetched, err = guardians.FetchVAA(context.Background(), obs)
+	require.NoError(err)
+	require.Equal(vaa.Bytes(), fetched.Bytes())
+}
+
+type mockEndpoint struct {
+	txs       map[xc.TxHash]*xc.LegacyTxInfo
+	completed bool
+}
+
+func (endpoint *mockEndpoint) FetchLegacyTxInfo(ctx context.Context, txHash xc.TxHash) (*xc.LegacyTxInfo, error) {
+	info, ok := endpoint.txs[txHash]
+	if !ok {
+		return nil, fmt.Errorf("tx %s not found", txHash)
+	}
+	return info, nil
+}
+
+func (endpoint *mockEndpoint) IsWormholeTransferCompleted(ctx context.Context, vaa *wormhole.VAA) (bool, error) {
+	return endpoint.completed, nil
+}
+
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	obs := newObservation(t, 99)
+	key, _ := crypto.GenerateKey()
+
+	sendTx := &xc.LegacyTxInfo{}
+	source := &mockEndpoint{txs: map[xc.TxHash]*xc.LegacyTxInfo{"0xsend": sendTx}}
+	destination := &mockEndpoint{txs: map[xc.TxHash]*xc.LegacyTxInfo{}}
+	tracker := wormhole.NewTracker(wormhole.NewLocalGuardians(0, key), map[wormhole.ChainID]wormhole.Endpoint{
+		wormhole.ChainEthereum: source,
+		wormhole.ChainSolana:   destination,
+	})
+	tracker.MinConfirmations = 2
+	transfer := wormhole.NewTransfer(xc.ETH, wormhole.ChainEthereum, "0xsend", xc.SOL, wormhole.ChainSolana)
+
+	// pending
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(wormhole.StatusSending, transfer.Status)
+	_, err := transfer.GetVAA()
+	require.Error(err)
+
+	// mined but not confirmed enough
+	sendTx.BlockIndex = 100
+	sendTx.Confirmations = 1
+	sendTx.AddMessage(xc.CrossChainProtocolWormhole, "0x3ee18B2214AFF97000D974cf647E7C347E8fa585", obs.Bytes())
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(wormhole.StatusSending, transfer.Status)
+	require.Error(transfer.SetRedeemTx("early"))
+
+	// the local guardians sign immediately
+	sendTx.Confirmations = 2
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(wormhole.StatusSigned, transfer.Status)
+	require.EqualValues(99, transfer.Sequence)
+	require.Equal(obs.ID().String(), transfer.MessageID)
+	require.Equal("1500000", transfer.Amount.String())
+	require.EqualValues("Hzn3n914JaSpnxo5mBbmuCDmGL6mxWN9Ac2HzEXFSGtb", transfer.Recipient)
+	vaa, err := transfer.GetVAA()
+	require.NoError(err)
+	guardians, err := vaa.RecoverGuardians()
+	require.NoError(err)
+	require.Equal(crypto.PubkeyToAddress(key.PublicKey), guardians[0])
+
+	// a failed redemption can be retried
+	destination.txs["redeem1"] = &xc.LegacyTxInfo{BlockIndex: 5, Status: xc.TxStatusFailure, Error: "custom program error: 0x1"}
+	require.NoError(transfer.SetRedeemTx("redeem1"))
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(wormhole.StatusSigned, transfer.Status)
+	require.Contains(transfer.Error, "custom program error")
+
+	destination.txs["redeem2"] = &xc.LegacyTxInfo{}
+	require.NoError(transfer.SetRedeemTx("redeem2"))
+	require.NoError(tracker.Update(ctx, transfer))
+	require.Equal(wormhole.StatusRedeeming, transfer.Status)
+	destination.txs["redeem2"].BlockIndex = 6
+	destination.completed = true
+	require.NoError(tracker.Wait(ctx, transfer, wormhole.StatusComplete, 0))
+	require.Equal(wormhole.StatusComplete, transfer.Status)
+	require.EqualValues("redeem2", transfer.Destination.TxHash)
+
+	// a transfer without a message to the destination fails
+	source.txs["0xother"] = &xc.LegacyTxInfo{BlockIndex: 100, Confirmations: 10}
+	other := wormhole.NewTransfer(xc.ETH, wormhole.ChainEthereum, "0xother", xc.SOL, wormhole.ChainSolana)
+	require.NoError(tracker.Update(ctx, other))
+	require.Equal(wormhole.StatusFailed, other.Status)
+	require.Contains(other.Error, "no wormhole transfer")
+}
@@ -1,6 +1,9 @@
 package builder
 
 import (
+	"fmt"
+
+	"github.com/openweb3-io/crosschain/signer"
 	xc_types "github.com/openweb3-io/crosschain/types"
 	"go.uber.org/zap"
 )
@@ -9,6 +12,7 @@ import (
 // Then the public BuilderArgs can typecast and select which arguments are needed.
 type builderOptions struct {
 	memo           *string
+	memoSigner     *signer.Signer
 	timestamp      *int64
 	gasFeePriority *xc_types.GasFeePriority
 	publicKey      *[]byte
@@ -16,8 +20,16 @@ type builderOptions struct {
 	validator    *string
 	stakeOwner   *xc_types.Address
 	stakeAccount *string
+	provider     *xc_types.StakingProvider
+
+	destinationStakeAccount *string
+	newAuthority            *xc_types.Address
+	authorityType           *xc_types.StakeAuthority
+
+	asset   *xc_types.IAsset
+	tokenId *xc_types.BigInt
 
-	asset *xc_types.IAsset
+	nonceAccount *xc_types.Address
 }
 
 // All ArgumentBuilders should provide base arguments for transactions
@@ -39,8 +51,9 @@ func get[T any](arg *T) (T, bool) {
 }
 
 // Transaction options
-func (opts *builderOptions) GetMemo() (string, bool)     { return get(opts.memo) }
-func (opts *builderOptions) GetTimestamp() (int64, bool) { return get(opts.timestamp) }
+func (opts *builderOptions) GetMemo() (string, bool)              { return get(opts.memo) }
+func (opts *builderOptions) GetTimestamp() (int64, bool)          { return get(opts.timestamp) }
+func (opts *builderOptions) GetMemoSigner() (signer.Signer, bool) { return get(opts.memoSigner) }
 func (opts *builderOptions) GetPriority() (xc_types.GasFeePriority, bool) {
 	return get(opts.gasFeePriority)
 }
@@ -50,8 +63,22 @@ func (opts *builderOptions) GetPublicKey() ([]byte, bool) { return get(opts.publ
 func (opts *builderOptions) GetValidator() (string, bool)            { return get(opts.validator) }
 func (opts *builderOptions) GetStakeOwner() (xc_types.Address, bool) { return get(opts.stakeOwner) }
 func (opts *builderOptions) GetStakeAccount() (string, bool)         { return get(opts.stakeAccount) }
+func (opts *builderOptions) GetStakingProvider() (xc_types.StakingProvider, bool) {
+	return get(opts.provider)
+}
+func (opts *builderOptions) GetDestinationStakeAccount() (string, bool) {
+	return get(opts.destinationStakeAccount)
+}
+func (opts *builderOptions) GetNewAuthority() (xc_types.Address, bool) { return get(opts.newAuthority) }
+func (opts *builderOptions) GetAuthorityType() (xc_types.StakeAuthority, bool) {
+	return get(opts.authorityType)
+}
 
-func (opts *builderOptions) GetAsset() (xc_types.IAsset, bool) { return get(opts.asset) }
+func (opts *builderOptions) GetAsset() (xc_types.IAsset, bool)   { return get(opts.asset) }
+func (opts *builderOptions) GetTokenId() (xc_types.BigInt, bool) { return get(opts.tokenId) }
+func (opts *builderOptions) GetNonceAccount() (xc_types.Address, bool) {
+	return get(opts.nonceAccount)
+}
 
 type BuilderOption func(opts *builderOptions) error
 
@@ -61,6 +88,19 @@ func WithMemo(memo string) BuilderOption {
 		return nil
 	}
 }
+
+// Encrypt the memo so only the sender and recipient can read it (ton).  The signer of the
+// sender derives the key shared with the recipient.
+func WithEncryptedMemo(memo string, sender signer.Signer) BuilderOption {
+	return func(opts *builderOptions) error {
+		if sender == nil {
+			return fmt.Errorf("a signer is required to encrypt the memo")
+		}
+		opts.memo = &memo
+		opts.memoSigner = &sender
+		return nil
+	}
+}
 func WithTimestamp(ts int64) BuilderOption {
 	return func(opts *builderOptions) error {
 		opts.timestamp = &ts
@@ -100,6 +140,36 @@ func WithStakeAccount(account string) BuilderOption {
 	}
 }
 
+// Set the staking provider, which may change how the stake is validated
+func WithStakingProvider(provider xc_types.StakingProvider) BuilderOption {
+	return func(opts *builderOptions) error {
+		opts.provider = &provider
+		return nil
+	}
+}
+
+// Set the stake account that stake is merged or moved into
+func WithDestinationStakeAccount(account string) BuilderOption {
+	return func(opts *builderOptions) error {
+		opts.destinationStakeAccount = &account
+		return nil
+	}
+}
+
+// Set the new authority of stake accounts, and which authority it replaces
+func WithNewAuthority(authority xc_types.Address, authorityType xc_types.StakeAuthority) BuilderOption {
+	return func(opts *builderOptions) error {
+		switch authorityType {
+		case xc_types.StakeAuthorityStaker, xc_types.StakeAuthorityWithdrawer:
+		default:
+			return fmt.Errorf("invalid stake authority type: %s", authorityType)
+		}
+		opts.newAuthority = &authority
+		opts.authorityType = &authorityType
+		return nil
+	}
+}
+
 func WithAsset(asset xc_types.IAsset) BuilderOption {
 	return func(opts *builderOptions) error {
 		if asset != nil {
@@ -109,6 +179,23 @@ func WithAsset(asset xc_types.IAsset) BuilderOption {
 	}
 }
 
+// Set the token ID for a non-fungible token transfer
+func WithTokenId(tokenId xc_types.BigInt) BuilderOption {
+	return func(opts *builderOptions) error {
+		opts.tokenId = &tokenId
+		return nil
+	}
+}
+
+// Use a durable nonce account rather than a recent block hash (solana), so the
+// transaction can be signed long after its input is fetched
+func WithNonceAccount(account xc_types.Address) BuilderOption {
+	return func(opts *builderOptions) error {
+		opts.nonceAccount = &account
+		return nil
+	}
+}
+
 // Previously the crosschain abstraction would require callers to set options
 // directly on the transaction input, if the interface was implemented on the input type.
 // However, this is very clear or easy to use.  This function bridges the gap, to allow
@@ -29,6 +29,11 @@ type FullBuilder interface {
 	Staking
 }
 
+// Builders that can manage existing stake accounts
+type StakeManagement interface {
+	ManageStake(stakingArgs StakeArgs, input types.StakeManagementTxInput) (types.Tx, error)
+}
+
 type Staking interface {
 	Stake(stakingArgs StakeArgs, input types.StakeTxInput) (types.Tx, error)
 	Unstake(stakingArgs StakeArgs, input types.UnstakeTxInput) (types.Tx, error)
@@ -31,6 +31,19 @@ func (args *StakeArgs) GetPublicKey() ([]byte, bool) { return args.options.GetPu
 func (args *StakeArgs) GetValidator() (string, bool)            { return args.options.GetValidator() }
 func (args *StakeArgs) GetStakeOwner() (xc_types.Address, bool) { return args.options.GetStakeOwner() }
 func (args *StakeArgs) GetStakeAccount() (string, bool)         { return args.options.GetStakeAccount() }
+func (args *StakeArgs) GetStakingProvider() (xc_types.StakingProvider, bool) {
+	return args.options.GetStakingProvider()
+}
+
+func (args *StakeArgs) GetDestinationStakeAccount() (string, bool) {
+	return args.options.GetDestinationStakeAccount()
+}
+func (args *StakeArgs) GetNewAuthority() (xc_types.Address, bool) {
+	return args.options.GetNewAuthority()
+}
+func (args *StakeArgs) GetAuthorityType() (xc_types.StakeAuthority, bool) {
+	return args.options.GetAuthorityType()
+}
 
 func (args *StakeArgs) GetAsset() (xc_types.IAsset, bool) { return args.options.GetAsset() }
 
@@ -51,12 +64,20 @@ func NewStakeArgs(chain xc_types.NativeAsset, from xc_types.Address, amount xc_t
 	// Chain specific validation of arguments
 	switch chain.Blockchain() {
 	case xc_types.BlockchainEVM:
+		if provider, ok := args.GetStakingProvider(); ok && provider.IsLiquid() {
+			// any amount can be staked
+			break
+		}
 		// Eth must stake or unstake in increments of 32
 		_, err := validation.Count32EthChunks(args.GetAmount())
 		if err != nil {
 			return args, err
 		}
 	case xc_types.BlockchainCosmos, xc_types.BlockchainSolana:
+		if provider, ok := args.GetStakingProvider(); ok && provider.IsLiquid() {
+			// stake is delegated by the pool
+			break
+		}
 		if _, ok := args.GetValidator(); !ok {
 			return args, fmt.Errorf("validator to be delegated to is required for %s chain", chain)
 		}
@@ -1,6 +1,7 @@
 package builder
 
 import (
+	"github.com/openweb3-io/crosschain/signer"
 	"github.com/openweb3-io/crosschain/types"
 )
 
@@ -70,6 +71,19 @@ func (args *TransferArgs) GetMemo() (string, bool) {
 	return args.options.GetMemo()
 }
 
+// The signer to encrypt the memo with, if it should be encrypted
+func (args *TransferArgs) GetMemoSigner() (signer.Signer, bool) {
+	return args.options.GetMemoSigner()
+}
+
 func (args *TransferArgs) GetAsset() (types.IAsset, bool) {
 	return args.options.GetAsset()
 }
+
+func (args *TransferArgs) GetTokenId() (types.BigInt, bool) {
+	return args.options.GetTokenId()
+}
+
+func (args *TransferArgs) GetNonceAccount() (types.Address, bool) {
+	return args.options.GetNonceAccount()
+}
@@ -49,11 +49,21 @@ type StakingClient interface {
 	FetchWithdrawInput(ctx context.Context, args builder.StakeArgs) (xc_types.WithdrawTxInput, error)
 }
 
+// Clients that can manage existing stake accounts, e.g. merging or splitting them
+type StakeManagementClient interface {
+	FetchStakeManagementInput(ctx context.Context, args builder.StakeArgs, operation xc_types.StakeOperation) (xc_types.StakeManagementTxInput, error)
+}
+
 // Special 3rd-party interface for Ethereum as ethereum doesn't understand delegated staking
 type ManualUnstakingClient interface {
 	CompleteManualUnstaking(ctx context.Context, unstake *Unstake) error
 }
 
+// Clients that can read a token's metadata from the chain
+type TokenMetadataClient interface {
+	FetchTokenMetadata(ctx context.Context, contract xc_types.ContractAddress) (*TokenMetadata, error)
+}
+
 type ClientError string
 
 // A transaction terminally failed due to no balance
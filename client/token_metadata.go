@@ -0,0 +1,23 @@
+package client
+
+import (
+	xc_types "github.com/openweb3-io/crosschain/types"
+)
+
+// The metadata of a token, as published on chain
+type TokenMetadata struct {
+	Contract xc_types.ContractAddress `json:"contract"`
+	// Optional; not every token standard requires a name or symbol
+	Name     string `json:"name,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals int32  `json:"decimals"`
+}
+
+func NewTokenMetadata(contract xc_types.ContractAddress, name string, symbol string, decimals int32) *TokenMetadata {
+	return &TokenMetadata{
+		Contract: contract,
+		Name:     name,
+		Symbol:   symbol,
+		Decimals: decimals,
+	}
+}
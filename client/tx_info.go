@@ -85,6 +85,8 @@ type BalanceChange struct {
 	Balance  xc_types.BigInt               `json:"balance"`
 	Amount   *xc_types.AmountHumanReadable `json:"amount,omitempty"`
 	Address  AddressName                   `json:"address"`
+	// Set for non-fungible token movements
+	TokenId *xc_types.BigInt `json:"token_id,omitempty"`
 }
 type Transfer struct {
 	// required: source debits
@@ -149,11 +151,16 @@ type TxInfo struct {
 
 	// output-only: calculate via .CalcuateFees() method
 	Fees []*Balance `json:"fees"`
+	// optional: the portion of the fee that L2 chains charge for posting the tx data to L1
+	L1Fee *xc_types.BigInt `json:"l1_fee,omitempty"`
 
 	// Native staking events
 	Stakes   []*Stake   `json:"stakes,omitempty"`
 	Unstakes []*Unstake `json:"unstakes,omitempty"`
 
+	// Messages to be relayed to another chain by a bridge
+	Messages []*xc_types.CrossChainMessage `json:"messages,omitempty"`
+
 	// required: set the confirmations at time of querying the info
 	Confirmations uint64 `json:"confirmations"`
 	// optional: set the error of the transaction if there was an error
@@ -175,6 +182,7 @@ func NewBalanceChange(chain xc_types.NativeAsset, contract xc_types.ContractAddr
 	asset := NewAssetName(chain, string(contract))
 	addressName := NewAddressName(chain, string(address))
 	var amount *xc_types.AmountHumanReadable
+	var tokenId *xc_types.BigInt
 
 	return &BalanceChange{
 		asset,
@@ -182,14 +190,17 @@ func NewBalanceChange(chain xc_types.NativeAsset, contract xc_types.ContractAddr
 		balance,
 		amount,
 		addressName,
+		tokenId,
 	}
 }
 
 func NewTxInfo(block *Block, chain xc_types.NativeAsset, hash string, confirmations uint64, err *string) *TxInfo {
 	transfers := []*Transfer{}
 	fees := []*Balance{}
+	var l1Fee *xc_types.BigInt = nil
 	var stakes []*Stake = nil
 	var unstakes []*Unstake = nil
+	var messages []*xc_types.CrossChainMessage = nil
 	name := NewTransactionName(chain, hash)
 	return &TxInfo{
 		name,
@@ -198,8 +209,10 @@ func NewTxInfo(block *Block, chain xc_types.NativeAsset, hash string, confirmati
 		block,
 		transfers,
 		fees,
+		l1Fee,
 		stakes,
 		unstakes,
+		messages,
 		confirmations,
 		err,
 	}
@@ -276,6 +289,16 @@ func (tf *Transfer) SetMemo(memo string) {
 	tf.Memo = memo
 }
 
+// SetTokenId marks all of the movements in the transfer as being for a non-fungible token
+func (tf *Transfer) SetTokenId(tokenId xc_types.BigInt) {
+	for _, from := range tf.From {
+		from.TokenId = &tokenId
+	}
+	for _, to := range tf.To {
+		to.TokenId = &tokenId
+	}
+}
+
 type LegacyTxInfoMappingType string
 
 var Utxo LegacyTxInfoMappingType = "utxo"
@@ -304,10 +327,12 @@ func TxInfoFromLegacy(chain xc_types.NativeAsset, legacyTx *xc_types.LegacyTxInf
 		tf := NewTransfer(chain)
 		for _, source := range legacyTx.Sources {
 			tf.AddSource(source.Address, source.ContractAddress, source.Amount, nil)
+			tf.From[len(tf.From)-1].TokenId = source.TokenId
 		}
 
 		for _, dest := range legacyTx.Destinations {
 			tf.AddDestination(dest.Address, dest.ContractAddress, dest.Amount, nil)
+			tf.To[len(tf.To)-1].TokenId = dest.TokenId
 		}
 		txInfo.AddTransfer(tf)
 	} else {
@@ -319,12 +344,20 @@ func TxInfoFromLegacy(chain xc_types.NativeAsset, legacyTx *xc_types.LegacyTxInf
 			}
 
 			txInfo.AddSimpleTransfer(fromAddr, dest.Address, dest.ContractAddress, dest.Amount, nil, dest.Memo)
+			if dest.TokenId != nil {
+				txInfo.Transfers[len(txInfo.Transfers)-1].SetTokenId(*dest.TokenId)
+			}
 		}
 	}
 	zero := big.NewInt(0)
 	if legacyTx.Fee.Cmp((*xc_types.BigInt)(zero)) != 0 {
 		txInfo.AddFee(legacyTx.From, legacyTx.FeeContract, legacyTx.Fee, nil)
 	}
+	if legacyTx.L1Fee.Cmp((*xc_types.BigInt)(zero)) != 0 {
+		// the fee includes the L1 data fee of L2 chains, which is also reported on its own
+		l1Fee := legacyTx.L1Fee
+		txInfo.L1Fee = &l1Fee
+	}
 
 	txInfo.Fees = txInfo.CalculateFees()
 
@@ -338,5 +371,6 @@ func TxInfoFromLegacy(chain xc_types.NativeAsset, legacyTx *xc_types.LegacyTxInf
 			zap.S().Warn("unknown stake event type: " + fmt.Sprintf("%T", ev))
 		}
 	}
+	txInfo.Messages = legacyTx.Messages
 	return *txInfo
 }
@@ -73,3 +73,22 @@ func TestTxInfoMultiLegFees(t *testing.T) {
 	require.Equal(t, "200", tx.CalculateFees()[0].Balance.String())
 	require.EqualValues(t, "BTC", tx.CalculateFees()[0].Contract)
 }
+
+func TestTxInfoFromLegacyL1Fee(t *testing.T) {
+	legacy := &xc_types.LegacyTxInfo{
+		TxID:  "0x1234",
+		From:  "sender",
+		Fee:   xc_types.NewBigIntFromUint64(150),
+		L1Fee: xc_types.NewBigIntFromUint64(100),
+	}
+	info := client.TxInfoFromLegacy(xc_types.OptETH, legacy, client.Account)
+	// a single fee, with the l1 portion reported on its own
+	require.Len(t, info.Transfers, 1)
+	require.Equal(t, "150", info.Transfers[0].From[0].Balance.String())
+	require.Len(t, info.Fees, 1)
+	require.Equal(t, "150", info.Fees[0].Balance.String())
+	require.Equal(t, "100", info.L1Fee.String())
+	// the legacy fee is left as is
+	require.Equal(t, "150", legacy.Fee.String())
+	require.Equal(t, "100", legacy.L1Fee.String())
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/openweb3-io/crosschain/cmd/xc/setup"
+	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +20,7 @@ func CmdChains() *cobra.Command {
 			// chain := setup.UnwrapChain(cmd.Context())
 
 			/*
-				client, err := xcFactory.NewClient(assetConfig(chain, "", 0))
+				client, err := xcFactory.NewClient(assetConfig(xcFactory, chain, "", 0))
 				if err != nil {
 					return err
 				}
@@ -44,7 +45,16 @@ func CmdTxInput() *cobra.Command {
 			addressRaw := args[0]
 
 			addressTo, _ := cmd.Flags().GetString("to")
-			// contract, _ := cmd.Flags().GetString("contract")
+			contract, _ := cmd.Flags().GetString("contract")
+			decimals, _ := cmd.Flags().GetInt32("decimals")
+			var asset xc.IAsset
+			if contract != "" {
+				var err error
+				asset, err = assetConfig(xcFactory, chain, xc.ContractAddress(contract), decimals)
+				if err != nil {
+					return err
+				}
+			}
 			client, err := xcFactory.NewClient(chain)
 			if err != nil {
 				return err
@@ -52,7 +62,7 @@ func CmdTxInput() *cobra.Command {
 
 			from := xcFactory.MustAddress(chain, addressRaw)
 			to := xcFactory.MustAddress(chain, addressTo)
-			input, err := client.FetchLegacyTxInput(context.Background(), from, to, nil)
+			input, err := client.FetchLegacyTxInput(context.Background(), from, to, asset)
 			if err != nil {
 				return fmt.Errorf("could not fetch transaction inputs: %v", err)
 			}
@@ -63,6 +73,7 @@ func CmdTxInput() *cobra.Command {
 		},
 	}
 	cmd.Flags().String("contract", "", "Optional contract of token asset")
+	cmd.Flags().Int32("decimals", 0, "Optional decimals of token asset; looked up from the contract by default")
 	cmd.Flags().String("to", "", "Optional destination address")
 	return cmd
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/openweb3-io/crosschain/cmd/xc/setup"
+	"github.com/openweb3-io/crosschain/factory"
 	"github.com/openweb3-io/crosschain/types"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/sirupsen/logrus"
@@ -50,7 +51,11 @@ func main() {
 	_ = cmd.Execute()
 }
 
-func assetConfig(chain *xc.ChainConfig, contractMaybe xc.ContractAddress, decimals int32) types.IAsset {
+// Tokens without decimals are looked up by their contract, which reads their metadata on chain if needed
+func assetConfig(xcFactory *factory.Factory, chain *xc.ChainConfig, contractMaybe xc.ContractAddress, decimals int32) (types.IAsset, error) {
+	if contractMaybe != "" && decimals == 0 {
+		return xcFactory.GetAssetConfigByContract(string(contractMaybe), chain.Chain)
+	}
 	if contractMaybe != "" {
 		token := xc.TokenAssetConfig{
 			Contract: contractMaybe,
@@ -58,8 +63,8 @@ func assetConfig(chain *xc.ChainConfig, contractMaybe xc.ContractAddress, decima
 			ChainConfig: chain,
 			Decimals:    decimals,
 		}
-		return &token
+		return &token, nil
 	} else {
-		return chain
+		return chain, nil
 	}
 }
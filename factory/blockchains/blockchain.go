@@ -49,6 +49,10 @@ func RegisterClient(cfg xc.Blockchain, creator ClientCreator) {
 	creatorMap[cfg] = creator
 }
 
+func UnregisterClient(cfg xc.Blockchain) {
+	delete(creatorMap, cfg)
+}
+
 func init() {
 	RegisterClient(xc.BlockchainBtc, func(cfg *xc.ChainConfig) (xc_client.IClient, error) {
 		return btcclient.NewClient(cfg)
@@ -104,8 +108,8 @@ func NewAddressBuilder(cfg *xc.ChainConfig) (xc.AddressBuilder, error) {
 	switch xc.Blockchain(cfg.Blockchain) {
 	case xc.BlockchainEVM:
 		return evmaddress.NewAddressBuilder(cfg)
-	//case types.BlockchainEVMLegacy:
-	//	return evm_legacy.NewAddressBuilder(cfg)
+	case xc.BlockchainEVMLegacy:
+		return evm_legacy.NewAddressBuilder(cfg)
 	case xc.BlockchainCosmos, xc.BlockchainCosmosEvmos:
 		return cosmosaddress.NewAddressBuilder(cfg)
 	case xc.BlockchainSolana:
@@ -136,8 +140,8 @@ func NewTxBuilder(cfg *xc.ChainConfig) (xcbuilder.TxBuilder, error) {
 	switch xc.Blockchain(cfg.Blockchain) {
 	case xc.BlockchainEVM:
 		return evmbuilder.NewTxBuilder(cfg)
-	//case BlockchainEVMLegacy:
-	//	return evm_legacy.NewTxBuilder(cfg)
+	case xc.BlockchainEVMLegacy:
+		return evm_legacy.NewTxBuilder(cfg)
 	case xc.BlockchainCosmos, xc.BlockchainCosmosEvmos:
 		return cosmosbuilder.NewTxBuilder(cfg)
 	case xc.BlockchainSolana:
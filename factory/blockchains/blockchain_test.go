@@ -3,6 +3,8 @@ package blockchains_test
 import (
 	"testing"
 
+	"github.com/gagliardetto/solana-go"
+	solanainput "github.com/openweb3-io/crosschain/blockchain/solana/tx_input"
 	"github.com/openweb3-io/crosschain/factory/blockchains"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/suite"
@@ -56,3 +58,54 @@ func createChainFor(blockchain xc.Blockchain) *xc.ChainConfig {
 	}
 	return fakeAsset
 }
+
+func (s *BlockchainTestSuite) TestEvmLegacyBuilders() {
+	require := s.Require()
+	cfg := &xc.ChainConfig{Chain: xc.BNB, Blockchain: xc.BlockchainEVMLegacy, ChainID: 56}
+
+	addressBuilder, err := blockchains.NewAddressBuilder(cfg)
+	require.NoError(err)
+	require.NotNil(addressBuilder)
+
+	txBuilder, err := blockchains.NewTxBuilder(cfg)
+	require.NoError(err)
+	require.NotNil(txBuilder)
+
+	input, err := blockchains.NewTxInput(xc.BlockchainEVMLegacy)
+	require.NoError(err)
+	require.Equal(xc.BlockchainEVMLegacy, input.GetBlockchain())
+}
+
+func (s *BlockchainTestSuite) TestStakeManagementInputs() {
+	require := s.Require()
+
+	merge := &solanainput.MergeStakeInput{
+		Destination: solana.MustPublicKeyFromBase58("CCTFhyxoUHGmdQvuUxFquyYMK4H5hdqwCCN7XAXtK9HC"),
+		Sources: []solana.PublicKey{
+			solana.MustPublicKeyFromBase58("GBrg73u6MuwgM6hXrBG9WYqKSrZ6jBr9kW9Nm3Ze5k68"),
+		},
+	}
+	bz, err := blockchains.MarshalVariantInput(merge)
+	require.NoError(err)
+	input, err := blockchains.UnmarshalStakeManagementInput(bz)
+	require.NoError(err)
+	require.Equal(xc.StakeMerge, input.ManagingStake())
+	require.Equal(merge, input)
+
+	authorize := &solanainput.AuthorizeStakeInput{
+		NewAuthority: solana.MustPublicKeyFromBase58("9BQ6j3ZL6QB5ugJ8XoTUy1trEtr1FpvyLhMkb6dDBVyL"),
+		Authority:    xc.StakeAuthorityWithdrawer,
+	}
+	bz, err = blockchains.MarshalVariantInput(authorize)
+	require.NoError(err)
+	input, err = blockchains.UnmarshalStakeManagementInput(bz)
+	require.NoError(err)
+	require.Equal(xc.StakeAuthorize, input.ManagingStake())
+	require.Equal(authorize, input)
+
+	// other variants are not stake management
+	bz, err = blockchains.MarshalVariantInput(&solanainput.StakingInput{})
+	require.NoError(err)
+	_, err = blockchains.UnmarshalStakeManagementInput(bz)
+	require.ErrorContains(err, "not a stake management input")
+}
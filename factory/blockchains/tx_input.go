@@ -169,3 +169,15 @@ func UnmarshalWithdrawingInput(data []byte) (xc.WithdrawTxInput, error) {
 	}
 	return staking, nil
 }
+
+func UnmarshalStakeManagementInput(data []byte) (xc.StakeManagementTxInput, error) {
+	inp, err := UnmarshalVariantInput(data)
+	if err != nil {
+		return nil, err
+	}
+	managing, ok := inp.(xc.StakeManagementTxInput)
+	if !ok {
+		return managing, fmt.Errorf("not a stake management input: %T", inp)
+	}
+	return managing, nil
+}
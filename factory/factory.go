@@ -1,7 +1,9 @@
 package factory
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	remoteclient "github.com/openweb3-io/crosschain/blockchain/crosschain"
@@ -49,6 +51,79 @@ func (f *Factory) GetAssetConfig(asset string, nativeAsset types.NativeAsset) (t
 	return f.cfgFromAsset(assetID)
 }
 
+// GetAssetConfigByContract looks up a token by its contract.  Tokens that have not been loaded are
+// resolved by the registered callback, or otherwise from their metadata on chain, and then cached.
+func (f *Factory) GetAssetConfigByContract(contract string, nativeAsset types.NativeAsset) (types.IAsset, error) {
+	if cfg, ok := f.findAssetConfigByContract(contract, nativeAsset); ok {
+		return f.cfgFromAsset(cfg.ID())
+	}
+	var cfg types.IAsset
+	var err error
+	if f.callbackGetAssetConfigByContract != nil {
+		cfg, err = f.callbackGetAssetConfigByContract(contract, nativeAsset)
+	} else {
+		cfg, err = f.FetchAssetConfigByContract(context.Background(), contract, nativeAsset)
+	}
+	if err != nil {
+		return &types.TokenAssetConfig{}, err
+	}
+	return f.PutAssetConfig(cfg)
+}
+
+func (f *Factory) findAssetConfigByContract(contract string, nativeAsset types.NativeAsset) (*types.TokenAssetConfig, bool) {
+	var found *types.TokenAssetConfig
+	f.AllAssets.Range(func(key, value any) bool {
+		cfg, ok := value.(*types.TokenAssetConfig)
+		if ok && cfg.Chain == nativeAsset && sameContract(string(cfg.Contract), contract) {
+			found = cfg
+			return false
+		}
+		return true
+	})
+	return found, found != nil
+}
+
+// Hex contracts are case insensitive, but other encodings (like base58) are not
+func sameContract(a string, b string) bool {
+	if strings.HasPrefix(a, "0x") && strings.HasPrefix(b, "0x") {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// FetchAssetConfigByContract builds the config of a token from the metadata its contract publishes on chain
+func (f *Factory) FetchAssetConfigByContract(ctx context.Context, contract string, nativeAsset types.NativeAsset) (*types.TokenAssetConfig, error) {
+	chainI, found := f.AllAssets.Load(types.AssetID(nativeAsset))
+	if !found {
+		return nil, fmt.Errorf("unsupported native asset: %s", nativeAsset)
+	}
+	chain := chainI.(*types.ChainConfig)
+	client, err := f.NewClient(chain)
+	if err != nil {
+		return nil, err
+	}
+	metadataClient, ok := client.(xc_client.TokenMetadataClient)
+	if !ok {
+		return nil, fmt.Errorf("unknown contract: '%s': %s does not support token metadata", contract, nativeAsset)
+	}
+	metadata, err := metadataClient.FetchTokenMetadata(ctx, types.ContractAddress(contract))
+	if err != nil {
+		return nil, fmt.Errorf("unknown contract: '%s': %v", contract, err)
+	}
+	cfg := &types.TokenAssetConfig{
+		Asset:       metadata.Symbol,
+		Chain:       nativeAsset,
+		Decimals:    metadata.Decimals,
+		Contract:    types.ContractAddress(contract),
+		ChainConfig: chain,
+	}
+	// the symbol may be missing, or taken by another token (possibly an imitation), so fall back to the contract
+	if _, taken := f.AllAssets.Load(cfg.ID()); cfg.Asset == "" || taken {
+		cfg.Asset = contract
+	}
+	return cfg, nil
+}
+
 func (f *Factory) cfgFromAsset(assetID types.AssetID) (types.IAsset, error) {
 	cfgI, found := f.AllAssets.Load(assetID)
 	if !found {
@@ -1,9 +1,13 @@
 package factory_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
+	xc_client "github.com/openweb3-io/crosschain/client"
 	"github.com/openweb3-io/crosschain/factory"
+	"github.com/openweb3-io/crosschain/factory/blockchains"
 	xc "github.com/openweb3-io/crosschain/types"
 	"github.com/stretchr/testify/suite"
 )
@@ -185,39 +189,83 @@ func (s *CrosschainTestSuite) TestGetMultiAssetConfigEdgeCases() {
 }
 */
 
-/*
+type mockMetadataClient struct {
+	xc_client.IClient
+	calls int
+}
+
+func (client *mockMetadataClient) FetchTokenMetadata(ctx context.Context, contract xc.ContractAddress) (*xc_client.TokenMetadata, error) {
+	client.calls++
+	switch contract {
+	case "0xdAC17F958D2ee523a2206206994597C13D831ec7":
+		return xc_client.NewTokenMetadata(contract, "Tether USD", "USDT", 6), nil
+	case "0x0000000000000000000000000000000000000bad":
+		// imitates a configured token
+		return xc_client.NewTokenMetadata(contract, "Wrapped Ether", "WETH", 18), nil
+	}
+	return nil, fmt.Errorf("not a token")
+}
+
 func (s *CrosschainTestSuite) TestGetAssetConfigByContract() {
 	require := s.Require()
+	client := &mockMetadataClient{}
+	blockchains.RegisterClient("mock-metadata", func(cfg *xc.ChainConfig) (xc_client.IClient, error) {
+		return client, nil
+	})
+	s.T().Cleanup(func() {
+		blockchains.UnregisterClient("mock-metadata")
+	})
+	s.Factory.PutAssetConfig(&xc.ChainConfig{
+		Chain:  "ETH",
+		Client: &xc.ClientConfig{Blockchain: "mock-metadata"},
+	})
 	s.Factory.PutAssetConfig(&xc.TokenAssetConfig{
 		Chain:    "ETH",
 		Contract: "0xB4FBF271143F4FBf7B91A5ded31805e42b2208d0",
 		Asset:    "WETH",
 	})
-	s.Factory.PutAssetConfig(&xc.TokenAssetConfig{
-		Chain:    "SOL",
-		Contract: "4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDu",
-		Asset:    "USDC",
-	})
 
-	assetI, err := s.Factory.GetAssetConfigByContract("0xB4FBF271143F4FBf7B91A5ded31805e42b2208d0", "ETH")
-	asset := assetI.(*xc.TokenAssetConfig)
-	require.Nil(err)
-	require.NotNil(asset)
-	require.Equal("WETH", asset.Asset)
+	// configured tokens match regardless of the case of hex contracts
+	assetI, err := s.Factory.GetAssetConfigByContract("0xb4fbf271143f4fbf7b91a5ded31805e42b2208d0", "ETH")
+	require.NoError(err)
+	require.Equal("WETH", assetI.(*xc.TokenAssetConfig).Asset)
+	require.Equal(0, client.calls)
 
-	assetI, err = s.Factory.GetAssetConfigByContract("4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDu", "SOL")
-	asset = assetI.(*xc.TokenAssetConfig)
-	require.Nil(err)
-	require.NotNil(asset)
-	require.Equal("USDC", asset.Asset)
+	// others are resolved on chain, once
+	for i := 0; i < 2; i++ {
+		assetI, err = s.Factory.GetAssetConfigByContract("0xdAC17F958D2ee523a2206206994597C13D831ec7", "ETH")
+		require.NoError(err)
+		asset := assetI.(*xc.TokenAssetConfig)
+		require.Equal("USDT", asset.Asset)
+		require.EqualValues(6, asset.Decimals)
+		require.Equal(xc.NativeAsset("ETH"), asset.GetChain().Chain)
+	}
+	require.Equal(1, client.calls)
+	assetI, err = s.Factory.GetAssetConfig("USDT", "ETH")
+	require.NoError(err)
+	require.EqualValues("0xdAC17F958D2ee523a2206206994597C13D831ec7", assetI.GetContract())
 
+	// a symbol that is taken does not replace the configured token
+	assetI, err = s.Factory.GetAssetConfigByContract("0x0000000000000000000000000000000000000bad", "ETH")
+	require.NoError(err)
+	require.Equal("0x0000000000000000000000000000000000000bad", assetI.(*xc.TokenAssetConfig).Asset)
+	assetI, err = s.Factory.GetAssetConfig("WETH", "ETH")
+	require.NoError(err)
+	require.EqualValues("0xB4FBF271143F4FBf7B91A5ded31805e42b2208d0", assetI.GetContract())
+
+	_, err = s.Factory.GetAssetConfigByContract("0x123456", "ETH")
+	require.ErrorContains(err, "unknown contract: '0x123456'")
+
+	// a registered callback replaces the lookup on chain
+	s.Factory.RegisterGetAssetConfigByContractCallback(func(contract string, nativeAsset xc.NativeAsset) (xc.IAsset, error) {
+		return &xc.TokenAssetConfig{Asset: "CB", Chain: nativeAsset, Contract: xc.ContractAddress(contract), Decimals: 8}, nil
+	})
+	defer s.Factory.UnregisterGetAssetConfigByContractCallback()
 	assetI, err = s.Factory.GetAssetConfigByContract("0x123456", "ETH")
-	asset = assetI.(*xc.TokenAssetConfig)
-	require.EqualError(err, "unknown contract: '0x123456'")
-	require.NotNil(asset)
-	require.Equal("", asset.Asset)
+	require.NoError(err)
+	require.Equal("CB", assetI.(*xc.TokenAssetConfig).Asset)
+	require.Equal(3, client.calls)
 }
-*/
 
 /*
 func (s *CrosschainTestSuite) TestPutAssetConfig() {
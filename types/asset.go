@@ -11,46 +11,47 @@ type NativeAsset string
 
 // List of supported NativeAsset
 const (
-	ACA    = NativeAsset("ACA")    // Acala
-	APTOS  = NativeAsset("APTOS")  // APTOS
-	ArbETH = NativeAsset("ArbETH") // Arbitrum
-	ATOM   = NativeAsset("ATOM")   // Cosmos
-	AurETH = NativeAsset("AurETH") // Aurora
-	AVAX   = NativeAsset("AVAX")   // Avalanche
-	BERA   = NativeAsset("BERA")   // Berachain
-	BCH    = NativeAsset("BCH")    // Bitcoin Cash
-	BNB    = NativeAsset("BNB")    // Binance Coin
-	BTC    = NativeAsset("BTC")    // Bitcoin
-	CELO   = NativeAsset("CELO")   // Celo
-	CHZ    = NativeAsset("CHZ")    // Chiliz
-	CHZ2   = NativeAsset("CHZ2")   // Chiliz 2.0
-	DOGE   = NativeAsset("DOGE")   // Dogecoin
-	DOT    = NativeAsset("DOT")    // Polkadot
-	ETC    = NativeAsset("ETC")    // Ethereum Classic
-	ETH    = NativeAsset("ETH")    // Ethereum
-	ETHW   = NativeAsset("ETHW")   // Ethereum PoW
-	FTM    = NativeAsset("FTM")    // Fantom
-	HASH   = NativeAsset("HASH")   // Provenance
-	INJ    = NativeAsset("INJ")    // Injective
-	LTC    = NativeAsset("LTC")    // Litecoin
-	LUNA   = NativeAsset("LUNA")   // Terra V2
-	LUNC   = NativeAsset("LUNC")   // Terra Classic
-	KAR    = NativeAsset("KAR")    // Karura
-	KLAY   = NativeAsset("KLAY")   // Klaytn
-	KSM    = NativeAsset("KSM")    // Kusama
-	XDC    = NativeAsset("XDC")    // XinFin
-	MATIC  = NativeAsset("MATIC")  // Polygon
-	OAS    = NativeAsset("OAS")    // Oasys (not Oasis!)
-	OptETH = NativeAsset("OptETH") // Optimism
-	EmROSE = NativeAsset("EmROSE") // Rose (Oasis EVM-compat "Emerald" parachain)
-	SOL    = NativeAsset("SOL")    // Solana
-	SUI    = NativeAsset("SUI")    // SUI
-	XPLA   = NativeAsset("XPLA")   // XPLA
-	TAO    = NativeAsset("TAO")    // Bittensor
-	TIA    = NativeAsset("TIA")    // celestia
-	TON    = NativeAsset("TON")    // TON
-	TRX    = NativeAsset("TRX")    // TRON
-	SEI    = NativeAsset("SEI")    // Sei
+	ACA     = NativeAsset("ACA")     // Acala
+	APTOS   = NativeAsset("APTOS")   // APTOS
+	ArbETH  = NativeAsset("ArbETH")  // Arbitrum
+	ATOM    = NativeAsset("ATOM")    // Cosmos
+	AurETH  = NativeAsset("AurETH")  // Aurora
+	AVAX    = NativeAsset("AVAX")    // Avalanche
+	BaseETH = NativeAsset("BaseETH") // Base
+	BERA    = NativeAsset("BERA")    // Berachain
+	BCH     = NativeAsset("BCH")     // Bitcoin Cash
+	BNB     = NativeAsset("BNB")     // Binance Coin
+	BTC     = NativeAsset("BTC")     // Bitcoin
+	CELO    = NativeAsset("CELO")    // Celo
+	CHZ     = NativeAsset("CHZ")     // Chiliz
+	CHZ2    = NativeAsset("CHZ2")    // Chiliz 2.0
+	DOGE    = NativeAsset("DOGE")    // Dogecoin
+	DOT     = NativeAsset("DOT")     // Polkadot
+	ETC     = NativeAsset("ETC")     // Ethereum Classic
+	ETH     = NativeAsset("ETH")     // Ethereum
+	ETHW    = NativeAsset("ETHW")    // Ethereum PoW
+	FTM     = NativeAsset("FTM")     // Fantom
+	HASH    = NativeAsset("HASH")    // Provenance
+	INJ     = NativeAsset("INJ")     // Injective
+	LTC     = NativeAsset("LTC")     // Litecoin
+	LUNA    = NativeAsset("LUNA")    // Terra V2
+	LUNC    = NativeAsset("LUNC")    // Terra Classic
+	KAR     = NativeAsset("KAR")     // Karura
+	KLAY    = NativeAsset("KLAY")    // Klaytn
+	KSM     = NativeAsset("KSM")     // Kusama
+	XDC     = NativeAsset("XDC")     // XinFin
+	MATIC   = NativeAsset("MATIC")   // Polygon
+	OAS     = NativeAsset("OAS")     // Oasys (not Oasis!)
+	OptETH  = NativeAsset("OptETH")  // Optimism
+	EmROSE  = NativeAsset("EmROSE")  // Rose (Oasis EVM-compat "Emerald" parachain)
+	SOL     = NativeAsset("SOL")     // Solana
+	SUI     = NativeAsset("SUI")     // SUI
+	XPLA    = NativeAsset("XPLA")    // XPLA
+	TAO     = NativeAsset("TAO")     // Bittensor
+	TIA     = NativeAsset("TIA")     // celestia
+	TON     = NativeAsset("TON")     // TON
+	TRX     = NativeAsset("TRX")     // TRON
+	SEI     = NativeAsset("SEI")     // Sei
 )
 
 var NativeAssetList []NativeAsset = []NativeAsset{
@@ -64,6 +65,7 @@ var NativeAssetList []NativeAsset = []NativeAsset{
 	ATOM,
 	AurETH,
 	AVAX,
+	BaseETH,
 	BERA,
 	BNB,
 	CELO,
@@ -117,6 +119,17 @@ func (staking *StakingConfig) Enabled() bool {
 	return len(staking.Providers) > 0
 }
 
+// L2FeeModel is how a rollup charges transactions for posting their data to L1
+type L2FeeModel string
+
+const (
+	L2FeeModelNone L2FeeModel = "none"
+	// Optimism, Base, and other OP Stack chains, via the GasPriceOracle predeploy
+	L2FeeModelOpStack L2FeeModel = "op-stack"
+	// Arbitrum chains, via the NodeInterface precompile
+	L2FeeModelArbitrum L2FeeModel = "arbitrum"
+)
+
 type ChainConfig struct {
 	Blockchain       Blockchain    `yaml:"blockchain,omitempty"` // chain
 	Chain            NativeAsset   `yaml:"chain,omitempty"`      // chainId
@@ -140,9 +153,14 @@ type ChainConfig struct {
 
 	ExplorerURL string `yaml:"explorer_url,omitempty"`
 	NoGasFees   bool   `yaml:"no_gas_fees,omitempty"`
+	// Optional; set for L2 chains that charge an L1 data fee.  Defaults based on the chain.
+	L2FeeModel L2FeeModel `yaml:"l2_fee_model,omitempty"`
 
 	Staking StakingConfig `yaml:"staking,omitempty"`
 
+	// Optional; address lookup tables that solana transactions load accounts from
+	AddressLookupTables []string `yaml:"address_lookup_tables,omitempty"`
+
 	// Internal
 	AuthSecret string `yaml:"-"`
 }
@@ -167,6 +185,19 @@ func (native *ChainConfig) GetAssetSymbol() string {
 	return string(native.Chain)
 }
 
+func (native *ChainConfig) GetL2FeeModel() L2FeeModel {
+	if native.L2FeeModel != "" {
+		return native.L2FeeModel
+	}
+	switch native.Chain {
+	case OptETH, BaseETH:
+		return L2FeeModelOpStack
+	case ArbETH:
+		return L2FeeModelArbitrum
+	}
+	return L2FeeModelNone
+}
+
 type AssetID string
 
 type IAsset interface {
@@ -177,11 +208,22 @@ type IAsset interface {
 	GetAssetSymbol() string
 }
 
+// TokenStandard is the interface a token contract implements.  Only needs to be set for
+// non-fungible tokens, as fungible tokens are assumed by default.
+type TokenStandard string
+
+const (
+	TokenStandardErc20   TokenStandard = "erc20"
+	TokenStandardErc721  TokenStandard = "erc721"
+	TokenStandardErc1155 TokenStandard = "erc1155"
+)
+
 type TokenAssetConfig struct {
 	Asset       string          `yaml:"asset,omitempty"`
 	Chain       NativeAsset     `yaml:"chain,omitempty"`
 	Decimals    int32           `yaml:"decimals,omitempty"`
 	Contract    ContractAddress `yaml:"contract,omitempty"`
+	Standard    TokenStandard   `yaml:"standard,omitempty"`
 	ChainConfig *ChainConfig    `yaml:"-"`
 }
 
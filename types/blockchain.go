@@ -59,18 +59,59 @@ const Kiln StakingProvider = "kiln"
 const Figment StakingProvider = "figment"
 const Twinstake StakingProvider = "twinstake"
 const Native StakingProvider = "native"
+const Lido StakingProvider = "lido"
+
+// Pools on the SPL stake-pool program, e.g. Jito
+const StakePool StakingProvider = "stake-pool"
 
 var SupportedStakingProviders = []StakingProvider{
 	Native,
 	Kiln,
 	Figment,
 	Twinstake,
+	Lido,
+	StakePool,
+}
+
+// Liquid staking providers pool stake, so any amount can be staked rather than whole validators
+var LiquidStakingProviders = []StakingProvider{
+	Lido,
+	StakePool,
 }
 
 func (stakingProvider StakingProvider) Valid() bool {
 	return slices.Contains(SupportedStakingProviders, stakingProvider)
 }
 
+func (stakingProvider StakingProvider) IsLiquid() bool {
+	return slices.Contains(LiquidStakingProviders, stakingProvider)
+}
+
+// Operations to manage existing stake accounts, on chains that have them
+type StakeOperation string
+
+const (
+	// Merge stake accounts in the same state into one
+	StakeMerge StakeOperation = "stake-merge"
+	// Split an amount of stake into a new account
+	StakeSplit StakeOperation = "stake-split"
+	// Move stake to another account delegated to the same validator, or to an inactive account.
+	// Stake can't be moved to another validator, it must be unstaked and staked again.
+	StakeMove StakeOperation = "stake-move"
+	// Change the stake or withdraw authority of stake accounts
+	StakeAuthorize StakeOperation = "stake-authorize"
+	// Withdraw the entire balance of inactive stake accounts, closing them
+	StakeClose StakeOperation = "stake-close"
+)
+
+// The authorities of a stake account
+type StakeAuthority string
+
+const (
+	StakeAuthorityStaker     StakeAuthority = "staker"
+	StakeAuthorityWithdrawer StakeAuthority = "withdrawer"
+)
+
 type TxVariantInputType string
 
 func NewStakingInputType(blockchain Blockchain, variant string) TxVariantInputType {
@@ -85,6 +126,10 @@ func NewWithdrawingInputType(blockchain Blockchain, variant string) TxVariantInp
 	return TxVariantInputType(fmt.Sprintf("blockchains/%s/withdrawing/%s", blockchain, variant))
 }
 
+func NewStakeManagementInputType(blockchain Blockchain, operation StakeOperation, variant string) TxVariantInputType {
+	return TxVariantInputType(fmt.Sprintf("blockchains/%s/%s/%s", blockchain, operation, variant))
+}
+
 func (variant TxVariantInputType) Blockchain() Blockchain {
 	return Blockchain(strings.Split(string(variant), "/")[1])
 }
@@ -111,7 +156,7 @@ func (native NativeAsset) Blockchain() Blockchain {
 		return BlockchainBtcCash
 	case DOGE, LTC:
 		return BlockchainBtcLegacy
-	case AVAX, CELO, ETH, ETHW, MATIC, OptETH, ArbETH, BERA:
+	case AVAX, CELO, ETH, ETHW, MATIC, OptETH, ArbETH, BaseETH, BERA:
 		return BlockchainEVM
 	case BNB, FTM, ETC, EmROSE, AurETH, ACA, KAR, KLAY, OAS, CHZ, XDC, CHZ2:
 		return BlockchainEVMLegacy
@@ -10,6 +10,8 @@ type LegacyTxInfoEndpoint struct {
 	NativeAsset     NativeAsset     `json:"chain"`
 	Asset           string          `json:"asset,omitempty"`
 	Memo            string          `json:"memo,omitempty"`
+	// Set for non-fungible token movements
+	TokenId *BigInt `json:"token_id,omitempty"`
 	// AssetConfig     *AssetConfig     `json:"asset_config,omitempty"`
 
 	// legacy behavior around reporting aptos contract as ""
@@ -27,6 +29,7 @@ type LegacyTxInfo struct {
 	ContractAddress ContractAddress         `json:"contract,omitempty"`
 	Amount          BigInt                  `json:"amount"`
 	Fee             BigInt                  `json:"fee"`
+	L1Fee           BigInt                  `json:"l1_fee,omitempty"` // L2 only: portion of the fee paid to post the tx data to L1
 	FeeContract     ContractAddress         `json:"fee_contract,omitempty"`
 	BlockIndex      int64                   `json:"block_index,omitempty"`
 	BlockTime       int64                   `json:"block_time,omitempty"`
@@ -38,10 +41,34 @@ type LegacyTxInfo struct {
 	TimeReceived    int64                   `json:"time_received,omitempty"`
 	// If this transaction failed, this is the reason why.
 	Error string `json:"error,omitempty"`
+	// Messages emitted to be relayed to another chain
+	Messages []*CrossChainMessage `json:"messages,omitempty"`
 	// to support new TxInfo model, we can't drop "change" btc movements
 	droppedBtcDestinations []*LegacyTxInfoEndpoint
 	stakeEvents            []StakeEvent
 }
+type CrossChainProtocol string
+
+const (
+	CrossChainProtocolCctp CrossChainProtocol = "cctp"
+	// Wormhole messages carry the whole observation (the body of the VAA) as the payload
+	CrossChainProtocolWormhole CrossChainProtocol = "wormhole"
+)
+
+// CrossChainMessage is a message sent by a bridge protocol, which must be attested to before it can be
+// delivered on the destination chain
+type CrossChainMessage struct {
+	Protocol CrossChainProtocol `json:"protocol"`
+	// The contract or program that emitted the message
+	Emitter Address `json:"emitter"`
+	// The raw message, as it's passed to the destination chain
+	Payload []byte `json:"payload"`
+}
+
+func (info *LegacyTxInfo) AddMessage(protocol CrossChainProtocol, emitter Address, payload []byte) {
+	info.Messages = append(info.Messages, &CrossChainMessage{protocol, emitter, payload})
+}
+
 type StakeEvent interface {
 	GetValidator() string
 }
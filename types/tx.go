@@ -50,3 +50,7 @@ type WithdrawTxInput interface {
 	TxVariantInput
 	Withdrawing()
 }
+type StakeManagementTxInput interface {
+	TxVariantInput
+	ManagingStake() StakeOperation
+}